@@ -1,9 +1,10 @@
 import { catchError, Observable, of, switchMap } from 'rxjs';
 
-import { DataQuery, DataQueryRequest, DataQueryResponse } from '@grafana/data';
+import { DataQuery, DataQueryRequest, DataQueryResponse, VariableHide } from '@grafana/data';
 
 import { config } from '../config';
 import { getBackendSrv } from '../services/backendSrv';
+import { getTemplateSrv } from '../services/templateSrv';
 
 import { BackendDataSourceResponse, toDataQueryResponse } from './queryResponse';
 
@@ -30,6 +31,7 @@ export function publicDashboardQueryHandler(request: DataQueryRequest<DataQuery>
       to: toRange.valueOf().toString(),
       timezone: request.timezone,
     },
+    variables: getVariableValues(),
   };
 
   return getBackendSrv()
@@ -48,3 +50,19 @@ export function publicDashboardQueryHandler(request: DataQueryRequest<DataQuery>
       })
     );
 }
+
+/**
+ * Returns the values of the template variables viewers can change. The other variables are resolved by the server
+ * with the values saved in the dashboard.
+ */
+function getVariableValues(): Record<string, string[]> {
+  const variables: Record<string, string[]> = {};
+  for (const variable of getTemplateSrv().getVariables()) {
+    if (variable.hide === VariableHide.hideVariable || !('current' in variable) || !variable.current) {
+      continue;
+    }
+    const value = variable.current.value;
+    variables[variable.name] = Array.isArray(value) ? value : [value];
+  }
+  return variables;
+}
@@ -59,6 +59,10 @@ export interface TempoQuery extends common.DataQuery {
    * Defines the maximum number of spans per spanset that are returned from Tempo
    */
   spss?: number;
+  /**
+   * Step of TraceQL metrics queries. Use duration format, for example: 30s, 1m
+   */
+  step?: string;
   /**
    * The type of the table that is used to display the search results
    */
@@ -73,7 +77,7 @@ export const defaultTempoQuery: Partial<TempoQuery> = {
 /**
  * search = Loki search, nativeSearch = Tempo search for backwards compatibility
  */
-export type TempoQueryType = ('traceql' | 'traceqlSearch' | 'search' | 'serviceMap' | 'upload' | 'nativeSearch' | 'traceId' | 'clear');
+export type TempoQueryType = ('traceql' | 'traceqlSearch' | 'traceqlMetrics' | 'traceqlServiceMap' | 'search' | 'serviceMap' | 'upload' | 'nativeSearch' | 'traceId' | 'clear');
 
 /**
  * The state of the TraceQL streaming search query
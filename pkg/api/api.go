@@ -45,6 +45,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginaccesscontrol"
 	publicdashboardsapi "github.com/grafana/grafana/pkg/services/publicdashboards/api"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/shorturls"
 	"github.com/grafana/grafana/pkg/services/user"
 )
 
@@ -464,7 +465,10 @@ func (hs *HTTPServer) registerRoutes() {
 			dashboardRoute.Group("/uid/:uid", func(dashUidRoute routing.RouteRegister) {
 				dashUidRoute.Get("/versions", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.GetDashboardVersions))
 				dashUidRoute.Post("/restore", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.RestoreDashboardVersion))
+				dashUidRoute.Post("/restore/preview", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.PreviewRestoreDashboardVersion))
 				dashUidRoute.Get("/versions/:id", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.GetDashboardVersion))
+				dashUidRoute.Post("/snapshot", authorize(ac.EvalPermission(dashboards.ActionDashboardsRead)), routing.Wrap(hs.CaptureDashboardSnapshot))
+				dashUidRoute.Get("/lint", authorize(ac.EvalPermission(dashboards.ActionDashboardsRead)), routing.Wrap(hs.LintDashboardByUID))
 				dashUidRoute.Group("/permissions", func(dashboardPermissionRoute routing.RouteRegister) {
 					dashboardPermissionRoute.Get("/", authorize(ac.EvalPermission(dashboards.ActionDashboardsPermissionsRead)), routing.Wrap(hs.GetDashboardPermissionList))
 					dashboardPermissionRoute.Post("/", authorize(ac.EvalPermission(dashboards.ActionDashboardsPermissionsWrite)), routing.Wrap(hs.UpdateDashboardPermissions))
@@ -472,6 +476,8 @@ func (hs *HTTPServer) registerRoutes() {
 			})
 
 			dashboardRoute.Post("/calculate-diff", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.CalculateDashboardDiff))
+			dashboardRoute.Post("/lint", routing.Wrap(hs.LintDashboard))
+			dashboardRoute.Get("/lint/rules", routing.Wrap(hs.GetDashboardLintRules))
 
 			dashboardRoute.Post("/db", authorize(ac.EvalAny(ac.EvalPermission(dashboards.ActionDashboardsCreate), ac.EvalPermission(dashboards.ActionDashboardsWrite))), routing.Wrap(hs.PostDashboard))
 			dashboardRoute.Get("/home", routing.Wrap(hs.GetHomeDashboard))
@@ -574,7 +580,12 @@ func (hs *HTTPServer) registerRoutes() {
 		}, requestmeta.SetSLOGroup(requestmeta.SLOGroupNone))
 
 		// short urls
-		apiRoute.Post("/short-urls", routing.Wrap(hs.createShortURL))
+		apiRoute.Group("/short-urls", func(shortURLRoute routing.RouteRegister) {
+			shortURLRoute.Get("/", reqSignedInNoAnonymous, routing.Wrap(hs.searchShortURLs))
+			shortURLRoute.Post("/", quota(string(shorturls.QuotaTargetSrv)), routing.Wrap(hs.createShortURL))
+			shortURLRoute.Get("/:uid", reqSignedInNoAnonymous, routing.Wrap(hs.getShortURL))
+			shortURLRoute.Delete("/:uid", reqSignedInNoAnonymous, routing.Wrap(hs.deleteShortURL))
+		})
 	}, reqSignedIn)
 
 	// admin api
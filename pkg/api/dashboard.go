@@ -28,6 +28,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/guardian"
 	"github.com/grafana/grafana/pkg/services/org"
 	pref "github.com/grafana/grafana/pkg/services/preference"
+	provisioningdashboards "github.com/grafana/grafana/pkg/services/provisioning/dashboards"
 	publicdashboardModels "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/star"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -413,6 +414,16 @@ func (hs *HTTPServer) postDashboard(c *contextmodel.ReqContext, cmd dashboards.S
 		}
 	}
 
+	if hs.Cfg.DashboardLintEnforceOnSave {
+		if result := hs.dashboardLinter.Lint(dash.Data); result.HasErrors() {
+			return response.JSON(http.StatusBadRequest, util.DynMap{
+				"status":   "lint-failed",
+				"message":  "The dashboard has lint errors",
+				"findings": result.Findings,
+			})
+		}
+	}
+
 	var provisioningData *dashboards.DashboardProvisioning
 	if dash.ID != 0 {
 		data, err := hs.dashboardProvisioningService.GetProvisionedDashboardDataByDashboardID(c.Req.Context(), dash.ID)
@@ -433,6 +444,18 @@ func (hs *HTTPServer) postDashboard(c *contextmodel.ReqContext, cmd dashboards.S
 		allowUiUpdate = hs.ProvisioningService.GetAllowUIUpdatesFromConfig(provisioningData.Name)
 	}
 
+	// provisioned dashboards are saved before their changes are written back to their provisioning source,
+	// so that the permission and version checks of the save apply to the written changes as well
+	writeToProvisioningSource := provisioningData != nil && allowUiUpdate &&
+		hs.ProvisioningService.PushesProvisionedDashboards(provisioningData.Name)
+	var previous *dashboards.Dashboard
+	if writeToProvisioningSource {
+		previous, err = hs.DashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{ID: provisioningData.DashboardID, OrgID: c.SignedInUser.GetOrgID()})
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to get the dashboard being saved", err)
+		}
+	}
+
 	dashItem := &dashboards.SaveDashboardDTO{
 		Dashboard: dash,
 		Message:   cmd.Message,
@@ -443,6 +466,12 @@ func (hs *HTTPServer) postDashboard(c *contextmodel.ReqContext, cmd dashboards.S
 
 	dashboard, err := hs.DashboardService.SaveDashboard(alerting.WithUAEnabled(ctx, hs.Cfg.UnifiedAlerting.IsEnabled()), dashItem, allowUiUpdate)
 
+	if err == nil && writeToProvisioningSource {
+		if rsp := hs.writeProvisionedDashboard(c, cmd, provisioningData, previous, dashboard); rsp != nil {
+			return rsp
+		}
+	}
+
 	if hs.Live != nil {
 		// Tell everyone listening that the dashboard changed
 		if dashboard == nil {
@@ -500,6 +529,51 @@ func (hs *HTTPServer) postDashboard(c *contextmodel.ReqContext, cmd dashboards.S
 	})
 }
 
+// writeProvisionedDashboard writes a saved provisioned dashboard back to its provisioning source. The previous
+// version of the dashboard is saved again when the change cannot be written, so that Grafana stays in sync with
+// the source.
+func (hs *HTTPServer) writeProvisionedDashboard(c *contextmodel.ReqContext, cmd dashboards.SaveDashboardCommand,
+	provisioningData *dashboards.DashboardProvisioning, previous *dashboards.Dashboard, saved *dashboards.Dashboard) response.Response {
+	ctx := c.Req.Context()
+	writeErr := hs.ProvisioningService.WriteProvisionedDashboard(ctx, &provisioningdashboards.DashboardChange{
+		Provisioning: provisioningData,
+		Dashboard:    saved.Data,
+		Message:      cmd.Message,
+		AuthorName:   c.SignedInUser.GetDisplayName(),
+		AuthorEmail:  c.SignedInUser.GetEmail(),
+	})
+	if writeErr == nil {
+		return nil
+	}
+
+	restoreCmd := dashboards.SaveDashboardCommand{
+		Dashboard:    previous.Data,
+		OrgID:        cmd.OrgID,
+		UserID:       cmd.UserID,
+		FolderUID:    previous.FolderUID,
+		Overwrite:    true,
+		RestoredFrom: previous.Version,
+		Message:      fmt.Sprintf("Restored from version %d", previous.Version),
+	}
+	restoreCmd.Dashboard.Set("id", saved.ID)
+	restoreCmd.Dashboard.Set("uid", saved.UID)
+	restoreCmd.Dashboard.Set("version", saved.Version)
+	if _, err := hs.DashboardService.SaveDashboard(alerting.WithUAEnabled(ctx, hs.Cfg.UnifiedAlerting.IsEnabled()), &dashboards.SaveDashboardDTO{
+		Dashboard: restoreCmd.GetDashboardModel(),
+		Message:   restoreCmd.Message,
+		OrgID:     c.SignedInUser.GetOrgID(),
+		User:      c.SignedInUser,
+		Overwrite: true,
+	}, true); err != nil {
+		hs.log.Error("Failed to restore the dashboard after writing it to its provisioning source failed", "uid", saved.UID, "error", err)
+	}
+
+	if errors.Is(writeErr, provisioningdashboards.ErrDashboardConflict) {
+		return response.Error(http.StatusConflict, writeErr.Error(), writeErr)
+	}
+	return response.Error(http.StatusInternalServerError, "Failed to write the dashboard to its provisioning source", writeErr)
+}
+
 // swagger:route GET /dashboards/home dashboards getHomeDashboard
 //
 // Get home dashboard.
@@ -894,7 +968,7 @@ func (hs *HTTPServer) CalculateDashboardDiff(c *contextmodel.ReqContext) respons
 		return response.Error(http.StatusInternalServerError, "Unable to compute diff", err)
 	}
 
-	if options.DiffType == dashdiffs.DiffDelta {
+	if options.DiffType == dashdiffs.DiffDelta || options.DiffType == dashdiffs.DiffSemantic {
 		return response.Respond(http.StatusOK, result.Delta).SetHeader("Content-Type", "application/json")
 	}
 
@@ -990,6 +1064,53 @@ func (hs *HTTPServer) RestoreDashboardVersion(c *contextmodel.ReqContext) respon
 	return hs.postDashboard(c, saveCmd)
 }
 
+// swagger:route POST /dashboards/uid/{uid}/restore/preview dashboard_versions previewRestoreDashboardVersionByUID
+//
+// Preview the changes of restoring a dashboard to a given dashboard version.
+//
+// Returns the semantic diff between the current dashboard and the version, nothing is saved.
+//
+// Responses:
+// 200: previewRestoreDashboardVersionResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) PreviewRestoreDashboardVersion(c *contextmodel.ReqContext) response.Response {
+	apiCmd := dtos.RestoreDashboardVersionCommand{}
+	if err := web.Bind(c.Req, &apiCmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	dash, rsp := hs.getDashboardHelper(c.Req.Context(), c.SignedInUser.GetOrgID(), 0, web.Params(c.Req)[":uid"])
+	if rsp != nil {
+		return rsp
+	}
+
+	guardian, err := guardian.NewByDashboard(c.Req.Context(), dash, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	if canSave, err := guardian.CanSave(); err != nil || !canSave {
+		return dashboardGuardianResponse(err)
+	}
+
+	versionQuery := dashver.GetDashboardVersionQuery{DashboardID: dash.ID, DashboardUID: dash.UID, Version: apiCmd.Version, OrgID: c.SignedInUser.GetOrgID()}
+	version, err := hs.dashboardVersionService.Get(c.Req.Context(), &versionQuery)
+	if err != nil {
+		return response.Error(http.StatusNotFound, "Dashboard version not found", nil)
+	}
+
+	return response.JSON(http.StatusOK, dtos.RestoreDashboardVersionPreview{
+		DashboardUID:   dash.UID,
+		CurrentVersion: dash.Version,
+		RestoreVersion: version.Version,
+		Diff:           dashdiffs.CalculateSemanticDiff(dash.Data, version.Data),
+	})
+}
+
 // swagger:route GET /dashboards/tags dashboards getDashboardTags
 //
 // Get all dashboards tags of an organisation.
@@ -1062,6 +1183,16 @@ type RestoreDashboardVersionByUIDParams struct {
 	UID string `json:"uid"`
 }
 
+// swagger:parameters previewRestoreDashboardVersionByUID
+type PreviewRestoreDashboardVersionByUIDParams struct {
+	// in:body
+	// required:true
+	Body dtos.RestoreDashboardVersionCommand
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
 // swagger:parameters getDashboardVersionByID
 type GetDashboardVersionByIDParams struct {
 	// in:path
@@ -1126,7 +1257,8 @@ type CalcDashboardDiffParams struct {
 		// Description:
 		// * `basic`
 		// * `json`
-		// Enum: basic,json
+		// * `semantic` the added, removed and modified panels, queries and template variables
+		// Enum: basic,json,semantic
 		DiffType string `json:"diffType" binding:"Required"`
 	}
 }
@@ -1206,6 +1338,12 @@ type CalculateDashboardDiffResponse struct {
 	Body []byte `json:"body"`
 }
 
+// swagger:response previewRestoreDashboardVersionResponse
+type PreviewRestoreDashboardVersionResponse struct {
+	// in: body
+	Body dtos.RestoreDashboardVersionPreview `json:"body"`
+}
+
 // swagger:response getHomeDashboardResponse
 type GetHomeDashboardResponse struct {
 	// in: body
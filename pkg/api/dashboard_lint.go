@@ -0,0 +1,100 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards/lint"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route POST /dashboards/lint dashboards lintDashboard
+//
+// Lint a dashboard.
+//
+// Runs the dashboard lint rules configured in the `[dashboard_lint]` section over the dashboard JSON model
+// of the request, and returns the findings with their severity.
+//
+// Responses:
+// 200: lintDashboardResponse
+// 400: badRequestError
+// 401: unauthorisedError
+func (hs *HTTPServer) LintDashboard(c *contextmodel.ReqContext) response.Response {
+	cmd := dtos.LintDashboardCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	return response.JSON(http.StatusOK, hs.dashboardLinter.Lint(cmd.Dashboard))
+}
+
+// swagger:route GET /dashboards/uid/{uid}/lint dashboards lintDashboardByUID
+//
+// Lint a dashboard by uid.
+//
+// Runs the dashboard lint rules configured in the `[dashboard_lint]` section over the saved dashboard.
+//
+// Responses:
+// 200: lintDashboardResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) LintDashboardByUID(c *contextmodel.ReqContext) response.Response {
+	dash, rsp := hs.getDashboardHelper(c.Req.Context(), c.SignedInUser.GetOrgID(), 0, web.Params(c.Req)[":uid"])
+	if rsp != nil {
+		return rsp
+	}
+
+	guardian, err := guardian.NewByDashboard(c.Req.Context(), dash, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return response.Err(err)
+	}
+	if canView, err := guardian.CanView(); err != nil || !canView {
+		return dashboardGuardianResponse(err)
+	}
+
+	return response.JSON(http.StatusOK, hs.dashboardLinter.Lint(dash.Data))
+}
+
+// swagger:route GET /dashboards/lint/rules dashboards getDashboardLintRules
+//
+// Get the dashboard lint rules.
+//
+// Returns the rules run by the dashboard linter, with their severity and whether they are enabled.
+//
+// Responses:
+// 200: getDashboardLintRulesResponse
+// 401: unauthorisedError
+func (hs *HTTPServer) GetDashboardLintRules(c *contextmodel.ReqContext) response.Response {
+	return response.JSON(http.StatusOK, hs.dashboardLinter.Rules())
+}
+
+// swagger:parameters lintDashboard
+type LintDashboardParams struct {
+	// in:body
+	// required:true
+	Body dtos.LintDashboardCommand
+}
+
+// swagger:parameters lintDashboardByUID
+type LintDashboardByUIDParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
+// swagger:response lintDashboardResponse
+type LintDashboardResponse struct {
+	// in: body
+	Body lint.Result `json:"body"`
+}
+
+// swagger:response getDashboardLintRulesResponse
+type GetDashboardLintRulesResponse struct {
+	// in: body
+	Body []lint.RuleInfo `json:"body"`
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboards/lint"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestDashboardLintAPIEndpoint(t *testing.T) {
+	invalidDashboard := func() *simplejson.Json {
+		return simplejson.NewFromAny(map[string]any{
+			"title":         "Dash",
+			"schemaVersion": 39,
+			"panels": []any{
+				map[string]any{"id": 1, "type": "graph", "title": "Requests", "datasource": "Prometheus"},
+			},
+		})
+	}
+
+	lintDashboardScenario(t, "When calling POST on", "/api/dashboards/lint", setting.NewCfg(), invalidDashboard(), func(sc *scenarioContext) {
+		sc.fakeReqWithParams("POST", sc.url, map[string]string{}).exec()
+		require.Equal(t, http.StatusOK, sc.resp.Code)
+
+		findings := sc.ToJSON().Get("findings")
+		require.Len(t, findings.MustArray(), 2)
+		assert.Equal(t, "datasource-uid", findings.GetIndex(0).Get("rule").MustString())
+		assert.Equal(t, "error", findings.GetIndex(0).Get("severity").MustString())
+		assert.Equal(t, "panels[0].datasource", findings.GetIndex(0).Get("path").MustString())
+		assert.Equal(t, "deprecated-panel-type", findings.GetIndex(1).Get("rule").MustString())
+		assert.Equal(t, "warning", findings.GetIndex(1).Get("severity").MustString())
+	})
+
+	t.Run("Saving a dashboard with lint errors", func(t *testing.T) {
+		cmd := dashboards.SaveDashboardCommand{OrgID: 1, UserID: 5, Dashboard: invalidDashboard()}
+
+		t.Run("is rejected when the linter is enforced on save", func(t *testing.T) {
+			cfg := setting.NewCfg()
+			cfg.DashboardLintEnforceOnSave = true
+			hs := HTTPServer{Cfg: cfg, QuotaService: quotatest.New(false, nil), dashboardLinter: newTestDashboardLinter(t, cfg), log: log.New("test-logger")}
+
+			sc := setupScenarioContext(t, "/api/dashboards/db")
+			sc.defaultHandler = routing.Wrap(func(c *contextmodel.ReqContext) response.Response {
+				c.Req.Body = mockRequestBody(cmd)
+				c.Req.Header.Add("Content-Type", "application/json")
+				sc.context = c
+				sc.context.SignedInUser = &user.SignedInUser{OrgID: cmd.OrgID, UserID: cmd.UserID}
+				return hs.PostDashboard(c)
+			})
+			sc.m.Post(sc.url, sc.defaultHandler)
+
+			callPostDashboard(sc)
+			require.Equal(t, http.StatusBadRequest, sc.resp.Code)
+			result := sc.ToJSON()
+			assert.Equal(t, "lint-failed", result.Get("status").MustString())
+			assert.Len(t, result.Get("findings").MustArray(), 2)
+		})
+	})
+}
+
+func lintDashboardScenario(t *testing.T, desc string, url string, cfg *setting.Cfg, dashboard *simplejson.Json, fn scenarioFunc) {
+	t.Run(fmt.Sprintf("%s %s", desc, url), func(t *testing.T) {
+		hs := HTTPServer{Cfg: cfg, dashboardLinter: newTestDashboardLinter(t, cfg)}
+
+		sc := setupScenarioContext(t, url)
+		sc.defaultHandler = routing.Wrap(func(c *contextmodel.ReqContext) response.Response {
+			c.Req.Body = mockRequestBody(dtos.LintDashboardCommand{Dashboard: dashboard})
+			c.Req.Header.Add("Content-Type", "application/json")
+			sc.context = c
+			return hs.LintDashboard(c)
+		})
+		sc.m.Post(url, sc.defaultHandler)
+
+		fn(sc)
+	})
+}
+
+func newTestDashboardLinter(t *testing.T, cfg *setting.Cfg) *lint.Linter {
+	t.Helper()
+
+	linter, err := lint.New(lint.ConfigFromSettings(cfg))
+	require.NoError(t, err)
+	return linter
+}
@@ -12,11 +12,13 @@ import (
 	"github.com/grafana/grafana/pkg/infra/appcontext"
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/services/apiserver/endpoints/request"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/util/errutil/errhttp"
 	"github.com/grafana/grafana/pkg/web"
@@ -76,6 +78,84 @@ func (hs *HTTPServer) CreateDashboardSnapshot(c *contextmodel.ReqContext) {
 	}, hs.dashboardsnapshotsService)
 }
 
+// swagger:route POST /dashboards/uid/{uid}/snapshot snapshots captureDashboardSnapshot
+//
+// Create a snapshot of a dashboard by running its queries on the server.
+//
+// The queries of every panel are run with the time range and template variable values of the request, and their
+// results are stored in the snapshot. Query, custom and constant template variables are supported.
+//
+// Responses:
+// 200: createDashboardSnapshotResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) CaptureDashboardSnapshot(c *contextmodel.ReqContext) response.Response {
+	if !hs.Cfg.SnapshotEnabled {
+		return response.Error(http.StatusForbidden, "Dashboard Snapshots are disabled", nil)
+	}
+
+	cmd := dashboardsnapshots.CaptureDashboardSnapshotCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	dash, rsp := hs.getDashboardHelper(c.Req.Context(), c.SignedInUser.GetOrgID(), 0, web.Params(c.Req)[":uid"])
+	if rsp != nil {
+		return rsp
+	}
+
+	guardian, err := guardian.NewByDashboard(c.Req.Context(), dash, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return response.Err(err)
+	}
+	if canView, err := guardian.CanView(); err != nil || !canView {
+		return dashboardGuardianResponse(err)
+	}
+
+	userID, err := identity.UserIdentifier(c.SignedInUser.GetNamespacedID())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to create snapshot", err)
+	}
+
+	snapshotDashboard, err := dashboardsnapshots.CaptureDashboard(c.Req.Context(), hs.queryDataService, c.SignedInUser, dash.Data, &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to capture dashboard", err)
+	}
+
+	createCmd := dashboardsnapshots.CreateDashboardSnapshotCommand{
+		DashboardCreateCommand: dashboardsnapshot.DashboardCreateCommand{
+			Name:      snapshotDashboard.GetNestedString("title"),
+			Dashboard: snapshotDashboard,
+			Expires:   cmd.Expires,
+		},
+		OrgID:  c.SignedInUser.GetOrgID(),
+		UserID: userID,
+	}
+	if createCmd.Key, err = util.GetRandomString(32); err != nil {
+		return response.Error(http.StatusInternalServerError, "Could not generate random string", err)
+	}
+	if createCmd.DeleteKey, err = util.GetRandomString(32); err != nil {
+		return response.Error(http.StatusInternalServerError, "Could not generate random string", err)
+	}
+
+	result, err := hs.dashboardsnapshotsService.CreateDashboardSnapshot(c.Req.Context(), &createCmd)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to create snapshot", err)
+	}
+
+	metrics.MApiDashboardSnapshotCreate.Inc()
+
+	return response.JSON(http.StatusOK, dashboardsnapshot.DashboardCreateResponse{
+		Key:       result.Key,
+		DeleteKey: result.DeleteKey,
+		URL:       setting.ToAbsUrl("dashboard/snapshot/" + result.Key),
+		DeleteURL: setting.ToAbsUrl("api/snapshots-delete/" + result.DeleteKey),
+	})
+}
+
 // GET /api/snapshots/:key
 // swagger:route GET /snapshots/{key} snapshots getDashboardSnapshot
 //
@@ -298,6 +378,16 @@ type CreateSnapshotParams struct {
 	Body dashboardsnapshots.CreateDashboardSnapshotCommand `json:"body"`
 }
 
+// swagger:parameters captureDashboardSnapshot
+type CaptureSnapshotParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:body
+	// required:true
+	Body dashboardsnapshots.CaptureDashboardSnapshotCommand `json:"body"`
+}
+
 // swagger:parameters searchDashboardSnapshots
 type GetSnapshotsParams struct {
 	// Search Query
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -9,12 +10,17 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/query"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/web/webtest"
 
@@ -124,6 +130,118 @@ func TestHTTPServer_DeleteDashboardSnapshot(t *testing.T) {
 	})
 }
 
+type fakeSnapshotQueryDataService struct {
+	query.Service
+}
+
+func (s *fakeSnapshotQueryDataService) QueryData(_ context.Context, _ identity.Requester, _ bool, reqDTO dtos.MetricRequest) (*backend.QueryDataResponse, error) {
+	resp := backend.NewQueryDataResponse()
+	for _, q := range reqDTO.Queries {
+		resp.Responses[q.Get("refId").MustString()] = backend.DataResponse{Frames: data.Frames{
+			data.NewFrame(q.Get("expr").MustString(), data.NewField("value", nil, []float64{1})),
+		}}
+	}
+	return resp, nil
+}
+
+func TestHTTPServer_CaptureDashboardSnapshot(t *testing.T) {
+	dashboardData := simplejson.NewFromAny(map[string]any{
+		"uid":   "1",
+		"title": "Service",
+		"templating": map[string]any{"list": []any{
+			map[string]any{"name": "job", "type": "custom", "query": "api,db", "current": map[string]any{"value": "api"}},
+		}},
+		"panels": []any{map[string]any{
+			"id":         1,
+			"datasource": map[string]any{"type": "prometheus", "uid": "prom"},
+			"targets":    []any{map[string]any{"refId": "A", "expr": "up{job=\"$job\"}"}},
+		}},
+	})
+
+	setup := func(t *testing.T, snapshotEnabled bool, snapshotService dashboardsnapshots.Service) *webtest.Server {
+		t.Helper()
+
+		return SetupAPITestServer(t, func(hs *HTTPServer) {
+			cfg := setting.NewCfg()
+			cfg.SnapshotEnabled = snapshotEnabled
+			hs.Cfg = cfg
+			hs.dashboardsnapshotsService = snapshotService
+			hs.queryDataService = &fakeSnapshotQueryDataService{}
+
+			svc := dashboards.NewFakeDashboardService(t)
+			svc.On("GetDashboard", mock.Anything, mock.Anything).Return(&dashboards.Dashboard{UID: "1", OrgID: 1, Data: dashboardData}, nil).Maybe()
+			hs.DashboardService = svc
+
+			hs.AccessControl = acimpl.ProvideAccessControl(hs.Cfg)
+			guardian.InitAccessControlGuardian(hs.Cfg, hs.AccessControl, hs.DashboardService)
+		})
+	}
+
+	viewer := userWithPermissions(1, []accesscontrol.Permission{
+		{Action: dashboards.ActionDashboardsRead, Scope: "dashboards:uid:1"},
+	})
+
+	t.Run("creates a snapshot with the results of the queries", func(t *testing.T) {
+		snapshotService := dashboardsnapshots.NewMockService(t)
+		var cmd *dashboardsnapshots.CreateDashboardSnapshotCommand
+		snapshotService.On("CreateDashboardSnapshot", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			cmd = args.Get(1).(*dashboardsnapshots.CreateDashboardSnapshotCommand)
+		}).Return(&dashboardsnapshots.DashboardSnapshot{Key: "key", DeleteKey: "deleteKey"}, nil)
+
+		server := setup(t, true, snapshotService)
+		res, err := server.SendJSON(webtest.RequestWithSignedInUser(
+			server.NewPostRequest("/api/dashboards/uid/1/snapshot", strings.NewReader(`{"from": "now-1h", "to": "now", "variables": {"job": ["db"]}, "expires": 3600}`)),
+			viewer,
+		))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+
+		require.NotNil(t, cmd)
+		assert.Equal(t, "Service", cmd.Name)
+		assert.Equal(t, int64(3600), cmd.Expires)
+		assert.Len(t, cmd.Key, 32)
+		assert.Len(t, cmd.DeleteKey, 32)
+		frames, ok := cmd.Dashboard.Object["panels"].([]any)[0].(map[string]any)["snapshotData"].([]any)
+		require.True(t, ok)
+		require.Len(t, frames, 1)
+		assert.Equal(t, `up{job="db"}`, frames[0].(map[string]any)["schema"].(map[string]any)["name"])
+	})
+
+	t.Run("returns 400 when the variable does not exist", func(t *testing.T) {
+		server := setup(t, true, dashboardsnapshots.NewMockService(t))
+		res, err := server.SendJSON(webtest.RequestWithSignedInUser(
+			server.NewPostRequest("/api/dashboards/uid/1/snapshot", strings.NewReader(`{"variables": {"env": ["prod"]}}`)),
+			viewer,
+		))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("returns 403 when snapshots are disabled", func(t *testing.T) {
+		server := setup(t, false, dashboardsnapshots.NewMockService(t))
+		res, err := server.SendJSON(webtest.RequestWithSignedInUser(
+			server.NewPostRequest("/api/dashboards/uid/1/snapshot", strings.NewReader(`{}`)),
+			viewer,
+		))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("returns 403 when the user can't view the dashboard", func(t *testing.T) {
+		server := setup(t, true, dashboardsnapshots.NewMockService(t))
+		res, err := server.SendJSON(webtest.RequestWithSignedInUser(
+			server.NewPostRequest("/api/dashboards/uid/1/snapshot", strings.NewReader(`{}`)),
+			userWithPermissions(1, []accesscontrol.Permission{{Action: dashboards.ActionDashboardsRead, Scope: "dashboards:uid:2"}}),
+		))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
+
 func TestDashboardSnapshotAPIEndpoint_singleSnapshot(t *testing.T) {
 	setupRemoteServer := func(fn func(http.ResponseWriter, *http.Request)) *httptest.Server {
 		s := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/db/dbtest"
@@ -50,6 +51,7 @@ import (
 	pref "github.com/grafana/grafana/pkg/services/preference"
 	"github.com/grafana/grafana/pkg/services/preference/preftest"
 	"github.com/grafana/grafana/pkg/services/provisioning"
+	provisioningdashboards "github.com/grafana/grafana/pkg/services/provisioning/dashboards"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/api"
 	"github.com/grafana/grafana/pkg/services/quota/quotatest"
@@ -632,6 +634,53 @@ func TestDashboardAPIEndpoint(t *testing.T) {
 			}, mockSQLStore)
 	})
 
+	t.Run("Given dashboard being previewed for restore", func(t *testing.T) {
+		fakeDash := dashboards.NewDashboard("Child dash")
+		fakeDash.ID = 2
+		fakeDash.UID = "uid"
+		fakeDash.Version = 3
+		fakeDash.Data = simplejson.NewFromAny(map[string]any{
+			"title":  "Child dash",
+			"panels": []any{map[string]any{"id": 1, "type": "stat", "title": "Uptime"}},
+		})
+
+		dashboardService := dashboards.NewFakeDashboardService(t)
+		dashboardService.On("GetDashboard", mock.Anything, mock.AnythingOfType("*dashboards.GetDashboardQuery")).Return(fakeDash, nil)
+
+		fakeDashboardVersionService := dashvertest.NewDashboardVersionServiceFake()
+		fakeDashboardVersionService.ExpectedDashboardVersions = []*dashver.DashboardVersionDTO{
+			{
+				DashboardID: 2,
+				Version:     1,
+				Data:        simplejson.NewFromAny(map[string]any{"title": "Child dash"}),
+			}}
+
+		cmd := dtos.RestoreDashboardVersionCommand{
+			Version: 1,
+		}
+		mockSQLStore := dbtest.NewFakeDB()
+		origNewGuardian := guardian.New
+		guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanSaveValue: true})
+		t.Cleanup(func() {
+			guardian.New = origNewGuardian
+		})
+
+		previewRestoreDashboardVersionScenario(t, "When calling POST on", "/api/dashboards/uid/uid/restore/preview",
+			"/api/dashboards/uid/:uid/restore/preview", dashboardService, fakeDashboardVersionService, cmd, func(sc *scenarioContext) {
+				callRestoreDashboardVersion(sc)
+				require.Equal(t, http.StatusOK, sc.resp.Code)
+
+				preview := dtos.RestoreDashboardVersionPreview{}
+				require.NoError(t, json.NewDecoder(sc.resp.Body).Decode(&preview))
+				assert.Equal(t, "uid", preview.DashboardUID)
+				assert.Equal(t, 3, preview.CurrentVersion)
+				assert.Equal(t, 1, preview.RestoreVersion)
+				require.Len(t, preview.Diff.Panels, 1)
+				assert.Equal(t, dashdiffs.SemanticRemoved, preview.Diff.Panels[0].Change)
+				assert.Equal(t, "Uptime", preview.Diff.Panels[0].Title)
+			}, mockSQLStore)
+	})
+
 	t.Run("Given provisioned dashboard", func(t *testing.T) {
 		mockSQLStore := dbtest.NewFakeDB()
 		dashboardStore := dashboards.NewFakeDashboardStore(t)
@@ -1007,6 +1056,40 @@ func restoreDashboardVersionScenario(t *testing.T, desc string, url string, rout
 	})
 }
 
+func previewRestoreDashboardVersionScenario(t *testing.T, desc string, url string, routePattern string,
+	mock *dashboards.FakeDashboardService, fakeDashboardVersionService *dashvertest.FakeDashboardVersionService,
+	cmd dtos.RestoreDashboardVersionCommand, fn scenarioFunc, sqlStore db.DB) {
+	t.Run(fmt.Sprintf("%s %s", desc, url), func(t *testing.T) {
+		hs := HTTPServer{
+			Cfg:                     setting.NewCfg(),
+			DashboardService:        mock,
+			SQLStore:                sqlStore,
+			Features:                featuremgmt.WithFeatures(),
+			dashboardVersionService: fakeDashboardVersionService,
+		}
+
+		sc := setupScenarioContext(t, url)
+		sc.sqlStore = sqlStore
+		sc.dashboardVersionService = fakeDashboardVersionService
+		sc.defaultHandler = routing.Wrap(func(c *contextmodel.ReqContext) response.Response {
+			c.Req.Body = mockRequestBody(cmd)
+			c.Req.Header.Add("Content-Type", "application/json")
+			sc.context = c
+			sc.context.SignedInUser = &user.SignedInUser{
+				OrgID:  testOrgID,
+				UserID: testUserID,
+			}
+			sc.context.OrgRole = org.RoleAdmin
+
+			return hs.PreviewRestoreDashboardVersion(c)
+		})
+
+		sc.m.Post(routePattern, sc.defaultHandler)
+
+		fn(sc)
+	})
+}
+
 func (sc *scenarioContext) ToJSON() *simplejson.Json {
 	result := simplejson.New()
 	err := json.NewDecoder(sc.resp.Body).Decode(result)
@@ -1067,3 +1150,136 @@ func (l *mockLibraryElementService) DisconnectElementsFromDashboard(c context.Co
 func (l *mockLibraryElementService) DeleteLibraryElementsInFolder(c context.Context, signedInUser identity.Requester, folderUID string) error {
 	return nil
 }
+
+func TestPostProvisionedDashboard(t *testing.T) {
+	const dashID int64 = 3
+	provisioningData := &dashboards.DashboardProvisioning{DashboardID: dashID, Name: "git", ExternalID: "api.json"}
+	previous := &dashboards.Dashboard{
+		ID: dashID, UID: "api", Title: "API", Version: 4, FolderUID: "team-a",
+		Data: simplejson.NewFromAny(map[string]any{"id": dashID, "uid": "api", "title": "API", "version": 4}),
+	}
+	saved := &dashboards.Dashboard{
+		ID: dashID, UID: "api", Title: "API v2", Slug: "api-v2", Version: 5, FolderUID: "team-a",
+		Data: simplejson.NewFromAny(map[string]any{"id": dashID, "uid": "api", "title": "API v2", "version": 5}),
+	}
+	cmd := dashboards.SaveDashboardCommand{
+		OrgID:     1,
+		UserID:    5,
+		Dashboard: simplejson.NewFromAny(map[string]any{"id": dashID, "uid": "api", "title": "API v2", "version": 4}),
+		FolderUID: "team-a",
+		Message:   "Rename",
+	}
+
+	scenario := func(t *testing.T, dashboardService dashboards.DashboardService, writeErr error, fn func(sc *scenarioContext, provisioningService *provisioning.ProvisioningServiceMock)) {
+		provisioningService := provisioning.NewProvisioningServiceMock(context.Background())
+		provisioningService.GetAllowUIUpdatesFromConfigFunc = func(name string) bool { return true }
+		provisioningService.PushesProvisionedDashboardsFunc = func(name string) bool { return true }
+		provisioningService.WriteProvisionedDashboardFunc = func(ctx context.Context, change *provisioningdashboards.DashboardChange) error {
+			return writeErr
+		}
+
+		hs := HTTPServer{
+			Cfg:                          setting.NewCfg(),
+			ProvisioningService:          provisioningService,
+			dashboardProvisioningService: provisionedDashboardProvisioningService{data: provisioningData},
+			QuotaService:                 quotatest.New(false, nil),
+			pluginStore:                  &pluginstore.FakePluginStore{},
+			LibraryPanelService:          &mockLibraryPanelService{},
+			LibraryElementService:        &mockLibraryElementService{},
+			DashboardService:             dashboardService,
+			Features:                     featuremgmt.WithFeatures(),
+			accesscontrolService:         actest.FakeService{},
+			log:                          log.New("test-logger"),
+		}
+
+		sc := setupScenarioContext(t, "/api/dashboards/db")
+		sc.defaultHandler = routing.Wrap(func(c *contextmodel.ReqContext) response.Response {
+			c.Req.Body = mockRequestBody(cmd)
+			c.Req.Header.Add("Content-Type", "application/json")
+			sc.context = c
+			sc.context.SignedInUser = &user.SignedInUser{OrgID: cmd.OrgID, UserID: cmd.UserID, Login: "editor", Email: "editor@example.com"}
+			return hs.PostDashboard(c)
+		})
+		sc.m.Post("/api/dashboards/db", sc.defaultHandler)
+
+		fn(sc, provisioningService)
+	}
+
+	newDashboardService := func(t *testing.T) *dashboards.FakeDashboardService {
+		dashboardService := dashboards.NewFakeDashboardService(t)
+		dashboardService.On("GetDashboard", mock.Anything, mock.AnythingOfType("*dashboards.GetDashboardQuery")).Return(previous, nil)
+		return dashboardService
+	}
+
+	t.Run("Does not write the dashboard to its provisioning source when the save is denied", func(t *testing.T) {
+		dashboardService := newDashboardService(t)
+		dashboardService.On("SaveDashboard", mock.Anything, mock.AnythingOfType("*dashboards.SaveDashboardDTO"), true).
+			Return(nil, dashboards.ErrDashboardUpdateAccessDenied).Once()
+
+		scenario(t, dashboardService, nil, func(sc *scenarioContext, provisioningService *provisioning.ProvisioningServiceMock) {
+			callPostDashboard(sc)
+			assert.Equal(t, http.StatusForbidden, sc.resp.Code)
+			assert.Empty(t, provisioningService.Calls.WriteProvisionedDashboard)
+		})
+	})
+
+	t.Run("Does not get the previous version when the provisioning source is not written", func(t *testing.T) {
+		dashboardService := dashboards.NewFakeDashboardService(t)
+		dashboardService.On("SaveDashboard", mock.Anything, mock.AnythingOfType("*dashboards.SaveDashboardDTO"), true).Return(saved, nil).Once()
+
+		scenario(t, dashboardService, nil, func(sc *scenarioContext, provisioningService *provisioning.ProvisioningServiceMock) {
+			provisioningService.PushesProvisionedDashboardsFunc = func(name string) bool { return false }
+
+			callPostDashboardShouldReturnSuccess(sc)
+			assert.Equal(t, []any{"git"}, provisioningService.Calls.PushesProvisionedDashboards)
+			assert.Empty(t, provisioningService.Calls.WriteProvisionedDashboard)
+			dashboardService.AssertNotCalled(t, "GetDashboard", mock.Anything, mock.Anything)
+		})
+	})
+
+	t.Run("Writes the saved dashboard to its provisioning source", func(t *testing.T) {
+		dashboardService := newDashboardService(t)
+		dashboardService.On("SaveDashboard", mock.Anything, mock.AnythingOfType("*dashboards.SaveDashboardDTO"), true).Return(saved, nil).Once()
+
+		scenario(t, dashboardService, nil, func(sc *scenarioContext, provisioningService *provisioning.ProvisioningServiceMock) {
+			callPostDashboardShouldReturnSuccess(sc)
+			require.Len(t, provisioningService.Calls.WriteProvisionedDashboard, 1)
+			change := provisioningService.Calls.WriteProvisionedDashboard[0].(*provisioningdashboards.DashboardChange)
+			assert.Equal(t, provisioningData, change.Provisioning)
+			assert.Equal(t, "API v2", change.Dashboard.Get("title").MustString())
+			assert.Equal(t, "Rename", change.Message)
+			assert.Equal(t, "editor@example.com", change.AuthorEmail)
+		})
+	})
+
+	t.Run("Restores the previous version when the dashboard cannot be written to its provisioning source", func(t *testing.T) {
+		dashboardService := newDashboardService(t)
+		dashboardService.On("SaveDashboard", mock.Anything, mock.AnythingOfType("*dashboards.SaveDashboardDTO"), true).Return(saved, nil).Once()
+		var restored *dashboards.SaveDashboardDTO
+		dashboardService.On("SaveDashboard", mock.Anything, mock.AnythingOfType("*dashboards.SaveDashboardDTO"), true).Run(func(args mock.Arguments) {
+			restored = args.Get(1).(*dashboards.SaveDashboardDTO)
+		}).Return(previous, nil).Once()
+
+		scenario(t, dashboardService, provisioningdashboards.ErrDashboardConflict, func(sc *scenarioContext, provisioningService *provisioning.ProvisioningServiceMock) {
+			callPostDashboard(sc)
+			assert.Equal(t, http.StatusConflict, sc.resp.Code)
+			require.Len(t, provisioningService.Calls.WriteProvisionedDashboard, 1)
+
+			require.NotNil(t, restored)
+			assert.True(t, restored.Overwrite)
+			assert.Equal(t, dashID, restored.Dashboard.ID)
+			assert.Equal(t, "API", restored.Dashboard.Title)
+			assert.Equal(t, "team-a", restored.Dashboard.FolderUID)
+		})
+	})
+}
+
+type provisionedDashboardProvisioningService struct {
+	dashboards.DashboardProvisioningService
+	data *dashboards.DashboardProvisioning
+}
+
+func (s provisionedDashboardProvisioningService) GetProvisionedDashboardDataByDashboardID(ctx context.Context, dashboardID int64) (
+	*dashboards.DashboardProvisioning, error) {
+	return s.data, nil
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	dashboardsV0 "github.com/grafana/grafana/pkg/apis/dashboard/v0alpha1"
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 )
 
@@ -62,3 +63,14 @@ type CalculateDiffTarget struct {
 type RestoreDashboardVersionCommand struct {
 	Version int `json:"version" binding:"Required"`
 }
+
+type RestoreDashboardVersionPreview struct {
+	DashboardUID   string                  `json:"dashboardUid"`
+	CurrentVersion int                     `json:"currentVersion"`
+	RestoreVersion int                     `json:"restoreVersion"`
+	Diff           *dashdiffs.SemanticDiff `json:"diff"`
+}
+
+type LintDashboardCommand struct {
+	Dashboard *simplejson.Json `json:"dashboard" binding:"Required"`
+}
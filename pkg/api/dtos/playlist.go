@@ -1,5 +1,11 @@
 package dtos
 
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/playlist"
+)
+
 type PlaylistDashboard struct {
 	Id    int64  `json:"id"`
 	Slug  string `json:"slug"`
@@ -22,3 +28,13 @@ func (slice PlaylistDashboardsSlice) Less(i, j int) bool {
 func (slice PlaylistDashboardsSlice) Swap(i, j int) {
 	slice[i], slice[j] = slice[j], slice[i]
 }
+
+type PlaylistCurrentItem struct {
+	PlaylistUID    string                   `json:"playlistUid"`
+	DashboardUID   string                   `json:"dashboardUid"`
+	DashboardTitle string                   `json:"dashboardTitle"`
+	Url            string                   `json:"url"`
+	Item           playlist.PlaylistItemDTO `json:"item"`
+	StartedAt      time.Time                `json:"startedAt"`
+	NextChangeAt   time.Time                `json:"nextChangeAt"`
+}
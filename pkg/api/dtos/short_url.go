@@ -1,5 +1,7 @@
 package dtos
 
+import "time"
+
 type ShortURL struct {
 	UID string `json:"uid"`
 	URL string `json:"url"`
@@ -7,4 +9,24 @@ type ShortURL struct {
 
 type CreateShortURLCmd struct {
 	Path string `json:"path"`
+	// Expires is the number of seconds after which the short URL expires, 0 if it never expires.
+	Expires int64 `json:"expires"`
+}
+
+type ShortURLDetails struct {
+	UID        string     `json:"uid"`
+	URL        string     `json:"url"`
+	Path       string     `json:"path"`
+	CreatedBy  int64      `json:"createdBy"`
+	Created    time.Time  `json:"created"`
+	Expires    *time.Time `json:"expires,omitempty"`
+	LastSeenAt *time.Time `json:"lastSeenAt,omitempty"`
+	HitCount   int64      `json:"hitCount"`
+}
+
+type SearchShortURLsResult struct {
+	TotalCount int64             `json:"totalCount"`
+	ShortURLs  []ShortURLDetails `json:"shortUrls"`
+	Page       int               `json:"page"`
+	PerPage    int               `json:"perPage"`
 }
@@ -1,10 +1,14 @@
 package api
 
 import (
+	"errors"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
-	"k8s.io/apimachinery/pkg/api/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
@@ -20,6 +24,8 @@ import (
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/playlist"
+	"github.com/grafana/grafana/pkg/services/search"
+	"github.com/grafana/grafana/pkg/services/search/model"
 	"github.com/grafana/grafana/pkg/util/errutil/errhttp"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -33,6 +39,7 @@ func (hs *HTTPServer) registerPlaylistAPI(apiRoute routing.RouteRegister) {
 			playlistRoute.Get("/", handler.searchPlaylists)
 			playlistRoute.Get("/:uid", handler.getPlaylist)
 			playlistRoute.Get("/:uid/items", handler.getPlaylistItems)
+			playlistRoute.Get("/:uid/current", hs.validateOrgPlaylist, routing.Wrap(hs.GetCurrentPlaylistItem))
 			playlistRoute.Delete("/:uid", handler.deletePlaylist)
 			playlistRoute.Put("/:uid", handler.updatePlaylist)
 			playlistRoute.Post("/", handler.createPlaylist)
@@ -41,6 +48,7 @@ func (hs *HTTPServer) registerPlaylistAPI(apiRoute routing.RouteRegister) {
 			playlistRoute.Get("/", routing.Wrap(hs.SearchPlaylists))
 			playlistRoute.Get("/:uid", hs.validateOrgPlaylist, routing.Wrap(hs.GetPlaylist))
 			playlistRoute.Get("/:uid/items", hs.validateOrgPlaylist, routing.Wrap(hs.GetPlaylistItems))
+			playlistRoute.Get("/:uid/current", hs.validateOrgPlaylist, routing.Wrap(hs.GetCurrentPlaylistItem))
 			playlistRoute.Delete("/:uid", middleware.ReqEditorRole, hs.validateOrgPlaylist, routing.Wrap(hs.DeletePlaylist))
 			playlistRoute.Put("/:uid", middleware.ReqEditorRole, hs.validateOrgPlaylist, routing.Wrap(hs.UpdatePlaylist))
 			playlistRoute.Post("/", middleware.ReqEditorRole, routing.Wrap(hs.CreatePlaylist))
@@ -142,6 +150,120 @@ func (hs *HTTPServer) GetPlaylistItems(c *contextmodel.ReqContext) response.Resp
 	return response.JSON(http.StatusOK, dto.Items)
 }
 
+// swagger:route GET /playlists/{uid}/current playlists getCurrentPlaylistItem
+//
+// Get the current item of a playlist.
+//
+// Returns the dashboard shown by the playlist now, for wall displays following the playlist without running it
+// in the browser. Only the items active at the current time of day are shown, each one for its dwell time, in
+// a rotation shared by every display. The URL opens the dashboard in kiosk mode with the time range and
+// template variable overrides of the item.
+//
+// Responses:
+// 200: getCurrentPlaylistItemResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetCurrentPlaylistItem(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	dto, err := hs.playlistService.Get(c.Req.Context(), &playlist.GetPlaylistByUidQuery{UID: uid, OrgId: c.SignedInUser.GetOrgID()})
+	if err != nil {
+		return response.Error(http.StatusNotFound, "Playlist not found", err)
+	}
+
+	location, err := time.LoadLocation(dto.Timezone)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Invalid playlist timezone", err)
+	}
+
+	now := time.Now()
+	slots := []playlist.Slot{}
+	titles := map[string]*model.Hit{}
+	for _, item := range dto.Items {
+		if !item.IsActive(now, location) {
+			continue
+		}
+
+		hits, err := hs.searchPlaylistItemDashboards(c, item)
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to load playlist dashboards", err)
+		}
+		for _, hit := range hits {
+			slot, err := playlist.NewSlot(hit.UID, item, dto.Interval)
+			if err != nil {
+				return response.Error(http.StatusInternalServerError, "Invalid playlist item", err)
+			}
+			slots = append(slots, slot)
+			titles[hit.UID] = hit
+		}
+	}
+
+	slot, start, ok := playlist.CurrentSlot(slots, now)
+	if !ok {
+		return response.Error(http.StatusNotFound, "No dashboard of the playlist is active", nil)
+	}
+
+	hit := titles[slot.DashboardUID]
+	return response.JSON(http.StatusOK, dtos.PlaylistCurrentItem{
+		PlaylistUID:    dto.Uid,
+		DashboardUID:   hit.UID,
+		DashboardTitle: hit.Title,
+		Url:            playlistItemURL(hit.URL, slot.Item),
+		Item:           slot.Item,
+		StartedAt:      start,
+		NextChangeAt:   start.Add(slot.Dwell),
+	})
+}
+
+// searchPlaylistItemDashboards returns the dashboards of a playlist item the user can view.
+func (hs *HTTPServer) searchPlaylistItemDashboards(c *contextmodel.ReqContext, item playlist.PlaylistItemDTO) (model.HitList, error) {
+	query := search.Query{
+		SignedInUser: c.SignedInUser,
+		OrgId:        c.SignedInUser.GetOrgID(),
+		Type:         string(model.DashHitDB),
+		Limit:        1000,
+		Sort:         search.SortAlphaAsc.Name,
+	}
+
+	switch item.Type {
+	case "dashboard_by_uid":
+		query.DashboardUIDs = []string{item.Value}
+	case "dashboard_by_id":
+		id, err := strconv.ParseInt(item.Value, 10, 64)
+		if err != nil {
+			return nil, nil
+		}
+		query.DashboardIds = []int64{id}
+	case "dashboard_by_tag":
+		query.Tags = []string{item.Value}
+	default:
+		return nil, nil
+	}
+
+	return hs.SearchService.SearchHandler(c.Req.Context(), &query)
+}
+
+// playlistItemURL returns the kiosk mode URL of a dashboard with the overrides of the playlist item.
+func playlistItemURL(dashboardURL string, item playlist.PlaylistItemDTO) string {
+	params := url.Values{}
+	if item.TimeFrom != "" {
+		params.Set("from", item.TimeFrom)
+	}
+	if item.TimeTo != "" {
+		params.Set("to", item.TimeTo)
+	}
+	for name, value := range item.Variables {
+		params.Set("var-"+name, value)
+	}
+
+	u := dashboardURL + "?kiosk"
+	if len(params) > 0 {
+		u += "&" + params.Encode()
+	}
+	return u
+}
+
 // swagger:route DELETE /playlists/{uid} playlists deletePlaylist
 //
 // Delete playlist.
@@ -182,6 +304,9 @@ func (hs *HTTPServer) CreatePlaylist(c *contextmodel.ReqContext) response.Respon
 
 	p, err := hs.playlistService.Create(c.Req.Context(), &cmd)
 	if err != nil {
+		if errors.Is(err, playlist.ErrInvalidSchedule) {
+			return response.Error(http.StatusBadRequest, err.Error(), err)
+		}
 		return response.Error(500, "Failed to create playlist", err)
 	}
 
@@ -208,6 +333,9 @@ func (hs *HTTPServer) UpdatePlaylist(c *contextmodel.ReqContext) response.Respon
 
 	_, err := hs.playlistService.Update(c.Req.Context(), &cmd)
 	if err != nil {
+		if errors.Is(err, playlist.ErrInvalidSchedule) {
+			return response.Error(http.StatusBadRequest, err.Error(), err)
+		}
 		return response.Error(500, "Failed to save playlist", err)
 	}
 
@@ -252,6 +380,13 @@ type GetPlaylistDashboardsParams struct {
 	UID string `json:"uid"`
 }
 
+// swagger:parameters getCurrentPlaylistItem
+type GetCurrentPlaylistItemParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
 // swagger:parameters deletePlaylist
 type DeletePlaylistParams struct {
 	// in:path
@@ -304,6 +439,13 @@ type GetPlaylistDashboardsResponse struct {
 	Body dtos.PlaylistDashboardsSlice `json:"body"`
 }
 
+// swagger:response getCurrentPlaylistItemResponse
+type GetCurrentPlaylistItemResponse struct {
+	// The response message
+	// in: body
+	Body dtos.PlaylistCurrentItem `json:"body"`
+}
+
 // swagger:response updatePlaylistResponse
 type UpdatePlaylistResponse struct {
 	// The response message
@@ -459,7 +601,7 @@ func (pk8s *playlistK8sHandler) getClient(c *contextmodel.ReqContext) (dynamic.R
 
 func (pk8s *playlistK8sHandler) writeError(c *contextmodel.ReqContext, err error) {
 	//nolint:errorlint
-	statusError, ok := err.(*errors.StatusError)
+	statusError, ok := err.(*apierrors.StatusError)
 	if ok {
 		c.JsonApiErr(int(statusError.Status().Code), statusError.Status().Message, err)
 		return
@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/shorturls"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
@@ -20,13 +22,21 @@ func (hs *HTTPServer) createShortURL(c *contextmodel.ReqContext) response.Respon
 	if err := web.Bind(c.Req, &cmd); err != nil {
 		return response.Err(shorturls.ErrShortURLBadRequest.Errorf("bad request data: %w", err))
 	}
+	if cmd.Expires < 0 {
+		return response.Err(shorturls.ErrShortURLInvalidExpiry.Errorf("negative expiry: %d", cmd.Expires))
+	}
 	hs.log.Debug("Received request to create short URL", "path", cmd.Path)
-	shortURL, err := hs.ShortURLService.CreateShortURL(c.Req.Context(), c.SignedInUser, cmd.Path)
+
+	createCmd := shorturls.CreateShortURLCommand{Path: cmd.Path}
+	if cmd.Expires > 0 {
+		createCmd.ExpiresAt = time.Now().Add(time.Duration(cmd.Expires) * time.Second)
+	}
+	shortURL, err := hs.ShortURLService.CreateShortURL(c.Req.Context(), c.SignedInUser, &createCmd)
 	if err != nil {
 		return response.Err(err)
 	}
 
-	url := fmt.Sprintf("%s/goto/%s?orgId=%d", strings.TrimSuffix(hs.Cfg.AppURL, "/"), shortURL.Uid, c.SignedInUser.GetOrgID())
+	url := hs.shortURLAbsoluteURL(shortURL)
 	c.Logger.Debug("Created short URL", "url", url)
 
 	dto := dtos.ShortURL{
@@ -37,6 +47,66 @@ func (hs *HTTPServer) createShortURL(c *contextmodel.ReqContext) response.Respon
 	return response.JSON(http.StatusOK, dto)
 }
 
+// searchShortURLs lists the short URLs created by the signed in user. Org admins can list the short URLs of
+// every user of the org with the all query parameter.
+func (hs *HTTPServer) searchShortURLs(c *contextmodel.ReqContext) response.Response {
+	query := shorturls.SearchShortURLsQuery{
+		OrgID:     c.SignedInUser.GetOrgID(),
+		CreatedBy: c.SignedInUser.UserID,
+		AllUsers:  c.QueryBool("all"),
+		Page:      c.QueryInt("page"),
+		Limit:     c.QueryInt("perpage"),
+	}
+	if query.AllUsers && !c.SignedInUser.HasRole(org.RoleAdmin) {
+		return response.Err(shorturls.ErrShortURLForbidden.Errorf("only org admins can list all short URLs"))
+	}
+	if !query.AllUsers && c.SignedInUser.UserID == 0 {
+		return response.Err(shorturls.ErrShortURLForbidden.Errorf("only users can list their short URLs"))
+	}
+
+	result, err := hs.ShortURLService.SearchShortURLs(c.Req.Context(), &query)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	dto := dtos.SearchShortURLsResult{
+		TotalCount: result.TotalCount,
+		ShortURLs:  make([]dtos.ShortURLDetails, 0, len(result.ShortURLs)),
+		Page:       result.Page,
+		PerPage:    result.PerPage,
+	}
+	for _, shortURL := range result.ShortURLs {
+		dto.ShortURLs = append(dto.ShortURLs, hs.shortURLDetails(shortURL))
+	}
+
+	return response.JSON(http.StatusOK, dto)
+}
+
+// getShortURL returns the details of a short URL to its owner or an org admin.
+func (hs *HTTPServer) getShortURL(c *contextmodel.ReqContext) response.Response {
+	shortURL, err := hs.getManagedShortURL(c)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	return response.JSON(http.StatusOK, hs.shortURLDetails(shortURL))
+}
+
+// deleteShortURL deletes a short URL on behalf of its owner or an org admin.
+func (hs *HTTPServer) deleteShortURL(c *contextmodel.ReqContext) response.Response {
+	shortURL, err := hs.getManagedShortURL(c)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	cmd := shorturls.DeleteShortURLCommand{OrgID: shortURL.OrgId, UID: shortURL.Uid}
+	if err := hs.ShortURLService.DeleteShortURL(c.Req.Context(), &cmd); err != nil {
+		return response.Err(err)
+	}
+
+	return response.Success("Short URL deleted")
+}
+
 func (hs *HTTPServer) redirectFromShortURL(c *contextmodel.ReqContext) {
 	shortURLUID := web.Params(c.Req)[":uid"]
 
@@ -63,3 +133,48 @@ func (hs *HTTPServer) redirectFromShortURL(c *contextmodel.ReqContext) {
 	hs.log.Debug("Redirecting short URL", "path", shortURL.Path)
 	c.Redirect(setting.ToAbsUrl(shortURL.Path), 302)
 }
+
+// getManagedShortURL returns the short URL of the request if the signed in user created it or is an org admin.
+func (hs *HTTPServer) getManagedShortURL(c *contextmodel.ReqContext) (*shorturls.ShortUrl, error) {
+	uid := web.Params(c.Req)[":uid"]
+	if !util.IsValidShortUID(uid) {
+		return nil, shorturls.ErrShortURLBadRequest.Errorf("invalid uid: %s", uid)
+	}
+
+	shortURL, err := hs.ShortURLService.GetShortURLByUID(c.Req.Context(), c.SignedInUser, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	// short URLs created without a user, such as by API keys, have no owner
+	isOwner := c.SignedInUser.UserID != 0 && shortURL.CreatedBy == c.SignedInUser.UserID
+	if !isOwner && !c.SignedInUser.HasRole(org.RoleAdmin) {
+		return nil, shorturls.ErrShortURLForbidden.Errorf("user %d cannot manage short URL %s", c.SignedInUser.UserID, uid)
+	}
+
+	return shortURL, nil
+}
+
+func (hs *HTTPServer) shortURLAbsoluteURL(shortURL *shorturls.ShortUrl) string {
+	return fmt.Sprintf("%s/goto/%s?orgId=%d", strings.TrimSuffix(hs.Cfg.AppURL, "/"), shortURL.Uid, shortURL.OrgId)
+}
+
+func (hs *HTTPServer) shortURLDetails(shortURL *shorturls.ShortUrl) dtos.ShortURLDetails {
+	dto := dtos.ShortURLDetails{
+		UID:       shortURL.Uid,
+		URL:       hs.shortURLAbsoluteURL(shortURL),
+		Path:      shortURL.Path,
+		CreatedBy: shortURL.CreatedBy,
+		Created:   time.Unix(shortURL.CreatedAt, 0),
+		HitCount:  shortURL.HitCount,
+	}
+	if shortURL.ExpiresAt > 0 {
+		expires := time.Unix(shortURL.ExpiresAt, 0)
+		dto.Expires = &expires
+	}
+	if shortURL.LastSeenAt > 0 {
+		lastSeenAt := time.Unix(shortURL.LastSeenAt, 0)
+		dto.LastSeenAt = &lastSeenAt
+	}
+	return dto
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -13,9 +14,11 @@ import (
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/shorturls"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
 )
 
 func TestShortURLAPIEndpoint(t *testing.T) {
@@ -31,7 +34,7 @@ func TestShortURLAPIEndpoint(t *testing.T) {
 			Path:  cmd.Path,
 		}
 		service := &fakeShortURLService{
-			createShortURLFunc: func(ctx context.Context, user *user.SignedInUser, path string) (*shorturls.ShortUrl, error) {
+			createShortURLFunc: func(ctx context.Context, user *user.SignedInUser, cmd *shorturls.CreateShortURLCommand) (*shorturls.ShortUrl, error) {
 				return createResp, nil
 			},
 		}
@@ -49,6 +52,115 @@ func TestShortURLAPIEndpoint(t *testing.T) {
 	})
 }
 
+func TestShortURLManagementAPIEndpoints(t *testing.T) {
+	shortURL := &shorturls.ShortUrl{Id: 1, OrgId: testOrgID, Uid: "N1u6L4eGz", Path: "d/TxKARsmGz", CreatedBy: 10, HitCount: 3}
+
+	testCases := []struct {
+		desc         string
+		signedInUser *user.SignedInUser
+		expectedCode int
+	}{
+		{desc: "owner", signedInUser: &user.SignedInUser{OrgID: testOrgID, UserID: 10, OrgRole: org.RoleViewer}, expectedCode: http.StatusOK},
+		{desc: "org admin", signedInUser: &user.SignedInUser{OrgID: testOrgID, UserID: 20, OrgRole: org.RoleAdmin}, expectedCode: http.StatusOK},
+		{desc: "other user", signedInUser: &user.SignedInUser{OrgID: testOrgID, UserID: 20, OrgRole: org.RoleEditor}, expectedCode: http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Deleting a short URL as %s", tc.desc), func(t *testing.T) {
+			service := &fakeShortURLService{shortURL: shortURL}
+			server := SetupAPITestServer(t, func(hs *HTTPServer) {
+				hs.ShortURLService = service
+			})
+
+			req := webtest.RequestWithSignedInUser(server.NewRequest(http.MethodDelete, "/api/short-urls/N1u6L4eGz", nil), tc.signedInUser)
+			res, err := server.Send(req)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedCode, res.StatusCode)
+			require.NoError(t, res.Body.Close())
+
+			if tc.expectedCode == http.StatusOK {
+				require.Equal(t, []string{"N1u6L4eGz"}, service.deleted)
+			} else {
+				require.Empty(t, service.deleted)
+			}
+		})
+	}
+
+	t.Run("Getting a short URL returns its usage", func(t *testing.T) {
+		server := SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.ShortURLService = &fakeShortURLService{shortURL: shortURL}
+		})
+
+		req := webtest.RequestWithSignedInUser(server.NewGetRequest("/api/short-urls/N1u6L4eGz"), testCases[0].signedInUser)
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		details := dtos.ShortURLDetails{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&details))
+		require.NoError(t, res.Body.Close())
+		require.Equal(t, int64(3), details.HitCount)
+		require.Nil(t, details.LastSeenAt)
+	})
+
+	t.Run("Listing all short URLs requires the org admin role", func(t *testing.T) {
+		server := SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.ShortURLService = &fakeShortURLService{}
+		})
+
+		req := webtest.RequestWithSignedInUser(server.NewGetRequest("/api/short-urls?all=true"), testCases[2].signedInUser)
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+
+		req = webtest.RequestWithSignedInUser(server.NewGetRequest("/api/short-urls?all=true"), testCases[1].signedInUser)
+		res, err = server.Send(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("Callers without user id cannot manage the short URLs created without user", func(t *testing.T) {
+		anonymousShortURL := &shorturls.ShortUrl{Id: 2, OrgId: testOrgID, Uid: "A1u6L4eGz", Path: "d/TxKARsmGz", CreatedBy: 0}
+		service := &fakeShortURLService{shortURL: anonymousShortURL}
+		server := SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.ShortURLService = service
+		})
+
+		anonymous := &contextmodel.ReqContext{
+			SignedInUser:   &user.SignedInUser{OrgID: testOrgID, IsAnonymous: true, OrgRole: org.RoleAdmin},
+			AllowAnonymous: true,
+		}
+		apiKey := &user.SignedInUser{OrgID: testOrgID, OrgRole: org.RoleViewer}
+
+		for _, tc := range []struct {
+			desc         string
+			request      func(*http.Request) *http.Request
+			expectedCode int
+		}{
+			{desc: "anonymous", expectedCode: http.StatusUnauthorized, request: func(req *http.Request) *http.Request {
+				return webtest.RequestWithWebContext(req, anonymous)
+			}},
+			{desc: "API key", expectedCode: http.StatusForbidden, request: func(req *http.Request) *http.Request {
+				return webtest.RequestWithSignedInUser(req, apiKey)
+			}},
+		} {
+			for _, req := range []*http.Request{
+				server.NewGetRequest("/api/short-urls"),
+				server.NewGetRequest("/api/short-urls/A1u6L4eGz"),
+				server.NewRequest(http.MethodDelete, "/api/short-urls/A1u6L4eGz", nil),
+			} {
+				res, err := server.Send(tc.request(req))
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedCode, res.StatusCode, "%s %s %s", tc.desc, req.Method, req.URL.Path)
+				require.NoError(t, res.Body.Close())
+			}
+		}
+		require.Empty(t, service.deleted)
+	})
+}
+
 func callCreateShortURL(sc *scenarioContext) {
 	sc.fakeReqWithParams("POST", sc.url, map[string]string{}).exec()
 }
@@ -78,21 +190,35 @@ func createShortURLScenario(t *testing.T, desc string, url string, routePattern
 }
 
 type fakeShortURLService struct {
-	createShortURLFunc func(ctx context.Context, user *user.SignedInUser, path string) (*shorturls.ShortUrl, error)
+	createShortURLFunc func(ctx context.Context, user *user.SignedInUser, cmd *shorturls.CreateShortURLCommand) (*shorturls.ShortUrl, error)
+	shortURL           *shorturls.ShortUrl
+	deleted            []string
 }
 
 func (s *fakeShortURLService) GetShortURLByUID(ctx context.Context, user *user.SignedInUser, uid string) (*shorturls.ShortUrl, error) {
-	return nil, nil
+	if s.shortURL == nil || s.shortURL.Uid != uid {
+		return nil, shorturls.ErrShortURLNotFound.Errorf("short URL not found")
+	}
+	return s.shortURL, nil
 }
 
-func (s *fakeShortURLService) CreateShortURL(ctx context.Context, user *user.SignedInUser, path string) (*shorturls.ShortUrl, error) {
+func (s *fakeShortURLService) CreateShortURL(ctx context.Context, user *user.SignedInUser, cmd *shorturls.CreateShortURLCommand) (*shorturls.ShortUrl, error) {
 	if s.createShortURLFunc != nil {
-		return s.createShortURLFunc(ctx, user, path)
+		return s.createShortURLFunc(ctx, user, cmd)
 	}
 
 	return nil, nil
 }
 
+func (s *fakeShortURLService) SearchShortURLs(ctx context.Context, query *shorturls.SearchShortURLsQuery) (*shorturls.SearchShortURLsResult, error) {
+	return &shorturls.SearchShortURLsResult{}, nil
+}
+
+func (s *fakeShortURLService) DeleteShortURL(ctx context.Context, cmd *shorturls.DeleteShortURLCommand) error {
+	s.deleted = append(s.deleted, cmd.UID)
+	return nil
+}
+
 func (s *fakeShortURLService) UpdateLastSeenAt(ctx context.Context, shortURL *shorturls.ShortUrl) error {
 	return nil
 }
@@ -32,6 +32,9 @@ type Spec struct {
 	// Interval sets the time between switching views in a playlist.
 	Interval string `json:"interval"`
 
+	// Timezone of the active windows of the items, for example `Europe/Paris`. Defaults to UTC.
+	Timezone string `json:"timezone,omitempty"`
+
 	// The ordered list of items that the playlist will iterate over.
 	Items []Item `json:"items,omitempty"`
 }
@@ -63,4 +66,23 @@ type Item struct {
 	//  dashboards behind the tag will be added to the playlist.
 	//  - dashboard_by_uid: The value is the dashboard UID
 	Value string `json:"value"`
+
+	// Dwell is the time the item is shown, it overrides the interval of the playlist.
+	Dwell string `json:"dwell,omitempty"`
+
+	// TimeFrom and TimeTo override the time range of the dashboards, for example `now-24h` and `now`.
+	TimeFrom string `json:"timeFrom,omitempty"`
+	TimeTo   string `json:"timeTo,omitempty"`
+
+	// Variables override the values of the template variables of the dashboards, by variable name.
+	Variables map[string]string `json:"variables,omitempty"`
+
+	// ActiveFrom and ActiveTo are the time of day, formatted as `15:04`, between which the item is shown.
+	// The window can span midnight, for example from `20:00` to `08:00`. The item is always shown when empty.
+	ActiveFrom string `json:"activeFrom,omitempty"`
+	ActiveTo   string `json:"activeTo,omitempty"`
+
+	// ActiveDays are the days of the week, such as `mon` or `sat`, on which the item is shown. A window spanning
+	// midnight belongs to the day it starts. The item is shown every day when empty.
+	ActiveDays []string `json:"activeDays,omitempty"`
 }
@@ -47,6 +47,70 @@ func schema_pkg_apis_playlist_v0alpha1_Item(ref common.ReferenceCallback) common
 							Format:      "",
 						},
 					},
+					"dwell": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Dwell is the time the item is shown, it overrides the interval of the playlist.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"timeFrom": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TimeFrom and TimeTo override the time range of the dashboards, for example `now-24h` and `now`.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"timeTo": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"variables": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Variables override the values of the template variables of the dashboards, by variable name.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"activeFrom": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ActiveFrom and ActiveTo are the time of day, formatted as `15:04`, between which the item is shown. The window can span midnight, for example from `20:00` to `08:00`. The item is always shown when empty.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"activeTo": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"activeDays": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ActiveDays are the days of the week, such as `mon` or `sat`, on which the item is shown. A window spanning midnight belongs to the day it starts. The item is shown every day when empty.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"type", "value"},
 			},
@@ -165,6 +229,13 @@ func schema_pkg_apis_playlist_v0alpha1_Spec(ref common.ReferenceCallback) common
 							Format:      "",
 						},
 					},
+					"timezone": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Timezone of the active windows of the items, for example `Europe/Paris`. Defaults to UTC.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"items": {
 						SchemaProps: spec.SchemaProps{
 							Description: "The ordered list of items that the playlist will iterate over.",
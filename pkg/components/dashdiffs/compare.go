@@ -23,6 +23,7 @@ const (
 	DiffJSON DiffType = iota
 	DiffBasic
 	DiffDelta
+	DiffSemantic
 )
 
 type Options struct {
@@ -50,6 +51,8 @@ func ParseDiffType(diff string) DiffType {
 		return DiffBasic
 	case "delta":
 		return DiffDelta
+	case "semantic":
+		return DiffSemantic
 	}
 	return DiffBasic
 }
@@ -57,6 +60,15 @@ func ParseDiffType(diff string) DiffType {
 // CompareDashboardVersionsCommand computes the JSON diff of two versions,
 // assigning the delta of the diff to the `Delta` field.
 func CalculateDiff(ctx context.Context, options *Options, baseData, newData *simplejson.Json) (*Result, error) {
+	// the semantic diff of identical dashboards is empty rather than an error
+	if options.DiffType == DiffSemantic {
+		semanticOutput, err := json.Marshal(CalculateSemanticDiff(baseData, newData))
+		if err != nil {
+			return nil, err
+		}
+		return &Result{Delta: semanticOutput}, nil
+	}
+
 	left, jsonDiff, err := getDiff(baseData, newData)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,347 @@
+package dashdiffs
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// SemanticChange is the kind of change applied to a panel, a query or a
+// template variable.
+type SemanticChange string
+
+const (
+	SemanticAdded    SemanticChange = "added"
+	SemanticRemoved  SemanticChange = "removed"
+	SemanticModified SemanticChange = "modified"
+)
+
+// ignoredDashboardFields are set by Grafana when the dashboard is saved and
+// change between every version.
+var ignoredDashboardFields = map[string]bool{
+	"id":      true,
+	"version": true,
+}
+
+// A SemanticDiff summarizes the changes between two dashboards in terms of
+// panels, queries and template variables. Changes to other dashboard fields
+// are listed in Fields.
+type SemanticDiff struct {
+	Fields    []FieldChange    `json:"fields"`
+	Panels    []PanelChange    `json:"panels"`
+	Variables []VariableChange `json:"variables"`
+}
+
+// A FieldChange is a change of the value at the given path, for example
+// `time.from` or `gridPos.y`. Old is unset when the field was added, New is
+// unset when it was removed.
+type FieldChange struct {
+	Path string `json:"path"`
+	Old  any    `json:"old,omitempty"`
+	New  any    `json:"new,omitempty"`
+}
+
+// A PanelChange is an added, removed or modified panel. Panels are matched by
+// their id, including the panels of collapsed rows.
+type PanelChange struct {
+	Change  SemanticChange `json:"change"`
+	ID      int64          `json:"id"`
+	Title   string         `json:"title"`
+	Type    string         `json:"type"`
+	Fields  []FieldChange  `json:"fields,omitempty"`
+	Queries []QueryChange  `json:"queries,omitempty"`
+}
+
+// A QueryChange is an added, removed or modified query of a panel. Queries are
+// matched by their refId.
+type QueryChange struct {
+	Change SemanticChange `json:"change"`
+	RefID  string         `json:"refId"`
+	Old    any            `json:"old,omitempty"`
+	New    any            `json:"new,omitempty"`
+}
+
+// A VariableChange is an added, removed or modified template variable.
+// Variables are matched by their name.
+type VariableChange struct {
+	Change SemanticChange `json:"change"`
+	Name   string         `json:"name"`
+	Type   string         `json:"type"`
+	Fields []FieldChange  `json:"fields,omitempty"`
+}
+
+// IsEmpty returns true when the two dashboards have no differences.
+func (d *SemanticDiff) IsEmpty() bool {
+	return len(d.Fields) == 0 && len(d.Panels) == 0 && len(d.Variables) == 0
+}
+
+// A PanelDiff summarizes the changes between two models of the same panel,
+// such as two versions of a library panel.
+type PanelDiff struct {
+	Fields  []FieldChange `json:"fields"`
+	Queries []QueryChange `json:"queries"`
+}
+
+// IsEmpty returns true when the two panels have no differences.
+func (d *PanelDiff) IsEmpty() bool {
+	return len(d.Fields) == 0 && len(d.Queries) == 0
+}
+
+// CalculatePanelDiff computes the semantic diff of two panel models.
+func CalculatePanelDiff(basePanel, newPanel *simplejson.Json) *PanelDiff {
+	fields, queries := comparePanel(basePanel.MustMap(), newPanel.MustMap())
+	return &PanelDiff{Fields: fields, Queries: queries}
+}
+
+// CalculateSemanticDiff computes the panel aware diff of two dashboards.
+func CalculateSemanticDiff(baseData, newData *simplejson.Json) *SemanticDiff {
+	base := baseData.MustMap()
+	next := newData.MustMap()
+
+	result := &SemanticDiff{
+		Fields:    make([]FieldChange, 0),
+		Panels:    make([]PanelChange, 0),
+		Variables: make([]VariableChange, 0),
+	}
+
+	for _, key := range sortedKeys(base, next) {
+		if ignoredDashboardFields[key] || key == "panels" || key == "templating" {
+			continue
+		}
+		result.Fields = append(result.Fields, compareValues(key, base[key], next[key])...)
+	}
+
+	result.Panels = comparePanels(baseData.Get("panels"), newData.Get("panels"))
+	result.Variables = compareVariables(baseData.GetPath("templating", "list"), newData.GetPath("templating", "list"))
+
+	return result
+}
+
+type keyedItem struct {
+	key   string
+	value map[string]any
+}
+
+func comparePanels(baseData, newData *simplejson.Json) []PanelChange {
+	basePanels := flattenPanels(baseData)
+	newPanels := flattenPanels(newData)
+	baseByKey := byKey(basePanels)
+	newByKey := byKey(newPanels)
+
+	changes := make([]PanelChange, 0)
+	for _, item := range newPanels {
+		old, ok := baseByKey[item.key]
+		if !ok {
+			changes = append(changes, newPanelChange(SemanticAdded, item.value))
+			continue
+		}
+
+		fields, queries := comparePanel(old, item.value)
+		if len(fields) == 0 && len(queries) == 0 {
+			continue
+		}
+
+		change := newPanelChange(SemanticModified, item.value)
+		change.Fields = fields
+		change.Queries = queries
+		changes = append(changes, change)
+	}
+
+	for _, item := range basePanels {
+		if _, ok := newByKey[item.key]; !ok {
+			changes = append(changes, newPanelChange(SemanticRemoved, item.value))
+		}
+	}
+
+	return changes
+}
+
+// comparePanel returns the changes of the fields and the queries of a panel,
+// ignoring its id and nested panels.
+func comparePanel(old, next map[string]any) ([]FieldChange, []QueryChange) {
+	fields := make([]FieldChange, 0)
+	for _, key := range sortedKeys(old, next) {
+		if key == "id" || key == "targets" || key == "panels" {
+			continue
+		}
+		fields = append(fields, compareValues(key, old[key], next[key])...)
+	}
+	return fields, compareQueries(old["targets"], next["targets"])
+}
+
+// flattenPanels returns the panels of the dashboard and the panels nested in
+// its collapsed rows. Panels without an id are keyed by their position.
+func flattenPanels(panels *simplejson.Json) []keyedItem {
+	items := make([]keyedItem, 0)
+	for _, panel := range panels.MustArray() {
+		p, ok := panel.(map[string]any)
+		if !ok {
+			continue
+		}
+		items = append(items, keyedItem{value: p})
+		for _, nested := range simplejson.NewFromAny(p).Get("panels").MustArray() {
+			if n, ok := nested.(map[string]any); ok {
+				items = append(items, keyedItem{value: n})
+			}
+		}
+	}
+
+	for i := range items {
+		if id := simplejson.NewFromAny(items[i].value).Get("id").MustInt64(); id != 0 {
+			items[i].key = fmt.Sprintf("id:%d", id)
+		} else {
+			items[i].key = fmt.Sprintf("index:%d", i)
+		}
+	}
+
+	return items
+}
+
+func newPanelChange(change SemanticChange, panel map[string]any) PanelChange {
+	p := simplejson.NewFromAny(panel)
+	return PanelChange{
+		Change: change,
+		ID:     p.Get("id").MustInt64(),
+		Title:  p.Get("title").MustString(),
+		Type:   p.Get("type").MustString(),
+	}
+}
+
+func compareQueries(baseTargets, newTargets any) []QueryChange {
+	baseQueries := keyedBy(baseTargets, "refId")
+	newQueries := keyedBy(newTargets, "refId")
+	baseByKey := byKey(baseQueries)
+	newByKey := byKey(newQueries)
+
+	changes := make([]QueryChange, 0)
+	for _, item := range newQueries {
+		refID := simplejson.NewFromAny(item.value).Get("refId").MustString()
+		old, ok := baseByKey[item.key]
+		if !ok {
+			changes = append(changes, QueryChange{Change: SemanticAdded, RefID: refID, New: item.value})
+			continue
+		}
+		if !reflect.DeepEqual(old, item.value) {
+			changes = append(changes, QueryChange{Change: SemanticModified, RefID: refID, Old: old, New: item.value})
+		}
+	}
+
+	for _, item := range baseQueries {
+		if _, ok := newByKey[item.key]; !ok {
+			refID := simplejson.NewFromAny(item.value).Get("refId").MustString()
+			changes = append(changes, QueryChange{Change: SemanticRemoved, RefID: refID, Old: item.value})
+		}
+	}
+
+	return changes
+}
+
+func compareVariables(baseData, newData *simplejson.Json) []VariableChange {
+	baseVariables := keyedBy(baseData.Interface(), "name")
+	newVariables := keyedBy(newData.Interface(), "name")
+	baseByKey := byKey(baseVariables)
+	newByKey := byKey(newVariables)
+
+	changes := make([]VariableChange, 0)
+	for _, item := range newVariables {
+		old, ok := baseByKey[item.key]
+		if !ok {
+			changes = append(changes, newVariableChange(SemanticAdded, item.value))
+			continue
+		}
+
+		fields := make([]FieldChange, 0)
+		for _, key := range sortedKeys(old, item.value) {
+			fields = append(fields, compareValues(key, old[key], item.value[key])...)
+		}
+		if len(fields) > 0 {
+			change := newVariableChange(SemanticModified, item.value)
+			change.Fields = fields
+			changes = append(changes, change)
+		}
+	}
+
+	for _, item := range baseVariables {
+		if _, ok := newByKey[item.key]; !ok {
+			changes = append(changes, newVariableChange(SemanticRemoved, item.value))
+		}
+	}
+
+	return changes
+}
+
+func newVariableChange(change SemanticChange, variable map[string]any) VariableChange {
+	v := simplejson.NewFromAny(variable)
+	return VariableChange{
+		Change: change,
+		Name:   v.Get("name").MustString(),
+		Type:   v.Get("type").MustString(),
+	}
+}
+
+// keyedBy returns the objects of the list keyed by the given field, or by their
+// position when the field is not set.
+func keyedBy(list any, field string) []keyedItem {
+	items := make([]keyedItem, 0)
+	values, _ := list.([]any)
+	for i, value := range values {
+		v, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+		key := fmt.Sprintf("index:%d", i)
+		if name := simplejson.NewFromAny(v).Get(field).MustString(); name != "" {
+			key = field + ":" + name
+		}
+		items = append(items, keyedItem{key: key, value: v})
+	}
+
+	return items
+}
+
+func byKey(items []keyedItem) map[string]map[string]any {
+	result := make(map[string]map[string]any, len(items))
+	for _, item := range items {
+		result[item.key] = item.value
+	}
+
+	return result
+}
+
+// compareValues returns the changes between two values. Objects are compared
+// field by field, other values including arrays are compared as a whole.
+func compareValues(path string, oldValue, newValue any) []FieldChange {
+	oldMap, oldIsMap := oldValue.(map[string]any)
+	newMap, newIsMap := newValue.(map[string]any)
+	if oldIsMap && newIsMap {
+		changes := make([]FieldChange, 0)
+		for _, key := range sortedKeys(oldMap, newMap) {
+			changes = append(changes, compareValues(path+"."+key, oldMap[key], newMap[key])...)
+		}
+		return changes
+	}
+
+	if reflect.DeepEqual(oldValue, newValue) {
+		return nil
+	}
+
+	return []FieldChange{{Path: path, Old: oldValue, New: newValue}}
+}
+
+func sortedKeys(maps ...map[string]any) []string {
+	seen := make(map[string]bool)
+	keys := make([]string, 0)
+	for _, m := range maps {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
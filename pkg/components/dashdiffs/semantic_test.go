@@ -0,0 +1,122 @@
+package dashdiffs
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestSemanticDiff(t *testing.T) {
+	const (
+		baseJSON = `{
+			"id": 1,
+			"version": 3,
+			"title": "API",
+			"time": {"from": "now-6h", "to": "now"},
+			"panels": [
+				{"id": 1, "type": "timeseries", "title": "Requests", "gridPos": {"x": 0, "y": 0, "w": 12, "h": 8},
+					"targets": [{"refId": "A", "expr": "rate(requests[5m])"}, {"refId": "B", "expr": "rate(errors[5m])"}]},
+				{"id": 2, "type": "stat", "title": "Uptime"},
+				{"id": 3, "type": "row", "title": "Details", "collapsed": true, "panels": [
+					{"id": 4, "type": "table", "title": "Instances", "targets": [{"refId": "A", "expr": "up"}]}
+				]}
+			],
+			"templating": {"list": [
+				{"name": "job", "type": "query", "query": "label_values(job)"},
+				{"name": "env", "type": "custom", "query": "dev,prod"}
+			]}
+		}`
+
+		newJSON = `{
+			"id": 1,
+			"version": 4,
+			"title": "API server",
+			"time": {"from": "now-24h", "to": "now"},
+			"panels": [
+				{"id": 1, "type": "timeseries", "title": "Requests", "gridPos": {"x": 0, "y": 8, "w": 12, "h": 8},
+					"targets": [{"refId": "A", "expr": "rate(requests[1m])"}, {"refId": "C", "expr": "rate(timeouts[5m])"}]},
+				{"id": 3, "type": "row", "title": "Details", "collapsed": true, "panels": [
+					{"id": 4, "type": "table", "title": "Instances", "targets": [{"refId": "A", "expr": "up"}]},
+					{"id": 5, "type": "logs", "title": "Logs"}
+				]}
+			],
+			"templating": {"list": [
+				{"name": "job", "type": "query", "query": "label_values(up, job)"},
+				{"name": "instance", "type": "query", "query": "label_values(instance)"}
+			]}
+		}`
+	)
+
+	baseData, err := simplejson.NewJson([]byte(baseJSON))
+	require.NoError(t, err)
+	newData, err := simplejson.NewJson([]byte(newJSON))
+	require.NoError(t, err)
+
+	result := CalculateSemanticDiff(baseData, newData)
+
+	t.Run("lists the changes of the dashboard fields", func(t *testing.T) {
+		assert.Equal(t, []FieldChange{
+			{Path: "time.from", Old: "now-6h", New: "now-24h"},
+			{Path: "title", Old: "API", New: "API server"},
+		}, result.Fields)
+	})
+
+	t.Run("lists the changes of the panels and their queries", func(t *testing.T) {
+		require.Len(t, result.Panels, 3)
+
+		modified := result.Panels[0]
+		assert.Equal(t, SemanticModified, modified.Change)
+		assert.Equal(t, int64(1), modified.ID)
+		assert.Equal(t, []FieldChange{{Path: "gridPos.y", Old: json.Number("0"), New: json.Number("8")}}, modified.Fields)
+		require.Len(t, modified.Queries, 3)
+		assert.Equal(t, SemanticModified, modified.Queries[0].Change)
+		assert.Equal(t, "A", modified.Queries[0].RefID)
+		assert.Equal(t, SemanticAdded, modified.Queries[1].Change)
+		assert.Equal(t, "C", modified.Queries[1].RefID)
+		assert.Equal(t, SemanticRemoved, modified.Queries[2].Change)
+		assert.Equal(t, "B", modified.Queries[2].RefID)
+
+		assert.Equal(t, PanelChange{Change: SemanticAdded, ID: 5, Title: "Logs", Type: "logs"}, result.Panels[1])
+		assert.Equal(t, PanelChange{Change: SemanticRemoved, ID: 2, Title: "Uptime", Type: "stat"}, result.Panels[2])
+	})
+
+	t.Run("lists the changes of the template variables", func(t *testing.T) {
+		assert.Equal(t, []VariableChange{
+			{Change: SemanticModified, Name: "job", Type: "query", Fields: []FieldChange{
+				{Path: "query", Old: "label_values(job)", New: "label_values(up, job)"},
+			}},
+			{Change: SemanticAdded, Name: "instance", Type: "query"},
+			{Change: SemanticRemoved, Name: "env", Type: "custom"},
+		}, result.Variables)
+	})
+
+	t.Run("identical dashboards have an empty diff", func(t *testing.T) {
+		assert.True(t, CalculateSemanticDiff(baseData, baseData).IsEmpty())
+	})
+
+	t.Run("CalculateDiff returns the semantic diff as JSON", func(t *testing.T) {
+		res, err := CalculateDiff(context.Background(), &Options{DiffType: ParseDiffType("semantic")}, baseData, baseData)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"fields": [], "panels": [], "variables": []}`, string(res.Delta))
+	})
+}
+
+func TestPanelDiff(t *testing.T) {
+	basePanel, err := simplejson.NewJson([]byte(`{"id": 1, "type": "timeseries", "title": "Requests",
+		"targets": [{"refId": "A", "expr": "rate(requests[5m])"}]}`))
+	require.NoError(t, err)
+	newPanel, err := simplejson.NewJson([]byte(`{"id": 2, "type": "timeseries", "title": "Requests per job",
+		"targets": [{"refId": "A", "expr": "sum by (job) (rate(requests[5m]))"}]}`))
+	require.NoError(t, err)
+
+	result := CalculatePanelDiff(basePanel, newPanel)
+	assert.Equal(t, []FieldChange{{Path: "title", Old: "Requests", New: "Requests per job"}}, result.Fields)
+	require.Len(t, result.Queries, 1)
+	assert.Equal(t, SemanticModified, result.Queries[0].Change)
+	assert.True(t, CalculatePanelDiff(basePanel, basePanel).IsEmpty())
+}
@@ -8,6 +8,7 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 
 	playlist "github.com/grafana/grafana/pkg/apis/playlist/v0alpha1"
@@ -17,20 +18,31 @@ import (
 )
 
 func LegacyUpdateCommandToUnstructured(cmd playlistsvc.UpdatePlaylistCommand) unstructured.Unstructured {
-	items := []map[string]string{}
+	items := []interface{}{}
 	for _, item := range cmd.Items {
-		items = append(items, map[string]string{
-			"type":  item.Type,
-			"value": item.Value,
-		})
+		items = append(items, itemToUnstructured(convertToK8sItem(playlistsvc.PlaylistItemDTO{
+			Type:       item.Type,
+			Value:      item.Value,
+			Dwell:      item.Dwell,
+			TimeFrom:   item.TimeFrom,
+			TimeTo:     item.TimeTo,
+			Variables:  item.Variables,
+			ActiveFrom: item.ActiveFrom,
+			ActiveTo:   item.ActiveTo,
+			ActiveDays: item.ActiveDays,
+		})))
+	}
+	spec := map[string]interface{}{
+		"title":    cmd.Name,
+		"interval": cmd.Interval,
+		"items":    items,
+	}
+	if cmd.Timezone != "" {
+		spec["timezone"] = cmd.Timezone
 	}
 	obj := unstructured.Unstructured{
 		Object: map[string]interface{}{
-			"spec": map[string]interface{}{
-				"title":    cmd.Name,
-				"interval": cmd.Interval,
-				"items":    items,
-			},
+			"spec": spec,
 		},
 	}
 	obj.SetName(cmd.UID)
@@ -39,20 +51,24 @@ func LegacyUpdateCommandToUnstructured(cmd playlistsvc.UpdatePlaylistCommand) un
 
 func UnstructuredToLegacyPlaylist(item unstructured.Unstructured) *playlistsvc.Playlist {
 	spec := item.Object["spec"].(map[string]any)
+	timezone, _ := spec["timezone"].(string)
 	return &playlistsvc.Playlist{
 		UID:      item.GetName(),
 		Name:     spec["title"].(string),
 		Interval: spec["interval"].(string),
+		Timezone: timezone,
 		Id:       getLegacyID(&item),
 	}
 }
 
 func UnstructuredToLegacyPlaylistDTO(item unstructured.Unstructured) *playlistsvc.PlaylistDTO {
 	spec := item.Object["spec"].(map[string]any)
+	timezone, _ := spec["timezone"].(string)
 	dto := &playlistsvc.PlaylistDTO{
 		Uid:      item.GetName(),
 		Name:     spec["title"].(string),
 		Interval: spec["interval"].(string),
+		Timezone: timezone,
 		Id:       getLegacyID(&item),
 	}
 	items := spec["items"]
@@ -69,12 +85,10 @@ func convertToK8sResource(v *playlistsvc.PlaylistDTO, namespacer request.Namespa
 	spec := playlist.Spec{
 		Title:    v.Name,
 		Interval: v.Interval,
+		Timezone: v.Timezone,
 	}
 	for _, item := range v.Items {
-		spec.Items = append(spec.Items, playlist.Item{
-			Type:  playlist.ItemType(item.Type),
-			Value: item.Value,
-		})
+		spec.Items = append(spec.Items, convertToK8sItem(item))
 	}
 
 	p := &playlist.Playlist{
@@ -108,6 +122,7 @@ func convertToLegacyUpdateCommand(p *playlist.Playlist, orgId int64) (*playlists
 		UID:      p.Name,
 		Name:     spec.Title,
 		Interval: spec.Interval,
+		Timezone: spec.Timezone,
 		OrgId:    orgId,
 	}
 	for _, item := range spec.Items {
@@ -115,13 +130,43 @@ func convertToLegacyUpdateCommand(p *playlist.Playlist, orgId int64) (*playlists
 			return nil, fmt.Errorf("unsupported item type: %s", item.Type)
 		}
 		cmd.Items = append(cmd.Items, playlistsvc.PlaylistItem{
-			Type:  string(item.Type),
-			Value: item.Value,
+			Type:       string(item.Type),
+			Value:      item.Value,
+			Dwell:      item.Dwell,
+			TimeFrom:   item.TimeFrom,
+			TimeTo:     item.TimeTo,
+			Variables:  item.Variables,
+			ActiveFrom: item.ActiveFrom,
+			ActiveTo:   item.ActiveTo,
+			ActiveDays: item.ActiveDays,
 		})
 	}
 	return cmd, nil
 }
 
+func convertToK8sItem(item playlistsvc.PlaylistItemDTO) playlist.Item {
+	return playlist.Item{
+		Type:       playlist.ItemType(item.Type),
+		Value:      item.Value,
+		Dwell:      item.Dwell,
+		TimeFrom:   item.TimeFrom,
+		TimeTo:     item.TimeTo,
+		Variables:  item.Variables,
+		ActiveFrom: item.ActiveFrom,
+		ActiveTo:   item.ActiveTo,
+		ActiveDays: item.ActiveDays,
+	}
+}
+
+// itemToUnstructured returns the item as decoded from JSON, so that the unstructured object can be deep copied.
+func itemToUnstructured(item playlist.Item) map[string]interface{} {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&item)
+	if err != nil {
+		return map[string]interface{}{"type": string(item.Type), "value": item.Value}
+	}
+	return obj
+}
+
 // Read legacy ID from metadata annotations
 func getLegacyID(item *unstructured.Unstructured) int64 {
 	meta, err := utils.MetaAccessor(item)
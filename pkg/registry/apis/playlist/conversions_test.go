@@ -67,3 +67,36 @@ func TestPlaylistConversion(t *testing.T) {
 		}
 	  }`, string(out))
 }
+
+func TestPlaylistScheduleConversion(t *testing.T) {
+	items := []playlist.PlaylistItemDTO{
+		{Type: "dashboard_by_uid", Value: "UID0", Dwell: "30s", TimeFrom: "now-24h", TimeTo: "now",
+			Variables: map[string]string{"env": "prod"}, ActiveFrom: "08:00", ActiveTo: "18:00", ActiveDays: []string{"mon", "fri"}},
+		{Type: "dashboard_by_tag", Value: "tagA"},
+	}
+	src := &playlist.PlaylistDTO{
+		OrgID:    3,
+		Uid:      "abc",
+		Name:     "Wall",
+		Interval: "5m",
+		Timezone: "Europe/Paris",
+		Items:    items,
+	}
+
+	dst := convertToK8sResource(src, request.GetNamespaceMapper(nil))
+	cmd, err := convertToLegacyUpdateCommand(dst, 3)
+	require.NoError(t, err)
+
+	require.Equal(t, "Europe/Paris", cmd.Timezone)
+	require.Equal(t, []playlist.PlaylistItem{
+		{Type: "dashboard_by_uid", Value: "UID0", Dwell: "30s", TimeFrom: "now-24h", TimeTo: "now",
+			Variables: map[string]string{"env": "prod"}, ActiveFrom: "08:00", ActiveTo: "18:00", ActiveDays: []string{"mon", "fri"}},
+		{Type: "dashboard_by_tag", Value: "tagA"},
+	}, cmd.Items)
+
+	// the legacy API in kubernetes mode goes through unstructured objects
+	obj := LegacyUpdateCommandToUnstructured(*cmd)
+	dto := UnstructuredToLegacyPlaylistDTO(*obj.DeepCopy())
+	require.Equal(t, "Europe/Paris", dto.Timezone)
+	require.Equal(t, items, dto.Items)
+}
@@ -116,6 +116,7 @@ func (s *legacyStorage) Create(ctx context.Context,
 		UID:      p.Name,
 		Name:     cmd.Name,
 		Interval: cmd.Interval,
+		Timezone: cmd.Timezone,
 		Items:    cmd.Items,
 		OrgId:    cmd.OrgId,
 	})
@@ -0,0 +1,431 @@
+package caching
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const incrementalKeyPrefix = "query-cache-incremental:"
+
+var errIncompatibleFrames = errors.New("cached frames do not match the queried frames")
+
+// incrementalEntry is the cached result of a single time series query and the time range it covers.
+type incrementalEntry struct {
+	From   time.Time   `json:"from"`
+	To     time.Time   `json:"to"`
+	Frames data.Frames `json:"frames"`
+}
+
+type incrementalKey struct {
+	OrgID             int64           `json:"orgId"`
+	DatasourceUID     string          `json:"datasourceUid"`
+	DatasourceUpdated int64           `json:"datasourceUpdated"`
+	RefID             string          `json:"refId"`
+	QueryType         string          `json:"queryType"`
+	MaxDataPoints     int64           `json:"maxDataPoints"`
+	Interval          time.Duration   `json:"interval"`
+	Model             json.RawMessage `json:"model"`
+}
+
+// incrementalQueryCacheKey builds the cache key of a single query, independent of its time range.
+func incrementalQueryCacheKey(pCtx backend.PluginContext, q backend.DataQuery) (string, error) {
+	model, err := normalizeQueryModel(q.JSON)
+	if err != nil {
+		return "", err
+	}
+	k := incrementalKey{
+		OrgID:         pCtx.OrgID,
+		RefID:         q.RefID,
+		QueryType:     q.QueryType,
+		MaxDataPoints: q.MaxDataPoints,
+		Interval:      q.Interval,
+		Model:         model,
+	}
+	if ds := pCtx.DataSourceInstanceSettings; ds != nil {
+		k.DatasourceUID = ds.UID
+		k.DatasourceUpdated = ds.Updated.UnixMilli()
+	}
+	return hashKey(incrementalKeyPrefix, k)
+}
+
+// incrementalEnabled returns true if the request can be cached incrementally: the datasource
+// opted in and every query is a time series query over a proper time range.
+func (s *OSSCachingService) incrementalEnabled(req *backend.QueryDataRequest, dsSettings dataSourceCachingSettings) bool {
+	if enabled := dsSettings.QueryCaching.Incremental; enabled != nil {
+		if !*enabled {
+			return false
+		}
+	} else if !s.settings.Incremental || !slices.Contains(s.settings.IncrementalDatasources, req.PluginContext.DataSourceInstanceSettings.Type) {
+		return false
+	}
+
+	if len(req.Queries) == 0 {
+		return false
+	}
+	for _, q := range req.Queries {
+		if q.TimeRange.From.IsZero() || !q.TimeRange.To.After(q.TimeRange.From) || !isTimeSeriesQuery(q) {
+			return false
+		}
+	}
+	return true
+}
+
+// isTimeSeriesQuery filters out the common table, instant and logs query models of the
+// eligible datasources, whose results cannot be stitched together by time.
+func isTimeSeriesQuery(q backend.DataQuery) bool {
+	model := struct {
+		Format    string `json:"format"`
+		QueryType string `json:"queryType"`
+		Instant   bool   `json:"instant"`
+		Range     *bool  `json:"range"`
+	}{}
+	if err := json.Unmarshal(q.JSON, &model); err != nil {
+		return false
+	}
+	switch {
+	case model.Format == "table" || model.Format == "logs":
+		return false
+	case model.QueryType == "instant" || q.QueryType == "instant":
+		return false
+	case model.Instant && (model.Range == nil || !*model.Range):
+		return false
+	}
+	return true
+}
+
+// handleIncrementalQueryRequest looks up the cached data of every query in the request. If all of
+// them are fully covered by the cache, the cached data is returned. Otherwise, the queries with
+// cached data are narrowed down to the part of their time range that is missing.
+func (s *OSSCachingService) handleIncrementalQueryRequest(ctx context.Context, req *backend.QueryDataRequest, ttl time.Duration, updateCacheFn CacheQueryResponseFn) (bool, CachedQueryDataResponse) {
+	logger := s.log.FromContext(ctx)
+
+	keys := make([]string, len(req.Queries))
+	entries := make([]*incrementalEntry, len(req.Queries))
+	for i, q := range req.Queries {
+		key, err := incrementalQueryCacheKey(req.PluginContext, q)
+		if err != nil {
+			logger.Warn("Failed to build incremental query cache key", "error", err)
+			return false, CachedQueryDataResponse{UpdateCacheFn: updateCacheFn}
+		}
+		keys[i] = key
+
+		b, err := s.get(ctx, key)
+		if err != nil || b == nil {
+			continue
+		}
+		entry := &incrementalEntry{}
+		if err := json.Unmarshal(b, entry); err != nil {
+			logger.Warn("Failed to decode cached incremental query response", "error", err)
+			continue
+		}
+		entries[i] = entry
+	}
+
+	// Store the per query entries alongside the full response.
+	update := func(ctx context.Context, resp *backend.QueryDataResponse) {
+		updateCacheFn(ctx, resp)
+		if !cacheableQueryResponse(resp) {
+			return
+		}
+		for i, q := range req.Queries {
+			dr, ok := resp.Responses[q.RefID]
+			if !ok || !incrementalFrames(dr.Frames) {
+				continue
+			}
+			b, err := json.Marshal(incrementalEntry{From: q.TimeRange.From, To: q.TimeRange.To, Frames: dr.Frames})
+			if err != nil {
+				s.log.FromContext(ctx).Warn("Failed to encode query response for incremental caching", "error", err)
+				continue
+			}
+			s.set(ctx, keys[i], b, ttl)
+		}
+	}
+
+	covered := true
+	for i, q := range req.Queries {
+		e := entries[i]
+		if e == nil || e.From.After(q.TimeRange.From) || e.To.Before(q.TimeRange.To) {
+			covered = false
+			break
+		}
+	}
+	if covered {
+		resp := backend.NewQueryDataResponse()
+		for i, q := range req.Queries {
+			// Nothing is fetched, so every cached sample up to and including the end of the range is kept.
+			frames, err := mergeFrames(entries[i].Frames, nil, q.TimeRange.To.Add(time.Nanosecond), q.TimeRange)
+			if err != nil {
+				logger.Warn("Failed to read cached incremental query response", "error", err)
+				return false, CachedQueryDataResponse{UpdateCacheFn: update}
+			}
+			resp.Responses[q.RefID] = backend.DataResponse{Frames: frames}
+		}
+		return true, CachedQueryDataResponse{Response: resp}
+	}
+
+	partial := false
+	fetchFrom := make([]time.Time, len(req.Queries))
+	queries := make([]backend.DataQuery, len(req.Queries))
+	for i, q := range req.Queries {
+		fetchFrom[i] = q.TimeRange.From
+		queries[i] = q
+
+		e := entries[i]
+		if e == nil || e.From.After(q.TimeRange.From) || !e.To.After(q.TimeRange.From) {
+			entries[i] = nil
+			continue
+		}
+
+		from := e.To
+		if q.TimeRange.To.Before(from) {
+			from = q.TimeRange.To
+		}
+		from = from.Add(-s.settings.IncrementalOverlap)
+		if !from.After(q.TimeRange.From) {
+			entries[i] = nil
+			continue
+		}
+
+		narrowed, err := narrowQuery(q, from)
+		if err != nil {
+			logger.Warn("Failed to narrow query for incremental caching", "error", err)
+			entries[i] = nil
+			continue
+		}
+		fetchFrom[i] = from
+		queries[i] = narrowed
+		partial = true
+	}
+
+	if !partial {
+		return false, CachedQueryDataResponse{UpdateCacheFn: update}
+	}
+
+	incrementalReq := *req
+	incrementalReq.Queries = queries
+
+	return false, CachedQueryDataResponse{
+		UpdateCacheFn:      update,
+		IncrementalRequest: &incrementalReq,
+		MergeFn: func(ctx context.Context, partialResp *backend.QueryDataResponse) (*backend.QueryDataResponse, error) {
+			if partialResp == nil {
+				return nil, errors.New("no response to merge")
+			}
+			resp := backend.NewQueryDataResponse()
+			for refID, dr := range partialResp.Responses {
+				resp.Responses[refID] = dr
+			}
+			for i, q := range req.Queries {
+				dr, ok := partialResp.Responses[q.RefID]
+				if !ok || entries[i] == nil || dr.Error != nil {
+					continue
+				}
+				frames, err := mergeFrames(entries[i].Frames, dr.Frames, fetchFrom[i], q.TimeRange)
+				if err != nil {
+					return nil, err
+				}
+				dr.Frames = frames
+				resp.Responses[q.RefID] = dr
+			}
+			return resp, nil
+		},
+	}
+}
+
+// narrowQuery returns a copy of the query starting at from. The max data points are scaled down
+// with the time range so that datasources calculate the same interval as for the full range.
+func narrowQuery(q backend.DataQuery, from time.Time) (backend.DataQuery, error) {
+	full := q.TimeRange.To.Sub(q.TimeRange.From)
+	q.TimeRange.From = from
+	if q.MaxDataPoints <= 0 {
+		return q, nil
+	}
+
+	ratio := float64(q.TimeRange.To.Sub(from)) / float64(full)
+	q.MaxDataPoints = int64(math.Max(1, math.Round(float64(q.MaxDataPoints)*ratio)))
+
+	model := map[string]any{}
+	dec := json.NewDecoder(bytes.NewReader(q.JSON))
+	dec.UseNumber()
+	if err := dec.Decode(&model); err != nil {
+		return q, err
+	}
+	if _, ok := model["maxDataPoints"]; ok {
+		model["maxDataPoints"] = q.MaxDataPoints
+		b, err := json.Marshal(model)
+		if err != nil {
+			return q, err
+		}
+		q.JSON = b
+	}
+	return q, nil
+}
+
+// incrementalFrames returns true if the frames can be stitched together by time.
+func incrementalFrames(frames data.Frames) bool {
+	for _, f := range frames {
+		if timeFieldIndex(f) < 0 {
+			return false
+		}
+		if f.Meta != nil && (f.Meta.Type == data.FrameTypeLogLines || f.Meta.PreferredVisualization == data.VisTypeLogs) {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeFrames combines cached frames with freshly queried frames covering the time range starting
+// at fetchFrom. Cached rows from fetchFrom on are replaced by the fresh rows, and the result is
+// trimmed to the requested time range.
+func mergeFrames(cached, fresh data.Frames, fetchFrom time.Time, tr backend.TimeRange) (data.Frames, error) {
+	remaining := map[string][]*data.Frame{}
+	for _, f := range cached {
+		id := frameIdentity(f)
+		remaining[id] = append(remaining[id], f)
+	}
+
+	used := map[*data.Frame]bool{}
+	out := make(data.Frames, 0, len(fresh))
+	for _, f := range fresh {
+		var old *data.Frame
+		if l := remaining[frameIdentity(f)]; len(l) > 0 {
+			old = l[0]
+			remaining[frameIdentity(f)] = l[1:]
+			used[old] = true
+		}
+		merged, err := mergeFrame(old, f, fetchFrom, tr)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, merged)
+	}
+
+	// Series without samples in the fetched range may still have samples earlier in the range.
+	for _, f := range cached {
+		if used[f] {
+			continue
+		}
+		merged, err := mergeFrame(f, nil, fetchFrom, tr)
+		if err != nil {
+			return nil, err
+		}
+		if merged.Rows() > 0 {
+			out = append(out, merged)
+		}
+	}
+
+	return out, nil
+}
+
+func mergeFrame(old, fresh *data.Frame, fetchFrom time.Time, tr backend.TimeRange) (*data.Frame, error) {
+	base := fresh
+	if base == nil {
+		base = old
+	}
+	timeIdx := timeFieldIndex(base)
+	if timeIdx < 0 {
+		return nil, errIncompatibleFrames
+	}
+	if old != nil && fresh != nil && !compatibleFrames(old, fresh) {
+		return nil, errIncompatibleFrames
+	}
+
+	out := base.EmptyCopy()
+	out.Meta = base.Meta
+	for i, f := range base.Fields {
+		out.Fields[i].Config = f.Config
+	}
+
+	inRange := func(t time.Time) bool {
+		return !t.Before(tr.From) && !t.After(tr.To)
+	}
+
+	// Datasources may align the start of the fetched range, e.g. to the query step.
+	cutoff := fetchFrom
+	if fresh != nil {
+		for i := 0; i < fresh.Rows(); i++ {
+			if t, ok := timeAt(fresh.Fields[timeIdx], i); ok && t.Before(cutoff) {
+				cutoff = t
+			}
+		}
+	}
+
+	if old != nil {
+		appendRows(out, old, timeIdx, func(t time.Time) bool { return inRange(t) && t.Before(cutoff) })
+	}
+	if fresh != nil {
+		appendRows(out, fresh, timeIdx, inRange)
+	}
+
+	return out, nil
+}
+
+func appendRows(dst, src *data.Frame, timeIdx int, keep func(time.Time) bool) {
+	for i := 0; i < src.Rows(); i++ {
+		t, ok := timeAt(src.Fields[timeIdx], i)
+		if !ok || !keep(t) {
+			continue
+		}
+		for j, f := range src.Fields {
+			dst.Fields[j].Append(f.At(i))
+		}
+	}
+}
+
+func compatibleFrames(a, b *data.Frame) bool {
+	if len(a.Fields) != len(b.Fields) {
+		return false
+	}
+	for i := range a.Fields {
+		if a.Fields[i].Type() != b.Fields[i].Type() {
+			return false
+		}
+	}
+
+	// A different interval means the datasource resolved a different step for the narrowed query.
+	ai, bi := a.Fields[timeFieldIndex(a)].Config, b.Fields[timeFieldIndex(b)].Config
+	if ai != nil && bi != nil && ai.Interval != 0 && bi.Interval != 0 && ai.Interval != bi.Interval {
+		return false
+	}
+	return true
+}
+
+func frameIdentity(f *data.Frame) string {
+	var sb strings.Builder
+	sb.WriteString(f.Name)
+	for _, field := range f.Fields {
+		sb.WriteString("|")
+		sb.WriteString(field.Name)
+		sb.WriteString(field.Labels.String())
+	}
+	return sb.String()
+}
+
+func timeFieldIndex(f *data.Frame) int {
+	if idx := f.TypeIndices(data.FieldTypeTime, data.FieldTypeNullableTime); len(idx) > 0 {
+		return idx[0]
+	}
+	return -1
+}
+
+func timeAt(f *data.Field, i int) (time.Time, bool) {
+	switch v := f.At(i).(type) {
+	case time.Time:
+		return v, true
+	case *time.Time:
+		if v == nil {
+			return time.Time{}, false
+		}
+		return *v, true
+	}
+	return time.Time{}, false
+}
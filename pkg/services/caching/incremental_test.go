@@ -0,0 +1,196 @@
+package caching
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+)
+
+func TestOSSCachingService_IncrementalQueries(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	newRequest := func(model string, from, to time.Time) *backend.QueryDataRequest {
+		return &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{
+				OrgID: 1,
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+					UID:      "ds-uid",
+					Type:     "prometheus",
+					JSONData: []byte(`{}`),
+				},
+			},
+			Queries: []backend.DataQuery{{
+				RefID:         "A",
+				JSON:          []byte(model),
+				MaxDataPoints: 60,
+				Interval:      time.Minute,
+				TimeRange:     backend.TimeRange{From: from, To: to},
+			}},
+		}
+	}
+	series := func(from time.Time, n int) *data.Frame {
+		times := make([]time.Time, n)
+		values := make([]float64, n)
+		for i := range times {
+			times[i] = from.Add(time.Duration(i) * time.Minute)
+			values[i] = float64(times[i].Unix())
+		}
+		return data.NewFrame("",
+			data.NewField("Time", nil, times),
+			data.NewField("Value", data.Labels{"job": "grafana"}, values),
+		)
+	}
+	newService := func() *OSSCachingService {
+		s := newTestService(remotecache.NewFakeCacheStorage())
+		s.settings.Incremental = true
+		s.settings.IncrementalDatasources = []string{"prometheus"}
+		s.settings.IncrementalOverlap = 5 * time.Minute
+		s.settings.TimeBucket = 0
+		return s
+	}
+	model := `{"refId":"A","expr":"up","maxDataPoints":60}`
+
+	t.Run("only queries the missing part of the time range", func(t *testing.T) {
+		s := newService()
+
+		ctx, rec := newTestContext()
+		hit, cr := s.HandleQueryRequest(ctx, newRequest(model, now.Add(-time.Hour), now))
+		require.False(t, hit)
+		require.Nil(t, cr.IncrementalRequest)
+		assert.Equal(t, StatusMiss, rec.Header().Get(XCacheHeader))
+		cr.UpdateCacheFn(ctx, &backend.QueryDataResponse{Responses: backend.Responses{
+			"A": {Frames: data.Frames{series(now.Add(-time.Hour), 61)}},
+		}})
+
+		// Ten minutes later, the range moved.
+		later := now.Add(10 * time.Minute)
+		ctx, rec = newTestContext()
+		hit, cr = s.HandleQueryRequest(ctx, newRequest(model, later.Add(-time.Hour), later))
+		require.False(t, hit)
+		assert.Equal(t, StatusPartial, rec.Header().Get(XCacheHeader))
+		require.NotNil(t, cr.IncrementalRequest)
+		require.NotNil(t, cr.MergeFn)
+
+		q := cr.IncrementalRequest.Queries[0]
+		assert.Equal(t, now.Add(-5*time.Minute), q.TimeRange.From)
+		assert.Equal(t, later, q.TimeRange.To)
+		assert.Equal(t, int64(15), q.MaxDataPoints)
+		assert.JSONEq(t, `{"refId":"A","expr":"up","maxDataPoints":15}`, string(q.JSON))
+
+		resp, err := cr.MergeFn(ctx, &backend.QueryDataResponse{Responses: backend.Responses{
+			"A": {Frames: data.Frames{series(now.Add(-5*time.Minute), 16)}},
+		}})
+		require.NoError(t, err)
+		frames := resp.Responses["A"].Frames
+		require.Len(t, frames, 1)
+		require.Equal(t, 61, frames[0].Rows())
+		first, _ := timeAt(frames[0].Fields[0], 0)
+		last, _ := timeAt(frames[0].Fields[0], 60)
+		assert.Equal(t, later.Add(-time.Hour), first)
+		assert.Equal(t, later, last)
+		for i := 1; i < frames[0].Rows(); i++ {
+			prev, _ := timeAt(frames[0].Fields[0], i-1)
+			cur, _ := timeAt(frames[0].Fields[0], i)
+			assert.Equal(t, time.Minute, cur.Sub(prev))
+		}
+	})
+
+	t.Run("returns cached data for a covered time range", func(t *testing.T) {
+		s := newService()
+
+		ctx, _ := newTestContext()
+		_, cr := s.HandleQueryRequest(ctx, newRequest(model, now.Add(-time.Hour), now))
+		cr.UpdateCacheFn(ctx, &backend.QueryDataResponse{Responses: backend.Responses{
+			"A": {Frames: data.Frames{series(now.Add(-time.Hour), 61)}},
+		}})
+
+		ctx, rec := newTestContext()
+		hit, cr := s.HandleQueryRequest(ctx, newRequest(model, now.Add(-30*time.Minute), now.Add(-10*time.Minute)))
+		require.True(t, hit)
+		assert.Equal(t, StatusHit, rec.Header().Get(XCacheHeader))
+		assert.Equal(t, 21, cr.Response.Responses["A"].Frames[0].Rows())
+	})
+
+	t.Run("ignores table queries", func(t *testing.T) {
+		s := newService()
+		tableModel := `{"refId":"A","expr":"up","format":"table"}`
+
+		ctx, _ := newTestContext()
+		_, cr := s.HandleQueryRequest(ctx, newRequest(tableModel, now.Add(-time.Hour), now))
+		cr.UpdateCacheFn(ctx, &backend.QueryDataResponse{Responses: backend.Responses{
+			"A": {Frames: data.Frames{series(now.Add(-time.Hour), 61)}},
+		}})
+
+		ctx, rec := newTestContext()
+		_, cr = s.HandleQueryRequest(ctx, newRequest(tableModel, now.Add(-50*time.Minute), now.Add(10*time.Minute)))
+		assert.Nil(t, cr.IncrementalRequest)
+		assert.Equal(t, StatusMiss, rec.Header().Get(XCacheHeader))
+	})
+
+	t.Run("can be enabled per datasource", func(t *testing.T) {
+		s := newService()
+		s.settings.Incremental = false
+
+		req := newRequest(model, now.Add(-time.Hour), now)
+		assert.False(t, s.incrementalEnabled(req, dataSourceCachingSettings{}))
+
+		settings := dataSourceCachingSettings{}
+		require.NoError(t, json.Unmarshal([]byte(`{"queryCaching":{"incremental":true}}`), &settings))
+		assert.True(t, s.incrementalEnabled(req, settings))
+	})
+}
+
+func TestMergeFrames(t *testing.T) {
+	at := func(m int) time.Time {
+		return time.Date(2024, 1, 1, 12, m, 0, 0, time.UTC)
+	}
+	tr := backend.TimeRange{From: at(2), To: at(8)}
+
+	t.Run("keeps series without fresh samples", func(t *testing.T) {
+		cached := data.Frames{
+			data.NewFrame("", data.NewField("Time", nil, []time.Time{at(1), at(2), at(3)}), data.NewField("Value", data.Labels{"a": "1"}, []float64{1, 2, 3})),
+			data.NewFrame("", data.NewField("Time", nil, []time.Time{at(4), at(5)}), data.NewField("Value", data.Labels{"a": "2"}, []float64{4, 5})),
+		}
+		fresh := data.Frames{
+			data.NewFrame("", data.NewField("Time", nil, []time.Time{at(5), at(6)}), data.NewField("Value", data.Labels{"a": "2"}, []float64{50, 60})),
+		}
+
+		merged, err := mergeFrames(cached, fresh, at(5), tr)
+		require.NoError(t, err)
+		require.Len(t, merged, 2)
+		assert.Equal(t, []float64{4, 50, 60}, fieldValues(merged[0].Fields[1]))
+		assert.Equal(t, data.Labels{"a": "2"}, merged[0].Fields[1].Labels)
+		assert.Equal(t, []float64{2, 3}, fieldValues(merged[1].Fields[1]))
+	})
+
+	t.Run("fails on incompatible frames", func(t *testing.T) {
+		cached := data.Frames{data.NewFrame("", data.NewField("Time", nil, []time.Time{at(3)}), data.NewField("Value", nil, []float64{3}))}
+		fresh := data.Frames{data.NewFrame("", data.NewField("Time", nil, []time.Time{at(6)}), data.NewField("Value", nil, []string{"6"}))}
+
+		_, err := mergeFrames(cached, fresh, at(5), tr)
+		assert.ErrorIs(t, err, errIncompatibleFrames)
+	})
+
+	t.Run("fails when the interval changed", func(t *testing.T) {
+		cached := data.NewFrame("", data.NewField("Time", nil, []time.Time{at(3)}).SetConfig(&data.FieldConfig{Interval: 60000}), data.NewField("Value", nil, []float64{3}))
+		fresh := data.NewFrame("", data.NewField("Time", nil, []time.Time{at(6)}).SetConfig(&data.FieldConfig{Interval: 15000}), data.NewField("Value", nil, []float64{6}))
+
+		_, err := mergeFrames(data.Frames{cached}, data.Frames{fresh}, at(5), tr)
+		assert.ErrorIs(t, err, errIncompatibleFrames)
+	})
+}
+
+func fieldValues(f *data.Field) []float64 {
+	values := make([]float64, f.Len())
+	for i := range values {
+		values[i] = f.At(i).(float64)
+	}
+	return values
+}
@@ -0,0 +1,139 @@
+package caching
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+const (
+	queryKeyPrefix    = "query-cache:"
+	resourceKeyPrefix = "resource-cache:"
+)
+
+// volatileQueryFields are query model properties that change between otherwise identical
+// requests and must not be part of the cache key.
+var volatileQueryFields = []string{"requestId", "key", "datasourceId"}
+
+type queryKeyEntry struct {
+	RefID         string          `json:"refId"`
+	QueryType     string          `json:"queryType"`
+	MaxDataPoints int64           `json:"maxDataPoints"`
+	Interval      time.Duration   `json:"interval"`
+	From          int64           `json:"from"`
+	To            int64           `json:"to"`
+	Model         json.RawMessage `json:"model"`
+}
+
+type queryKey struct {
+	OrgID             int64           `json:"orgId"`
+	DatasourceUID     string          `json:"datasourceUid"`
+	DatasourceUpdated int64           `json:"datasourceUpdated"`
+	Queries           []queryKeyEntry `json:"queries"`
+}
+
+type resourceKey struct {
+	OrgID             int64  `json:"orgId"`
+	PluginID          string `json:"pluginId"`
+	DatasourceUID     string `json:"datasourceUid"`
+	DatasourceUpdated int64  `json:"datasourceUpdated"`
+	Path              string `json:"path"`
+	URL               string `json:"url"`
+	Body              []byte `json:"body"`
+}
+
+// queryCacheKey builds the cache key for a query request. The key is derived from the
+// datasource, the normalized query models and the query time ranges aligned to bucket.
+func queryCacheKey(req *backend.QueryDataRequest, bucket time.Duration) (string, error) {
+	k := queryKey{
+		OrgID:   req.PluginContext.OrgID,
+		Queries: make([]queryKeyEntry, 0, len(req.Queries)),
+	}
+	if ds := req.PluginContext.DataSourceInstanceSettings; ds != nil {
+		k.DatasourceUID = ds.UID
+		k.DatasourceUpdated = ds.Updated.UnixMilli()
+	}
+
+	for _, q := range req.Queries {
+		model, err := normalizeQueryModel(q.JSON)
+		if err != nil {
+			return "", err
+		}
+		tr := alignTimeRange(q.TimeRange, bucket)
+		k.Queries = append(k.Queries, queryKeyEntry{
+			RefID:         q.RefID,
+			QueryType:     q.QueryType,
+			MaxDataPoints: q.MaxDataPoints,
+			Interval:      q.Interval,
+			From:          tr.From.UnixMilli(),
+			To:            tr.To.UnixMilli(),
+			Model:         model,
+		})
+	}
+
+	return hashKey(queryKeyPrefix, k)
+}
+
+// resourceCacheKey builds the cache key for a resource request.
+func resourceCacheKey(req *backend.CallResourceRequest) (string, error) {
+	k := resourceKey{
+		OrgID:    req.PluginContext.OrgID,
+		PluginID: req.PluginContext.PluginID,
+		Path:     req.Path,
+		URL:      req.URL,
+		Body:     req.Body,
+	}
+	if ds := req.PluginContext.DataSourceInstanceSettings; ds != nil {
+		k.DatasourceUID = ds.UID
+		k.DatasourceUpdated = ds.Updated.UnixMilli()
+	}
+
+	return hashKey(resourceKeyPrefix, k)
+}
+
+func hashKey(prefix string, v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return prefix + hex.EncodeToString(sum[:]), nil
+}
+
+// normalizeQueryModel re-encodes a query model with sorted keys and without volatile fields,
+// so that semantically identical queries produce the same bytes.
+func normalizeQueryModel(raw json.RawMessage) (json.RawMessage, error) {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return json.RawMessage("null"), nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var model any
+	if err := dec.Decode(&model); err != nil {
+		return nil, err
+	}
+
+	if m, ok := model.(map[string]any); ok {
+		for _, f := range volatileQueryFields {
+			delete(m, f)
+		}
+	}
+
+	return json.Marshal(model)
+}
+
+// alignTimeRange truncates both ends of the time range to the given bucket size.
+func alignTimeRange(tr backend.TimeRange, bucket time.Duration) backend.TimeRange {
+	if bucket <= 0 {
+		return tr
+	}
+	return backend.TimeRange{
+		From: tr.From.Truncate(bucket),
+		To:   tr.To.Truncate(bucket),
+	}
+}
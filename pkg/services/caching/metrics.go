@@ -0,0 +1,33 @@
+package caching
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/grafana/pkg/infra/metrics"
+)
+
+type cachingMetrics struct {
+	queryRequests    *prometheus.CounterVec
+	resourceRequests *prometheus.CounterVec
+}
+
+func newCachingMetrics(registerer prometheus.Registerer) *cachingMetrics {
+	m := &cachingMetrics{
+		queryRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metrics.ExporterName,
+			Subsystem: "caching",
+			Name:      "query_requests_total",
+			Help:      "Number of query requests handled by the query cache, partitioned by cache status",
+		}, []string{"datasource_type", "cache"}),
+		resourceRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metrics.ExporterName,
+			Subsystem: "caching",
+			Name:      "resource_requests_total",
+			Help:      "Number of resource requests handled by the query cache, partitioned by cache status",
+		}, []string{"plugin_id", "cache"}),
+	}
+	if registerer != nil {
+		registerer.MustRegister(m.queryRequests, m.resourceRequests)
+	}
+	return m
+}
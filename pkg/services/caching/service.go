@@ -2,8 +2,20 @@ package caching
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/contexthandler"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 const (
@@ -13,6 +25,7 @@ const (
 	StatusBypass   = "BYPASS"
 	StatusError    = "ERROR"
 	StatusDisabled = "DISABLED"
+	StatusPartial  = "PARTIAL"
 )
 
 type CacheQueryResponseFn func(context.Context, *backend.QueryDataResponse)
@@ -24,6 +37,12 @@ type CachedQueryDataResponse struct {
 	// A function that should be used to cache a QueryDataResponse for a given query.
 	// It can be set to nil by the method implementation (if there is an error, for example), so it should be checked before being called.
 	UpdateCacheFn CacheQueryResponseFn
+	// IncrementalRequest, when set, is a copy of the original request narrowed down to the time ranges missing from the cache.
+	// It should be executed in place of the original request, and its response combined with the cached data using MergeFn.
+	IncrementalRequest *backend.QueryDataRequest
+	// MergeFn combines the response to IncrementalRequest with the cached data into a response for the original request.
+	// If it returns an error, the original request should be executed instead.
+	MergeFn func(context.Context, *backend.QueryDataResponse) (*backend.QueryDataResponse, error)
 }
 
 type CachedResourceDataResponse struct {
@@ -33,10 +52,19 @@ type CachedResourceDataResponse struct {
 	// It can be set to nil by the method implementation (if there is an error, for example), so it should be checked before being called.
 	// Because plugins can send multiple responses asynchronously, the implementation should be able to handle multiple calls to this function for one request.
 	UpdateCacheFn CacheResourceResponseFn
+	// CommitCacheFn, when set, should be called once the request completed successfully and all of its responses were passed
+	// to UpdateCacheFn. Implementations setting it only write to the cache once it is called.
+	CommitCacheFn func(context.Context)
 }
 
-func ProvideCachingService() *OSSCachingService {
-	return &OSSCachingService{}
+func ProvideCachingService(cfg *setting.Cfg, cache remotecache.CacheStorage, registerer prometheus.Registerer) *OSSCachingService {
+	return &OSSCachingService{
+		settings:       cfg.QueryCaching,
+		sendUserHeader: cfg.SendUserHeader,
+		cache:          cache,
+		log:            log.New("query-caching"),
+		metrics:        newCachingMetrics(registerer),
+	}
 }
 
 type CachingService interface {
@@ -49,16 +77,271 @@ type CachingService interface {
 	HandleResourceRequest(context.Context, *backend.CallResourceRequest) (bool, CachedResourceDataResponse)
 }
 
-// Implementation of interface - does nothing
+// OSSCachingService caches query and resource responses in the configured remote cache.
+// The zero value is a valid caching service that never caches anything.
 type OSSCachingService struct {
+	settings       setting.QueryCachingSettings
+	sendUserHeader bool
+	cache          remotecache.CacheStorage
+	log            log.Logger
+	metrics        *cachingMetrics
+}
+
+// dataSourceCachingSettings are the per-datasource caching overrides, stored in the
+// "queryCaching" object of the datasource JSON data.
+type dataSourceCachingSettings struct {
+	QueryCaching struct {
+		Enabled        *bool
+		TTLQueriesMs   int64
+		TTLResourcesMs int64
+		Incremental    *bool
+	}
 }
 
 func (s *OSSCachingService) HandleQueryRequest(ctx context.Context, req *backend.QueryDataRequest) (bool, CachedQueryDataResponse) {
-	return false, CachedQueryDataResponse{}
+	if !s.enabled() || req == nil {
+		return false, CachedQueryDataResponse{}
+	}
+
+	dsType := ""
+	if ds := req.PluginContext.DataSourceInstanceSettings; ds != nil {
+		dsType = ds.Type
+	}
+	setStatus := func(status string) {
+		setCacheHeader(ctx, status)
+		s.metrics.queryRequests.WithLabelValues(dsType, status).Inc()
+	}
+
+	dsSettings, status := s.dataSourceSettings(req.PluginContext)
+	if status != "" {
+		setStatus(status)
+		return false, CachedQueryDataResponse{}
+	}
+
+	key, err := queryCacheKey(req, s.settings.TimeBucket)
+	if err != nil {
+		s.log.FromContext(ctx).Warn("Failed to build query cache key", "error", err)
+		setStatus(StatusError)
+		return false, CachedQueryDataResponse{}
+	}
+
+	cached, err := s.get(ctx, key)
+	if err != nil {
+		setStatus(StatusError)
+		return false, CachedQueryDataResponse{}
+	}
+	if cached != nil {
+		resp := &backend.QueryDataResponse{}
+		if err := json.Unmarshal(cached, resp); err == nil {
+			setStatus(StatusHit)
+			return true, CachedQueryDataResponse{Response: resp}
+		}
+		s.log.FromContext(ctx).Warn("Failed to decode cached query response", "error", err)
+	}
+
+	ttl := s.settings.TTL
+	if dsSettings.QueryCaching.TTLQueriesMs > 0 {
+		ttl = time.Duration(dsSettings.QueryCaching.TTLQueriesMs) * time.Millisecond
+	}
+
+	updateCacheFn := func(ctx context.Context, resp *backend.QueryDataResponse) {
+		if !cacheableQueryResponse(resp) {
+			return
+		}
+		b, err := json.Marshal(resp)
+		if err != nil {
+			s.log.FromContext(ctx).Warn("Failed to encode query response for caching", "error", err)
+			return
+		}
+		s.set(ctx, key, b, ttl)
+	}
+
+	if s.incrementalEnabled(req, dsSettings) {
+		hit, cr := s.handleIncrementalQueryRequest(ctx, req, ttl, updateCacheFn)
+		switch {
+		case hit:
+			setStatus(StatusHit)
+		case cr.IncrementalRequest != nil:
+			setStatus(StatusPartial)
+		default:
+			setStatus(StatusMiss)
+		}
+		return hit, cr
+	}
+
+	setStatus(StatusMiss)
+	return false, CachedQueryDataResponse{UpdateCacheFn: updateCacheFn}
 }
 
 func (s *OSSCachingService) HandleResourceRequest(ctx context.Context, req *backend.CallResourceRequest) (bool, CachedResourceDataResponse) {
-	return false, CachedResourceDataResponse{}
+	if !s.enabled() || req == nil {
+		return false, CachedResourceDataResponse{}
+	}
+
+	setStatus := func(status string) {
+		setCacheHeader(ctx, status)
+		s.metrics.resourceRequests.WithLabelValues(req.PluginContext.PluginID, status).Inc()
+	}
+
+	// Only idempotent requests are cached.
+	if req.Method != "" && req.Method != "GET" {
+		setStatus(StatusBypass)
+		return false, CachedResourceDataResponse{}
+	}
+
+	dsSettings, status := s.dataSourceSettings(req.PluginContext)
+	if status != "" {
+		setStatus(status)
+		return false, CachedResourceDataResponse{}
+	}
+
+	key, err := resourceCacheKey(req)
+	if err != nil {
+		s.log.FromContext(ctx).Warn("Failed to build resource cache key", "error", err)
+		setStatus(StatusError)
+		return false, CachedResourceDataResponse{}
+	}
+
+	cached, err := s.get(ctx, key)
+	if err != nil {
+		setStatus(StatusError)
+		return false, CachedResourceDataResponse{}
+	}
+	if cached != nil {
+		resp := &backend.CallResourceResponse{}
+		if err := json.Unmarshal(cached, resp); err == nil {
+			setStatus(StatusHit)
+			return true, CachedResourceDataResponse{Response: resp}
+		}
+		s.log.FromContext(ctx).Warn("Failed to decode cached resource response", "error", err)
+	}
+
+	setStatus(StatusMiss)
+	ttl := s.settings.ResourceTTL
+	if dsSettings.QueryCaching.TTLResourcesMs > 0 {
+		ttl = time.Duration(dsSettings.QueryCaching.TTLResourcesMs) * time.Millisecond
+	}
+
+	// Streamed resource responses consist of several messages that cannot be replayed
+	// from a single cache entry, so the responses are buffered until the request completes
+	// and only stored if there was a single one.
+	var (
+		mu        sync.Mutex
+		responses []*backend.CallResourceResponse
+	)
+	return false, CachedResourceDataResponse{
+		UpdateCacheFn: func(ctx context.Context, resp *backend.CallResourceResponse) {
+			mu.Lock()
+			defer mu.Unlock()
+			responses = append(responses, resp)
+		},
+		CommitCacheFn: func(ctx context.Context) {
+			mu.Lock()
+			defer mu.Unlock()
+			if len(responses) != 1 {
+				return
+			}
+			resp := responses[0]
+			if resp == nil || resp.Status < 200 || resp.Status >= 300 {
+				return
+			}
+			b, err := json.Marshal(resp)
+			if err != nil {
+				s.log.FromContext(ctx).Warn("Failed to encode resource response for caching", "error", err)
+				return
+			}
+			s.set(ctx, key, b, ttl)
+		},
+	}
+}
+
+func (s *OSSCachingService) enabled() bool {
+	return s.settings.Enabled && s.cache != nil
+}
+
+// dataSourceSettings reads the caching overrides of the datasource the request targets.
+// A non-empty status is returned if the request must not be cached.
+func (s *OSSCachingService) dataSourceSettings(pCtx backend.PluginContext) (dataSourceCachingSettings, string) {
+	settings := dataSourceCachingSettings{}
+	ds := pCtx.DataSourceInstanceSettings
+	if ds == nil {
+		return settings, StatusBypass
+	}
+
+	jsonData := simplejson.New()
+	if len(ds.JSONData) > 0 {
+		var err error
+		if jsonData, err = simplejson.NewJson(ds.JSONData); err != nil {
+			s.log.Warn("Failed to read datasource caching settings", "datasource", ds.UID, "error", err)
+			return settings, StatusError
+		}
+	}
+
+	queryCaching := jsonData.Get("queryCaching")
+	if enabled, err := queryCaching.Get("enabled").Bool(); err == nil {
+		settings.QueryCaching.Enabled = &enabled
+	}
+	if incremental, err := queryCaching.Get("incremental").Bool(); err == nil {
+		settings.QueryCaching.Incremental = &incremental
+	}
+	settings.QueryCaching.TTLQueriesMs = queryCaching.Get("ttlQueriesMs").MustInt64(0)
+	settings.QueryCaching.TTLResourcesMs = queryCaching.Get("ttlResourcesMs").MustInt64(0)
+
+	if settings.QueryCaching.Enabled != nil && !*settings.QueryCaching.Enabled {
+		return settings, StatusDisabled
+	}
+
+	// Responses of datasources that forward the user's identity depend on who is asking.
+	if datasources.ResponsesDependOnUser(s.sendUserHeader, jsonData) {
+		return settings, StatusBypass
+	}
+
+	return settings, ""
+}
+
+// get returns the cached value for key, or nil if there is none.
+func (s *OSSCachingService) get(ctx context.Context, key string) ([]byte, error) {
+	b, err := s.cache.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, remotecache.ErrCacheItemNotFound) {
+			return nil, nil
+		}
+		s.log.FromContext(ctx).Warn("Failed to read from query cache", "error", err)
+		return nil, err
+	}
+	return b, nil
+}
+
+func (s *OSSCachingService) set(ctx context.Context, key string, value []byte, ttl time.Duration) {
+	if s.settings.MaxValueSize > 0 && len(value) > s.settings.MaxValueSize {
+		s.log.FromContext(ctx).Debug("Response too large to be cached", "size", len(value), "limit", s.settings.MaxValueSize)
+		return
+	}
+	if err := s.cache.Set(ctx, key, value, ttl); err != nil {
+		s.log.FromContext(ctx).Warn("Failed to write to query cache", "error", err)
+	}
+}
+
+// cacheableQueryResponse returns false for responses containing errors, which should be
+// retried on the next request instead of being served from the cache.
+func cacheableQueryResponse(resp *backend.QueryDataResponse) bool {
+	if resp == nil {
+		return false
+	}
+	for _, r := range resp.Responses {
+		if r.Error != nil || r.Status >= 400 {
+			return false
+		}
+	}
+	return true
+}
+
+func setCacheHeader(ctx context.Context, status string) {
+	reqCtx := contexthandler.FromContext(ctx)
+	if reqCtx == nil || reqCtx.Resp == nil {
+		return
+	}
+	reqCtx.Resp.Header().Set(XCacheHeader, status)
 }
 
 var _ CachingService = &OSSCachingService{}
@@ -0,0 +1,289 @@
+package caching
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/contexthandler/ctxkey"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestOSSCachingService_HandleQueryRequest(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	newRequest := func(jsonData string, from time.Time) *backend.QueryDataRequest {
+		return &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{
+				OrgID: 1,
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+					UID:      "ds-uid",
+					Type:     "prometheus",
+					JSONData: []byte(jsonData),
+				},
+			},
+			Queries: []backend.DataQuery{{
+				RefID:     "A",
+				JSON:      []byte(`{"refId":"A","expr":"up","requestId":"Q100"}`),
+				TimeRange: backend.TimeRange{From: from, To: from.Add(time.Hour)},
+			}},
+		}
+	}
+	response := &backend.QueryDataResponse{Responses: backend.Responses{
+		"A": {Frames: data.Frames{data.NewFrame("up", data.NewField("value", nil, []float64{1}))}},
+	}}
+
+	t.Run("is a no-op when disabled", func(t *testing.T) {
+		s := &OSSCachingService{}
+		ctx, rec := newTestContext()
+
+		hit, cr := s.HandleQueryRequest(ctx, newRequest(`{}`, now))
+		assert.False(t, hit)
+		assert.Nil(t, cr.UpdateCacheFn)
+		assert.Empty(t, rec.Header().Get(XCacheHeader))
+	})
+
+	t.Run("misses, then hits once the response is stored", func(t *testing.T) {
+		s := newTestService(remotecache.NewFakeCacheStorage())
+
+		ctx, rec := newTestContext()
+		hit, cr := s.HandleQueryRequest(ctx, newRequest(`{}`, now))
+		require.False(t, hit)
+		require.NotNil(t, cr.UpdateCacheFn)
+		assert.Equal(t, StatusMiss, rec.Header().Get(XCacheHeader))
+		cr.UpdateCacheFn(ctx, response)
+
+		// A request a few seconds later with a different request ID falls in the same time bucket.
+		ctx, rec = newTestContext()
+		hit, cr = s.HandleQueryRequest(ctx, newRequest(`{}`, now.Add(3*time.Second)))
+		require.True(t, hit)
+		assert.Equal(t, StatusHit, rec.Header().Get(XCacheHeader))
+		require.Contains(t, cr.Response.Responses, "A")
+		assert.Equal(t, "up", cr.Response.Responses["A"].Frames[0].Name)
+
+		ctx, _ = newTestContext()
+		hit, _ = s.HandleQueryRequest(ctx, newRequest(`{}`, now.Add(time.Minute)))
+		assert.False(t, hit)
+	})
+
+	t.Run("does not store responses with errors", func(t *testing.T) {
+		s := newTestService(remotecache.NewFakeCacheStorage())
+
+		ctx, _ := newTestContext()
+		_, cr := s.HandleQueryRequest(ctx, newRequest(`{}`, now))
+		cr.UpdateCacheFn(ctx, &backend.QueryDataResponse{Responses: backend.Responses{
+			"A": backend.ErrDataResponse(backend.StatusBadRequest, "bad query"),
+		}})
+
+		hit, _ := s.HandleQueryRequest(ctx, newRequest(`{}`, now))
+		assert.False(t, hit)
+	})
+
+	t.Run("uses the datasource TTL", func(t *testing.T) {
+		cache := &recordingCacheStorage{CacheStorage: remotecache.NewFakeCacheStorage()}
+		s := newTestService(cache)
+
+		ctx, _ := newTestContext()
+		_, cr := s.HandleQueryRequest(ctx, newRequest(`{"queryCaching":{"ttlQueriesMs":30000}}`, now))
+		cr.UpdateCacheFn(ctx, response)
+		assert.Equal(t, 30*time.Second, cache.lastTTL)
+	})
+
+	t.Run("is disabled per datasource", func(t *testing.T) {
+		s := newTestService(remotecache.NewFakeCacheStorage())
+
+		ctx, rec := newTestContext()
+		hit, cr := s.HandleQueryRequest(ctx, newRequest(`{"queryCaching":{"enabled":false}}`, now))
+		assert.False(t, hit)
+		assert.Nil(t, cr.UpdateCacheFn)
+		assert.Equal(t, StatusDisabled, rec.Header().Get(XCacheHeader))
+	})
+
+	t.Run("bypasses datasources forwarding the user identity", func(t *testing.T) {
+		for name, jsonData := range map[string]string{
+			"oauth pass through": `{"oauthPassThru":true}`,
+			"team http headers":  `{"teamHttpHeaders":{"headers":{"1":[{"header":"X-Team","value":"a"}]}}}`,
+			"forwarded cookies":  `{"keepCookies":["session"]}`,
+		} {
+			t.Run(name, func(t *testing.T) {
+				s := newTestService(remotecache.NewFakeCacheStorage())
+
+				ctx, rec := newTestContext()
+				hit, cr := s.HandleQueryRequest(ctx, newRequest(jsonData, now))
+				assert.False(t, hit)
+				assert.Nil(t, cr.UpdateCacheFn)
+				assert.Equal(t, StatusBypass, rec.Header().Get(XCacheHeader))
+			})
+		}
+	})
+
+	t.Run("bypasses every datasource when the user header is sent", func(t *testing.T) {
+		s := newTestService(remotecache.NewFakeCacheStorage())
+		s.sendUserHeader = true
+
+		ctx, rec := newTestContext()
+		hit, cr := s.HandleQueryRequest(ctx, newRequest(`{}`, now))
+		assert.False(t, hit)
+		assert.Nil(t, cr.UpdateCacheFn)
+		assert.Equal(t, StatusBypass, rec.Header().Get(XCacheHeader))
+	})
+
+	t.Run("reports cache errors", func(t *testing.T) {
+		s := newTestService(&recordingCacheStorage{CacheStorage: remotecache.NewFakeCacheStorage(), getErr: errors.New("boom")})
+
+		ctx, rec := newTestContext()
+		hit, cr := s.HandleQueryRequest(ctx, newRequest(`{}`, now))
+		assert.False(t, hit)
+		assert.Nil(t, cr.UpdateCacheFn)
+		assert.Equal(t, StatusError, rec.Header().Get(XCacheHeader))
+	})
+}
+
+func TestOSSCachingService_HandleResourceRequest(t *testing.T) {
+	newRequest := func(method string) *backend.CallResourceRequest {
+		return &backend.CallResourceRequest{
+			PluginContext: backend.PluginContext{
+				OrgID:                      1,
+				PluginID:                   "prometheus",
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{UID: "ds-uid"},
+			},
+			Path:   "api/v1/labels",
+			URL:    "api/v1/labels?match=up",
+			Method: method,
+		}
+	}
+
+	t.Run("caches successful GET responses", func(t *testing.T) {
+		s := newTestService(remotecache.NewFakeCacheStorage())
+
+		ctx, _ := newTestContext()
+		hit, cr := s.HandleResourceRequest(ctx, newRequest(http.MethodGet))
+		require.False(t, hit)
+		cr.UpdateCacheFn(ctx, &backend.CallResourceResponse{Status: http.StatusOK, Body: []byte(`["job"]`)})
+		cr.CommitCacheFn(ctx)
+
+		ctx, rec := newTestContext()
+		hit, cr = s.HandleResourceRequest(ctx, newRequest(http.MethodGet))
+		require.True(t, hit)
+		assert.Equal(t, `["job"]`, string(cr.Response.Body))
+		assert.Equal(t, StatusHit, rec.Header().Get(XCacheHeader))
+	})
+
+	t.Run("does not keep streamed responses", func(t *testing.T) {
+		s := newTestService(remotecache.NewFakeCacheStorage())
+
+		ctx, _ := newTestContext()
+		_, cr := s.HandleResourceRequest(ctx, newRequest(http.MethodGet))
+		cr.UpdateCacheFn(ctx, &backend.CallResourceResponse{Status: http.StatusOK, Body: []byte(`part 1`)})
+
+		// Nothing is stored while the stream is still in progress.
+		hit, _ := s.HandleResourceRequest(ctx, newRequest(http.MethodGet))
+		assert.False(t, hit)
+
+		cr.UpdateCacheFn(ctx, &backend.CallResourceResponse{Status: http.StatusOK, Body: []byte(`part 2`)})
+		cr.CommitCacheFn(ctx)
+
+		hit, _ = s.HandleResourceRequest(ctx, newRequest(http.MethodGet))
+		assert.False(t, hit)
+	})
+
+	t.Run("does not store responses of requests that did not complete", func(t *testing.T) {
+		s := newTestService(remotecache.NewFakeCacheStorage())
+
+		ctx, _ := newTestContext()
+		_, cr := s.HandleResourceRequest(ctx, newRequest(http.MethodGet))
+		cr.UpdateCacheFn(ctx, &backend.CallResourceResponse{Status: http.StatusOK, Body: []byte(`["job"]`)})
+
+		hit, _ := s.HandleResourceRequest(ctx, newRequest(http.MethodGet))
+		assert.False(t, hit)
+	})
+
+	t.Run("bypasses non GET requests", func(t *testing.T) {
+		s := newTestService(remotecache.NewFakeCacheStorage())
+
+		ctx, rec := newTestContext()
+		hit, cr := s.HandleResourceRequest(ctx, newRequest(http.MethodPost))
+		assert.False(t, hit)
+		assert.Nil(t, cr.UpdateCacheFn)
+		assert.Equal(t, StatusBypass, rec.Header().Get(XCacheHeader))
+	})
+}
+
+func TestQueryCacheKey(t *testing.T) {
+	from := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	newRequest := func(model string, from time.Time) *backend.QueryDataRequest {
+		return &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{UID: "ds-uid"},
+			},
+			Queries: []backend.DataQuery{{
+				RefID:     "A",
+				JSON:      []byte(model),
+				TimeRange: backend.TimeRange{From: from, To: from.Add(time.Hour)},
+			}},
+		}
+	}
+
+	key := func(req *backend.QueryDataRequest) string {
+		k, err := queryCacheKey(req, 10*time.Second)
+		require.NoError(t, err)
+		return k
+	}
+
+	base := key(newRequest(`{"expr":"up","legendFormat":"{{job}}"}`, from))
+	assert.Equal(t, base, key(newRequest(`{"legendFormat":"{{job}}", "expr":"up", "requestId":"Q1"}`, from.Add(5*time.Second))))
+	assert.NotEqual(t, base, key(newRequest(`{"expr":"up","legendFormat":"{{job}}"}`, from.Add(10*time.Second))))
+	assert.NotEqual(t, base, key(newRequest(`{"expr":"down","legendFormat":"{{job}}"}`, from)))
+
+	_, err := queryCacheKey(newRequest(`{invalid`, from), 0)
+	assert.Error(t, err)
+}
+
+func newTestService(cache remotecache.CacheStorage) *OSSCachingService {
+	return ProvideCachingService(&setting.Cfg{QueryCaching: setting.QueryCachingSettings{
+		Enabled:     true,
+		TTL:         time.Minute,
+		ResourceTTL: time.Minute,
+		TimeBucket:  10 * time.Second,
+	}}, cache, prometheus.NewRegistry())
+}
+
+func newTestContext() (context.Context, *httptest.ResponseRecorder) {
+	rec := httptest.NewRecorder()
+	reqCtx := &contextmodel.ReqContext{
+		Context: &web.Context{
+			Resp: web.NewResponseWriter(http.MethodGet, rec),
+		},
+	}
+	return ctxkey.Set(context.Background(), reqCtx), rec
+}
+
+type recordingCacheStorage struct {
+	remotecache.CacheStorage
+	getErr  error
+	lastTTL time.Duration
+}
+
+func (r *recordingCacheStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	if r.getErr != nil {
+		return nil, r.getErr
+	}
+	return r.CacheStorage.Get(ctx, key)
+}
+
+func (r *recordingCacheStorage) Set(ctx context.Context, key string, value []byte, expire time.Duration) error {
+	r.lastTTL = expire
+	return r.CacheStorage.Set(ctx, key, value, expire)
+}
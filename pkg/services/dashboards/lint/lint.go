@@ -0,0 +1,169 @@
+// Package lint checks dashboards against a set of rules, for example panels without a title or queries
+// without a data source UID, and reports the findings with their severity.
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// Severity is the severity of a finding.
+type Severity string
+
+const (
+	SeverityInfo    Severity = "info"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// Finding is a problem found in a dashboard by a rule.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	// Path of the problem in the dashboard JSON, for example `panels[2].targets[0].datasource`
+	Path    string `json:"path"`
+	PanelID int64  `json:"panelId,omitempty"`
+}
+
+// Result is the findings of the linter for a dashboard.
+type Result struct {
+	Findings []Finding `json:"findings"`
+}
+
+// HasErrors returns true when one of the findings has the error severity.
+func (r *Result) HasErrors() bool {
+	for _, f := range r.Findings {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// RuleInfo describes a rule of the linter.
+type RuleInfo struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Severity    Severity `json:"severity"`
+	Enabled     bool     `json:"enabled"`
+}
+
+// Config is the rule set of the linter.
+type Config struct {
+	// DisabledRules are the names of the rules that are not run
+	DisabledRules []string
+	// Severities overrides the default severity of rules, by rule name
+	Severities map[string]Severity
+	// MaxDataPoints is the highest max data points of a panel not reported by the max-data-points rule
+	MaxDataPoints int64
+}
+
+// ConfigFromSettings returns the rule set configured in the [dashboard_lint] section.
+func ConfigFromSettings(cfg *setting.Cfg) Config {
+	severities := make(map[string]Severity, len(cfg.DashboardLintSeverities))
+	for rule, severity := range cfg.DashboardLintSeverities {
+		severities[rule] = Severity(severity)
+	}
+
+	return Config{
+		DisabledRules: cfg.DashboardLintDisabledRules,
+		Severities:    severities,
+		MaxDataPoints: cfg.DashboardLintMaxDataPoints,
+	}
+}
+
+// Linter runs a rule set over dashboards.
+type Linter struct {
+	cfg      Config
+	rules    []rule
+	disabled map[string]bool
+}
+
+// New returns a linter running the rules of the rule set. It fails when the rule set refers
+// to an unknown rule or severity.
+func New(cfg Config) (*Linter, error) {
+	l := &Linter{cfg: cfg, rules: rules, disabled: map[string]bool{}}
+
+	for _, name := range cfg.DisabledRules {
+		if !l.exists(name) {
+			return nil, fmt.Errorf("unknown dashboard lint rule %q", name)
+		}
+		l.disabled[name] = true
+	}
+
+	for name, severity := range cfg.Severities {
+		if !l.exists(name) {
+			return nil, fmt.Errorf("unknown dashboard lint rule %q", name)
+		}
+		switch severity {
+		case SeverityInfo, SeverityWarning, SeverityError:
+		default:
+			return nil, fmt.Errorf("invalid severity %q for dashboard lint rule %q", severity, name)
+		}
+	}
+
+	return l, nil
+}
+
+// Rules returns the rules of the linter with their effective severity.
+func (l *Linter) Rules() []RuleInfo {
+	result := make([]RuleInfo, 0, len(l.rules))
+	for _, r := range l.rules {
+		result = append(result, RuleInfo{
+			Name:        r.name,
+			Description: r.description,
+			Severity:    l.severity(r),
+			Enabled:     !l.disabled[r.name],
+		})
+	}
+	return result
+}
+
+// Lint runs the enabled rules over the dashboard JSON model.
+func (l *Linter) Lint(dashboard *simplejson.Json) *Result {
+	doc := newDocument(dashboard)
+
+	result := &Result{Findings: make([]Finding, 0)}
+	for _, r := range l.rules {
+		if l.disabled[r.name] {
+			continue
+		}
+		for _, f := range r.check(doc, l.cfg) {
+			f.Rule = r.name
+			f.Severity = l.severity(r)
+			result.Findings = append(result.Findings, f)
+		}
+	}
+
+	sort.SliceStable(result.Findings, func(i, j int) bool {
+		return severityOrder[result.Findings[i].Severity] > severityOrder[result.Findings[j].Severity]
+	})
+
+	return result
+}
+
+var severityOrder = map[Severity]int{
+	SeverityInfo:    0,
+	SeverityWarning: 1,
+	SeverityError:   2,
+}
+
+func (l *Linter) severity(r rule) Severity {
+	if severity, ok := l.cfg.Severities[r.name]; ok {
+		return severity
+	}
+	return r.severity
+}
+
+func (l *Linter) exists(name string) bool {
+	for _, r := range l.rules {
+		if r.name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+const dashboardJSON = `{
+	"title": "API",
+	"schemaVersion": 30,
+	"panels": [
+		{"id": 1, "type": "timeseries", "title": "Requests", "maxDataPoints": 10000,
+			"datasource": {"type": "prometheus", "uid": "prom"},
+			"targets": [{"refId": "A", "datasource": "Prometheus"}, {"refId": "B", "datasource": {"type": "prometheus"}}, {"refId": "C"}]},
+		{"id": 2, "type": "graph", "title": "", "gridPos": {"x": "0", "y": 0, "w": 12, "h": 8}, "targets": [{"refId": "A"}]},
+		{"id": 3, "type": "row", "title": "Details", "collapsed": true, "panels": [
+			{"id": 2, "type": "stat", "title": "Uptime"}
+		]}
+	],
+	"templating": {"list": [
+		{"name": "job", "type": "query", "datasource": "Prometheus"},
+		{"name": "env", "type": "custom", "query": "dev,prod"}
+	]}
+}`
+
+func TestLinter(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(dashboardJSON))
+	require.NoError(t, err)
+
+	findingsByRule := func(result *Result) map[string][]Finding {
+		byRule := map[string][]Finding{}
+		for _, f := range result.Findings {
+			byRule[f.Rule] = append(byRule[f.Rule], f)
+		}
+		return byRule
+	}
+
+	t.Run("Reports the findings of the rules", func(t *testing.T) {
+		linter, err := New(Config{MaxDataPoints: 5000})
+		require.NoError(t, err)
+
+		result := linter.Lint(dashboard)
+		assert.True(t, result.HasErrors())
+		byRule := findingsByRule(result)
+
+		assert.Equal(t, []Finding{{Rule: "schema-version", Severity: SeverityInfo, Path: "schemaVersion",
+			Message: "Schema version 30 is older than 36, save the dashboard in Grafana to migrate it"}}, byRule["schema-version"])
+
+		require.Len(t, byRule["panel-schema"], 1)
+		assert.Equal(t, "panels[1]", byRule["panel-schema"][0].Path)
+		assert.Equal(t, int64(2), byRule["panel-schema"][0].PanelID)
+
+		assert.Equal(t, []Finding{{Rule: "panel-id", Severity: SeverityError, Path: "panels[2].panels[0].id", PanelID: 2,
+			Message: "Panel id 2 is used by several panels"}}, byRule["panel-id"])
+
+		require.Len(t, byRule["panel-title"], 1)
+		assert.Equal(t, "panels[1].title", byRule["panel-title"][0].Path)
+
+		var paths []string
+		for _, f := range byRule["datasource-uid"] {
+			paths = append(paths, f.Path)
+		}
+		assert.Equal(t, []string{"panels[0].targets[0].datasource", "panels[0].targets[1].datasource",
+			"panels[1].targets[0].datasource", "templating.list[0].datasource"}, paths)
+
+		require.Len(t, byRule["max-data-points"], 1)
+		assert.Equal(t, "Max data points 10000 is above the limit of 5000", byRule["max-data-points"][0].Message)
+
+		assert.Equal(t, []Finding{{Rule: "deprecated-panel-type", Severity: SeverityWarning, Path: "panels[1].type", PanelID: 2,
+			Message: `Panel type "graph" is deprecated, use "timeseries" instead`}}, byRule["deprecated-panel-type"])
+	})
+
+	t.Run("Sorts the findings by severity", func(t *testing.T) {
+		linter, err := New(Config{})
+		require.NoError(t, err)
+
+		result := linter.Lint(dashboard)
+		assert.Equal(t, SeverityError, result.Findings[0].Severity)
+		assert.Equal(t, SeverityInfo, result.Findings[len(result.Findings)-1].Severity)
+	})
+
+	t.Run("Applies the rule set", func(t *testing.T) {
+		linter, err := New(Config{
+			DisabledRules: []string{"panel-schema", "panel-id", "datasource-uid"},
+			Severities:    map[string]Severity{"panel-title": SeverityError},
+		})
+		require.NoError(t, err)
+
+		byRule := findingsByRule(linter.Lint(dashboard))
+		assert.NotContains(t, byRule, "panel-schema")
+		assert.NotContains(t, byRule, "datasource-uid")
+		assert.NotContains(t, byRule, "max-data-points")
+		assert.Equal(t, SeverityError, byRule["panel-title"][0].Severity)
+
+		for _, r := range linter.Rules() {
+			switch r.Name {
+			case "panel-schema":
+				assert.False(t, r.Enabled)
+			case "panel-title":
+				assert.True(t, r.Enabled)
+				assert.Equal(t, SeverityError, r.Severity)
+			}
+		}
+	})
+
+	t.Run("A valid dashboard has no findings", func(t *testing.T) {
+		linter, err := New(Config{MaxDataPoints: 5000})
+		require.NoError(t, err)
+
+		valid := simplejson.NewFromAny(map[string]any{
+			"title":         "API",
+			"schemaVersion": 39,
+			"panels": []any{
+				map[string]any{"id": 1, "type": "stat", "title": "Uptime", "datasource": map[string]any{"uid": "prom"}},
+			},
+		})
+		assert.Empty(t, linter.Lint(valid).Findings)
+	})
+
+	t.Run("Rejects an invalid rule set", func(t *testing.T) {
+		_, err := New(Config{DisabledRules: []string{"unknown"}})
+		require.Error(t, err)
+
+		_, err = New(Config{Severities: map[string]Severity{"panel-title": "fatal"}})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,273 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/kinds/dashboard"
+)
+
+// rule checks a dashboard and returns its findings, the rule name and severity are set by the linter.
+type rule struct {
+	name        string
+	description string
+	severity    Severity
+	check       func(doc *document, cfg Config) []Finding
+}
+
+var rules = []rule{
+	{
+		name:        "schema-version",
+		description: "The dashboard uses the current schema version",
+		severity:    SeverityInfo,
+		check:       checkSchemaVersion,
+	},
+	{
+		name:        "panel-schema",
+		description: "The panels match the panel schema of the dashboard kind",
+		severity:    SeverityError,
+		check:       checkPanelSchema,
+	},
+	{
+		name:        "panel-id",
+		description: "The panel ids are unique",
+		severity:    SeverityError,
+		check:       checkPanelID,
+	},
+	{
+		name:        "panel-title",
+		description: "The panels have a title",
+		severity:    SeverityWarning,
+		check:       checkPanelTitle,
+	},
+	{
+		name:        "datasource-uid",
+		description: "The panels, queries and query variables refer to their data source by uid",
+		severity:    SeverityError,
+		check:       checkDatasourceUID,
+	},
+	{
+		name:        "max-data-points",
+		description: "The max data points of the panels are below the configured limit",
+		severity:    SeverityWarning,
+		check:       checkMaxDataPoints,
+	},
+	{
+		name:        "deprecated-panel-type",
+		description: "The panels do not use a deprecated panel type",
+		severity:    SeverityWarning,
+		check:       checkDeprecatedPanelType,
+	},
+}
+
+// deprecatedPanelTypes are the deprecated panel types with the panel type replacing them
+var deprecatedPanelTypes = map[string]string{
+	"graph":                    "timeseries",
+	"singlestat":               "stat",
+	"table-old":                "table",
+	"grafana-singlestat-panel": "stat",
+	"grafana-piechart-panel":   "piechart",
+	"grafana-worldmap-panel":   "geomap",
+}
+
+// document is the dashboard with its panels, including the panels of collapsed rows.
+type document struct {
+	dashboard *simplejson.Json
+	panels    []panel
+}
+
+type panel struct {
+	path string
+	id   int64
+	json *simplejson.Json
+}
+
+func (p panel) isRow() bool {
+	return p.json.Get("type").MustString() == "row"
+}
+
+func newDocument(dashboard *simplejson.Json) *document {
+	doc := &document{dashboard: dashboard}
+	for i := range dashboard.Get("panels").MustArray() {
+		p := newPanel(dashboard.Get("panels").GetIndex(i), fmt.Sprintf("panels[%d]", i))
+		doc.panels = append(doc.panels, p)
+
+		for j := range p.json.Get("panels").MustArray() {
+			doc.panels = append(doc.panels, newPanel(p.json.Get("panels").GetIndex(j), fmt.Sprintf("%s.panels[%d]", p.path, j)))
+		}
+	}
+	return doc
+}
+
+func newPanel(model *simplejson.Json, path string) panel {
+	return panel{path: path, id: model.Get("id").MustInt64(), json: model}
+}
+
+func checkSchemaVersion(doc *document, _ Config) []Finding {
+	version := doc.dashboard.Get("schemaVersion").MustInt()
+	if version >= dashboard.HandoffSchemaVersion {
+		return nil
+	}
+	return []Finding{{
+		Message: fmt.Sprintf("Schema version %d is older than %d, save the dashboard in Grafana to migrate it", version, dashboard.HandoffSchemaVersion),
+		Path:    "schemaVersion",
+	}}
+}
+
+func checkPanelSchema(doc *document, _ Config) []Finding {
+	var findings []Finding
+	for _, p := range doc.panels {
+		if p.json.Get("type").MustString() == "" {
+			findings = append(findings, Finding{Message: "Panel has no type", Path: p.path + ".type", PanelID: p.id})
+			continue
+		}
+
+		// data sources are checked by the datasource-uid rule, the nested panels of rows are checked on their own
+		model := make(map[string]any, len(p.json.MustMap()))
+		for key, value := range p.json.MustMap() {
+			if key != "datasource" && key != "panels" {
+				model[key] = value
+			}
+		}
+		data, err := json.Marshal(model)
+		if err != nil {
+			return append(findings, Finding{Message: err.Error(), Path: p.path, PanelID: p.id})
+		}
+
+		var target any = &dashboard.Panel{}
+		if p.isRow() {
+			target = &dashboard.RowPanel{}
+		}
+		if err := json.Unmarshal(data, target); err != nil {
+			findings = append(findings, Finding{
+				Message: fmt.Sprintf("Panel does not match the panel schema: %s", err),
+				Path:    p.path,
+				PanelID: p.id,
+			})
+		}
+	}
+	return findings
+}
+
+func checkPanelID(doc *document, _ Config) []Finding {
+	var findings []Finding
+	seen := map[int64]bool{}
+	for _, p := range doc.panels {
+		if _, ok := p.json.CheckGet("id"); !ok {
+			continue
+		}
+		if seen[p.id] {
+			findings = append(findings, Finding{Message: fmt.Sprintf("Panel id %d is used by several panels", p.id), Path: p.path + ".id", PanelID: p.id})
+		}
+		seen[p.id] = true
+	}
+	return findings
+}
+
+func checkPanelTitle(doc *document, _ Config) []Finding {
+	var findings []Finding
+	for _, p := range doc.panels {
+		if !p.isRow() && p.json.Get("title").MustString() == "" {
+			findings = append(findings, Finding{Message: "Panel has no title", Path: p.path + ".title", PanelID: p.id})
+		}
+	}
+	return findings
+}
+
+func checkDatasourceUID(doc *document, _ Config) []Finding {
+	var findings []Finding
+	for _, p := range doc.panels {
+		if p.isRow() {
+			continue
+		}
+		if f := checkDatasourceRef(p.json, p.path); f != nil {
+			f.PanelID = p.id
+			findings = append(findings, *f)
+		}
+		for i := range p.json.Get("targets").MustArray() {
+			target := p.json.Get("targets").GetIndex(i)
+			path := fmt.Sprintf("%s.targets[%d]", p.path, i)
+			// a query without data source uses the data source of its panel, the default one is only used when
+			// the panel has none either
+			if !hasDatasource(target) && !hasDatasource(p.json) {
+				findings = append(findings, Finding{Message: "Query has no data source and neither has its panel",
+					Path: path + ".datasource", PanelID: p.id})
+				continue
+			}
+			if f := checkDatasourceRef(target, path); f != nil {
+				f.PanelID = p.id
+				findings = append(findings, *f)
+			}
+		}
+	}
+
+	variables := doc.dashboard.GetPath("templating", "list")
+	for i := range variables.MustArray() {
+		variable := variables.GetIndex(i)
+		if variable.Get("type").MustString() != "query" {
+			continue
+		}
+		if f := checkDatasourceRef(variable, fmt.Sprintf("templating.list[%d]", i)); f != nil {
+			findings = append(findings, *f)
+		}
+	}
+	return findings
+}
+
+func hasDatasource(model *simplejson.Json) bool {
+	ds, ok := model.CheckGet("datasource")
+	return ok && ds.Interface() != nil
+}
+
+// checkDatasourceRef checks the data source of a panel, query or variable. A missing data source is valid, the
+// default data source is used.
+func checkDatasourceRef(model *simplejson.Json, path string) *Finding {
+	if !hasDatasource(model) {
+		return nil
+	}
+
+	ds := model.Get("datasource")
+	path += ".datasource"
+	if name, err := ds.String(); err == nil {
+		return &Finding{Message: fmt.Sprintf("Data source %q is referred to by name instead of uid", name), Path: path}
+	}
+	if ds.Get("uid").MustString() == "" {
+		return &Finding{Message: "Data source has no uid", Path: path}
+	}
+	return nil
+}
+
+func checkMaxDataPoints(doc *document, cfg Config) []Finding {
+	if cfg.MaxDataPoints <= 0 {
+		return nil
+	}
+
+	var findings []Finding
+	for _, p := range doc.panels {
+		maxDataPoints := p.json.Get("maxDataPoints").MustInt64()
+		if maxDataPoints > cfg.MaxDataPoints {
+			findings = append(findings, Finding{
+				Message: fmt.Sprintf("Max data points %d is above the limit of %d", maxDataPoints, cfg.MaxDataPoints),
+				Path:    p.path + ".maxDataPoints",
+				PanelID: p.id,
+			})
+		}
+	}
+	return findings
+}
+
+func checkDeprecatedPanelType(doc *document, _ Config) []Finding {
+	var findings []Finding
+	for _, p := range doc.panels {
+		panelType := p.json.Get("type").MustString()
+		if replacement, ok := deprecatedPanelTypes[panelType]; ok {
+			findings = append(findings, Finding{
+				Message: fmt.Sprintf("Panel type %q is deprecated, use %q instead", panelType, replacement),
+				Path:    p.path + ".type",
+				PanelID: p.id,
+			})
+		}
+	}
+	return findings
+}
@@ -0,0 +1,207 @@
+// Package templatevars resolves the template variables of a dashboard on the server, for the features running
+// panel queries without a browser.
+package templatevars
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// AllValue is the value of the All option of a template variable
+const AllValue = "$__all"
+
+// supportedTypes are the types of template variables resolved on the server
+var supportedTypes = map[string]bool{
+	"query":    true,
+	"custom":   true,
+	"constant": true,
+}
+
+// variableRegex matches the $var, ${var}, ${var:format} and [[var]] syntaxes, like the frontend does
+var variableRegex = regexp.MustCompile(`\$(\w+)|\[\[(\w+?)(?::(\w+))?\]\]|\$\{(\w+)(?::([^}]+))?\}`)
+
+// regexEscaper escapes the characters with a meaning in regular expressions
+var regexEscaper = strings.NewReplacer(
+	`\`, `\\`, `.`, `\.`, `+`, `\+`, `*`, `\*`, `?`, `\?`, `^`, `\^`, `$`, `\$`,
+	`(`, `\(`, `)`, `\)`, `[`, `\[`, `]`, `\]`, `{`, `\{`, `}`, `\}`, `|`, `\|`,
+)
+
+// Value is the value a template variable is replaced with
+type Value struct {
+	values []string
+	// raw is true for the custom all value of a variable, which is never formatted
+	raw bool
+}
+
+// IsSupportedType returns true when the template variables of the type are resolved on the server
+func IsSupportedType(variableType string) bool {
+	return supportedTypes[variableType]
+}
+
+// Resolve returns the values of the supported template variables of the dashboard. Those are the values saved in
+// the dashboard, or the selected values, which must have been validated by the caller.
+func Resolve(dashboard *simplejson.Json, selected map[string][]string) map[string]Value {
+	result := make(map[string]Value)
+	for _, variableObj := range dashboard.GetPath("templating", "list").MustArray() {
+		variable := simplejson.NewFromAny(variableObj)
+		name := variable.Get("name").MustString()
+		variableType := variable.Get("type").MustString()
+		if name == "" || !IsSupportedType(variableType) {
+			continue
+		}
+
+		if variableType == "constant" {
+			result[name] = Value{values: []string{variable.Get("query").MustString()}}
+			continue
+		}
+
+		values, ok := selected[name]
+		if !ok {
+			values = CurrentValues(variable)
+		}
+
+		if len(values) > 0 && values[0] == AllValue {
+			if customAllValue := variable.Get("allValue").MustString(); customAllValue != "" {
+				result[name] = Value{values: []string{customAllValue}, raw: true}
+				continue
+			}
+			values = optionValues(variable)
+		}
+		result[name] = Value{values: values}
+	}
+	return result
+}
+
+// CurrentValues returns the values of the current option of the variable, which is a string or an array for
+// multi-value variables
+func CurrentValues(variable *simplejson.Json) []string {
+	current := variable.GetPath("current", "value")
+	if value, err := current.String(); err == nil {
+		return []string{value}
+	}
+	return current.MustStringArray()
+}
+
+func optionValues(variable *simplejson.Json) []string {
+	var values []string
+	for _, optionObj := range variable.Get("options").MustArray() {
+		value := simplejson.NewFromAny(optionObj).Get("value").MustString()
+		if value != "" && value != AllValue {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// InterpolateQuery replaces the template variables in the string fields of the query
+func InterpolateQuery(query *simplejson.Json, variables map[string]Value) {
+	if len(variables) == 0 {
+		return
+	}
+
+	datasourceType := query.Get("datasource").Get("type").MustString()
+	for key, value := range query.MustMap() {
+		if key == "datasource" {
+			continue
+		}
+		query.Set(key, interpolateValue(value, datasourceType, variables))
+	}
+}
+
+func interpolateValue(value any, datasourceType string, variables map[string]Value) any {
+	switch v := value.(type) {
+	case string:
+		return interpolateString(v, datasourceType, variables)
+	case map[string]any:
+		for key, item := range v {
+			v[key] = interpolateValue(item, datasourceType, variables)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = interpolateValue(item, datasourceType, variables)
+		}
+	}
+	return value
+}
+
+func interpolateString(s string, datasourceType string, variables map[string]Value) string {
+	return variableRegex.ReplaceAllStringFunc(s, func(match string) string {
+		groups := variableRegex.FindStringSubmatch(match)
+		name := groups[1] + groups[2] + groups[4]
+		format := groups[3] + groups[5]
+
+		variable, ok := variables[name]
+		if !ok {
+			// built-in variables like $__interval are replaced by the datasources
+			return match
+		}
+		if variable.raw {
+			return variable.values[0]
+		}
+		return formatValues(variable.values, format, datasourceType)
+	})
+}
+
+// formatValues formats the values of a variable with the format of the ${var:format} syntax. Without format,
+// multiple values are formatted like the datasource would in the frontend.
+func formatValues(values []string, format string, datasourceType string) string {
+	switch format {
+	case "raw", "csv":
+		return strings.Join(values, ",")
+	case "pipe":
+		return strings.Join(values, "|")
+	case "regex":
+		return formatRegex(values)
+	case "glob":
+		return formatGlob(values)
+	case "json":
+		b, _ := json.Marshal(values)
+		return string(b)
+	case "singlequote":
+		return joinQuoted(values, "'", `\'`)
+	case "doublequote":
+		return joinQuoted(values, `"`, `\"`)
+	case "sqlstring":
+		return joinQuoted(values, "'", "''")
+	}
+
+	if len(values) == 1 {
+		return values[0]
+	}
+	switch datasourceType {
+	case "prometheus", "loki":
+		return formatRegex(values)
+	case "mysql", "grafana-postgresql-datasource", "postgres", "mssql":
+		return joinQuoted(values, "'", "''")
+	}
+	return formatGlob(values)
+}
+
+func formatRegex(values []string) string {
+	escaped := make([]string, 0, len(values))
+	for _, v := range values {
+		escaped = append(escaped, regexEscaper.Replace(v))
+	}
+	if len(escaped) == 1 {
+		return escaped[0]
+	}
+	return "(" + strings.Join(escaped, "|") + ")"
+}
+
+func formatGlob(values []string) string {
+	if len(values) == 1 {
+		return values[0]
+	}
+	return "{" + strings.Join(values, ",") + "}"
+}
+
+func joinQuoted(values []string, quote string, escapedQuote string) string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, quote+strings.ReplaceAll(v, quote, escapedQuote)+quote)
+	}
+	return strings.Join(quoted, ",")
+}
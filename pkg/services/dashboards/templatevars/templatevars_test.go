@@ -0,0 +1,126 @@
+package templatevars
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+const dashboardWithTemplateVariables = `
+{
+  "templating": {
+    "list": [
+      {
+        "name": "job",
+        "type": "query",
+        "query": "label_values(job)",
+        "definition": "label_values(job)",
+        "refresh": 1,
+        "current": {"text": "api", "value": "api"},
+        "options": [{"text": "api", "value": "api"}, {"text": "db", "value": "db"}, {"text": "web", "value": "web"}]
+      },
+      {
+        "name": "env",
+        "type": "custom",
+        "query": "dev,prod",
+        "multi": true,
+        "current": {"text": ["dev", "prod"], "value": ["dev", "prod"]},
+        "options": [{"text": "dev", "value": "dev"}, {"text": "prod", "value": "prod"}]
+      },
+      {
+        "name": "region",
+        "type": "custom",
+        "query": "eu,us",
+        "includeAll": true,
+        "current": {"text": "All", "value": "$__all"},
+        "options": [{"text": "All", "value": "$__all"}, {"text": "eu", "value": "eu"}, {"text": "us", "value": "us"}]
+      },
+      {
+        "name": "cluster",
+        "type": "constant",
+        "query": "c1"
+      },
+      {
+        "name": "filter",
+        "type": "textbox",
+        "query": "x"
+      }
+    ]
+  }
+}`
+
+func TestResolve(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+	require.NoError(t, err)
+
+	t.Run("uses the values saved in the dashboard", func(t *testing.T) {
+		variables := Resolve(dashboard, nil)
+
+		assert.Equal(t, map[string]Value{
+			"job":     {values: []string{"api"}},
+			"env":     {values: []string{"dev", "prod"}},
+			"region":  {values: []string{"eu", "us"}},
+			"cluster": {values: []string{"c1"}},
+		}, variables)
+	})
+
+	t.Run("uses the values selected by the viewer", func(t *testing.T) {
+		variables := Resolve(dashboard, map[string][]string{"job": {"db", "web"}, "cluster": {"c2"}})
+
+		assert.Equal(t, []string{"db", "web"}, variables["job"].values)
+		assert.Equal(t, []string{"c1"}, variables["cluster"].values)
+	})
+
+	t.Run("uses the custom all value", func(t *testing.T) {
+		dashboard.GetPath("templating", "list").GetIndex(2).Set("allValue", ".*")
+		defer dashboard.GetPath("templating", "list").GetIndex(2).Del("allValue")
+
+		variables := Resolve(dashboard, nil)
+
+		assert.Equal(t, Value{values: []string{".*"}, raw: true}, variables["region"])
+	})
+}
+
+func TestInterpolateQuery(t *testing.T) {
+	variables := map[string]Value{
+		"job":    {values: []string{"api"}},
+		"env":    {values: []string{"dev", "prod.eu"}},
+		"region": {values: []string{".*"}, raw: true},
+	}
+
+	testCases := []struct {
+		name           string
+		datasourceType string
+		text           string
+		expected       string
+	}{
+		{name: "single value", datasourceType: "prometheus", text: `up{job="$job"}`, expected: `up{job="api"}`},
+		{name: "braces syntax", datasourceType: "prometheus", text: `up{job="${job}"}`, expected: `up{job="api"}`},
+		{name: "brackets syntax", datasourceType: "prometheus", text: `up{job="[[job]]"}`, expected: `up{job="api"}`},
+		{name: "multiple values as a regex", datasourceType: "prometheus", text: `up{env=~"$env"}`, expected: `up{env=~"(dev|prod\.eu)"}`},
+		{name: "multiple values as sql strings", datasourceType: "mysql", text: `WHERE env IN ($env)`, expected: `WHERE env IN ('dev','prod.eu')`},
+		{name: "multiple values as a glob", datasourceType: "graphite", text: `servers.$env.cpu`, expected: `servers.{dev,prod.eu}.cpu`},
+		{name: "format", datasourceType: "prometheus", text: `${env:csv} ${env:pipe} ${env:json}`, expected: `dev,prod.eu dev|prod.eu ["dev","prod.eu"]`},
+		{name: "custom all value", datasourceType: "prometheus", text: `up{region=~"$region"}`, expected: `up{region=~".*"}`},
+		{name: "unknown and built-in variables", datasourceType: "prometheus", text: `rate(up{a="$unknown"}[$__rate_interval])`, expected: `rate(up{a="$unknown"}[$__rate_interval])`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			query := simplejson.NewFromAny(map[string]any{
+				"datasource": map[string]any{"type": tc.datasourceType, "uid": "$job"},
+				"expr":       tc.text,
+				"nested":     []any{map[string]any{"text": tc.text}},
+			})
+
+			InterpolateQuery(query, variables)
+
+			assert.Equal(t, tc.expected, query.Get("expr").MustString())
+			assert.Equal(t, tc.expected, query.Get("nested").GetIndex(0).Get("text").MustString())
+			assert.Equal(t, "$job", query.Get("datasource").Get("uid").MustString())
+		})
+	}
+}
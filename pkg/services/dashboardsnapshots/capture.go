@@ -0,0 +1,373 @@
+package dashboardsnapshots
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	common "github.com/grafana/grafana/pkg/apis/common/v0alpha1"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/dashboards/templatevars"
+	"github.com/grafana/grafana/pkg/tsdb/legacydata"
+)
+
+const (
+	// captureMaxDataPoints is the resolution of the panels without maxDataPoints, the frontend uses their width
+	captureMaxDataPoints = 1000
+	// captureConcurrency is the number of panels queried at the same time
+	captureConcurrency = 4
+	// dashboardDatasourceUID is the datasource of the panels reusing the results of another panel
+	dashboardDatasourceUID = "-- Dashboard --"
+	// snapshotTimeFormat is how the frontend serializes the times of a snapshot
+	snapshotTimeFormat = "2006-01-02T15:04:05.000Z"
+)
+
+// QueryDataService runs the queries of the panels of a captured dashboard. It is implemented by the query service.
+type QueryDataService interface {
+	QueryData(ctx context.Context, user identity.Requester, skipDSCache bool, reqDTO dtos.MetricRequest) (*backend.QueryDataResponse, error)
+}
+
+// CaptureDashboard runs the queries of every panel of the dashboard as the user, and returns the dashboard model of
+// a snapshot with the results embedded in the panels, like the frontend creates it. The queries of a panel failing
+// don't fail the snapshot, the error is displayed in the panel instead.
+func CaptureDashboard(ctx context.Context, querier QueryDataService, user identity.Requester, dashboard *simplejson.Json, cmd *CaptureDashboardSnapshotCommand) (*common.Unstructured, error) {
+	raw, err := dashboard.Encode()
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := simplejson.NewJson(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateCaptureVariables(snapshot, cmd.Variables); err != nil {
+		return nil, err
+	}
+
+	from, to, err := captureTimeRange(snapshot, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := templatevars.Resolve(snapshot, cmd.Variables)
+	panels := flattenPanels(snapshot.Get("panels").MustArray())
+	snapshotData := make([][]any, len(panels))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(captureConcurrency)
+	for i, panel := range panels {
+		i, panel := i, panel
+		queries := panelQueries(panel)
+		if len(queries) == 0 {
+			continue
+		}
+		for _, query := range queries {
+			templatevars.InterpolateQuery(query, variables)
+		}
+
+		g.Go(func() error {
+			snapshotData[i] = queryPanel(gctx, querier, user, panel, queries, from, to)
+			return nil
+		})
+	}
+	// the errors of the queries are embedded in the panels
+	_ = g.Wait()
+
+	reuseDashboardDatasourceResults(panels, snapshotData)
+
+	for i, panel := range panels {
+		if snapshotData[i] != nil {
+			panel.Set("snapshotData", snapshotData[i])
+		}
+	}
+
+	name := cmd.Name
+	if name == "" {
+		name = snapshot.Get("title").MustString()
+	}
+	scrubDashboard(snapshot, name, from, to, cmd.Variables)
+
+	raw, err = snapshot.Encode()
+	if err != nil {
+		return nil, err
+	}
+	result := &common.Unstructured{}
+	if err := json.Unmarshal(raw, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// validateCaptureVariables checks that the selected template variables are resolved on the server
+func validateCaptureVariables(dashboard *simplejson.Json, selected map[string][]string) error {
+	if len(selected) == 0 {
+		return nil
+	}
+
+	supported := make(map[string]bool)
+	for _, variableObj := range dashboard.GetPath("templating", "list").MustArray() {
+		variable := simplejson.NewFromAny(variableObj)
+		variableType := variable.Get("type").MustString()
+		// constant variables can't be changed
+		if templatevars.IsSupportedType(variableType) && variableType != "constant" {
+			supported[variable.Get("name").MustString()] = true
+		}
+	}
+
+	for name, values := range selected {
+		if !supported[name] {
+			return ErrInvalidVariable.Errorf("CaptureDashboard: variable %s does not exist or is not supported", name)
+		}
+		if len(values) == 0 {
+			return ErrInvalidVariable.Errorf("CaptureDashboard: variable %s should have at least one value", name)
+		}
+	}
+	return nil
+}
+
+// captureTimeRange returns the absolute time range of the snapshot, the time range of the dashboard by default
+func captureTimeRange(dashboard *simplejson.Json, cmd *CaptureDashboardSnapshotCommand) (time.Time, time.Time, error) {
+	from := cmd.From
+	if from == "" {
+		from = dashboard.GetPath("time", "from").MustString("now-6h")
+	}
+	to := cmd.To
+	if to == "" {
+		to = dashboard.GetPath("time", "to").MustString("now")
+	}
+
+	location, err := time.LoadLocation(dashboard.Get("timezone").MustString())
+	if err != nil {
+		// the browser timezone is not known on the server
+		location = time.UTC
+	}
+
+	timeRange := legacydata.NewDataTimeRange(from, to)
+	now := time.Now()
+	fromTime, err := timeRange.ParseFrom(legacydata.WithLocation(location), legacydata.WithNow(now))
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrInvalidTimeRange.Errorf("CaptureDashboard: invalid from %s: %w", from, err)
+	}
+	toTime, err := timeRange.ParseTo(legacydata.WithLocation(location), legacydata.WithNow(now))
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrInvalidTimeRange.Errorf("CaptureDashboard: invalid to %s: %w", to, err)
+	}
+	if !fromTime.Before(toTime) {
+		return time.Time{}, time.Time{}, ErrInvalidTimeRange.Errorf("CaptureDashboard: from should be before to")
+	}
+
+	return fromTime, toTime, nil
+}
+
+// flattenPanels returns the panels of the dashboard, including the panels of collapsed rows, but not the rows
+func flattenPanels(panels []any) []*simplejson.Json {
+	var result []*simplejson.Json
+	for _, panelObj := range panels {
+		panel := simplejson.NewFromAny(panelObj)
+		if panel.Get("type").MustString() == "row" {
+			// the panels of expanded rows are in the list of panels of the dashboard
+			result = append(result, flattenPanels(panel.Get("panels").MustArray())...)
+			continue
+		}
+		result = append(result, panel)
+	}
+	return result
+}
+
+// panelQueries returns the queries to run for the panel, with the datasource of the panel when they don't have one
+func panelQueries(panel *simplejson.Json) []*simplejson.Json {
+	if datasourceUID(panel) == dashboardDatasourceUID {
+		return nil
+	}
+
+	targets := panel.Get("targets").MustArray()
+	hasExpression := false
+	for _, queryObj := range targets {
+		if expr.NodeTypeFromDatasourceUID(datasourceUID(simplejson.NewFromAny(queryObj))) == expr.TypeCMDNode {
+			hasExpression = true
+		}
+	}
+
+	var queries []*simplejson.Json
+	for _, queryObj := range targets {
+		query := simplejson.NewFromAny(queryObj)
+		// hidden queries are still needed to compute the expressions
+		if !hasExpression && query.Get("hide").MustBool() {
+			continue
+		}
+		if _, ok := query.CheckGet("datasource"); !ok {
+			if datasource, ok := panel.CheckGet("datasource"); ok {
+				query.Set("datasource", datasource.Interface())
+			}
+		}
+		queries = append(queries, query)
+	}
+	return queries
+}
+
+func datasourceUID(query *simplejson.Json) string {
+	uid := query.Get("datasource").Get("uid").MustString()
+	// before 8.3 the datasource was a string
+	if uid == "" {
+		uid = query.Get("datasource").MustString()
+	}
+	return uid
+}
+
+// queryPanel runs the queries of the panel and returns the resulting frames in the format of the snapshot data
+func queryPanel(ctx context.Context, querier QueryDataService, user identity.Requester, panel *simplejson.Json, queries []*simplejson.Json, from time.Time, to time.Time) []any {
+	maxDataPoints := panel.Get("maxDataPoints").MustInt64(captureMaxDataPoints)
+	if maxDataPoints <= 0 {
+		maxDataPoints = captureMaxDataPoints
+	}
+	interval := gtime.RoundInterval(to.Sub(from) / time.Duration(maxDataPoints))
+	if interval < time.Millisecond {
+		interval = time.Millisecond
+	}
+	for _, query := range queries {
+		query.Set("intervalMs", interval.Milliseconds())
+		query.Set("maxDataPoints", maxDataPoints)
+	}
+
+	resp, err := querier.QueryData(ctx, user, false, dtos.MetricRequest{
+		From:    strconv.FormatInt(from.UnixMilli(), 10),
+		To:      strconv.FormatInt(to.UnixMilli(), 10),
+		Queries: queries,
+	})
+	if err != nil {
+		return framesToSnapshotData(data.Frames{errorFrame("", err)})
+	}
+
+	var frames data.Frames
+	for _, query := range queries {
+		refID := query.Get("refId").MustString()
+		dataResponse, ok := resp.Responses[refID]
+		if !ok {
+			continue
+		}
+		if dataResponse.Error != nil {
+			frames = append(frames, errorFrame(refID, dataResponse.Error))
+			continue
+		}
+		for _, frame := range dataResponse.Frames {
+			if frame.RefID == "" {
+				frame.RefID = refID
+			}
+			frames = append(frames, frame)
+		}
+	}
+	return framesToSnapshotData(frames)
+}
+
+// errorFrame returns an empty frame with the error as notice, which the panel displays
+func errorFrame(refID string, err error) *data.Frame {
+	frame := data.NewFrame("").SetMeta(&data.FrameMeta{
+		Notices: []data.Notice{{Severity: data.NoticeSeverityError, Text: err.Error()}},
+	})
+	frame.RefID = refID
+	return frame
+}
+
+func framesToSnapshotData(frames data.Frames) []any {
+	snapshotData := make([]any, 0, len(frames))
+	for _, frame := range frames {
+		raw, err := frame.MarshalJSON()
+		if err != nil {
+			raw, _ = errorFrame(frame.RefID, fmt.Errorf("failed to encode the results: %w", err)).MarshalJSON()
+		}
+		snapshotData = append(snapshotData, json.RawMessage(raw))
+	}
+	return snapshotData
+}
+
+// reuseDashboardDatasourceResults copies the results of the panels reused by other panels through the dashboard
+// datasource
+func reuseDashboardDatasourceResults(panels []*simplejson.Json, snapshotData [][]any) {
+	indexByID := make(map[int64]int, len(panels))
+	for i, panel := range panels {
+		indexByID[panel.Get("id").MustInt64()] = i
+	}
+
+	for i, panel := range panels {
+		if datasourceUID(panel) != dashboardDatasourceUID {
+			continue
+		}
+		source := panel.Get("targets").GetIndex(0).Get("panelId").MustInt64()
+		if j, ok := indexByID[source]; ok {
+			snapshotData[i] = snapshotData[j]
+		}
+	}
+}
+
+// scrubDashboard removes the queries and links of the dashboard like the frontend does, and makes the time range
+// absolute
+func scrubDashboard(snapshot *simplejson.Json, name string, from time.Time, to time.Time, selected map[string][]string) {
+	snapshot.Set("title", name)
+	snapshot.Set("time", map[string]any{
+		"from": from.UTC().Format(snapshotTimeFormat),
+		"to":   to.UTC().Format(snapshotTimeFormat),
+	})
+	snapshot.Set("links", []any{})
+	snapshot.Set("snapshot", map[string]any{
+		"timestamp":   time.Now().UTC().Format(snapshotTimeFormat),
+		"originalUrl": "/d/" + snapshot.Get("uid").MustString(),
+	})
+
+	for _, panel := range flattenPanels(snapshot.Get("panels").MustArray()) {
+		panel.Set("targets", []any{})
+		panel.Set("links", []any{})
+		panel.Set("datasource", nil)
+	}
+
+	// annotations are not captured, only the enabled ones are kept without their queries
+	annotations := []any{}
+	for _, annotationObj := range snapshot.GetPath("annotations", "list").MustArray() {
+		annotation := simplejson.NewFromAny(annotationObj)
+		if !annotation.Get("enable").MustBool() {
+			continue
+		}
+		annotations = append(annotations, map[string]any{
+			"name":      annotation.Get("name").Interface(),
+			"enable":    true,
+			"iconColor": annotation.Get("iconColor").Interface(),
+			"type":      annotation.Get("type").Interface(),
+			"builtIn":   annotation.Get("builtIn").Interface(),
+			"hide":      annotation.Get("hide").Interface(),
+		})
+	}
+	snapshot.SetPath([]string{"annotations", "list"}, annotations)
+
+	for _, variableObj := range snapshot.GetPath("templating", "list").MustArray() {
+		variable := simplejson.NewFromAny(variableObj)
+		if values, ok := selected[variable.Get("name").MustString()]; ok {
+			var current any = values
+			if !variable.Get("multi").MustBool() {
+				current = values[0]
+			}
+			variable.Set("current", map[string]any{"text": current, "value": current, "selected": true})
+		}
+
+		// the value of constant variables is their query
+		if _, ok := variable.CheckGet("query"); ok && variable.Get("type").MustString() != "constant" {
+			variable.Set("query", "")
+		}
+		if current := variable.Get("current").MustMap(); len(current) > 0 {
+			variable.Set("options", []any{current})
+		} else {
+			variable.Set("options", []any{})
+		}
+		if _, ok := variable.CheckGet("refresh"); ok {
+			variable.Set("refresh", 0)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package dashboardsnapshots
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+const dashboardToCapture = `
+{
+  "uid": "dash1",
+  "title": "Service",
+  "time": {"from": "now-1h", "to": "now"},
+  "links": [{"title": "docs"}],
+  "annotations": {"list": [
+    {"name": "Annotations & Alerts", "enable": true, "builtIn": 1, "type": "dashboard", "datasource": {"uid": "-- Grafana --"}},
+    {"name": "Deployments", "enable": false, "datasource": {"uid": "loki"}, "expr": "{job=\"deploy\"}"}
+  ]},
+  "templating": {"list": [
+    {
+      "name": "job",
+      "type": "query",
+      "query": "label_values(job)",
+      "refresh": 1,
+      "current": {"text": "api", "value": "api"},
+      "options": [{"text": "api", "value": "api"}, {"text": "db", "value": "db"}]
+    }
+  ]},
+  "panels": [
+    {
+      "id": 1,
+      "type": "timeseries",
+      "datasource": {"type": "prometheus", "uid": "prom"},
+      "targets": [{"refId": "A", "expr": "up{job=\"$job\"}"}, {"refId": "B", "expr": "down", "hide": true}]
+    },
+    {
+      "id": 2,
+      "type": "stat",
+      "datasource": {"uid": "-- Dashboard --"},
+      "targets": [{"refId": "A", "panelId": 1}]
+    },
+    {
+      "id": 3,
+      "type": "row",
+      "collapsed": true,
+      "panels": [
+        {
+          "id": 4,
+          "type": "table",
+          "targets": [{"refId": "A", "datasource": {"type": "loki", "uid": "loki"}, "expr": "{job=\"$job\"}"}]
+        }
+      ]
+    },
+    {
+      "id": 5,
+      "type": "text"
+    }
+  ]
+}`
+
+type fakeQueryDataService struct {
+	mu       sync.Mutex
+	requests []dtos.MetricRequest
+}
+
+func (s *fakeQueryDataService) QueryData(_ context.Context, _ identity.Requester, _ bool, reqDTO dtos.MetricRequest) (*backend.QueryDataResponse, error) {
+	s.mu.Lock()
+	s.requests = append(s.requests, reqDTO)
+	s.mu.Unlock()
+
+	resp := backend.NewQueryDataResponse()
+	for _, query := range reqDTO.Queries {
+		refID := query.Get("refId").MustString()
+		if query.Get("datasource").Get("uid").MustString() == "loki" {
+			resp.Responses[refID] = backend.DataResponse{Error: errors.New("loki is down")}
+			continue
+		}
+		resp.Responses[refID] = backend.DataResponse{Frames: data.Frames{
+			data.NewFrame(query.Get("expr").MustString(), data.NewField("value", nil, []float64{1})),
+		}}
+	}
+	return resp, nil
+}
+
+func TestCaptureDashboard(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(dashboardToCapture))
+	require.NoError(t, err)
+	signedInUser := &user.SignedInUser{UserID: 1, OrgID: 1}
+
+	t.Run("embeds the results of the queries in the panels", func(t *testing.T) {
+		querier := &fakeQueryDataService{}
+		result, err := CaptureDashboard(context.Background(), querier, signedInUser, dashboard, &CaptureDashboardSnapshotCommand{
+			From:      "1700000000000",
+			To:        "1700003600000",
+			Variables: map[string][]string{"job": {"db"}},
+		})
+		require.NoError(t, err)
+
+		require.Len(t, querier.requests, 2)
+		for _, req := range querier.requests {
+			assert.Equal(t, "1700000000000", req.From)
+			assert.Equal(t, "1700003600000", req.To)
+		}
+
+		raw, err := json.Marshal(result)
+		require.NoError(t, err)
+		snapshot, err := simplejson.NewJson(raw)
+		require.NoError(t, err)
+
+		assert.Equal(t, "Service", snapshot.Get("title").MustString())
+		assert.Equal(t, "2023-11-14T22:13:20.000Z", snapshot.GetPath("time", "from").MustString())
+		assert.Equal(t, "2023-11-14T23:13:20.000Z", snapshot.GetPath("time", "to").MustString())
+		assert.Equal(t, "/d/dash1", snapshot.GetPath("snapshot", "originalUrl").MustString())
+		assert.Empty(t, snapshot.Get("links").MustArray())
+
+		panel := snapshot.Get("panels").GetIndex(0)
+		assert.Empty(t, panel.Get("targets").MustArray())
+		assert.Nil(t, panel.Get("datasource").Interface())
+		frames := panel.Get("snapshotData").MustArray()
+		require.Len(t, frames, 1)
+		// the variable is interpolated and the hidden query is skipped
+		assert.Equal(t, `up{job="db"}`, simplejson.NewFromAny(frames[0]).GetPath("schema", "name").MustString())
+		assert.Equal(t, "A", simplejson.NewFromAny(frames[0]).GetPath("schema", "refId").MustString())
+
+		// the panel using the dashboard datasource reuses the results of the other panel
+		assert.Equal(t, frames, snapshot.Get("panels").GetIndex(1).Get("snapshotData").MustArray())
+
+		// the error of the panel in the collapsed row is displayed in the panel
+		rowPanel := snapshot.Get("panels").GetIndex(2).Get("panels").GetIndex(0)
+		frames = rowPanel.Get("snapshotData").MustArray()
+		require.Len(t, frames, 1)
+		notice := simplejson.NewFromAny(frames[0]).GetPath("schema", "meta", "notices").GetIndex(0)
+		assert.Equal(t, "loki is down", notice.Get("text").MustString())
+
+		_, ok := snapshot.Get("panels").GetIndex(3).CheckGet("snapshotData")
+		assert.False(t, ok)
+
+		annotations := snapshot.GetPath("annotations", "list").MustArray()
+		require.Len(t, annotations, 1)
+		_, ok = simplejson.NewFromAny(annotations[0]).CheckGet("datasource")
+		assert.False(t, ok)
+
+		variable := snapshot.GetPath("templating", "list").GetIndex(0)
+		assert.Equal(t, "", variable.Get("query").MustString())
+		assert.Equal(t, 0, variable.Get("refresh").MustInt())
+		assert.Equal(t, "db", variable.GetPath("current", "value").MustString())
+		assert.Len(t, variable.Get("options").MustArray(), 1)
+	})
+
+	t.Run("does not change the dashboard", func(t *testing.T) {
+		_, err := CaptureDashboard(context.Background(), &fakeQueryDataService{}, signedInUser, dashboard, &CaptureDashboardSnapshotCommand{})
+		require.NoError(t, err)
+
+		assert.Len(t, dashboard.Get("panels").GetIndex(0).Get("targets").MustArray(), 2)
+		_, ok := dashboard.Get("panels").GetIndex(0).CheckGet("snapshotData")
+		assert.False(t, ok)
+	})
+
+	t.Run("returns an error when the variable does not exist", func(t *testing.T) {
+		_, err := CaptureDashboard(context.Background(), &fakeQueryDataService{}, signedInUser, dashboard, &CaptureDashboardSnapshotCommand{
+			Variables: map[string][]string{"env": {"prod"}},
+		})
+		require.ErrorIs(t, err, ErrInvalidVariable)
+	})
+
+	t.Run("returns an error when the time range is invalid", func(t *testing.T) {
+		for _, cmd := range []*CaptureDashboardSnapshotCommand{{From: "yesterday", To: "now"}, {From: "now", To: "now-1h"}} {
+			_, err := CaptureDashboard(context.Background(), &fakeQueryDataService{}, signedInUser, dashboard, cmd)
+			require.ErrorIs(t, err, ErrInvalidTimeRange)
+		}
+	})
+}
@@ -5,3 +5,5 @@ import (
 )
 
 var ErrBaseNotFound = errutil.NotFound("dashboardsnapshots.not-found", errutil.WithPublicMessage("Snapshot not found"))
+var ErrInvalidTimeRange = errutil.BadRequest("dashboardsnapshots.invalid-time-range", errutil.WithPublicMessage("Invalid time range"))
+var ErrInvalidVariable = errutil.BadRequest("dashboardsnapshots.invalid-variable", errutil.WithPublicMessage("Invalid template variable"))
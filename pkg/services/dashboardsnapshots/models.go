@@ -69,6 +69,33 @@ type CreateDashboardSnapshotCommand struct {
 	DashboardEncrypted []byte `json:"-"`
 }
 
+// CaptureDashboardSnapshotCommand runs the queries of a dashboard on the server and stores the results in a snapshot
+// swagger:model
+type CaptureDashboardSnapshotCommand struct {
+	// Snapshot name. Defaults to the title of the dashboard.
+	// required:false
+	Name string `json:"name"`
+
+	// Start of the time range, in epoch milliseconds or relative like now-6h. Defaults to the time range of the dashboard.
+	// required:false
+	// example: now-6h
+	From string `json:"from"`
+
+	// End of the time range, in epoch milliseconds or relative like now. Defaults to the time range of the dashboard.
+	// required:false
+	// example: now
+	To string `json:"to"`
+
+	// Values of the template variables by name. Defaults to the values saved in the dashboard.
+	// required:false
+	Variables map[string][]string `json:"variables"`
+
+	// When the snapshot should expire in seconds. Default is never to expire.
+	// required:false
+	// default:0
+	Expires int64 `json:"expires"`
+}
+
 type DeleteDashboardSnapshotCommand struct {
 	DeleteKey string `json:"-"`
 }
@@ -134,6 +134,26 @@ func (ds DataSource) AllowedCookies() []string {
 	return []string{}
 }
 
+// ResponsesDependOnUser returns true if requests to a datasource with the given JSON data
+// carry the identity of the requesting user, either because the user header is sent to all
+// datasources or because the datasource forwards OAuth tokens, team headers or cookies.
+// Responses of such datasources must not be shared between users.
+func ResponsesDependOnUser(sendUserHeader bool, jsonData *simplejson.Json) bool {
+	if sendUserHeader {
+		return true
+	}
+	if jsonData == nil {
+		return false
+	}
+	if jsonData.Get("oauthPassThru").MustBool(false) {
+		return true
+	}
+	if _, ok := jsonData.CheckGet("teamHttpHeaders"); ok {
+		return true
+	}
+	return len(jsonData.Get("keepCookies").MustStringArray()) > 0
+}
+
 // Specific error type for grpc secrets management so that we can show more detailed plugin errors to users
 type ErrDatasourceSecretsPluginUserFriendly struct {
 	Err string
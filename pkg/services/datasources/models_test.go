@@ -102,3 +102,26 @@ func TestTeamHTTPHeaders(t *testing.T) {
 		})
 	}
 }
+
+func TestResponsesDependOnUser(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		sendUserHeader bool
+		jsonData       *simplejson.Json
+		want           bool
+	}{
+		{desc: "no json data", want: false},
+		{desc: "plain datasource", jsonData: simplejson.NewFromAny(map[string]any{"httpMethod": "POST"}), want: false},
+		{desc: "user header sent to all datasources", sendUserHeader: true, jsonData: simplejson.New(), want: true},
+		{desc: "oauth pass through", jsonData: simplejson.NewFromAny(map[string]any{"oauthPassThru": true}), want: true},
+		{desc: "team http headers", jsonData: simplejson.NewFromAny(map[string]any{"teamHttpHeaders": map[string]any{}}), want: true},
+		{desc: "forwarded cookies", jsonData: simplejson.NewFromAny(map[string]any{"keepCookies": []any{"session"}}), want: true},
+		{desc: "empty cookie list", jsonData: simplejson.NewFromAny(map[string]any{"keepCookies": []any{}}), want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.want, ResponsesDependOnUser(tc.sendUserHeader, tc.jsonData))
+		})
+	}
+}
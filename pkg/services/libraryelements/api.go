@@ -3,6 +3,7 @@ package libraryelements
 import (
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/api/routing"
@@ -30,6 +31,11 @@ func (l *LibraryElementService) registerAPIEndpoints() {
 			entities.Get("/:uid/connections/", authorize(ac.EvalPermission(ActionLibraryPanelsRead, uidScope)), routing.Wrap(l.getConnectionsHandler))
 			entities.Get("/name/:name", routing.Wrap(l.getByNameHandler))
 			entities.Patch("/:uid", authorize(ac.EvalPermission(ActionLibraryPanelsWrite, uidScope)), routing.Wrap(l.patchHandler))
+			entities.Get("/:uid/versions", authorize(ac.EvalPermission(ActionLibraryPanelsRead, uidScope)), routing.Wrap(l.getVersionsHandler))
+			entities.Get("/:uid/versions/:version", authorize(ac.EvalPermission(ActionLibraryPanelsRead, uidScope)), routing.Wrap(l.getVersionHandler))
+			entities.Post("/:uid/versions/:version/restore", authorize(ac.EvalPermission(ActionLibraryPanelsWrite, uidScope)), routing.Wrap(l.restoreVersionHandler))
+			entities.Get("/:uid/diff", authorize(ac.EvalPermission(ActionLibraryPanelsRead, uidScope)), routing.Wrap(l.diffHandler))
+			entities.Post("/:uid/impact", authorize(ac.EvalPermission(ActionLibraryPanelsRead, uidScope)), routing.Wrap(l.impactHandler))
 		} else {
 			entities.Post("/", routing.Wrap(l.createHandler))
 			entities.Delete("/:uid", routing.Wrap(l.deleteHandler))
@@ -38,6 +44,11 @@ func (l *LibraryElementService) registerAPIEndpoints() {
 			entities.Get("/:uid/connections/", routing.Wrap(l.getConnectionsHandler))
 			entities.Get("/name/:name", routing.Wrap(l.getByNameHandler))
 			entities.Patch("/:uid", routing.Wrap(l.patchHandler))
+			entities.Get("/:uid/versions", routing.Wrap(l.getVersionsHandler))
+			entities.Get("/:uid/versions/:version", routing.Wrap(l.getVersionHandler))
+			entities.Post("/:uid/versions/:version/restore", routing.Wrap(l.restoreVersionHandler))
+			entities.Get("/:uid/diff", routing.Wrap(l.diffHandler))
+			entities.Post("/:uid/impact", routing.Wrap(l.impactHandler))
 		}
 	})
 }
@@ -311,6 +322,140 @@ func (l *LibraryElementService) getByNameHandler(c *contextmodel.ReqContext) res
 	}
 }
 
+// swagger:route GET /library-elements/{library_element_uid}/versions library_elements getLibraryElementVersions
+//
+// Get library element versions.
+//
+// Returns the versions of a library element, the most recent first.
+// Use the `limit` query parameter to control the maximum number of versions returned; the default limit is `100`.
+// Use the `start` query parameter to skip versions.
+//
+// Responses:
+// 200: getLibraryElementVersionsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (l *LibraryElementService) getVersionsHandler(c *contextmodel.ReqContext) response.Response {
+	versions, err := l.getLibraryElementVersions(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"], c.QueryInt("limit"), c.QueryInt("start"))
+	if err != nil {
+		return toLibraryElementError(err, "Failed to get library element versions")
+	}
+
+	return response.JSON(http.StatusOK, model.LibraryElementVersionsResponse{Result: versions})
+}
+
+// swagger:route GET /library-elements/{library_element_uid}/versions/{version} library_elements getLibraryElementVersion
+//
+// Get library element version.
+//
+// Returns a version of a library element, including its model.
+//
+// Responses:
+// 200: getLibraryElementVersionResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (l *LibraryElementService) getVersionHandler(c *contextmodel.ReqContext) response.Response {
+	version, err := strconv.ParseInt(web.Params(c.Req)[":version"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "version is invalid", err)
+	}
+
+	dto, err := l.getLibraryElementVersion(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"], version)
+	if err != nil {
+		return toLibraryElementError(err, "Failed to get library element version")
+	}
+
+	return response.JSON(http.StatusOK, model.LibraryElementVersionResponse{Result: dto})
+}
+
+// swagger:route POST /library-elements/{library_element_uid}/versions/{version}/restore library_elements restoreLibraryElementVersion
+//
+// Restore library element version.
+//
+// Restores the name and the model of a previous version of a library element as a new version.
+//
+// Responses:
+// 200: getLibraryElementResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 412: preconditionFailedError
+// 500: internalServerError
+func (l *LibraryElementService) restoreVersionHandler(c *contextmodel.ReqContext) response.Response {
+	version, err := strconv.ParseInt(web.Params(c.Req)[":version"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "version is invalid", err)
+	}
+
+	element, err := l.restoreLibraryElementVersion(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"], version)
+	if err != nil {
+		return toLibraryElementError(err, "Failed to restore library element version")
+	}
+
+	return response.JSON(http.StatusOK, model.LibraryElementResponse{Result: element})
+}
+
+// swagger:route GET /library-elements/{library_element_uid}/diff library_elements diffLibraryElementVersions
+//
+// Compare library element versions.
+//
+// Returns the changes of the fields and the queries between two versions of a library element.
+// The current version is used when the `new` query parameter is omitted.
+//
+// Responses:
+// 200: getLibraryElementVersionDiffResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (l *LibraryElementService) diffHandler(c *contextmodel.ReqContext) response.Response {
+	baseVersion := c.QueryInt64("base")
+	if baseVersion <= 0 {
+		return response.Error(http.StatusBadRequest, "base version is required", nil)
+	}
+
+	diff, err := l.diffLibraryElementVersions(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"], baseVersion, c.QueryInt64("new"))
+	if err != nil {
+		return toLibraryElementError(err, "Failed to compare library element versions")
+	}
+
+	return response.JSON(http.StatusOK, model.LibraryElementVersionDiffResponse{Result: diff})
+}
+
+// swagger:route POST /library-elements/{library_element_uid}/impact library_elements checkLibraryPanelImpact
+//
+// Check library panel impact.
+//
+// Returns, for every dashboard using the library panel, whether the panel renders correctly in the dashboard
+// with the proposed model. The current model is checked when no model is given.
+//
+// Responses:
+// 200: getLibraryPanelImpactReportResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (l *LibraryElementService) impactHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := model.CheckLibraryPanelImpactCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	report, err := l.checkLibraryPanelImpact(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"], cmd)
+	if err != nil {
+		return toLibraryElementError(err, "Failed to check library panel impact")
+	}
+
+	return response.JSON(http.StatusOK, model.LibraryPanelImpactReportResponse{Result: report})
+}
+
 func (l *LibraryElementService) filterLibraryPanelsByPermission(c *contextmodel.ReqContext, elements []model.LibraryElementDTO) ([]model.LibraryElementDTO, error) {
 	filteredPanels := make([]model.LibraryElementDTO, 0)
 	for _, p := range elements {
@@ -333,6 +478,12 @@ func toLibraryElementError(err error, message string) response.Response {
 	if errors.Is(err, model.ErrLibraryElementNotFound) {
 		return response.Error(404, model.ErrLibraryElementNotFound.Error(), err)
 	}
+	if errors.Is(err, model.ErrLibraryElementVersionNotFound) {
+		return response.Error(404, model.ErrLibraryElementVersionNotFound.Error(), err)
+	}
+	if errors.Is(err, model.ErrLibraryElementUnSupportedElementKind) {
+		return response.Error(400, model.ErrLibraryElementUnSupportedElementKind.Error(), err)
+	}
 	if errors.Is(err, model.ErrLibraryElementDashboardNotFound) {
 		return response.Error(404, model.ErrLibraryElementDashboardNotFound.Error(), err)
 	}
@@ -357,7 +508,7 @@ func toLibraryElementError(err error, message string) response.Response {
 	return response.ErrOrFallback(http.StatusInternalServerError, message, err)
 }
 
-// swagger:parameters getLibraryElementByUID getLibraryElementConnections
+// swagger:parameters getLibraryElementByUID getLibraryElementConnections getLibraryElementVersions diffLibraryElementVersions
 type LibraryElementByUID struct {
 	// in:path
 	// required:true
@@ -478,3 +629,73 @@ type GetLibraryElementConnectionsResponse struct {
 	// in: body
 	Body model.LibraryElementConnectionsResponse `json:"body"`
 }
+
+// swagger:parameters getLibraryElementVersions
+type GetLibraryElementVersionsParams struct {
+	// The maximum number of versions returned.
+	// in:query
+	// required:false
+	// default: 100
+	Limit int `json:"limit"`
+	// The number of versions to skip.
+	// in:query
+	// required:false
+	// default: 0
+	Start int `json:"start"`
+}
+
+// swagger:parameters getLibraryElementVersion restoreLibraryElementVersion
+type LibraryElementVersionParams struct {
+	// in:path
+	// required:true
+	UID string `json:"library_element_uid"`
+	// in:path
+	// required:true
+	Version int64 `json:"version"`
+}
+
+// swagger:parameters diffLibraryElementVersions
+type DiffLibraryElementVersionsParams struct {
+	// The version to compare from.
+	// in:query
+	// required:true
+	Base int64 `json:"base"`
+	// The version to compare to, the current version when omitted.
+	// in:query
+	// required:false
+	New int64 `json:"new"`
+}
+
+// swagger:parameters checkLibraryPanelImpact
+type CheckLibraryPanelImpactParams struct {
+	// in:body
+	// required:true
+	Body model.CheckLibraryPanelImpactCommand `json:"body"`
+	// in:path
+	// required:true
+	UID string `json:"library_element_uid"`
+}
+
+// swagger:response getLibraryElementVersionsResponse
+type GetLibraryElementVersionsResponse struct {
+	// in: body
+	Body model.LibraryElementVersionsResponse `json:"body"`
+}
+
+// swagger:response getLibraryElementVersionResponse
+type GetLibraryElementVersionResponse struct {
+	// in: body
+	Body model.LibraryElementVersionResponse `json:"body"`
+}
+
+// swagger:response getLibraryElementVersionDiffResponse
+type GetLibraryElementVersionDiffResponse struct {
+	// in: body
+	Body model.LibraryElementVersionDiffResponse `json:"body"`
+}
+
+// swagger:response getLibraryPanelImpactReportResponse
+type GetLibraryPanelImpactReportResponse struct {
+	// in: body
+	Body model.LibraryPanelImpactReportResponse `json:"body"`
+}
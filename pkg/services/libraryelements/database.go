@@ -197,7 +197,7 @@ func (l *LibraryElementService) createLibraryElement(c context.Context, signedIn
 			}
 			return err
 		}
-		return nil
+		return insertLibraryElementVersion(session, element)
 	})
 
 	metrics.MFolderIDsServiceCount.WithLabelValues(metrics.LibraryElements).Inc()
@@ -270,6 +270,9 @@ func (l *LibraryElementService) deleteLibraryElement(c context.Context, signedIn
 		} else if rowsAffected != 1 {
 			return model.ErrLibraryElementNotFound
 		}
+		if _, err := session.Exec("DELETE FROM "+model.LibraryElementVersionTableName+" WHERE element_id=?", element.ID); err != nil {
+			return err
+		}
 
 		elementID = element.ID
 		return nil
@@ -638,6 +641,9 @@ func (l *LibraryElementService) patchLibraryElement(c context.Context, signedInU
 		} else if rowsAffected != 1 {
 			return model.ErrLibraryElementNotFound
 		}
+		if err := insertLibraryElementVersion(session, libraryElement); err != nil {
+			return err
+		}
 
 		metrics.MFolderIDsServiceCount.WithLabelValues(metrics.LibraryElements).Inc()
 		dto = model.LibraryElementDTO{
@@ -887,6 +893,10 @@ func (l *LibraryElementService) deleteLibraryElementsInFolderUID(c context.Conte
 			if err != nil {
 				return err
 			}
+			_, err = session.Exec("DELETE FROM "+model.LibraryElementVersionTableName+" WHERE element_id=?", elementID.ID)
+			if err != nil {
+				return err
+			}
 		}
 		if _, err := session.Exec("DELETE FROM library_element WHERE folder_id=? AND org_id=?", folderID, signedInUser.GetOrgID()); err != nil {
 			return err
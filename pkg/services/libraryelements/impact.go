@@ -0,0 +1,206 @@
+package libraryelements
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboards/dashboardaccess"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+// variableRegex matches the $var, ${var}, ${var:format}, ${var.field} and [[var]] template variable syntaxes.
+var variableRegex = regexp.MustCompile(`\$(\w+)|\[\[(\w+?)(?::(\w+))?\]\]|\$\{(\w+)(?:\.([^:^\}]+))?(?::([^\}]+))?\}`)
+
+// checkLibraryPanelImpact reports, for every dashboard using a library panel that the user can view, whether the
+// library panel renders correctly in the dashboard with the proposed model.
+func (l *LibraryElementService) checkLibraryPanelImpact(c context.Context, signedInUser identity.Requester, uid string, cmd model.CheckLibraryPanelImpactCommand) (model.LibraryPanelImpactReport, error) {
+	element, err := l.getLibraryElementByUid(c, signedInUser, model.GetLibraryElementCommand{UID: uid, FolderName: dashboards.RootFolderName})
+	if err != nil {
+		return model.LibraryPanelImpactReport{}, err
+	}
+	if element.Kind != int64(model.PanelElement) {
+		return model.LibraryPanelImpactReport{}, model.ErrLibraryElementUnSupportedElementKind
+	}
+
+	proposedModel := cmd.Model
+	if len(proposedModel) == 0 {
+		proposedModel = element.Model
+	}
+
+	diff, err := diffLibraryElementModels(element.Model, proposedModel)
+	if err != nil {
+		return model.LibraryPanelImpactReport{}, err
+	}
+	currentVariables, err := referencedVariables(element.Model)
+	if err != nil {
+		return model.LibraryPanelImpactReport{}, err
+	}
+	proposedVariables, err := referencedVariables(proposedModel)
+	if err != nil {
+		return model.LibraryPanelImpactReport{}, err
+	}
+
+	connectedDashboards, err := l.getConnectedDashboards(c, signedInUser, element.ID)
+	if err != nil {
+		return model.LibraryPanelImpactReport{}, err
+	}
+
+	report := model.LibraryPanelImpactReport{
+		UID:                 element.UID,
+		Version:             element.Version,
+		OK:                  true,
+		ConnectedDashboards: element.Meta.ConnectedDashboards,
+		Diff:                *diff,
+		Dashboards:          make([]model.LibraryPanelDashboardImpact, 0, len(connectedDashboards)),
+	}
+	for _, dashboard := range connectedDashboards {
+		defined := definedVariables(dashboard.Data)
+
+		impact := model.LibraryPanelDashboardImpact{
+			DashboardID:    dashboard.ID,
+			DashboardUID:   dashboard.UID,
+			DashboardTitle: dashboard.Title,
+			OK:             true,
+			Problems:       make([]model.LibraryPanelProblem, 0),
+		}
+		for _, name := range proposedVariables {
+			if defined[name] {
+				continue
+			}
+
+			introduced := true
+			for _, current := range currentVariables {
+				if current == name {
+					introduced = false
+					break
+				}
+			}
+			impact.OK = false
+			impact.Problems = append(impact.Problems, model.LibraryPanelProblem{
+				Kind:       model.LibraryPanelProblemMissingVariable,
+				Variable:   name,
+				Message:    fmt.Sprintf("variable %q is not defined in the dashboard", name),
+				Introduced: introduced,
+			})
+			if introduced {
+				report.OK = false
+			}
+		}
+
+		report.Dashboards = append(report.Dashboards, impact)
+	}
+
+	return report, nil
+}
+
+type connectedDashboard struct {
+	ID    int64  `xorm:"id"`
+	UID   string `xorm:"uid"`
+	Title string
+	Data  *simplejson.Json
+}
+
+// getConnectedDashboards gets the dashboards connected to a Library Element that the user can view.
+func (l *LibraryElementService) getConnectedDashboards(c context.Context, signedInUser identity.Requester, elementID int64) ([]connectedDashboard, error) {
+	recursiveQueriesAreSupported, err := l.SQLStore.RecursiveQueriesAreSupported()
+	if err != nil {
+		return nil, err
+	}
+
+	connected := make([]connectedDashboard, 0)
+	err = l.SQLStore.WithDbSession(c, func(session *db.Session) error {
+		builder := db.NewSqlBuilder(l.Cfg, l.features, l.SQLStore.GetDialect(), recursiveQueriesAreSupported)
+		builder.Write("SELECT dashboard.id, dashboard.uid, dashboard.title, dashboard.data")
+		builder.Write(" FROM " + model.LibraryElementConnectionTableName + " AS lec")
+		builder.Write(" INNER JOIN dashboard AS dashboard on lec.connection_id = dashboard.id")
+		builder.Write(` WHERE lec.element_id=? AND lec.kind=?`, elementID, model.Dashboard)
+		if signedInUser.GetOrgRole() != org.RoleAdmin {
+			builder.WriteDashboardPermissionFilter(signedInUser, dashboardaccess.PERMISSION_VIEW, "")
+		}
+		builder.Write(" ORDER BY dashboard.title ASC")
+		return session.SQL(builder.GetSQLString(), builder.GetParams()...).Find(&connected)
+	})
+
+	return connected, err
+}
+
+// referencedVariables returns the sorted names of the template variables referenced by a panel model, without
+// the built-in variables.
+func referencedVariables(panelModel []byte) ([]string, error) {
+	var panel map[string]any
+	if err := json.Unmarshal(panelModel, &panel); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	if repeat, ok := panel["repeat"].(string); ok && repeat != "" {
+		names[repeat] = true
+	}
+
+	var walk func(value any)
+	walk = func(value any) {
+		switch v := value.(type) {
+		case string:
+			for _, match := range variableRegex.FindAllStringSubmatch(v, -1) {
+				for _, name := range []string{match[1], match[2], match[4]} {
+					if name != "" && !isBuiltInVariable(name) {
+						names[name] = true
+					}
+				}
+			}
+		case map[string]any:
+			for key, item := range v {
+				if key == "libraryPanel" {
+					continue
+				}
+				walk(item)
+			}
+		case []any:
+			for _, item := range v {
+				walk(item)
+			}
+		}
+	}
+	walk(panel)
+
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+
+	return result, nil
+}
+
+// isBuiltInVariable returns true for the variables Grafana defines in every dashboard, such as $__interval, and for
+// references that are not variables, such as the $1 of a regex replacement.
+func isBuiltInVariable(name string) bool {
+	if strings.HasPrefix(name, "__") || name == "timeFilter" || name == "interval" {
+		return true
+	}
+	return strings.Trim(name, "0123456789") == ""
+}
+
+// definedVariables returns the names of the template variables of a dashboard.
+func definedVariables(dashboard *simplejson.Json) map[string]bool {
+	names := make(map[string]bool)
+	if dashboard == nil {
+		return names
+	}
+	for _, variable := range dashboard.GetPath("templating", "list").MustArray() {
+		if name := simplejson.NewFromAny(variable).Get("name").MustString(); name != "" {
+			names[name] = true
+		}
+	}
+
+	return names
+}
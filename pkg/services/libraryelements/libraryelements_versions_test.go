@@ -0,0 +1,218 @@
+package libraryelements
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestLibraryElementVersions(t *testing.T) {
+	scenarioWithPanel(t, "When an admin tries to get the versions of a library panel that does not exist, it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": "unknown"})
+			resp := sc.service.getVersionsHandler(sc.reqContext)
+			require.Equal(t, 404, resp.Status())
+		})
+
+	scenarioWithPanel(t, "When an admin tries to get the versions of a patched library panel, it should return every version",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.SignedInUser.Permissions[sc.reqContext.OrgID][dashboards.ActionFoldersRead] = []string{dashboards.ScopeFoldersAll}
+
+			patchTitle(t, sc, "Text - New title", 1)
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			resp := sc.service.getVersionsHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			var result model.LibraryElementVersionsResponse
+			require.NoError(t, json.Unmarshal(resp.Body(), &result))
+			require.Len(t, result.Result, 2)
+			require.Equal(t, int64(2), result.Result[0].Version)
+			require.Equal(t, int64(1), result.Result[1].Version)
+			require.Equal(t, sc.initialResult.Result.UID, result.Result[0].ElementUID)
+			require.Equal(t, userInDbName, result.Result[0].CreatedBy.Name)
+			require.Nil(t, result.Result[0].Model)
+		})
+
+	scenarioWithPanel(t, "When an admin tries to get a version of a library panel, it should return its model",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.SignedInUser.Permissions[sc.reqContext.OrgID][dashboards.ActionFoldersRead] = []string{dashboards.ScopeFoldersAll}
+
+			patchTitle(t, sc, "Text - New title", 1)
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID, ":version": "1"})
+			resp := sc.service.getVersionHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			var result model.LibraryElementVersionResponse
+			require.NoError(t, json.Unmarshal(resp.Body(), &result))
+			require.Equal(t, int64(1), result.Result.Version)
+			require.Equal(t, "Text - Library Panel", simplejsonFromRaw(t, result.Result.Model).Get("title").MustString())
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID, ":version": "3"})
+			resp = sc.service.getVersionHandler(sc.reqContext)
+			require.Equal(t, 404, resp.Status())
+		})
+
+	scenarioWithPanel(t, "When an admin tries to compare two versions of a library panel, it should return the changed fields",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.SignedInUser.Permissions[sc.reqContext.OrgID][dashboards.ActionFoldersRead] = []string{dashboards.ScopeFoldersAll}
+
+			patchTitle(t, sc, "Text - New title", 1)
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			err := sc.reqContext.Req.ParseForm()
+			require.NoError(t, err)
+			sc.reqContext.Req.Form.Add("base", "1")
+			resp := sc.service.diffHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			var result model.LibraryElementVersionDiffResponse
+			require.NoError(t, json.Unmarshal(resp.Body(), &result))
+			require.Equal(t, int64(1), result.Result.BaseVersion)
+			require.Equal(t, int64(2), result.Result.NewVersion)
+			require.Len(t, result.Result.Diff.Fields, 1)
+			require.Equal(t, "title", result.Result.Diff.Fields[0].Path)
+			require.Equal(t, "Text - New title", result.Result.Diff.Fields[0].New)
+		})
+
+	scenarioWithPanel(t, "When an admin tries to restore a version of a library panel, it should add a new version with its model",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.SignedInUser.Permissions[sc.reqContext.OrgID][dashboards.ActionFoldersRead] = []string{dashboards.ScopeFoldersAll}
+
+			patchTitle(t, sc, "Text - New title", 1)
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID, ":version": "1"})
+			resp := sc.service.restoreVersionHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			result := validateAndUnMarshalResponse(t, resp)
+			require.Equal(t, int64(3), result.Result.Version)
+			require.Equal(t, "Text - Library Panel", result.Result.Model["title"])
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID, ":version": "3"})
+			resp = sc.service.getVersionHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+		})
+}
+
+func TestCheckLibraryPanelImpact(t *testing.T) {
+	scenarioWithPanel(t, "When an admin checks a model referencing a variable missing in a connected dashboard, it should report it",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.SignedInUser.Permissions[sc.reqContext.OrgID][dashboards.ActionFoldersRead] = []string{dashboards.ScopeFoldersAll}
+
+			dashJSON := map[string]any{
+				"panels": []any{
+					map[string]any{
+						"id": int64(1),
+						"libraryPanel": map[string]any{
+							"uid":  sc.initialResult.Result.UID,
+							"name": sc.initialResult.Result.Name,
+						},
+					},
+				},
+				"templating": map[string]any{
+					"list": []any{
+						map[string]any{"name": "job", "type": "query"},
+					},
+				},
+			}
+			dash := dashboards.Dashboard{
+				Title: "Testing impact",
+				Data:  simplejson.NewFromAny(dashJSON),
+			}
+			// nolint:staticcheck
+			dashInDB := createDashboard(t, sc.sqlStore, sc.user, &dash, sc.folder.ID, sc.folder.UID)
+			err := sc.service.ConnectElementsToDashboard(sc.reqContext.Req.Context(), sc.reqContext.SignedInUser, []string{sc.initialResult.Result.UID}, dashInDB.ID)
+			require.NoError(t, err)
+
+			cmd := model.CheckLibraryPanelImpactCommand{
+				Model: []byte(`
+					{
+					  "id": 1,
+					  "title": "Requests of $job on ${instance}",
+					  "type": "timeseries",
+					  "targets": [{"refId": "A", "expr": "rate(requests{job=\"$job\"}[$__rate_interval])"}]
+					}
+				`),
+			}
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(cmd)
+			resp := sc.service.impactHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			var result model.LibraryPanelImpactReportResponse
+			require.NoError(t, json.Unmarshal(resp.Body(), &result))
+			require.False(t, result.Result.OK)
+			require.Equal(t, int64(1), result.Result.ConnectedDashboards)
+			require.False(t, result.Result.Diff.IsEmpty())
+			require.Len(t, result.Result.Dashboards, 1)
+
+			impact := result.Result.Dashboards[0]
+			require.Equal(t, dashInDB.UID, impact.DashboardUID)
+			require.False(t, impact.OK)
+			require.Equal(t, []model.LibraryPanelProblem{{
+				Kind:       model.LibraryPanelProblemMissingVariable,
+				Variable:   "instance",
+				Message:    `variable "instance" is not defined in the dashboard`,
+				Introduced: true,
+			}}, impact.Problems)
+		})
+
+	scenarioWithPanel(t, "When an admin checks the current model of a library panel without connections, it should succeed",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.SignedInUser.Permissions[sc.reqContext.OrgID][dashboards.ActionFoldersRead] = []string{dashboards.ScopeFoldersAll}
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(model.CheckLibraryPanelImpactCommand{})
+			resp := sc.service.impactHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			var result model.LibraryPanelImpactReportResponse
+			require.NoError(t, json.Unmarshal(resp.Body(), &result))
+			require.True(t, result.Result.OK)
+			require.True(t, result.Result.Diff.IsEmpty())
+			require.Empty(t, result.Result.Dashboards)
+		})
+}
+
+func TestReferencedVariables(t *testing.T) {
+	variables, err := referencedVariables([]byte(`{
+		"title": "$job [[env]] ${instance:csv} ${__from} $__interval $interval",
+		"repeat": "region",
+		"targets": [{"expr": "sum(x{a=\"${cluster.text}\"}) by (le) $1"}],
+		"datasource": "${DS_GDEV-TESTDATA}",
+		"libraryPanel": {"name": "$ignored"}
+	}`))
+	require.NoError(t, err)
+	require.Equal(t, []string{"cluster", "env", "instance", "job", "region"}, variables)
+}
+
+func patchTitle(t *testing.T, sc scenarioContext, title string, version int64) {
+	t.Helper()
+
+	cmd := model.PatchLibraryElementCommand{
+		FolderID: -1,
+		Model:    []byte(`{"datasource": "${DS_GDEV-TESTDATA}", "id": 1, "title": "` + title + `", "type": "text", "description": "A description"}`),
+		Kind:     int64(model.PanelElement),
+		Version:  version,
+	}
+	sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+	sc.reqContext.Req.Body = mockRequestBody(cmd)
+	resp := sc.service.patchHandler(sc.reqContext)
+	require.Equal(t, 200, resp.Status())
+}
+
+func simplejsonFromRaw(t *testing.T, raw json.RawMessage) *simplejson.Json {
+	t.Helper()
+
+	j, err := simplejson.NewJson(raw)
+	require.NoError(t, err)
+	return j
+}
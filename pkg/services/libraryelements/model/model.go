@@ -5,6 +5,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
 	"github.com/grafana/grafana/pkg/kinds/librarypanel"
 )
 
@@ -137,6 +138,88 @@ type LibraryElementConnectionDTO struct {
 	CreatedBy     librarypanel.LibraryElementDTOMetaUser `json:"createdBy"`
 }
 
+// LibraryElementVersion is the model for the versions of library elements.
+type LibraryElementVersion struct {
+	ID        int64 `xorm:"pk autoincr 'id'"`
+	OrgID     int64 `xorm:"org_id"`
+	ElementID int64 `xorm:"element_id"`
+	Version   int64
+	Name      string
+	Model     json.RawMessage
+	Created   time.Time
+	CreatedBy int64
+}
+
+// LibraryElementVersionWithMeta is the model used to retrieve versions with additional meta information.
+type LibraryElementVersionWithMeta struct {
+	ID             int64 `xorm:"pk autoincr 'id'"`
+	OrgID          int64 `xorm:"org_id"`
+	ElementID      int64 `xorm:"element_id"`
+	Version        int64
+	Name           string
+	Model          json.RawMessage
+	Created        time.Time
+	CreatedBy      int64
+	CreatedByName  string
+	CreatedByEmail string
+}
+
+// LibraryElementVersionDTO is the frontend DTO for library element versions.
+type LibraryElementVersionDTO struct {
+	ID         int64  `json:"id"`
+	ElementUID string `json:"elementUid"`
+	Version    int64  `json:"version"`
+	Name       string `json:"name"`
+	// The JSON model of the library element at this version, omitted when listing versions.
+	Model     json.RawMessage                        `json:"model,omitempty"`
+	Created   time.Time                              `json:"created"`
+	CreatedBy librarypanel.LibraryElementDTOMetaUser `json:"createdBy"`
+}
+
+// LibraryElementVersionDiff is the diff between two versions of a library element.
+type LibraryElementVersionDiff struct {
+	BaseVersion int64               `json:"baseVersion"`
+	NewVersion  int64               `json:"newVersion"`
+	Diff        dashdiffs.PanelDiff `json:"diff"`
+}
+
+// LibraryPanelImpactReport reports how a proposed model of a library panel affects the dashboards using it.
+type LibraryPanelImpactReport struct {
+	UID string `json:"uid"`
+	// Version of the library panel the proposed model is compared to.
+	Version int64 `json:"version"`
+	// OK is true when the proposed model introduces no problem in the dashboards.
+	OK bool `json:"ok"`
+	// ConnectedDashboards is the number of dashboards using the library panel, including the ones
+	// the user cannot view and that are not part of the report.
+	ConnectedDashboards int64                         `json:"connectedDashboards"`
+	Diff                dashdiffs.PanelDiff           `json:"diff"`
+	Dashboards          []LibraryPanelDashboardImpact `json:"dashboards"`
+}
+
+// LibraryPanelDashboardImpact is the impact of a proposed model of a library panel on one dashboard.
+type LibraryPanelDashboardImpact struct {
+	DashboardID    int64  `json:"dashboardId"`
+	DashboardUID   string `json:"dashboardUid"`
+	DashboardTitle string `json:"dashboardTitle"`
+	// OK is true when the library panel renders correctly in the dashboard with the proposed model.
+	OK       bool                  `json:"ok"`
+	Problems []LibraryPanelProblem `json:"problems"`
+}
+
+// LibraryPanelProblem is a reason a library panel does not render correctly in a dashboard.
+type LibraryPanelProblem struct {
+	// Kind of problem, only missing-variable for now.
+	Kind     string `json:"kind"`
+	Variable string `json:"variable,omitempty"`
+	Message  string `json:"message"`
+	// Introduced is true when the current version of the library panel does not have the problem.
+	Introduced bool `json:"introduced"`
+}
+
+// LibraryPanelProblemMissingVariable is a problem for a variable referenced by a library panel that the dashboard does not define.
+const LibraryPanelProblemMissingVariable = "missing-variable"
+
 var (
 	// errLibraryElementAlreadyExists is an error for when the user tries to add a library element that already exists.
 	ErrLibraryElementAlreadyExists = errors.New("library element with that name or UID already exists")
@@ -156,6 +239,8 @@ var (
 	ErrLibraryElementInvalidUID = errors.New("uid contains illegal characters")
 	// errLibraryElementUIDTooLong is an error for when the uid of a library element is invalid
 	ErrLibraryElementUIDTooLong = errors.New("uid too long, max 40 characters")
+	// ErrLibraryElementVersionNotFound is an error for when a version of a library element can't be found.
+	ErrLibraryElementVersionNotFound = errors.New("library element version could not be found")
 )
 
 // Commands
@@ -208,6 +293,14 @@ type PatchLibraryElementCommand struct {
 	UID string `json:"uid"`
 }
 
+// CheckLibraryPanelImpactCommand is the command for checking the impact of a new model of a library panel
+// on the dashboards using it.
+type CheckLibraryPanelImpactCommand struct {
+	// The proposed JSON model for the library panel, the current model is checked when omitted.
+	// swagger:type object
+	Model json.RawMessage `json:"model,omitempty"`
+}
+
 // GetLibraryElementCommand is the command for getting a library element.
 type GetLibraryElementCommand struct {
 	FolderName string
@@ -250,6 +343,26 @@ type LibraryElementConnectionsResponse struct {
 	Result []LibraryElementConnectionDTO `json:"result"`
 }
 
+// LibraryElementVersionsResponse is a response struct for an array of LibraryElementVersionDTO.
+type LibraryElementVersionsResponse struct {
+	Result []LibraryElementVersionDTO `json:"result"`
+}
+
+// LibraryElementVersionResponse is a response struct for LibraryElementVersionDTO.
+type LibraryElementVersionResponse struct {
+	Result LibraryElementVersionDTO `json:"result"`
+}
+
+// LibraryElementVersionDiffResponse is a response struct for LibraryElementVersionDiff.
+type LibraryElementVersionDiffResponse struct {
+	Result LibraryElementVersionDiff `json:"result"`
+}
+
+// LibraryPanelImpactReportResponse is a response struct for LibraryPanelImpactReport.
+type LibraryPanelImpactReportResponse struct {
+	Result LibraryPanelImpactReport `json:"result"`
+}
+
 // DeleteLibraryElementResponse is the response struct for deleting a library element.
 type DeleteLibraryElementResponse struct {
 	ID      int64  `json:"id"`
@@ -267,3 +380,5 @@ const (
 )
 
 const LibraryElementConnectionTableName = "library_element_connection"
+
+const LibraryElementVersionTableName = "library_element_version"
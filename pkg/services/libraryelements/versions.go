@@ -0,0 +1,182 @@
+package libraryelements
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/kinds/librarypanel"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+)
+
+// insertLibraryElementVersion adds the current model of a library element to its version history.
+func insertLibraryElementVersion(session *db.Session, element model.LibraryElement) error {
+	version := model.LibraryElementVersion{
+		OrgID:     element.OrgID,
+		ElementID: element.ID,
+		Version:   element.Version,
+		Name:      element.Name,
+		Model:     element.Model,
+		Created:   element.Updated,
+		CreatedBy: element.UpdatedBy,
+	}
+	_, err := session.Insert(&version)
+	return err
+}
+
+// getLibraryElementVersions gets the versions of a Library Element, the most recent first.
+func (l *LibraryElementService) getLibraryElementVersions(c context.Context, signedInUser identity.Requester, uid string, limit int, start int) ([]model.LibraryElementVersionDTO, error) {
+	element, err := l.getLibraryElementByUid(c, signedInUser, model.GetLibraryElementCommand{UID: uid, FolderName: dashboards.RootFolderName})
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = 100
+	}
+	if start < 0 {
+		start = 0
+	}
+
+	versions := make([]model.LibraryElementVersionDTO, 0)
+	err = l.SQLStore.WithDbSession(c, func(session *db.Session) error {
+		var libraryElementVersions []model.LibraryElementVersionWithMeta
+		sql := selectLibraryElementVersionWithMeta(l.SQLStore) +
+			" WHERE lev.element_id=? ORDER BY lev.version DESC" +
+			l.SQLStore.GetDialect().LimitOffset(int64(limit), int64(start))
+		if err := session.SQL(sql, element.ID).Find(&libraryElementVersions); err != nil {
+			return err
+		}
+
+		for _, version := range libraryElementVersions {
+			dto := l.toLibraryElementVersionDTO(element.UID, version)
+			dto.Model = nil
+			versions = append(versions, dto)
+		}
+		return nil
+	})
+
+	return versions, err
+}
+
+// getLibraryElementVersion gets a version of a Library Element.
+func (l *LibraryElementService) getLibraryElementVersion(c context.Context, signedInUser identity.Requester, uid string, version int64) (model.LibraryElementVersionDTO, error) {
+	element, err := l.getLibraryElementByUid(c, signedInUser, model.GetLibraryElementCommand{UID: uid, FolderName: dashboards.RootFolderName})
+	if err != nil {
+		return model.LibraryElementVersionDTO{}, err
+	}
+
+	var dto model.LibraryElementVersionDTO
+	err = l.SQLStore.WithDbSession(c, func(session *db.Session) error {
+		var libraryElementVersions []model.LibraryElementVersionWithMeta
+		sql := selectLibraryElementVersionWithMeta(l.SQLStore) + " WHERE lev.element_id=? AND lev.version=?"
+		if err := session.SQL(sql, element.ID, version).Find(&libraryElementVersions); err != nil {
+			return err
+		}
+		if len(libraryElementVersions) == 0 {
+			return model.ErrLibraryElementVersionNotFound
+		}
+
+		dto = l.toLibraryElementVersionDTO(element.UID, libraryElementVersions[0])
+		return nil
+	})
+
+	return dto, err
+}
+
+// diffLibraryElementVersions computes the diff between two versions of a Library Element. The current version
+// is used when newVersion is 0.
+func (l *LibraryElementService) diffLibraryElementVersions(c context.Context, signedInUser identity.Requester, uid string, baseVersion int64, newVersion int64) (model.LibraryElementVersionDiff, error) {
+	if newVersion == 0 {
+		element, err := l.getLibraryElementByUid(c, signedInUser, model.GetLibraryElementCommand{UID: uid, FolderName: dashboards.RootFolderName})
+		if err != nil {
+			return model.LibraryElementVersionDiff{}, err
+		}
+		newVersion = element.Version
+	}
+
+	base, err := l.getLibraryElementVersion(c, signedInUser, uid, baseVersion)
+	if err != nil {
+		return model.LibraryElementVersionDiff{}, err
+	}
+	next, err := l.getLibraryElementVersion(c, signedInUser, uid, newVersion)
+	if err != nil {
+		return model.LibraryElementVersionDiff{}, err
+	}
+
+	diff, err := diffLibraryElementModels(base.Model, next.Model)
+	if err != nil {
+		return model.LibraryElementVersionDiff{}, err
+	}
+
+	return model.LibraryElementVersionDiff{
+		BaseVersion: base.Version,
+		NewVersion:  next.Version,
+		Diff:        *diff,
+	}, nil
+}
+
+// restoreLibraryElementVersion restores the name and the model of a previous version of a Library Element,
+// as a new version.
+func (l *LibraryElementService) restoreLibraryElementVersion(c context.Context, signedInUser identity.Requester, uid string, version int64) (model.LibraryElementDTO, error) {
+	element, err := l.getLibraryElementByUid(c, signedInUser, model.GetLibraryElementCommand{UID: uid, FolderName: dashboards.RootFolderName})
+	if err != nil {
+		return model.LibraryElementDTO{}, err
+	}
+
+	restored, err := l.getLibraryElementVersion(c, signedInUser, uid, version)
+	if err != nil {
+		return model.LibraryElementDTO{}, err
+	}
+
+	return l.patchLibraryElement(c, signedInUser, model.PatchLibraryElementCommand{
+		FolderID: -1,
+		Name:     restored.Name,
+		Model:    restored.Model,
+		Kind:     element.Kind,
+		Version:  element.Version,
+	}, uid)
+}
+
+func (l *LibraryElementService) toLibraryElementVersionDTO(uid string, version model.LibraryElementVersionWithMeta) model.LibraryElementVersionDTO {
+	return model.LibraryElementVersionDTO{
+		ID:         version.ID,
+		ElementUID: uid,
+		Version:    version.Version,
+		Name:       version.Name,
+		Model:      version.Model,
+		Created:    version.Created,
+		CreatedBy: librarypanel.LibraryElementDTOMetaUser{
+			Id:        version.CreatedBy,
+			Name:      version.CreatedByName,
+			AvatarUrl: dtos.GetGravatarUrl(l.Cfg, version.CreatedByEmail),
+		},
+	}
+}
+
+func selectLibraryElementVersionWithMeta(store db.DB) string {
+	return "SELECT lev.*, u1.login AS created_by_name, u1.email AS created_by_email" +
+		" FROM " + model.LibraryElementVersionTableName + " AS lev" +
+		" LEFT JOIN " + store.GetDialect().Quote("user") + " AS u1 ON lev.created_by = u1.id"
+}
+
+func diffLibraryElementModels(baseModel, newModel []byte) (*dashdiffs.PanelDiff, error) {
+	base, err := simplejson.NewJson(baseModel)
+	if err != nil {
+		return nil, err
+	}
+	next, err := simplejson.NewJson(newModel)
+	if err != nil {
+		return nil, err
+	}
+
+	// the reference to the library panel is added to the model when it is read, it is not part of the versions
+	base.Del("libraryPanel")
+	next.Del("libraryPanel")
+
+	return dashdiffs.CalculatePanelDiff(base, next), nil
+}
@@ -8,6 +8,7 @@ import (
 var (
 	ErrPlaylistNotFound        = errors.New("Playlist not found")
 	ErrCommandValidationFailed = errors.New("command missing required fields")
+	ErrInvalidSchedule         = errors.New("invalid playlist schedule")
 )
 
 // Playlist model
@@ -16,6 +17,7 @@ type Playlist struct {
 	UID      string `json:"uid" xorm:"uid" db:"uid"`
 	Name     string `json:"name" db:"name"`
 	Interval string `json:"interval" db:"interval"`
+	Timezone string `json:"timezone,omitempty" db:"timezone"`
 	OrgId    int64  `json:"-" db:"org_id"`
 
 	// Added for kubernetes migration + synchronization
@@ -36,6 +38,9 @@ type PlaylistDTO struct {
 	// Interval sets the time between switching views in a playlist.
 	Interval string `json:"interval"`
 
+	// Timezone of the active windows of the items, for example `Europe/Paris`. Defaults to UTC.
+	Timezone string `json:"timezone,omitempty" db:"timezone"`
+
 	// The ordered list of items that the playlist will iterate over.
 	Items []PlaylistItemDTO `json:"items,omitempty"`
 
@@ -68,15 +73,41 @@ type PlaylistItemDTO struct {
 	//  dashboards behind the tag will be added to the playlist.
 	//  - dashboard_by_uid: The value is the dashboard UID
 	Value string `json:"value"`
+
+	// Dwell is the time the item is shown, it overrides the interval of the playlist.
+	Dwell string `json:"dwell,omitempty"`
+
+	// TimeFrom and TimeTo override the time range of the dashboards, for example `now-24h` and `now`.
+	TimeFrom string `json:"timeFrom,omitempty"`
+	TimeTo   string `json:"timeTo,omitempty"`
+
+	// Variables override the values of the template variables of the dashboards, by variable name.
+	Variables map[string]string `json:"variables,omitempty"`
+
+	// ActiveFrom and ActiveTo are the time of day, formatted as `15:04`, between which the item is shown.
+	// The window can span midnight, for example from `20:00` to `08:00`. The item is always shown when empty.
+	ActiveFrom string `json:"activeFrom,omitempty"`
+	ActiveTo   string `json:"activeTo,omitempty"`
+
+	// ActiveDays are the days of the week, such as `mon` or `sat`, on which the item is shown. A window spanning
+	// midnight belongs to the day it starts. The item is shown every day when empty.
+	ActiveDays []string `json:"activeDays,omitempty"`
 }
 
 type PlaylistItem struct {
-	Id         int64  `db:"id"`
-	PlaylistId int64  `db:"playlist_id"`
-	Type       string `json:"type" db:"type"`
-	Value      string `json:"value" db:"value"`
-	Order      int    `json:"order" db:"order"`
-	Title      string `json:"title" db:"title"`
+	Id         int64             `db:"id"`
+	PlaylistId int64             `db:"playlist_id"`
+	Type       string            `json:"type" db:"type"`
+	Value      string            `json:"value" db:"value"`
+	Order      int               `json:"order" db:"order"`
+	Title      string            `json:"title" db:"title"`
+	Dwell      string            `json:"dwell,omitempty" db:"dwell"`
+	TimeFrom   string            `json:"timeFrom,omitempty" db:"time_from"`
+	TimeTo     string            `json:"timeTo,omitempty" db:"time_to"`
+	Variables  map[string]string `json:"variables,omitempty" db:"variables"`
+	ActiveFrom string            `json:"activeFrom,omitempty" db:"active_from"`
+	ActiveTo   string            `json:"activeTo,omitempty" db:"active_to"`
+	ActiveDays []string          `json:"activeDays,omitempty" db:"active_days"`
 }
 
 type Playlists []*Playlist
@@ -90,12 +121,14 @@ type UpdatePlaylistCommand struct {
 	UID      string         `json:"uid"`
 	Name     string         `json:"name" binding:"Required"`
 	Interval string         `json:"interval"`
+	Timezone string         `json:"timezone"`
 	Items    []PlaylistItem `json:"items"`
 }
 
 type CreatePlaylistCommand struct {
 	Name     string         `json:"name" binding:"Required"`
 	Interval string         `json:"interval"`
+	Timezone string         `json:"timezone"`
 	Items    []PlaylistItem `json:"items"`
 	OrgId    int64          `json:"-"`
 	// Used to create playlists from kubectl with a known uid/name
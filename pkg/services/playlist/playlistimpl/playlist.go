@@ -27,12 +27,18 @@ func ProvideService(db db.DB, tracer tracing.Tracer) playlist.Service {
 func (s *Service) Create(ctx context.Context, cmd *playlist.CreatePlaylistCommand) (*playlist.Playlist, error) {
 	ctx, span := s.tracer.Start(ctx, "playlists.Create")
 	defer span.End()
+	if err := playlist.ValidateSchedule(cmd.Interval, cmd.Timezone, cmd.Items); err != nil {
+		return nil, err
+	}
 	return s.store.Insert(ctx, cmd)
 }
 
 func (s *Service) Update(ctx context.Context, cmd *playlist.UpdatePlaylistCommand) (*playlist.PlaylistDTO, error) {
 	ctx, span := s.tracer.Start(ctx, "playlists.Update")
 	defer span.End()
+	if err := playlist.ValidateSchedule(cmd.Interval, cmd.Timezone, cmd.Items); err != nil {
+		return nil, err
+	}
 	return s.store.Update(ctx, cmd)
 }
 
@@ -60,6 +66,13 @@ func (s *Service) Get(ctx context.Context, q *playlist.GetPlaylistByUidQuery) (*
 	for i := 0; i < len(rawItems); i++ {
 		items[i].Type = rawItems[i].Type
 		items[i].Value = rawItems[i].Value
+		items[i].Dwell = rawItems[i].Dwell
+		items[i].TimeFrom = rawItems[i].TimeFrom
+		items[i].TimeTo = rawItems[i].TimeTo
+		items[i].Variables = rawItems[i].Variables
+		items[i].ActiveFrom = rawItems[i].ActiveFrom
+		items[i].ActiveTo = rawItems[i].ActiveTo
+		items[i].ActiveDays = rawItems[i].ActiveDays
 
 		// Add the unused title to the result
 		title := rawItems[i].Title
@@ -72,6 +85,7 @@ func (s *Service) Get(ctx context.Context, q *playlist.GetPlaylistByUidQuery) (*
 		Uid:       v.UID,
 		Name:      v.Name,
 		Interval:  v.Interval,
+		Timezone:  v.Timezone,
 		Items:     items,
 		CreatedAt: v.CreatedAt,
 		UpdatedAt: v.UpdatedAt,
@@ -72,6 +72,31 @@ func testIntegrationPlaylistDataAccess(t *testing.T, fn getStore) {
 			require.Greater(t, pl.UpdatedAt, pl.CreatedAt)
 		})
 
+		t.Run("Can update playlist schedule", func(t *testing.T) {
+			items := []playlist.PlaylistItem{
+				{Value: "ops", Type: "dashboard_by_tag", Dwell: "30s", ActiveFrom: "08:00", ActiveTo: "18:00", ActiveDays: []string{"mon", "fri"}},
+				{Value: "night", Type: "dashboard_by_uid", TimeFrom: "now-12h", TimeTo: "now", Variables: map[string]string{"env": "prod"}},
+			}
+			query := playlist.UpdatePlaylistCommand{Name: "NOC", OrgId: 1, UID: uid, Interval: "1m", Timezone: "Europe/Paris", Items: items}
+			_, err = playlistStore.Update(context.Background(), &query)
+			require.NoError(t, err)
+
+			pl, err := playlistStore.Get(context.Background(), &playlist.GetPlaylistByUidQuery{UID: uid, OrgId: 1})
+			require.NoError(t, err)
+			require.Equal(t, "Europe/Paris", pl.Timezone)
+
+			storedPlaylistItems, err := playlistStore.GetItems(context.Background(), &playlist.GetPlaylistItemsByUidQuery{PlaylistUID: uid, OrgId: 1})
+			require.NoError(t, err)
+			require.Len(t, storedPlaylistItems, 2)
+			require.Equal(t, "30s", storedPlaylistItems[0].Dwell)
+			require.Equal(t, "08:00", storedPlaylistItems[0].ActiveFrom)
+			require.Equal(t, "18:00", storedPlaylistItems[0].ActiveTo)
+			require.Equal(t, []string{"mon", "fri"}, storedPlaylistItems[0].ActiveDays)
+			require.Equal(t, "now-12h", storedPlaylistItems[1].TimeFrom)
+			require.Equal(t, "now", storedPlaylistItems[1].TimeTo)
+			require.Equal(t, map[string]string{"env": "prod"}, storedPlaylistItems[1].Variables)
+		})
+
 		t.Run("Can remove playlist", func(t *testing.T) {
 			deleteQuery := playlist.DeletePlaylistCommand{UID: uid, OrgId: 1}
 			err = playlistStore.Delete(context.Background(), &deleteQuery)
@@ -33,6 +33,7 @@ func (s *sqlStore) Insert(ctx context.Context, cmd *playlist.CreatePlaylistComma
 		p = playlist.Playlist{
 			Name:      cmd.Name,
 			Interval:  cmd.Interval,
+			Timezone:  cmd.Timezone,
 			OrgId:     cmd.OrgId,
 			UID:       cmd.UID,
 			CreatedAt: ts,
@@ -46,13 +47,10 @@ func (s *sqlStore) Insert(ctx context.Context, cmd *playlist.CreatePlaylistComma
 
 		playlistItems := make([]playlist.PlaylistItem, 0)
 		for order, item := range cmd.Items {
-			playlistItems = append(playlistItems, playlist.PlaylistItem{
-				PlaylistId: p.Id,
-				Type:       item.Type,
-				Value:      item.Value,
-				Order:      order + 1,
-				Title:      item.Title,
-			})
+			item.Id = 0
+			item.PlaylistId = p.Id
+			item.Order = order + 1
+			playlistItems = append(playlistItems, item)
 		}
 
 		_, err = sess.Insert(&playlistItems)
@@ -70,6 +68,7 @@ func (s *sqlStore) Update(ctx context.Context, cmd *playlist.UpdatePlaylistComma
 			OrgId:    cmd.OrgId,
 			Name:     cmd.Name,
 			Interval: cmd.Interval,
+			Timezone: cmd.Timezone,
 		}
 
 		existingPlaylist := playlist.Playlist{UID: cmd.UID, OrgId: cmd.OrgId}
@@ -85,9 +84,10 @@ func (s *sqlStore) Update(ctx context.Context, cmd *playlist.UpdatePlaylistComma
 			Uid:      p.UID,
 			Name:     p.Name,
 			Interval: p.Interval,
+			Timezone: p.Timezone,
 		}
 
-		_, err = sess.Where("id=?", p.Id).Cols("name", "interval", "updated_at").Update(&p)
+		_, err = sess.Where("id=?", p.Id).Cols("name", "interval", "timezone", "updated_at").Update(&p)
 		if err != nil {
 			return err
 		}
@@ -102,13 +102,10 @@ func (s *sqlStore) Update(ctx context.Context, cmd *playlist.UpdatePlaylistComma
 		playlistItems := make([]playlist.PlaylistItem, 0)
 
 		for index, item := range cmd.Items {
-			playlistItems = append(playlistItems, playlist.PlaylistItem{
-				PlaylistId: p.Id,
-				Type:       item.Type,
-				Value:      item.Value,
-				Order:      index + 1,
-				Title:      item.Title,
-			})
+			item.Id = 0
+			item.PlaylistId = p.Id
+			item.Order = index + 1
+			playlistItems = append(playlistItems, item)
 		}
 
 		_, err = sess.Insert(&playlistItems)
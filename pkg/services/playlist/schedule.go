@@ -0,0 +1,161 @@
+package playlist
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+)
+
+const activeTimeLayout = "15:04"
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ValidateSchedule returns ErrInvalidSchedule when the timezone, the dwell times or the active windows of the
+// items are invalid, or when an item has no dwell time and the interval of the playlist is not a valid dwell time.
+func ValidateSchedule(interval string, timezone string, items []PlaylistItem) error {
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return fmt.Errorf("%w: unknown timezone %q", ErrInvalidSchedule, timezone)
+	}
+
+	for i, item := range items {
+		if _, err := dwell(item.Dwell, interval); err != nil {
+			return fmt.Errorf("%w: item %d: %s", ErrInvalidSchedule, i+1, err)
+		}
+
+		if (item.ActiveFrom == "") != (item.ActiveTo == "") {
+			return fmt.Errorf("%w: item %d: both activeFrom and activeTo must be set", ErrInvalidSchedule, i+1)
+		}
+		for _, value := range []string{item.ActiveFrom, item.ActiveTo} {
+			if _, err := parseTimeOfDay(value); value != "" && err != nil {
+				return fmt.Errorf("%w: item %d: invalid time of day %q, expected HH:MM", ErrInvalidSchedule, i+1, value)
+			}
+		}
+		if item.ActiveFrom != "" && item.ActiveFrom == item.ActiveTo {
+			return fmt.Errorf("%w: item %d: activeFrom and activeTo must be different", ErrInvalidSchedule, i+1)
+		}
+
+		for _, day := range item.ActiveDays {
+			if _, ok := weekdays[strings.ToLower(day)]; !ok {
+				return fmt.Errorf("%w: item %d: invalid day %q", ErrInvalidSchedule, i+1, day)
+			}
+		}
+	}
+
+	return nil
+}
+
+// IsActive returns true when the item is shown at the given time, in the timezone of the playlist.
+func (item PlaylistItemDTO) IsActive(t time.Time, location *time.Location) bool {
+	t = t.In(location)
+	day := t.Weekday()
+
+	if item.ActiveFrom != "" && item.ActiveTo != "" {
+		from, errFrom := parseTimeOfDay(item.ActiveFrom)
+		to, errTo := parseTimeOfDay(item.ActiveTo)
+		if errFrom != nil || errTo != nil {
+			return false
+		}
+
+		now := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+		switch {
+		case from <= to:
+			if now < from || now >= to {
+				return false
+			}
+		case now >= from:
+			// in the window spanning midnight, on the day it starts
+		case now < to:
+			// in the window spanning midnight, on the day after it starts
+			day = (day + 6) % 7
+		default:
+			return false
+		}
+	}
+
+	if len(item.ActiveDays) == 0 {
+		return true
+	}
+	for _, d := range item.ActiveDays {
+		if weekday, ok := weekdays[strings.ToLower(d)]; ok && weekday == day {
+			return true
+		}
+	}
+	return false
+}
+
+// Slot is a dashboard shown by a playlist, for the dwell time of the item it comes from. Items
+// matching several dashboards, such as dashboard_by_tag items, are shown in one slot per dashboard.
+type Slot struct {
+	DashboardUID string
+	Item         PlaylistItemDTO
+	Dwell        time.Duration
+}
+
+// NewSlot returns the slot of a dashboard of the item, shown for the dwell time of the item or the playlist interval.
+func NewSlot(dashboardUID string, item PlaylistItemDTO, interval string) (Slot, error) {
+	d, err := dwell(item.Dwell, interval)
+	if err != nil {
+		return Slot{}, err
+	}
+	return Slot{DashboardUID: dashboardUID, Item: item, Dwell: d}, nil
+}
+
+// CurrentSlot returns the slot shown at the given time and when it started. The slots are shown in rotation since
+// the Unix epoch, so every display showing the playlist shows the same dashboard at the same time.
+func CurrentSlot(slots []Slot, now time.Time) (Slot, time.Time, bool) {
+	var cycle time.Duration
+	for _, slot := range slots {
+		cycle += slot.Dwell
+	}
+	if cycle <= 0 {
+		return Slot{}, time.Time{}, false
+	}
+
+	elapsed := time.Duration(now.UnixNano()) % cycle
+	start := now.Add(-elapsed)
+	for _, slot := range slots {
+		if elapsed < slot.Dwell {
+			return slot, start, true
+		}
+		elapsed -= slot.Dwell
+		start = start.Add(slot.Dwell)
+	}
+
+	return Slot{}, time.Time{}, false
+}
+
+func dwell(value string, interval string) (time.Duration, error) {
+	if value == "" {
+		value = interval
+	}
+	if value == "" {
+		return 0, fmt.Errorf("dwell time or playlist interval is required")
+	}
+
+	d, err := gtime.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid dwell time %q", value)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("dwell time %q must be positive", value)
+	}
+	return d, nil
+}
+
+func parseTimeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse(activeTimeLayout, value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
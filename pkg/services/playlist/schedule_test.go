@@ -0,0 +1,98 @@
+package playlist
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSchedule(t *testing.T) {
+	valid := []PlaylistItem{
+		{Type: "dashboard_by_uid", Value: "a", Dwell: "30s", ActiveFrom: "08:00", ActiveTo: "18:00", ActiveDays: []string{"mon", "Fri"}},
+		{Type: "dashboard_by_tag", Value: "night", ActiveFrom: "18:00", ActiveTo: "08:00"},
+	}
+	require.NoError(t, ValidateSchedule("5m", "Europe/Paris", valid))
+	require.NoError(t, ValidateSchedule("", "", nil))
+	require.NoError(t, ValidateSchedule("", "", valid[:1]))
+
+	invalid := map[string]struct {
+		interval string
+		timezone string
+		item     PlaylistItem
+	}{
+		"unknown timezone":        {timezone: "Mars/Olympus", item: PlaylistItem{Dwell: "1m"}},
+		"no dwell nor interval":   {item: PlaylistItem{Value: "a"}},
+		"invalid interval":        {interval: "often", item: PlaylistItem{Value: "a"}},
+		"invalid dwell":           {interval: "5m", item: PlaylistItem{Dwell: "often"}},
+		"window without end":      {interval: "5m", item: PlaylistItem{ActiveFrom: "08:00"}},
+		"invalid time of day":     {interval: "5m", item: PlaylistItem{ActiveFrom: "8h", ActiveTo: "18:00"}},
+		"empty window":            {interval: "5m", item: PlaylistItem{ActiveFrom: "08:00", ActiveTo: "08:00"}},
+		"invalid day":             {interval: "5m", item: PlaylistItem{ActiveDays: []string{"monday"}}},
+		"negative dwell":          {interval: "5m", item: PlaylistItem{Dwell: "-5m"}},
+		"window with end past 24": {interval: "5m", item: PlaylistItem{ActiveFrom: "08:00", ActiveTo: "25:00"}},
+	}
+	for name, tc := range invalid {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateSchedule(tc.interval, tc.timezone, []PlaylistItem{tc.item})
+			require.ErrorIs(t, err, ErrInvalidSchedule)
+		})
+	}
+}
+
+func TestPlaylistItemIsActive(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	// Monday 2024-03-04
+	at := func(day int, hour, minute int) time.Time {
+		return time.Date(2024, 3, 3+day, hour, minute, 0, 0, paris)
+	}
+
+	businessHours := PlaylistItemDTO{ActiveFrom: "08:00", ActiveTo: "18:00", ActiveDays: []string{"mon", "tue", "wed", "thu", "fri"}}
+	assert.True(t, businessHours.IsActive(at(1, 8, 0), paris))
+	assert.True(t, businessHours.IsActive(at(5, 17, 59), paris))
+	assert.False(t, businessHours.IsActive(at(1, 18, 0), paris))
+	assert.False(t, businessHours.IsActive(at(6, 12, 0), paris))
+	// the window is in the timezone of the playlist
+	assert.True(t, businessHours.IsActive(time.Date(2024, 3, 4, 7, 30, 0, 0, time.UTC), paris))
+	assert.False(t, businessHours.IsActive(time.Date(2024, 3, 4, 7, 30, 0, 0, time.UTC), time.UTC))
+
+	nights := PlaylistItemDTO{ActiveFrom: "18:00", ActiveTo: "08:00", ActiveDays: []string{"fri"}}
+	assert.True(t, nights.IsActive(at(5, 23, 0), paris))
+	// the window starting on friday ends on saturday morning
+	assert.True(t, nights.IsActive(at(6, 7, 0), paris))
+	assert.False(t, nights.IsActive(at(5, 7, 0), paris))
+	assert.False(t, nights.IsActive(at(6, 12, 0), paris))
+
+	always := PlaylistItemDTO{}
+	assert.True(t, always.IsActive(at(0, 3, 0), paris))
+}
+
+func TestCurrentSlot(t *testing.T) {
+	a, err := NewSlot("a", PlaylistItemDTO{Dwell: "1m"}, "5m")
+	require.NoError(t, err)
+	b, err := NewSlot("b", PlaylistItemDTO{}, "2m")
+	require.NoError(t, err)
+	slots := []Slot{a, b}
+
+	cycleStart := time.Unix(0, 0).Add(1000 * 3 * time.Minute)
+
+	slot, start, ok := CurrentSlot(slots, cycleStart.Add(30*time.Second))
+	require.True(t, ok)
+	assert.Equal(t, "a", slot.DashboardUID)
+	assert.True(t, cycleStart.Equal(start))
+
+	slot, start, ok = CurrentSlot(slots, cycleStart.Add(150*time.Second))
+	require.True(t, ok)
+	assert.Equal(t, "b", slot.DashboardUID)
+	assert.Equal(t, 2*time.Minute, slot.Dwell)
+	assert.True(t, cycleStart.Add(time.Minute).Equal(start))
+
+	_, _, ok = CurrentSlot(nil, cycleStart)
+	assert.False(t, ok)
+
+	_, err = NewSlot("c", PlaylistItemDTO{}, "")
+	require.Error(t, err)
+}
@@ -89,7 +89,7 @@ func (m *CachingMiddleware) QueryData(ctx context.Context, req *backend.QueryDat
 	}
 
 	// Cache miss; do the actual queries
-	resp, err := m.next.QueryData(ctx, req)
+	resp, err := m.queryMissing(ctx, req, cr)
 
 	// Update the query cache with the result for this metrics request
 	if err == nil && cr.UpdateCacheFn != nil {
@@ -117,6 +117,26 @@ func (m *CachingMiddleware) QueryData(ctx context.Context, req *backend.QueryDat
 	return resp, err
 }
 
+// queryMissing performs the queries that could not be answered from the cache. If the cache holds part of the
+// requested data, only the missing part is queried and merged with the cached data.
+func (m *CachingMiddleware) queryMissing(ctx context.Context, req *backend.QueryDataRequest, cr caching.CachedQueryDataResponse) (*backend.QueryDataResponse, error) {
+	if cr.IncrementalRequest == nil || cr.MergeFn == nil {
+		return m.next.QueryData(ctx, req)
+	}
+
+	partial, err := m.next.QueryData(ctx, cr.IncrementalRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := cr.MergeFn(ctx, partial)
+	if err != nil {
+		m.log.FromContext(ctx).Debug("Failed to merge cached data, querying the full time range", "error", err)
+		return m.next.QueryData(ctx, req)
+	}
+	return resp, nil
+}
+
 // CallResource receives a resource request and attempts to access results already stored in the cache for that request.
 // If data is found, it will return it immediately. Otherwise, it will perform the request as usual. The caller of CallResource is expected to explicitly update the cache with any responses.
 // If the cache service is implemented, we capture the request duration as a metric. The service is expected to write any response headers.
@@ -162,7 +182,11 @@ func (m *CachingMiddleware) CallResource(ctx context.Context, req *backend.CallR
 		return sender.Send(res)
 	})
 
-	return m.next.CallResource(ctx, req, cacheSender)
+	err := m.next.CallResource(ctx, req, cacheSender)
+	if err == nil && cr.CommitCacheFn != nil {
+		cr.CommitCacheFn(ctx)
+	}
+	return err
 }
 
 func (m *CachingMiddleware) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
@@ -3,6 +3,7 @@ package clientmiddleware
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"testing"
 
@@ -104,6 +105,55 @@ func TestCachingMiddleware(t *testing.T) {
 			assert.False(t, shouldCacheQueryCalled)
 		})
 
+		t.Run("If cache returns a partial response, the incremental request is issued and merged", func(t *testing.T) {
+			t.Cleanup(func() {
+				updateCacheCalled = false
+				cs.Reset()
+			})
+
+			incrementalReq := &backend.QueryDataRequest{PluginContext: pluginCtx}
+			mergedResp := &backend.QueryDataResponse{}
+			cs.ReturnHit = false
+			cs.ReturnQueryResponse = caching.CachedQueryDataResponse{
+				UpdateCacheFn: func(ctx context.Context, resp *backend.QueryDataResponse) {
+					assert.Same(t, mergedResp, resp)
+					updateCacheCalled = true
+				},
+				IncrementalRequest: incrementalReq,
+				MergeFn: func(ctx context.Context, resp *backend.QueryDataResponse) (*backend.QueryDataResponse, error) {
+					return mergedResp, nil
+				},
+			}
+
+			resp, err := cdt.Decorator.QueryData(req.Context(), qdr)
+			assert.NoError(t, err)
+			// Only the missing data was queried
+			assert.Same(t, incrementalReq, cdt.QueryDataReq)
+			assert.Same(t, mergedResp, resp)
+			assert.True(t, updateCacheCalled)
+		})
+
+		t.Run("If merging a partial response fails, the full request is issued", func(t *testing.T) {
+			t.Cleanup(func() {
+				updateCacheCalled = false
+				cs.Reset()
+			})
+
+			cs.ReturnHit = false
+			cs.ReturnQueryResponse = caching.CachedQueryDataResponse{
+				UpdateCacheFn:      dataResponse.UpdateCacheFn,
+				IncrementalRequest: &backend.QueryDataRequest{PluginContext: pluginCtx},
+				MergeFn: func(ctx context.Context, resp *backend.QueryDataResponse) (*backend.QueryDataResponse, error) {
+					return nil, errors.New("incompatible frames")
+				},
+			}
+
+			_, err := cdt.Decorator.QueryData(req.Context(), qdr)
+			assert.NoError(t, err)
+			assert.Same(t, qdr, cdt.QueryDataReq)
+			assert.True(t, updateCacheCalled)
+		})
+
 		t.Run("with async queries", func(t *testing.T) {
 			asyncCdt := clienttest.NewClientDecoratorTest(t,
 				clienttest.WithReqContext(req, &user.SignedInUser{}),
@@ -178,7 +228,7 @@ func TestCachingMiddleware(t *testing.T) {
 
 		// This is the response returned by the HandleResourceRequest call
 		// Track whether the update cache fn was called, depending on what the response headers are in the cache request
-		var updateCacheCalled bool
+		var updateCacheCalled, commitCacheCalled bool
 		dataResponse := caching.CachedResourceDataResponse{
 			Response: &backend.CallResourceResponse{
 				Status: 200,
@@ -187,6 +237,9 @@ func TestCachingMiddleware(t *testing.T) {
 			UpdateCacheFn: func(ctx context.Context, rdr *backend.CallResourceResponse) {
 				updateCacheCalled = true
 			},
+			CommitCacheFn: func(ctx context.Context) {
+				commitCacheCalled = true
+			},
 		}
 
 		// This is the response sent via the passed-in sender when there is a cache miss
@@ -244,6 +297,7 @@ func TestCachingMiddleware(t *testing.T) {
 			assert.Equal(t, dataResponse.Response, sentResponse)
 			// Cache was not updated by the middleware
 			assert.False(t, updateCacheCalled)
+			assert.False(t, commitCacheCalled)
 		})
 
 		t.Run("If cache returns a miss, resource call is issued and the update cache function is called", func(t *testing.T) {
@@ -262,8 +316,9 @@ func TestCachingMiddleware(t *testing.T) {
 			// Simulated plugin response was sent
 			assert.NotNil(t, sentResponse)
 			assert.Equal(t, simulatedPluginResponse, sentResponse)
-			// Since it was a miss, the middleware called the update func
+			// Since it was a miss, the middleware called the update func, then committed the responses
 			assert.True(t, updateCacheCalled)
+			assert.True(t, commitCacheCalled)
 		})
 	})
 
@@ -7,6 +7,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboards/lint"
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/provisioning/utils"
@@ -21,10 +22,12 @@ type DashboardProvisioner interface {
 	GetProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
 	CleanUpOrphanedDashboards(ctx context.Context)
+	WriteDashboard(ctx context.Context, change *DashboardChange) error
+	PushesDashboards(name string) bool
 }
 
 // DashboardProvisionerFactory creates DashboardProvisioners based on input
-type DashboardProvisionerFactory func(context.Context, string, dashboards.DashboardProvisioningService, org.Service, utils.DashboardStore, folder.Service) (DashboardProvisioner, error)
+type DashboardProvisionerFactory func(context.Context, string, string, dashboards.DashboardProvisioningService, org.Service, utils.DashboardStore, folder.Service, *lint.Linter) (DashboardProvisioner, error)
 
 // Provisioner is responsible for syncing dashboard from disk to Grafana's database.
 type Provisioner struct {
@@ -39,19 +42,26 @@ func (provider *Provisioner) HasDashboardSources() bool {
 	return len(provider.fileReaders) > 0
 }
 
-// New returns a new DashboardProvisioner
-func New(ctx context.Context, configDirectory string, provisioner dashboards.DashboardProvisioningService, orgService org.Service, dashboardStore utils.DashboardStore, folderService folder.Service) (DashboardProvisioner, error) {
+// New returns a new DashboardProvisioner. Dashboards with lint errors are not provisioned when linter is not nil.
+// Repositories of git providers are cloned in dataPath unless their configuration sets another location.
+func New(ctx context.Context, configDirectory string, dataPath string, provisioner dashboards.DashboardProvisioningService, orgService org.Service, dashboardStore utils.DashboardStore, folderService folder.Service, linter *lint.Linter) (DashboardProvisioner, error) {
 	logger := log.New("provisioning.dashboard")
 	cfgReader := &configReader{path: configDirectory, log: logger, orgService: orgService}
 	configs, err := cfgReader.readConfig(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("%v: %w", "Failed to read dashboards config", err)
 	}
+	for _, cfg := range configs {
+		cfg.DataPath = dataPath
+	}
 
 	fileReaders, err := getFileReaders(configs, logger, provisioner, dashboardStore, folderService)
 	if err != nil {
 		return nil, fmt.Errorf("%v: %w", "Failed to initialize file readers", err)
 	}
+	for _, reader := range fileReaders {
+		reader.linter = linter
+	}
 
 	d := &Provisioner{
 		log:                logger,
@@ -130,6 +140,31 @@ func (provider *Provisioner) GetAllowUIUpdatesFromConfig(name string) bool {
 	return false
 }
 
+// WriteDashboard writes a provisioned dashboard saved in Grafana back to its provisioning source. It does nothing
+// unless the dashboard is provisioned from a git repository with the pushChanges option.
+func (provider *Provisioner) WriteDashboard(ctx context.Context, change *DashboardChange) error {
+	for _, reader := range provider.fileReaders {
+		if reader.Cfg.Name == change.Provisioning.Name {
+			if !reader.pushesDashboards() {
+				return nil
+			}
+			return reader.writeDashboard(ctx, change)
+		}
+	}
+	return nil
+}
+
+// PushesDashboards returns if a dashboard provisioner writes the dashboards saved in Grafana back to its
+// provisioning source
+func (provider *Provisioner) PushesDashboards(name string) bool {
+	for _, reader := range provider.fileReaders {
+		if reader.Cfg.Name == name {
+			return reader.pushesDashboards()
+		}
+	}
+	return false
+}
+
 func getFileReaders(
 	configs []*config,
 	logger log.Logger,
@@ -153,6 +188,18 @@ func getFileReaders(
 				return nil, fmt.Errorf("failed to create file reader for config %v: %w", config.Name, err)
 			}
 			readers = append(readers, fileReader)
+		case "git":
+			gitReader, err := NewDashboardGitReader(
+				config,
+				logger.New("type", config.Type, "name", config.Name),
+				service,
+				store,
+				folderService,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create git reader for config %v: %w", config.Name, err)
+			}
+			readers = append(readers, gitReader)
 		default:
 			return nil, fmt.Errorf("type %s is not supported", config.Type)
 		}
@@ -8,6 +8,8 @@ type calls struct {
 	PollChanges                 []any
 	GetProvisionerResolvedPath  []any
 	GetAllowUIUpdatesFromConfig []any
+	WriteDashboard              []any
+	PushesDashboards            []any
 }
 
 // ProvisionerMock is a mock implementation of `Provisioner`
@@ -17,6 +19,8 @@ type ProvisionerMock struct {
 	PollChangesFunc                 func(ctx context.Context)
 	GetProvisionerResolvedPathFunc  func(name string) string
 	GetAllowUIUpdatesFromConfigFunc func(name string) bool
+	WriteDashboardFunc              func(ctx context.Context, change *DashboardChange) error
+	PushesDashboardsFunc            func(name string) bool
 }
 
 // NewDashboardProvisionerMock returns a new dashboardprovisionermock
@@ -67,3 +71,21 @@ func (dpm *ProvisionerMock) GetAllowUIUpdatesFromConfig(name string) bool {
 
 // CleanUpOrphanedDashboards not implemented for mocks
 func (dpm *ProvisionerMock) CleanUpOrphanedDashboards(ctx context.Context) {}
+
+// WriteDashboard is a mock implementation of `Provisioner.WriteDashboard`
+func (dpm *ProvisionerMock) WriteDashboard(ctx context.Context, change *DashboardChange) error {
+	dpm.Calls.WriteDashboard = append(dpm.Calls.WriteDashboard, change)
+	if dpm.WriteDashboardFunc != nil {
+		return dpm.WriteDashboardFunc(ctx, change)
+	}
+	return nil
+}
+
+// PushesDashboards is a mock implementation of `Provisioner.PushesDashboards`
+func (dpm *ProvisionerMock) PushesDashboards(name string) bool {
+	dpm.Calls.PushesDashboards = append(dpm.Calls.PushesDashboards, name)
+	if dpm.PushesDashboardsFunc != nil {
+		return dpm.PushesDashboardsFunc(name)
+	}
+	return false
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboards/lint"
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/provisioning/utils"
 	"github.com/grafana/grafana/pkg/util"
@@ -41,6 +42,19 @@ type FileReader struct {
 	mux                     sync.RWMutex
 	usageTracker            *usageTracker
 	dbWriteAccessRestricted bool
+
+	// repo is set when the dashboards are read from a git repository
+	repo        *gitRepository
+	pushChanges bool
+	// pushRepo is the clone the dashboards saved from the UI are pushed from, set when pushChanges is enabled
+	pushRepo *gitRepository
+	pushPath string
+	// pushedCheckSums are the checksums of the files pushed to the repository, by path, which are already saved
+	// in the database. It is guarded by mux.
+	pushedCheckSums map[string]string
+
+	// linter is set when the dashboards are linted before they are saved, dashboards with lint errors are skipped
+	linter *lint.Linter
 }
 
 // NewDashboardFileReader returns a new filereader based on `config`
@@ -92,6 +106,13 @@ func (fr *FileReader) pollChanges(ctx context.Context) {
 // walkDisk traverses the file system for the defined path, reading dashboard definition files,
 // and applies any change to the database.
 func (fr *FileReader) walkDisk(ctx context.Context) error {
+	if fr.repo != nil {
+		// keep provisioning from the existing clone when the remote can't be reached
+		if err := fr.syncRepository(ctx); err != nil {
+			fr.log.Error("failed to sync git repository", "url", fr.repo.url, "error", err)
+		}
+	}
+
 	fr.log.Debug("Start walking disk", "path", fr.Path)
 	resolvedPath := fr.resolvedPath()
 	if _, err := os.Stat(resolvedPath); err != nil {
@@ -242,7 +263,7 @@ func (fr *FileReader) saveDashboard(ctx context.Context, path string, folderID i
 
 	upToDate := alreadyProvisioned
 	if provisionedData != nil {
-		upToDate = jsonFile.checkSum == provisionedData.CheckSum
+		upToDate = jsonFile.checkSum == provisionedData.CheckSum || jsonFile.checkSum == fr.pushedCheckSum(path)
 	}
 
 	// keeps track of which UIDs and titles we have already provisioned
@@ -284,6 +305,11 @@ func (fr *FileReader) saveDashboard(ctx context.Context, path string, folderID i
 		return provisioningMetadata, nil
 	}
 
+	if findings := lintDashboard(fr.linter, dash.Dashboard.Data); len(findings) > 0 {
+		fr.log.Error("dashboard has lint errors and is not provisioned", "file", path, "errors", findings)
+		return provisioningMetadata, nil
+	}
+
 	if dash.Dashboard.ID != 0 {
 		dash.Dashboard.Data.Set("id", nil)
 		dash.Dashboard.ID = 0
@@ -307,6 +333,7 @@ func (fr *FileReader) saveDashboard(ctx context.Context, path string, folderID i
 		if err != nil {
 			return provisioningMetadata, err
 		}
+		fr.setPushedCheckSum(path, "")
 	} else {
 		metrics.MFolderIDsServiceCount.WithLabelValues(metrics.Provisioning).Inc()
 		// nolint:staticcheck
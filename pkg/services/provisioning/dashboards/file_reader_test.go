@@ -14,6 +14,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboards/lint"
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -147,6 +148,27 @@ func TestDashboardFileReader(t *testing.T) {
 			assert.Equal(t, inserted, 1)
 		})
 
+		t.Run("Dashboard with lint errors will not be saved", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = oneDashboard
+
+			linter, err := lint.New(lint.Config{Severities: map[string]lint.Severity{"schema-version": lint.SeverityError}})
+			require.NoError(t, err)
+
+			lintService := &dashboards.FakeDashboardProvisioning{}
+			lintService.On("GetProvisionedDashboardData", mock.Anything, configName).Return(nil, nil).Once()
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore, nil)
+			reader.dashboardProvisioningService = lintService
+			reader.linter = linter
+			require.NoError(t, err)
+
+			err = reader.walkDisk(context.Background())
+			require.NoError(t, err)
+
+			lintService.AssertNotCalled(t, "SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything)
+		})
+
 		t.Run("Dashboard with older timestamp and the same checksum will not replace imported dashboard", func(t *testing.T) {
 			setup()
 			cfg.Options["path"] = oneDashboard
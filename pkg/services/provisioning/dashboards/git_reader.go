@@ -0,0 +1,211 @@
+package dashboards
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// DashboardChange is a change of a provisioned dashboard saved in Grafana, which is written back
+// to the provisioning source of the dashboard.
+type DashboardChange struct {
+	Provisioning *dashboards.DashboardProvisioning
+	Dashboard    *simplejson.Json
+	Message      string
+	AuthorName   string
+	AuthorEmail  string
+}
+
+// NewDashboardGitReader returns a new filereader reading the dashboards of the git repository configured in `config`.
+func NewDashboardGitReader(cfg *config, log log.Logger, service dashboards.DashboardProvisioningService,
+	dashboardStore utils.DashboardStore, folderService folder.Service) (*FileReader, error) {
+	repo, err := newGitRepository(cfg, log)
+	if err != nil {
+		return nil, err
+	}
+
+	// path is optional for git repositories, the dashboards are read from the root of the repository by default
+	path, _ := cfg.Options["path"].(string)
+	if filepath.IsAbs(path) || strings.HasPrefix(filepath.Clean(path), "..") {
+		return nil, fmt.Errorf("path param must be relative to the root of the git repository")
+	}
+
+	pushChanges, _ := cfg.Options["pushChanges"].(bool)
+	if pushChanges && !cfg.AllowUIUpdates {
+		return nil, fmt.Errorf("'pushChanges' option requires 'allowUiUpdates' to be enabled")
+	}
+
+	foldersFromFilesStructure, _ := cfg.Options["foldersFromFilesStructure"].(bool)
+	if foldersFromFilesStructure && cfg.Folder != "" && cfg.FolderUID != "" {
+		return nil, fmt.Errorf("'folder' and 'folderUID' should be empty using 'foldersFromFilesStructure' option")
+	}
+
+	var pushRepo *gitRepository
+	var pushPath string
+	if pushChanges {
+		pushRepo = repo.pushClone()
+		pushPath = filepath.Join(pushRepo.dir, path)
+	}
+
+	return &FileReader{
+		Cfg:                          cfg,
+		Path:                         filepath.Join(repo.dir, path),
+		log:                          log,
+		dashboardProvisioningService: service,
+		dashboardStore:               dashboardStore,
+		folderService:                folderService,
+		FoldersFromFilesStructure:    foldersFromFilesStructure,
+		usageTracker:                 newUsageTracker(),
+		repo:                         repo,
+		pushChanges:                  pushChanges,
+		pushRepo:                     pushRepo,
+		pushPath:                     pushPath,
+		pushedCheckSums:              map[string]string{},
+	}, nil
+}
+
+// pushesDashboards returns if the dashboards saved in Grafana are pushed to the git repository.
+func (fr *FileReader) pushesDashboards() bool {
+	return fr.repo != nil && fr.pushChanges
+}
+
+// syncRepository updates the clone the dashboards are provisioned from. The repository is only locked while
+// it is synced, the dashboards are saved in the database afterwards without holding the lock.
+func (fr *FileReader) syncRepository(ctx context.Context) error {
+	fr.repo.mux.Lock()
+	defer fr.repo.mux.Unlock()
+
+	return fr.repo.sync(ctx)
+}
+
+// writeDashboard commits the dashboard to the file it was provisioned from and pushes it to the git repository.
+// ErrDashboardConflict is returned when the file was changed in the repository since it was provisioned.
+//
+// The dashboard is committed in a separate clone, the clone the dashboards are provisioned from only gets the
+// change the next time it is synced.
+func (fr *FileReader) writeDashboard(ctx context.Context, change *DashboardChange) error {
+	if fr.pushRepo == nil {
+		return fmt.Errorf("pushing dashboards is not enabled for provider %q", fr.Cfg.Name)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, gitPushTimeout)
+	defer cancel()
+
+	fr.pushRepo.mux.Lock()
+	defer fr.pushRepo.mux.Unlock()
+
+	provisionedPath := change.Provisioning.ExternalID
+	rel, err := filepath.Rel(fr.resolvedPath(), provisionedPath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return fmt.Errorf("dashboard file %q is not in the git repository", provisionedPath)
+	}
+	path := filepath.Join(fr.pushPath, rel)
+
+	if err := fr.pushRepo.sync(ctx); err != nil {
+		return err
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `path` is in the git repository of the provisioner.
+	current, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%w: %s was removed", ErrDashboardConflict, rel)
+		}
+		return err
+	}
+
+	currentCheckSum, err := util.Md5SumString(string(current))
+	if err != nil {
+		return err
+	}
+	expectedCheckSum := change.Provisioning.CheckSum
+	if pushed := fr.pushedCheckSum(provisionedPath); pushed != "" {
+		expectedCheckSum = pushed
+	}
+	if currentCheckSum != expectedCheckSum {
+		return fmt.Errorf("%w: %s was updated", ErrDashboardConflict, rel)
+	}
+
+	content, err := marshalDashboard(change.Dashboard)
+	if err != nil {
+		return err
+	}
+	checkSum, err := util.Md5SumString(string(content))
+	if err != nil {
+		return err
+	}
+	if checkSum == currentCheckSum {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, content, info.Mode()); err != nil {
+		return err
+	}
+
+	message := change.Message
+	if message == "" {
+		message = fmt.Sprintf("Update dashboard %s", change.Dashboard.Get("title").MustString())
+	}
+	if err := fr.pushRepo.commitAndPush(ctx, message, change.AuthorName, change.AuthorEmail); err != nil {
+		return err
+	}
+
+	fr.log.Info("pushed dashboard to git repository", "file", rel, "author", change.AuthorName)
+	fr.setPushedCheckSum(provisionedPath, checkSum)
+	return nil
+}
+
+// pushedCheckSum returns the checksum of the file pushed to the repository at path, empty if it wasn't pushed.
+func (fr *FileReader) pushedCheckSum(path string) string {
+	fr.mux.RLock()
+	defer fr.mux.RUnlock()
+
+	return fr.pushedCheckSums[path]
+}
+
+// setPushedCheckSum records the checksum of the file pushed to the repository at path, an empty checksum
+// forgets it.
+func (fr *FileReader) setPushedCheckSum(path, checkSum string) {
+	fr.mux.Lock()
+	defer fr.mux.Unlock()
+
+	if checkSum == "" {
+		delete(fr.pushedCheckSums, path)
+		return
+	}
+	fr.pushedCheckSums[path] = checkSum
+}
+
+// marshalDashboard returns the dashboard as written in provisioning files, without the fields set by Grafana
+// when the dashboard is saved.
+func marshalDashboard(dashboard *simplejson.Json) ([]byte, error) {
+	data := make(map[string]any, len(dashboard.MustMap()))
+	for key, value := range dashboard.MustMap() {
+		if key == "id" || key == "version" {
+			continue
+		}
+		data[key] = value
+	}
+
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(content, '\n'), nil
+}
@@ -0,0 +1,253 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+)
+
+const gitDashboard = `{"title": "API", "uid": "api", "panels": []}`
+
+func TestDashboardGitReader(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	logger := log.New("test-logger")
+
+	setup := func(t *testing.T) (*config, string) {
+		remote := filepath.Join(t.TempDir(), "remote.git")
+		runGit(t, "", "init", "--quiet", "--bare", "--initial-branch", "main", remote)
+
+		work := cloneRemote(t, remote)
+		require.NoError(t, os.MkdirAll(filepath.Join(work, "dashboards", "Team A"), 0750))
+		require.NoError(t, os.WriteFile(filepath.Join(work, "dashboards", "Team A", "api.json"), []byte(gitDashboard), 0600))
+		runGit(t, work, "add", ".")
+		runGit(t, work, "commit", "--quiet", "--message", "Add dashboards")
+		runGit(t, work, "push", "--quiet", "origin", "HEAD:main")
+
+		return &config{
+			Name:           configName,
+			Type:           "git",
+			OrgID:          1,
+			AllowUIUpdates: true,
+			Options: map[string]any{
+				"url":                       "file://" + remote,
+				"path":                      "dashboards",
+				"clonePath":                 filepath.Join(t.TempDir(), "clone"),
+				"foldersFromFilesStructure": true,
+				"pushChanges":               true,
+			},
+		}, remote
+	}
+
+	// newFakeService returns a provisioning service recording the provisioning data of the saved dashboards
+	newFakeService := func() (*dashboards.FakeDashboardProvisioning, *[]*dashboards.DashboardProvisioning) {
+		saved := []*dashboards.DashboardProvisioning{}
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		fakeService.On("SaveFolderForProvisionedDashboards", mock.Anything, mock.Anything).Return(&folder.Folder{ID: 1, UID: "team-a"}, nil)
+		fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			saved = append(saved, args.Get(2).(*dashboards.DashboardProvisioning))
+		}).Return(&dashboards.Dashboard{ID: 2}, nil)
+		return fakeService, &saved
+	}
+
+	// provision reads the dashboards of the repository and returns the provisioning data of the saved dashboard
+	provision := func(t *testing.T, reader *FileReader, fakeService *dashboards.FakeDashboardProvisioning,
+		saved *[]*dashboards.DashboardProvisioning, provisioned ...*dashboards.DashboardProvisioning) *dashboards.DashboardProvisioning {
+		count := len(*saved)
+		fakeService.On("GetProvisionedDashboardData", mock.Anything, configName).Return(provisioned, nil).Once()
+
+		require.NoError(t, reader.walkDisk(context.Background()))
+		if len(*saved) == count {
+			return nil
+		}
+		return (*saved)[len(*saved)-1]
+	}
+
+	t.Run("Can read dashboards from a git repository", func(t *testing.T) {
+		cfg, _ := setup(t)
+		fakeService, savedDashboards := newFakeService()
+
+		reader, err := NewDashboardGitReader(cfg, logger, fakeService, &fakeDashboardStore{}, nil)
+		require.NoError(t, err)
+
+		saved := provision(t, reader, fakeService, savedDashboards)
+		require.NotNil(t, saved)
+		assert.Equal(t, filepath.Join("Team A", "api.json"), relativePath(t, reader, saved.ExternalID))
+		fakeService.AssertCalled(t, "SaveFolderForProvisionedDashboards", mock.Anything, mock.MatchedBy(func(cmd *folder.CreateFolderCommand) bool {
+			return cmd.Title == "Team A"
+		}))
+	})
+
+	t.Run("Pulls the changes of the repository", func(t *testing.T) {
+		cfg, remote := setup(t)
+		fakeService, savedDashboards := newFakeService()
+
+		reader, err := NewDashboardGitReader(cfg, logger, fakeService, &fakeDashboardStore{}, nil)
+		require.NoError(t, err)
+		saved := provision(t, reader, fakeService, savedDashboards)
+		saved.DashboardID = 2
+
+		work := cloneRemote(t, remote)
+		require.NoError(t, os.WriteFile(filepath.Join(work, "dashboards", "Team A", "api.json"), []byte(`{"title": "API v2", "uid": "api"}`), 0600))
+		runGit(t, work, "commit", "--quiet", "--all", "--message", "Rename dashboard")
+		runGit(t, work, "push", "--quiet", "origin", "HEAD:main")
+
+		checkSum := saved.CheckSum
+		updated := provision(t, reader, fakeService, savedDashboards, saved)
+		require.NotNil(t, updated)
+		assert.NotEqual(t, checkSum, updated.CheckSum)
+	})
+
+	t.Run("Pushes the dashboards saved in Grafana with the editor as author", func(t *testing.T) {
+		cfg, remote := setup(t)
+		fakeService, savedDashboards := newFakeService()
+
+		reader, err := NewDashboardGitReader(cfg, logger, fakeService, &fakeDashboardStore{}, nil)
+		require.NoError(t, err)
+		saved := provision(t, reader, fakeService, savedDashboards)
+
+		dashboard := simplejson.NewFromAny(map[string]any{"id": 2, "version": 4, "title": "API", "uid": "api", "refresh": "1m"})
+		change := &DashboardChange{Provisioning: saved, Dashboard: dashboard, AuthorName: "Editor", AuthorEmail: "editor@example.com"}
+		require.NoError(t, reader.writeDashboard(context.Background(), change))
+
+		work := cloneRemote(t, remote)
+		assert.Equal(t, "Editor <editor@example.com>|Update dashboard API", runGit(t, work, "log", "-1", "--format=%an <%ae>|%s"))
+		content, err := os.ReadFile(filepath.Join(work, "dashboards", "Team A", "api.json"))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"title": "API", "uid": "api", "refresh": "1m"}`, string(content))
+
+		// the pushed dashboard is already saved in Grafana so it's not provisioned again
+		saved.DashboardID = 2
+		assert.Nil(t, provision(t, reader, fakeService, savedDashboards, saved))
+
+		// and can be saved again
+		dashboard.Set("refresh", "5m")
+		require.NoError(t, reader.writeDashboard(context.Background(), change))
+	})
+
+	t.Run("Pushes the dashboards while the repository is provisioned", func(t *testing.T) {
+		cfg, _ := setup(t)
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		fakeService.On("SaveFolderForProvisionedDashboards", mock.Anything, mock.Anything).Return(&folder.Folder{ID: 1, UID: "team-a"}, nil)
+		fakeService.On("GetProvisionedDashboardData", mock.Anything, configName).Return(nil, nil)
+
+		reader, err := NewDashboardGitReader(cfg, logger, fakeService, &fakeDashboardStore{}, nil)
+		require.NoError(t, err)
+
+		var pushErr error
+		fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			dashboard := simplejson.NewFromAny(map[string]any{"title": "API", "uid": "api", "refresh": "1m"})
+			change := &DashboardChange{Provisioning: args.Get(2).(*dashboards.DashboardProvisioning), Dashboard: dashboard, AuthorName: "Editor"}
+
+			done := make(chan error, 1)
+			go func() { done <- reader.writeDashboard(context.Background(), change) }()
+			select {
+			case pushErr = <-done:
+			case <-time.After(gitPushTimeout):
+				pushErr = errors.New("the push is blocked by the provisioning of the repository")
+			}
+		}).Return(&dashboards.Dashboard{ID: 2}, nil)
+
+		require.NoError(t, reader.walkDisk(context.Background()))
+		require.NoError(t, pushErr)
+	})
+
+	t.Run("Returns a conflict when the dashboard was changed in the repository", func(t *testing.T) {
+		cfg, remote := setup(t)
+		fakeService, savedDashboards := newFakeService()
+
+		reader, err := NewDashboardGitReader(cfg, logger, fakeService, &fakeDashboardStore{}, nil)
+		require.NoError(t, err)
+		saved := provision(t, reader, fakeService, savedDashboards)
+
+		work := cloneRemote(t, remote)
+		require.NoError(t, os.WriteFile(filepath.Join(work, "dashboards", "Team A", "api.json"), []byte(`{"title": "API v2", "uid": "api"}`), 0600))
+		runGit(t, work, "commit", "--quiet", "--all", "--message", "Rename dashboard")
+		runGit(t, work, "push", "--quiet", "origin", "HEAD:main")
+
+		dashboard := simplejson.NewFromAny(map[string]any{"title": "API", "uid": "api", "refresh": "1m"})
+		err = reader.writeDashboard(context.Background(), &DashboardChange{Provisioning: saved, Dashboard: dashboard, AuthorName: "Editor"})
+		require.ErrorIs(t, err, ErrDashboardConflict)
+
+		assert.Equal(t, "Rename dashboard", runGit(t, cloneRemote(t, remote), "log", "-1", "--format=%s"))
+	})
+
+	t.Run("Validates the options", func(t *testing.T) {
+		cfg, _ := setup(t)
+		cfg.AllowUIUpdates = false
+		_, err := NewDashboardGitReader(cfg, logger, nil, nil, nil)
+		require.Error(t, err)
+
+		cfg, _ = setup(t)
+		cfg.Options["path"] = "../dashboards"
+		_, err = NewDashboardGitReader(cfg, logger, nil, nil, nil)
+		require.Error(t, err)
+
+		cfg, _ = setup(t)
+		delete(cfg.Options, "url")
+		_, err = NewDashboardGitReader(cfg, logger, nil, nil, nil)
+		require.Error(t, err)
+
+		for _, name := range []string{"../dashboards", "team/dashboards", `team\dashboards`, ".."} {
+			cfg, _ = setup(t)
+			delete(cfg.Options, "clonePath")
+			cfg.DataPath = t.TempDir()
+			cfg.Name = name
+			_, err = NewDashboardGitReader(cfg, logger, nil, nil, nil)
+			require.Error(t, err, name)
+		}
+	})
+
+	t.Run("Clones the repository in the data directory by default", func(t *testing.T) {
+		cfg, _ := setup(t)
+		delete(cfg.Options, "clonePath")
+		cfg.DataPath = t.TempDir()
+
+		repo, err := newGitRepository(cfg, logger)
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(cfg.DataPath, "provisioning", "git", "1-"+configName), repo.dir)
+	})
+}
+
+func cloneRemote(t *testing.T, remote string) string {
+	t.Helper()
+	work := filepath.Join(t.TempDir(), "work")
+	runGit(t, "", "clone", "--quiet", "file://"+remote, work)
+	return work
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com",
+	)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	return strings.TrimSpace(string(out))
+}
+
+func relativePath(t *testing.T, reader *FileReader, path string) string {
+	t.Helper()
+	rel, err := filepath.Rel(reader.resolvedPath(), path)
+	require.NoError(t, err)
+	return rel
+}
@@ -0,0 +1,157 @@
+package dashboards
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+const (
+	defaultGitBranch = "main"
+	gitCommitterName = "Grafana"
+	gitCommitterMail = "grafana@localhost"
+	// gitPushTimeout bounds the sync, commit and push of a dashboard saved from the UI
+	gitPushTimeout = 30 * time.Second
+)
+
+var (
+	// ErrDashboardConflict is returned when a dashboard saved in Grafana was also changed in its git repository.
+	ErrDashboardConflict = errors.New("the dashboard was changed in the git repository")
+)
+
+// gitRepository is the local clone of the repository a git provisioner reads dashboards from.
+// Commands are run with the git binary, so any remote supported by git can be used including file:// remotes.
+type gitRepository struct {
+	url    string
+	branch string
+	dir    string
+	log    log.Logger
+
+	mux sync.Mutex
+}
+
+func newGitRepository(cfg *config, log log.Logger) (*gitRepository, error) {
+	url, ok := cfg.Options["url"].(string)
+	if !ok || url == "" {
+		return nil, fmt.Errorf("failed to load dashboards, url param is not a string")
+	}
+
+	branch, _ := cfg.Options["branch"].(string)
+	if branch == "" {
+		branch = defaultGitBranch
+	}
+
+	dir, _ := cfg.Options["clonePath"].(string)
+	if dir == "" {
+		// the provider name is part of the default location, it must not point outside of the data directory
+		if cfg.Name == "" || cfg.Name == "." || cfg.Name == ".." || strings.ContainsAny(cfg.Name, `/\`) {
+			return nil, fmt.Errorf("provider name %q cannot be used in the clone path, set the clonePath param", cfg.Name)
+		}
+		if cfg.DataPath == "" {
+			return nil, fmt.Errorf("failed to load dashboards, clonePath param is required")
+		}
+		dir = filepath.Join(cfg.DataPath, "provisioning", "git", fmt.Sprintf("%d-%s", cfg.OrgID, cfg.Name))
+	}
+
+	return &gitRepository{
+		url:    url,
+		branch: branch,
+		dir:    dir,
+		log:    log,
+	}, nil
+}
+
+// pushClone returns a second clone of the repository, next to this one, where the dashboards saved from the UI
+// are committed, so that pushes never change the files being provisioned.
+func (r *gitRepository) pushClone() *gitRepository {
+	return &gitRepository{
+		url:    r.url,
+		branch: r.branch,
+		dir:    r.dir + "-push",
+		log:    r.log,
+	}
+}
+
+// sync clones the repository, or resets the clone to the latest commit of the branch when it already exists.
+func (r *gitRepository) sync(ctx context.Context) error {
+	if _, err := os.Stat(filepath.Join(r.dir, ".git")); err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+
+		r.log.Info("cloning git repository", "url", r.url, "branch", r.branch, "path", r.dir)
+		if err := os.MkdirAll(filepath.Dir(r.dir), 0750); err != nil {
+			return err
+		}
+		_, err := r.run(ctx, "", "clone", "--quiet", "--single-branch", "--branch", r.branch, r.url, r.dir)
+		return err
+	}
+
+	if _, err := r.run(ctx, r.dir, "fetch", "--quiet", "origin", r.branch); err != nil {
+		return err
+	}
+	_, err := r.run(ctx, r.dir, "reset", "--quiet", "--hard", "origin/"+r.branch)
+	return err
+}
+
+// commitAndPush commits the changes of the tracked files with the given author and pushes them to the branch.
+// The clone is reset to the branch when the push fails, ErrDashboardConflict is returned when the branch has
+// new commits.
+func (r *gitRepository) commitAndPush(ctx context.Context, message, authorName, authorEmail string) error {
+	author := fmt.Sprintf("%s <%s>", authorName, authorEmail)
+	if _, err := r.run(ctx, r.dir, "commit", "--quiet", "--all", "--author", author, "--message", message); err != nil {
+		return err
+	}
+
+	_, pushErr := r.run(ctx, r.dir, "push", "--quiet", "origin", "HEAD:"+r.branch)
+	if pushErr == nil {
+		return nil
+	}
+
+	parent, err := r.run(ctx, r.dir, "rev-parse", "HEAD~1")
+	if err != nil {
+		return err
+	}
+	if err := r.sync(ctx); err != nil {
+		return err
+	}
+	head, err := r.run(ctx, r.dir, "rev-parse", "HEAD")
+	if err != nil {
+		return err
+	}
+
+	if head != parent {
+		return fmt.Errorf("%w: push to branch %q was rejected", ErrDashboardConflict, r.branch)
+	}
+	return pushErr
+}
+
+func (r *gitRepository) run(ctx context.Context, dir string, args ...string) (string, error) {
+	// nolint:gosec
+	// We can ignore the gosec G204 warning on this one because the arguments come from the provisioning configuration file.
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_TERMINAL_PROMPT=0",
+		"GIT_COMMITTER_NAME="+gitCommitterName,
+		"GIT_COMMITTER_EMAIL="+gitCommitterMail,
+	)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -21,6 +21,8 @@ type config struct {
 	DisableDeletion       bool
 	UpdateIntervalSeconds int64
 	AllowUIUpdates        bool
+	// DataPath is the data directory of Grafana, it's not read from the provisioning files.
+	DataPath string
 }
 
 type configV0 struct {
@@ -4,8 +4,10 @@ import (
 	"context"
 	"time"
 
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/services/dashboards/lint"
 )
 
 type duplicate struct {
@@ -160,3 +162,19 @@ func keysToSlice(data map[string]struct{}) []string {
 
 	return entries
 }
+
+// lintDashboard returns the findings with the error severity of the linter for the dashboard, it returns nothing
+// when linting of provisioned dashboards is disabled.
+func lintDashboard(linter *lint.Linter, dashboard *simplejson.Json) []lint.Finding {
+	if linter == nil {
+		return nil
+	}
+
+	var lintErrors []lint.Finding
+	for _, f := range linter.Lint(dashboard).Findings {
+		if f.Severity == lint.SeverityError {
+			lintErrors = append(lintErrors, f)
+		}
+	}
+	return lintErrors
+}
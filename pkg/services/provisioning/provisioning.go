@@ -13,6 +13,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/alerting"
 	"github.com/grafana/grafana/pkg/services/correlations"
 	dashboardservice "github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboards/lint"
 	datasourceservice "github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/encryption"
 	"github.com/grafana/grafana/pkg/services/folder"
@@ -91,6 +92,8 @@ type ProvisioningService interface {
 	ProvisionAlerting(ctx context.Context) error
 	GetDashboardProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
+	WriteProvisionedDashboard(ctx context.Context, change *dashboards.DashboardChange) error
+	PushesProvisionedDashboards(name string) bool
 }
 
 // Add a public constructor for overriding service to be able to instantiate OSS as fallback
@@ -242,7 +245,15 @@ func (ps *ProvisioningServiceImpl) ProvisionNotifications(ctx context.Context) e
 
 func (ps *ProvisioningServiceImpl) ProvisionDashboards(ctx context.Context) error {
 	dashboardPath := filepath.Join(ps.Cfg.ProvisioningPath, "dashboards")
-	dashProvisioner, err := ps.newDashboardProvisioner(ctx, dashboardPath, ps.dashboardProvisioningService, ps.orgService, ps.dashboardService, ps.folderService)
+	var linter *lint.Linter
+	if ps.Cfg.DashboardLintEnforceOnProvisioning {
+		var err error
+		if linter, err = lint.New(lint.ConfigFromSettings(ps.Cfg)); err != nil {
+			return fmt.Errorf("%v: %w", "Invalid dashboard lint configuration", err)
+		}
+	}
+
+	dashProvisioner, err := ps.newDashboardProvisioner(ctx, dashboardPath, ps.Cfg.DataPath, ps.dashboardProvisioningService, ps.orgService, ps.dashboardService, ps.folderService, linter)
 	if err != nil {
 		return fmt.Errorf("%v: %w", "Failed to create provisioner", err)
 	}
@@ -310,6 +321,17 @@ func (ps *ProvisioningServiceImpl) GetAllowUIUpdatesFromConfig(name string) bool
 	return ps.dashboardProvisioner.GetAllowUIUpdatesFromConfig(name)
 }
 
+// WriteProvisionedDashboard writes a provisioned dashboard saved in Grafana back to its provisioning source.
+func (ps *ProvisioningServiceImpl) WriteProvisionedDashboard(ctx context.Context, change *dashboards.DashboardChange) error {
+	return ps.dashboardProvisioner.WriteDashboard(ctx, change)
+}
+
+// PushesProvisionedDashboards returns if the dashboards saved in Grafana are written back to the provisioning
+// source of the provider.
+func (ps *ProvisioningServiceImpl) PushesProvisionedDashboards(name string) bool {
+	return ps.dashboardProvisioner.PushesDashboards(name)
+}
+
 func (ps *ProvisioningServiceImpl) cancelPolling() {
 	if ps.pollingCtxCancel != nil {
 		ps.log.Debug("Stop polling for dashboard changes")
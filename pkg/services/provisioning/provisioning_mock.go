@@ -1,6 +1,10 @@
 package provisioning
 
-import "context"
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
+)
 
 type Calls struct {
 	RunInitProvisioners                 []any
@@ -11,6 +15,8 @@ type Calls struct {
 	ProvisionAlerting                   []any
 	GetDashboardProvisionerResolvedPath []any
 	GetAllowUIUpdatesFromConfig         []any
+	WriteProvisionedDashboard           []any
+	PushesProvisionedDashboards         []any
 	Run                                 []any
 }
 
@@ -23,6 +29,8 @@ type ProvisioningServiceMock struct {
 	ProvisionDashboardsFunc                 func() error
 	GetDashboardProvisionerResolvedPathFunc func(name string) string
 	GetAllowUIUpdatesFromConfigFunc         func(name string) bool
+	WriteProvisionedDashboardFunc           func(ctx context.Context, change *dashboards.DashboardChange) error
+	PushesProvisionedDashboardsFunc         func(name string) bool
 	RunFunc                                 func(ctx context.Context) error
 }
 
@@ -93,6 +101,22 @@ func (mock *ProvisioningServiceMock) GetAllowUIUpdatesFromConfig(name string) bo
 	return false
 }
 
+func (mock *ProvisioningServiceMock) WriteProvisionedDashboard(ctx context.Context, change *dashboards.DashboardChange) error {
+	mock.Calls.WriteProvisionedDashboard = append(mock.Calls.WriteProvisionedDashboard, change)
+	if mock.WriteProvisionedDashboardFunc != nil {
+		return mock.WriteProvisionedDashboardFunc(ctx, change)
+	}
+	return nil
+}
+
+func (mock *ProvisioningServiceMock) PushesProvisionedDashboards(name string) bool {
+	mock.Calls.PushesProvisionedDashboards = append(mock.Calls.PushesProvisionedDashboards, name)
+	if mock.PushesProvisionedDashboardsFunc != nil {
+		return mock.PushesProvisionedDashboardsFunc(name)
+	}
+	return false
+}
+
 func (mock *ProvisioningServiceMock) Run(ctx context.Context) error {
 	mock.Calls.Run = append(mock.Calls.Run, nil)
 	if mock.RunFunc != nil {
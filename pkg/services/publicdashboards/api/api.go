@@ -25,11 +25,12 @@ type Api struct {
 	PublicDashboardService publicdashboards.Service
 	Middleware             publicdashboards.Middleware
 
-	accessControl accesscontrol.AccessControl
-	cfg           *setting.Cfg
-	features      featuremgmt.FeatureToggles
-	log           log.Logger
-	routeRegister routing.RouteRegister
+	accessControl    accesscontrol.AccessControl
+	cfg              *setting.Cfg
+	features         featuremgmt.FeatureToggles
+	log              log.Logger
+	routeRegister    routing.RouteRegister
+	tokenRateLimiter *TokenRateLimiter
 }
 
 func ProvideApi(
@@ -48,6 +49,7 @@ func ProvideApi(
 		features:               features,
 		log:                    log.New("publicdashboards.api"),
 		routeRegister:          rr,
+		tokenRateLimiter:       NewTokenRateLimiter(),
 	}
 
 	// register endpoints if the feature is enabled
@@ -68,10 +70,11 @@ func (api *Api) RegisterAPIEndpoints() {
 	// Anonymous access to public dashboard route is configured in pkg/api/api.go
 	// because it is deeply dependent on the HTTPServer.Index() method and would result in a
 	// circular dependency
+	rateLimit := RateLimitAccessToken(api.PublicDashboardService, api.tokenRateLimiter)
 	api.routeRegister.Group("/api/public/dashboards/:accessToken", func(apiRoute routing.RouteRegister) {
-		apiRoute.Get("/", routing.Wrap(api.ViewPublicDashboard))
-		apiRoute.Get("/annotations", routing.Wrap(api.GetPublicAnnotations))
-		apiRoute.Post("/panels/:panelId/query", routing.Wrap(api.QueryPublicDashboard))
+		apiRoute.Get("/", rateLimit, routing.Wrap(api.ViewPublicDashboard))
+		apiRoute.Get("/annotations", rateLimit, routing.Wrap(api.GetPublicAnnotations))
+		apiRoute.Post("/panels/:panelId/query", rateLimit, routing.Wrap(api.QueryPublicDashboard))
 	}, api.Middleware.HandleApi)
 
 	// Auth endpoints
@@ -99,6 +102,21 @@ func (api *Api) RegisterAPIEndpoints() {
 	api.routeRegister.Delete("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid",
 		auth(accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
 		routing.Wrap(api.DeletePublicDashboard))
+
+	// List access tokens of a public dashboard
+	api.routeRegister.Get("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid/tokens",
+		auth(accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
+		routing.Wrap(api.ListPublicDashboardTokens))
+
+	// Create access token for a public dashboard
+	api.routeRegister.Post("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid/tokens",
+		auth(accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
+		routing.Wrap(api.CreatePublicDashboardToken))
+
+	// Revoke access token of a public dashboard
+	api.routeRegister.Delete("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid/tokens/:tokenUid",
+		auth(accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
+		routing.Wrap(api.RevokePublicDashboardToken))
 }
 
 // swagger:route GET /dashboards/public-dashboards dashboard_public listPublicDashboards
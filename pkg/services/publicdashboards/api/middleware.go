@@ -2,14 +2,21 @@ package api
 
 import (
 	"net/http"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
 	"github.com/grafana/grafana/pkg/web"
 )
 
+// tokenRateLimitWindow is the window the rate limits of the access tokens apply to
+const tokenRateLimitWindow = time.Minute
+
 // SetPublicDashboardOrgIdOnContext Adds orgId to context based on org of public dashboard
 func SetPublicDashboardOrgIdOnContext(publicDashboardService publicdashboards.Service) func(c *contextmodel.ReqContext) {
 	return func(c *contextmodel.ReqContext) {
@@ -62,6 +69,78 @@ func RequiresExistingAccessToken(publicDashboardService publicdashboards.Service
 	}
 }
 
+// RateLimitAccessToken Middleware to enforce the query rate limit of the access token of the request, and record its
+// use. The access token of the public dashboard itself is not rate limited.
+func RateLimitAccessToken(publicDashboardService publicdashboards.Service, limiter *TokenRateLimiter) func(c *contextmodel.ReqContext) {
+	return func(c *contextmodel.ReqContext) {
+		accessToken, ok := web.Params(c.Req)[":accessToken"]
+		if !ok || !validation.IsValidAccessToken(accessToken) {
+			// the handler responds to invalid access tokens
+			return
+		}
+
+		token, err := publicDashboardService.UseToken(c.Req.Context(), accessToken)
+		if err != nil {
+			c.WriteErr(err)
+			return
+		}
+
+		if token != nil && !limiter.Allow(token.Uid, token.RateLimit) {
+			c.Resp.Header().Set("Retry-After", strconv.Itoa(int(tokenRateLimitWindow.Seconds())))
+			c.WriteErr(ErrTokenRateLimited.Errorf("RateLimitAccessToken: rate limit of access token %s exceeded", token.Uid))
+			return
+		}
+	}
+}
+
+// TokenRateLimiter counts the queries of each access token in fixed windows
+type TokenRateLimiter struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	windows map[string]*tokenWindow
+}
+
+type tokenWindow struct {
+	start time.Time
+	count int64
+}
+
+func NewTokenRateLimiter() *TokenRateLimiter {
+	return &TokenRateLimiter{
+		now:     time.Now,
+		windows: make(map[string]*tokenWindow),
+	}
+}
+
+// Allow counts a query of the token and returns whether it is within the limit of queries per window
+func (l *TokenRateLimiter) Allow(tokenUid string, limit int64) bool {
+	if limit <= 0 {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	window, ok := l.windows[tokenUid]
+	if !ok || now.Sub(window.start) >= tokenRateLimitWindow {
+		// drop the windows that are over, so tokens no longer used are forgotten
+		for uid, w := range l.windows {
+			if now.Sub(w.start) >= tokenRateLimitWindow {
+				delete(l.windows, uid)
+			}
+		}
+		window = &tokenWindow{start: now}
+		l.windows[tokenUid] = window
+	}
+
+	if window.count >= limit {
+		return false
+	}
+	window.count++
+	return true
+}
+
 func CountPublicDashboardRequest() func(c *contextmodel.ReqContext) {
 	return func(c *contextmodel.ReqContext) {
 		metrics.MPublicDashboardRequestCount.Inc()
@@ -5,11 +5,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"errors"
 
+	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/service"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/web"
@@ -152,6 +155,75 @@ func TestSetPublicDashboardFlag(t *testing.T) {
 	})
 }
 
+func TestRateLimitAccessToken(t *testing.T) {
+	token := &PublicDashboardToken{Uid: "token-uid", Token: validAccessToken, RateLimit: 2}
+
+	tests := []struct {
+		Name                  string
+		AccessToken           string
+		Token                 *PublicDashboardToken
+		TokenErr              error
+		ExpectedResponseCodes []int
+	}{
+		{
+			Name:                  "Does not limit the access token of the public dashboard",
+			AccessToken:           validAccessToken,
+			ExpectedResponseCodes: []int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+		{
+			Name:                  "Limits the queries of an access token",
+			AccessToken:           validAccessToken,
+			Token:                 token,
+			ExpectedResponseCodes: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			Name:                  "Returns 404 when the access token is revoked or expired",
+			AccessToken:           validAccessToken,
+			TokenErr:              ErrPublicDashboardNotFound.Errorf("revoked"),
+			ExpectedResponseCodes: []int{http.StatusNotFound},
+		},
+		{
+			Name:                  "Leaves invalid access tokens to the handler",
+			AccessToken:           "invalidAccessToken",
+			ExpectedResponseCodes: []int{http.StatusOK},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			publicdashboardService := &publicdashboards.FakePublicDashboardService{}
+			publicdashboardService.On("UseToken", mock.Anything, tt.AccessToken).Return(tt.Token, tt.TokenErr)
+
+			mw := RateLimitAccessToken(publicdashboardService, NewTokenRateLimiter())
+			params := map[string]string{":accessToken": tt.AccessToken}
+			for _, code := range tt.ExpectedResponseCodes {
+				ctx := &contextmodel.ReqContext{Logger: log.New("publicdashboards-test")}
+				_, resp := runMw(t, ctx, "POST", "/api/public/dashboards/myaccesstoken/panels/1/query", params, mw)
+				assert.Equal(t, code, resp.Code)
+			}
+		})
+	}
+}
+
+func TestTokenRateLimiter(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := NewTokenRateLimiter()
+	limiter.now = func() time.Time { return now }
+
+	assert.True(t, limiter.Allow("a", 2))
+	assert.True(t, limiter.Allow("a", 2))
+	assert.False(t, limiter.Allow("a", 2))
+	// the limit is per token
+	assert.True(t, limiter.Allow("b", 2))
+	// no limit
+	assert.True(t, limiter.Allow("c", 0))
+
+	now = now.Add(tokenRateLimitWindow)
+	assert.True(t, limiter.Allow("a", 2))
+	// windows that are over are dropped
+	assert.Len(t, limiter.windows, 1)
+}
+
 // This is a helper to test middleware. It handles creating a
 // proper contextmodel.ReqContext, setting web parameters, executing middleware, and
 // returning a response. Response will default to result of
@@ -53,6 +53,7 @@ func TestAPIViewPublicDashboard(t *testing.T) {
 		ExpectedHttpResponse int
 		DashboardResult      *dtos.DashboardFullWithMeta
 		Err                  error
+		TokenErr             error
 		FixedErrorResponse   string
 	}{
 		{
@@ -85,6 +86,15 @@ func TestAPIViewPublicDashboard(t *testing.T) {
 			Err:                  ErrPublicDashboardNotFound.Errorf(""),
 			FixedErrorResponse:   "",
 		},
+		{
+			Name:                 "It should return 404 if the access token is revoked or expired",
+			AccessToken:          validAccessToken,
+			ExpectedHttpResponse: http.StatusNotFound,
+			DashboardResult:      nil,
+			Err:                  ErrPublicDashboardNotFound.Errorf(""),
+			TokenErr:             ErrPublicDashboardNotFound.Errorf("revoked"),
+			FixedErrorResponse:   "",
+		},
 		{
 			Name:                 "It should return 400 if it is an invalid access token",
 			AccessToken:          "SomeInvalidAccessToken",
@@ -100,6 +110,7 @@ func TestAPIViewPublicDashboard(t *testing.T) {
 			service := publicdashboards.NewFakePublicDashboardService(t)
 			service.On("GetPublicDashboardForView", mock.Anything, mock.AnythingOfType("string")).
 				Return(test.DashboardResult, test.Err).Maybe()
+			service.On("UseToken", mock.Anything, mock.Anything).Return(nil, test.TokenErr).Maybe()
 
 			testServer := setupTestServer(t, nil, service, anonymousUser, true)
 
@@ -193,6 +204,7 @@ func TestAPIQueryPublicDashboard(t *testing.T) {
 
 	setup := func(enabled bool) (*web.Mux, *publicdashboards.FakePublicDashboardService) {
 		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("UseToken", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
 		testServer := setupTestServer(t, nil, service, anonymousUser, true)
 
 		return testServer, service
@@ -413,6 +425,7 @@ func TestAPIGetAnnotations(t *testing.T) {
 	for _, test := range testCases {
 		t.Run(test.Name, func(t *testing.T) {
 			service := publicdashboards.NewFakePublicDashboardService(t)
+			service.On("UseToken", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
 
 			if test.ExpectedServiceCalled {
 				service.On("FindAnnotations", mock.Anything, mock.Anything, mock.AnythingOfType("string")).
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+func TestAPIListPublicDashboardTokens(t *testing.T) {
+	tokens := []*PublicDashboardToken{{Uid: "token1", Name: "status page", Token: validAccessToken, RateLimit: 60}}
+
+	t.Run("returns the access tokens", func(t *testing.T) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("FindTokens", mock.Anything, int64(1), "abc123", "pubdash1").Return(tokens, nil)
+
+		testServer := setupTestServer(t, nil, service, userAdmin, true)
+		response := callAPI(testServer, http.MethodGet, "/api/dashboards/uid/abc123/public-dashboards/pubdash1/tokens", nil, t)
+		require.Equal(t, http.StatusOK, response.Code)
+
+		var result []*PublicDashboardToken
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &result))
+		assert.Equal(t, tokens, result)
+	})
+
+	t.Run("returns 404 when the public dashboard does not exist", func(t *testing.T) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("FindTokens", mock.Anything, int64(1), "abc123", "pubdash1").Return(nil, ErrPublicDashboardNotFound.Errorf(""))
+
+		testServer := setupTestServer(t, nil, service, userAdmin, true)
+		response := callAPI(testServer, http.MethodGet, "/api/dashboards/uid/abc123/public-dashboards/pubdash1/tokens", nil, t)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("viewers cannot list the access tokens", func(t *testing.T) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+
+		testServer := setupTestServer(t, nil, service, userViewer, true)
+		response := callAPI(testServer, http.MethodGet, "/api/dashboards/uid/abc123/public-dashboards/pubdash1/tokens", nil, t)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+}
+
+func TestAPICreatePublicDashboardToken(t *testing.T) {
+	testCases := []struct {
+		Name                 string
+		Body                 string
+		ServiceError         error
+		ExpectedHttpResponse int
+	}{
+		{
+			Name:                 "creates the access token",
+			Body:                 `{"name": "status page", "expiresAt": "2100-01-01T00:00:00Z", "rateLimit": 60}`,
+			ExpectedHttpResponse: http.StatusOK,
+		},
+		{
+			Name:                 "returns 400 when the token is invalid",
+			Body:                 `{"name": ""}`,
+			ServiceError:         ErrInvalidTokenName.Errorf(""),
+			ExpectedHttpResponse: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			service := publicdashboards.NewFakePublicDashboardService(t)
+			service.On("CreateToken", mock.Anything, mock.Anything, mock.MatchedBy(func(dto *SavePublicDashboardTokenDTO) bool {
+				return dto.DashboardUid == "abc123" && dto.PublicDashboardUid == "pubdash1" && dto.OrgID == 1 && dto.UserId == 2
+			})).Return(&PublicDashboardToken{Uid: "token1"}, test.ServiceError)
+
+			testServer := setupTestServer(t, nil, service, userAdmin, true)
+			response := callAPI(testServer, http.MethodPost, "/api/dashboards/uid/abc123/public-dashboards/pubdash1/tokens", strings.NewReader(test.Body), t)
+			assert.Equal(t, test.ExpectedHttpResponse, response.Code)
+		})
+	}
+}
+
+func TestAPIRevokePublicDashboardToken(t *testing.T) {
+	testCases := []struct {
+		Name                 string
+		ServiceError         error
+		ExpectedHttpResponse int
+	}{
+		{
+			Name:                 "revokes the access token",
+			ExpectedHttpResponse: http.StatusOK,
+		},
+		{
+			Name:                 "returns 404 when the access token does not exist",
+			ServiceError:         ErrTokenNotFound.Errorf(""),
+			ExpectedHttpResponse: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			service := publicdashboards.NewFakePublicDashboardService(t)
+			service.On("RevokeToken", mock.Anything, int64(1), "abc123", "pubdash1", "token1").Return(test.ServiceError)
+
+			testServer := setupTestServer(t, nil, service, userAdmin, true)
+			url := fmt.Sprintf("/api/dashboards/uid/%s/public-dashboards/%s/tokens/%s", "abc123", "pubdash1", "token1")
+			response := callAPI(testServer, http.MethodDelete, url, nil, t)
+			assert.Equal(t, test.ExpectedHttpResponse, response.Code)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -28,6 +29,15 @@ type PublicDashboardStoreImpl struct {
 
 var LogPrefix = "publicdashboards.store"
 
+// accessTokenCondition matches the public dashboard of an access token, which is either the access token of the
+// public dashboard or one of its active additional tokens
+const accessTokenCondition = `(access_token = ? OR uid IN (SELECT public_dashboard_uid FROM dashboard_public_token
+	WHERE token = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)))`
+
+func accessTokenArgs(accessToken string) []any {
+	return []any{accessToken, accessToken, time.Now().UTC().Format("2006-01-02 15:04:05")}
+}
+
 // Gives us a compile time error if our database does not adhere to contract of
 // the interface
 var _ publicdashboards.Store = (*PublicDashboardStoreImpl)(nil)
@@ -123,10 +133,10 @@ func (d *PublicDashboardStoreImpl) FindByAccessToken(ctx context.Context, access
 	}
 
 	var found bool
-	publicDashboard := &PublicDashboard{AccessToken: accessToken}
+	publicDashboard := &PublicDashboard{}
 	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
 		var err error
-		found, err = sess.Get(publicDashboard)
+		found, err = sess.Where(accessTokenCondition, accessTokenArgs(accessToken)...).Get(publicDashboard)
 		return err
 	})
 
@@ -191,9 +201,9 @@ func (d *PublicDashboardStoreImpl) ExistsEnabledByDashboardUid(ctx context.Conte
 func (d *PublicDashboardStoreImpl) ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error) {
 	hasPublicDashboard := false
 	err := d.sqlStore.WithDbSession(ctx, func(dbSession *db.Session) error {
-		sql := "SELECT COUNT(*) FROM dashboard_public WHERE access_token=? AND is_enabled=true"
+		sql := "SELECT COUNT(*) FROM dashboard_public WHERE " + accessTokenCondition + " AND is_enabled=true"
 
-		result, err := dbSession.SQL(sql, accessToken).Count()
+		result, err := dbSession.SQL(sql, accessTokenArgs(accessToken)...).Count()
 		if err != nil {
 			return err
 		}
@@ -209,9 +219,9 @@ func (d *PublicDashboardStoreImpl) ExistsEnabledByAccessToken(ctx context.Contex
 func (d *PublicDashboardStoreImpl) GetOrgIdByAccessToken(ctx context.Context, accessToken string) (int64, error) {
 	var orgId int64
 	err := d.sqlStore.WithDbSession(ctx, func(dbSession *db.Session) error {
-		sql := "SELECT org_id FROM dashboard_public WHERE access_token=?"
+		sql := "SELECT org_id FROM dashboard_public WHERE " + accessTokenCondition
 
-		_, err := dbSession.SQL(sql, accessToken).Get(&orgId)
+		_, err := dbSession.SQL(sql, accessTokenArgs(accessToken)...).Get(&orgId)
 		if err != nil {
 			return err
 		}
@@ -247,12 +257,18 @@ func (d *PublicDashboardStoreImpl) Update(ctx context.Context, cmd SavePublicDas
 			return err
 		}
 
-		sqlResult, err := sess.Exec("UPDATE dashboard_public SET is_enabled = ?, annotations_enabled = ?, time_selection_enabled = ?, share = ?, time_settings = ?, updated_by = ?, updated_at = ? WHERE uid = ?",
+		variableSettingsJSON, err := json.Marshal(cmd.PublicDashboard.VariableSettings)
+		if err != nil {
+			return err
+		}
+
+		sqlResult, err := sess.Exec("UPDATE dashboard_public SET is_enabled = ?, annotations_enabled = ?, time_selection_enabled = ?, share = ?, time_settings = ?, variable_settings = ?, updated_by = ?, updated_at = ? WHERE uid = ?",
 			cmd.PublicDashboard.IsEnabled,
 			cmd.PublicDashboard.AnnotationsEnabled,
 			cmd.PublicDashboard.TimeSelectionEnabled,
 			cmd.PublicDashboard.Share,
 			string(timeSettingsJSON),
+			string(variableSettingsJSON),
 			cmd.PublicDashboard.UpdatedBy,
 			cmd.PublicDashboard.UpdatedAt.UTC().Format("2006-01-02 15:04:05"),
 			cmd.PublicDashboard.Uid)
@@ -269,20 +285,106 @@ func (d *PublicDashboardStoreImpl) Update(ctx context.Context, cmd SavePublicDas
 	return affectedRows, err
 }
 
-// Deletes a public dashboard
+// Deletes a public dashboard and its access tokens
 func (d *PublicDashboardStoreImpl) Delete(ctx context.Context, uid string) (int64, error) {
 	dashboard := &PublicDashboard{Uid: uid}
 	var affectedRows int64
-	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+	err := d.sqlStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
 		var err error
 		affectedRows, err = sess.Delete(dashboard)
+		if err != nil {
+			return err
+		}
 
+		_, err = sess.Delete(&PublicDashboardToken{PublicDashboardUid: uid})
 		return err
 	})
 
 	return affectedRows, err
 }
 
+// FindTokens Returns the access tokens of a public dashboard, including the revoked and expired ones
+func (d *PublicDashboardStoreImpl) FindTokens(ctx context.Context, publicDashboardUid string) ([]*PublicDashboardToken, error) {
+	tokens := make([]*PublicDashboardToken, 0)
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("public_dashboard_uid = ?", publicDashboardUid).Asc("created_at", "id").Find(&tokens)
+	})
+
+	return tokens, err
+}
+
+// FindToken Returns an access token of a public dashboard by uid or nil if not found
+func (d *PublicDashboardStoreImpl) FindToken(ctx context.Context, uid string) (*PublicDashboardToken, error) {
+	return d.findToken(ctx, &PublicDashboardToken{Uid: uid})
+}
+
+// FindTokenByToken Returns an access token of a public dashboard by its value or nil if not found
+func (d *PublicDashboardStoreImpl) FindTokenByToken(ctx context.Context, token string) (*PublicDashboardToken, error) {
+	if token == "" {
+		return nil, nil
+	}
+	return d.findToken(ctx, &PublicDashboardToken{Token: token})
+}
+
+func (d *PublicDashboardStoreImpl) findToken(ctx context.Context, token *PublicDashboardToken) (*PublicDashboardToken, error) {
+	var found bool
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		found, err = sess.Get(token)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	return token, nil
+}
+
+// CreateToken Creates an access token for a public dashboard
+func (d *PublicDashboardStoreImpl) CreateToken(ctx context.Context, token *PublicDashboardToken) (int64, error) {
+	var affectedRows int64
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		affectedRows, err = sess.Insert(token)
+		return err
+	})
+
+	return affectedRows, err
+}
+
+// RevokeToken Revokes an access token of a public dashboard. Revoked tokens are kept to be listed.
+func (d *PublicDashboardStoreImpl) RevokeToken(ctx context.Context, uid string, revokedAt time.Time) (int64, error) {
+	var affectedRows int64
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		sqlResult, err := sess.Exec("UPDATE dashboard_public_token SET revoked_at = ? WHERE uid = ? AND revoked_at IS NULL",
+			revokedAt.UTC().Format("2006-01-02 15:04:05"),
+			uid)
+		if err != nil {
+			return err
+		}
+
+		affectedRows, err = sqlResult.RowsAffected()
+		return err
+	})
+
+	return affectedRows, err
+}
+
+// UpdateTokenLastUsed Records when an access token of a public dashboard was last used
+func (d *PublicDashboardStoreImpl) UpdateTokenLastUsed(ctx context.Context, uid string, usedAt time.Time) error {
+	return d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("UPDATE dashboard_public_token SET last_used_at = ? WHERE uid = ?",
+			usedAt.UTC().Format("2006-01-02 15:04:05"),
+			uid)
+		return err
+	})
+}
+
 func (d *PublicDashboardStoreImpl) FindByFolder(ctx context.Context, orgId int64, folderUid string) ([]*PublicDashboard, error) {
 	var pubdashes []*PublicDashboard
 
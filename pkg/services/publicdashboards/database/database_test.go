@@ -594,6 +594,7 @@ func TestIntegrationUpdatePublicDashboard(t *testing.T) {
 			TimeSelectionEnabled: true,
 			Share:                EmailShareType,
 			TimeSettings:         &TimeSettings{From: "now-8", To: "now"},
+			VariableSettings:     VariableSettings{AllowedValues: map[string][]string{"env": {"dev", "prod"}}},
 			UpdatedAt:            time.Now().UTC().Round(time.Second),
 			UpdatedBy:            8,
 		}
@@ -615,6 +616,7 @@ func TestIntegrationUpdatePublicDashboard(t *testing.T) {
 		assert.Equal(t, updatedPublicDashboard.AnnotationsEnabled, pdRetrieved.AnnotationsEnabled)
 		assert.Equal(t, updatedPublicDashboard.TimeSelectionEnabled, pdRetrieved.TimeSelectionEnabled)
 		assert.Equal(t, updatedPublicDashboard.Share, pdRetrieved.Share)
+		assert.Equal(t, updatedPublicDashboard.VariableSettings, pdRetrieved.VariableSettings)
 
 		// not updated dashboard shouldn't have changed
 		pdNotUpdatedRetrieved, err := publicdashboardStore.FindByDashboardUid(context.Background(), anotherSavedDashboard.OrgID, anotherSavedDashboard.UID)
@@ -741,6 +743,136 @@ func TestIntegrationDelete(t *testing.T) {
 	})
 }
 
+func TestIntegrationPublicDashboardTokens(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	var publicdashboardStore *PublicDashboardStoreImpl
+	var savedPublicDashboard *PublicDashboard
+
+	setup := func() {
+		sqlStore, cfg := db.InitTestDBwithCfg(t)
+		dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore), quotatest.New(false, nil))
+		require.NoError(t, err)
+		publicdashboardStore = ProvideStore(sqlStore, cfg, featuremgmt.WithFeatures())
+		savedDashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, "", true)
+		savedPublicDashboard = insertPublicDashboard(t, publicdashboardStore, savedDashboard.UID, savedDashboard.OrgID, true, PublicShareType)
+	}
+
+	insertToken := func(t *testing.T, expiresAt *time.Time) *PublicDashboardToken {
+		accessToken, err := service.GenerateAccessToken()
+		require.NoError(t, err)
+
+		token := &PublicDashboardToken{
+			Uid:                util.GenerateShortUID(),
+			PublicDashboardUid: savedPublicDashboard.Uid,
+			OrgId:              savedPublicDashboard.OrgId,
+			Name:               "status page",
+			Token:              accessToken,
+			RateLimit:          60,
+			ExpiresAt:          expiresAt,
+			CreatedBy:          1,
+			CreatedAt:          time.Now().UTC().Truncate(time.Second),
+		}
+		affectedRows, err := publicdashboardStore.CreateToken(context.Background(), token)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, affectedRows)
+		return token
+	}
+
+	t.Run("finds the public dashboard by an active token", func(t *testing.T) {
+		setup()
+		token := insertToken(t, nil)
+
+		pubdash, err := publicdashboardStore.FindByAccessToken(context.Background(), token.Token)
+		require.NoError(t, err)
+		require.NotNil(t, pubdash)
+		assert.Equal(t, savedPublicDashboard.Uid, pubdash.Uid)
+
+		exists, err := publicdashboardStore.ExistsEnabledByAccessToken(context.Background(), token.Token)
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		orgId, err := publicdashboardStore.GetOrgIdByAccessToken(context.Background(), token.Token)
+		require.NoError(t, err)
+		assert.Equal(t, savedPublicDashboard.OrgId, orgId)
+
+		// the access token of the public dashboard still works
+		pubdash, err = publicdashboardStore.FindByAccessToken(context.Background(), savedPublicDashboard.AccessToken)
+		require.NoError(t, err)
+		require.NotNil(t, pubdash)
+	})
+
+	t.Run("does not find the public dashboard by an expired token", func(t *testing.T) {
+		setup()
+		expiresAt := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+		token := insertToken(t, &expiresAt)
+
+		pubdash, err := publicdashboardStore.FindByAccessToken(context.Background(), token.Token)
+		require.NoError(t, err)
+		assert.Nil(t, pubdash)
+
+		exists, err := publicdashboardStore.ExistsEnabledByAccessToken(context.Background(), token.Token)
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("does not find the public dashboard by a revoked token", func(t *testing.T) {
+		setup()
+		token := insertToken(t, nil)
+
+		affectedRows, err := publicdashboardStore.RevokeToken(context.Background(), token.Uid, time.Now())
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, affectedRows)
+
+		// revoking twice does nothing
+		affectedRows, err = publicdashboardStore.RevokeToken(context.Background(), token.Uid, time.Now())
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, affectedRows)
+
+		pubdash, err := publicdashboardStore.FindByAccessToken(context.Background(), token.Token)
+		require.NoError(t, err)
+		assert.Nil(t, pubdash)
+
+		revoked, err := publicdashboardStore.FindToken(context.Background(), token.Uid)
+		require.NoError(t, err)
+		require.NotNil(t, revoked.RevokedAt)
+	})
+
+	t.Run("lists and updates the tokens", func(t *testing.T) {
+		setup()
+		first := insertToken(t, nil)
+		second := insertToken(t, nil)
+
+		usedAt := time.Now().UTC().Truncate(time.Second)
+		require.NoError(t, publicdashboardStore.UpdateTokenLastUsed(context.Background(), first.Uid, usedAt))
+
+		tokens, err := publicdashboardStore.FindTokens(context.Background(), savedPublicDashboard.Uid)
+		require.NoError(t, err)
+		require.Len(t, tokens, 2)
+		assert.Equal(t, first.Uid, tokens[0].Uid)
+		assert.Equal(t, usedAt, tokens[0].LastUsedAt.UTC())
+		assert.Equal(t, second.Uid, tokens[1].Uid)
+		assert.Nil(t, tokens[1].LastUsedAt)
+
+		found, err := publicdashboardStore.FindTokenByToken(context.Background(), second.Token)
+		require.NoError(t, err)
+		assert.Equal(t, second.Uid, found.Uid)
+	})
+
+	t.Run("deletes the tokens with the public dashboard", func(t *testing.T) {
+		setup()
+		token := insertToken(t, nil)
+
+		_, err := publicdashboardStore.Delete(context.Background(), savedPublicDashboard.Uid)
+		require.NoError(t, err)
+
+		found, err := publicdashboardStore.FindToken(context.Background(), token.Uid)
+		require.NoError(t, err)
+		assert.Nil(t, found)
+	})
+}
+
 func TestFindByFolder(t *testing.T) {
 	t.Run("returns nil when dashboard is not a folder", func(t *testing.T) {
 		sqlStore, _ := db.InitTestDBwithCfg(t)
@@ -8,6 +8,7 @@ var (
 	ErrPublicDashboardNotFound = errutil.NotFound("publicdashboards.notFound", errutil.WithPublicMessage("Public dashboard not found"))
 	ErrDashboardNotFound       = errutil.NotFound("publicdashboards.dashboardNotFound", errutil.WithPublicMessage("Dashboard not found"))
 	ErrPanelNotFound           = errutil.NotFound("publicdashboards.panelNotFound", errutil.WithPublicMessage("Public dashboard panel not found"))
+	ErrTokenNotFound           = errutil.NotFound("publicdashboards.tokenNotFound", errutil.WithPublicMessage("Public dashboard access token not found"))
 
 	ErrBadRequest                          = errutil.BadRequest("publicdashboards.badRequest")
 	ErrPanelQueriesNotFound                = errutil.BadRequest("publicdashboards.panelQueriesNotFound", errutil.WithPublicMessage("Failed to extract queries from panel"))
@@ -20,9 +21,15 @@ var (
 	ErrInvalidMaxDataPoints                = errutil.BadRequest("publicdashboards.maxDataPoints", errutil.WithPublicMessage("maxDataPoints should be greater than 0"))
 	ErrInvalidTimeRange                    = errutil.BadRequest("publicdashboards.invalidTimeRange", errutil.WithPublicMessage("Invalid time range"))
 	ErrInvalidShareType                    = errutil.BadRequest("publicdashboards.invalidShareType", errutil.WithPublicMessage("Invalid share type"))
+	ErrInvalidVariable                     = errutil.BadRequest("publicdashboards.invalidVariable", errutil.WithPublicMessage("Invalid template variable"))
+	ErrInvalidTokenName                    = errutil.BadRequest("publicdashboards.invalidTokenName", errutil.WithPublicMessage("Invalid access token name"))
+	ErrInvalidTokenExpiry                  = errutil.BadRequest("publicdashboards.invalidTokenExpiry", errutil.WithPublicMessage("Access token expiry should be in the future"))
+	ErrInvalidRateLimit                    = errutil.BadRequest("publicdashboards.invalidRateLimit", errutil.WithPublicMessage("rateLimit should be greater than or equal to 0"))
 	ErrDashboardIsPublic                   = errutil.BadRequest("publicdashboards.dashboardIsPublic", errutil.WithPublicMessage("Dashboard is already public"))
 	ErrPublicDashboardUidExists            = errutil.BadRequest("publicdashboards.uidExists", errutil.WithPublicMessage("Public Dashboard Uid already exists"))
 	ErrPublicDashboardAccessTokenExists    = errutil.BadRequest("publicdashboards.accessTokenExists", errutil.WithPublicMessage("Public Dashboard Access Token already exists"))
 
 	ErrPublicDashboardNotEnabled = errutil.Forbidden("publicdashboards.notEnabled", errutil.WithPublicMessage("Public dashboard paused"))
+
+	ErrTokenRateLimited = errutil.TooManyRequests("publicdashboards.tokenRateLimited", errutil.WithPublicMessage("Too many queries for this access token"))
 )
@@ -47,21 +47,49 @@ type PublicDashboard struct {
 	CreatedAt    time.Time `json:"createdAt" xorm:"created_at"`
 	UpdatedAt    time.Time `json:"updatedAt" xorm:"updated_at"`
 	//config fields
-	TimeSettings         *TimeSettings `json:"-" xorm:"time_settings"`
-	TimeSelectionEnabled bool          `json:"timeSelectionEnabled" xorm:"time_selection_enabled"`
-	IsEnabled            bool          `json:"isEnabled" xorm:"is_enabled"`
-	AnnotationsEnabled   bool          `json:"annotationsEnabled" xorm:"annotations_enabled"`
-	Share                ShareType     `json:"share" xorm:"share"`
-	Recipients           []EmailDTO    `json:"recipients,omitempty" xorm:"-"`
+	TimeSettings         *TimeSettings    `json:"-" xorm:"time_settings"`
+	TimeSelectionEnabled bool             `json:"timeSelectionEnabled" xorm:"time_selection_enabled"`
+	IsEnabled            bool             `json:"isEnabled" xorm:"is_enabled"`
+	AnnotationsEnabled   bool             `json:"annotationsEnabled" xorm:"annotations_enabled"`
+	VariableSettings     VariableSettings `json:"variableSettings" xorm:"variable_settings"`
+	Share                ShareType        `json:"share" xorm:"share"`
+	Recipients           []EmailDTO       `json:"recipients,omitempty" xorm:"-"`
+}
+
+// PublicDashboardToken is an additional access token of a public dashboard. Unlike the access token of the public
+// dashboard, it can expire, be revoked and have its queries rate limited.
+type PublicDashboardToken struct {
+	Id                 int64      `json:"-" xorm:"pk autoincr 'id'"`
+	Uid                string     `json:"uid" xorm:"uid"`
+	PublicDashboardUid string     `json:"publicDashboardUid" xorm:"public_dashboard_uid"`
+	OrgId              int64      `json:"-" xorm:"org_id"`
+	Name               string     `json:"name" xorm:"name"`
+	Token              string     `json:"token" xorm:"token"`
+	RateLimit          int64      `json:"rateLimit" xorm:"rate_limit"` // queries per minute, no limit when 0
+	ExpiresAt          *time.Time `json:"expiresAt" xorm:"expires_at"`
+	RevokedAt          *time.Time `json:"revokedAt" xorm:"revoked_at"`
+	LastUsedAt         *time.Time `json:"lastUsedAt" xorm:"last_used_at"`
+	CreatedBy          int64      `json:"createdBy" xorm:"created_by"`
+	CreatedAt          time.Time  `json:"createdAt" xorm:"created_at"`
+}
+
+func (t PublicDashboardToken) TableName() string {
+	return "dashboard_public_token"
+}
+
+// IsActive returns whether the token can be used to access the public dashboard
+func (t *PublicDashboardToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && (t.ExpiresAt == nil || t.ExpiresAt.After(now))
 }
 
 type PublicDashboardDTO struct {
-	Uid                  string    `json:"uid"`
-	AccessToken          string    `json:"accessToken"`
-	TimeSelectionEnabled *bool     `json:"timeSelectionEnabled"`
-	IsEnabled            *bool     `json:"isEnabled"`
-	AnnotationsEnabled   *bool     `json:"annotationsEnabled"`
-	Share                ShareType `json:"share"`
+	Uid                  string            `json:"uid"`
+	AccessToken          string            `json:"accessToken"`
+	TimeSelectionEnabled *bool             `json:"timeSelectionEnabled"`
+	IsEnabled            *bool             `json:"isEnabled"`
+	AnnotationsEnabled   *bool             `json:"annotationsEnabled"`
+	VariableSettings     *VariableSettings `json:"variableSettings"`
+	Share                ShareType         `json:"share"`
 }
 
 type EmailDTO struct {
@@ -133,6 +161,37 @@ func (ts *TimeSettings) ToDB() ([]byte, error) {
 	return json.Marshal(ts)
 }
 
+// VariableSettings lists the template variables viewers are allowed to change, with the values they can select for
+// each of them. The other variables always use the value saved in the dashboard.
+type VariableSettings struct {
+	AllowedValues map[string][]string `json:"allowedValues,omitempty"`
+}
+
+// IsAllowed returns whether the value can be selected for the variable
+func (vs *VariableSettings) IsAllowed(name string, value string) bool {
+	if vs == nil {
+		return false
+	}
+	for _, v := range vs.AllowedValues[name] {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (vs *VariableSettings) FromDB(data []byte) error {
+	// the column is empty for public dashboards created before variables were supported
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, vs)
+}
+
+func (vs *VariableSettings) ToDB() ([]byte, error) {
+	return json.Marshal(vs)
+}
+
 // DTO for transforming user input in the api
 type SavePublicDashboardDTO struct {
 	Uid             string
@@ -142,6 +201,21 @@ type SavePublicDashboardDTO struct {
 	PublicDashboard *PublicDashboardDTO
 }
 
+// DTO for creating an access token of a public dashboard
+type SavePublicDashboardTokenDTO struct {
+	DashboardUid       string
+	PublicDashboardUid string
+	OrgID              int64
+	UserId             int64
+	Token              *PublicDashboardTokenDTO
+}
+
+type PublicDashboardTokenDTO struct {
+	Name      string     `json:"name"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+	RateLimit int64      `json:"rateLimit"`
+}
+
 type TimeRangeDTO struct {
 	From     string
 	To       string
@@ -153,6 +227,7 @@ type PublicDashboardQueryDTO struct {
 	MaxDataPoints   int64
 	QueryCachingTTL int64
 	TimeRange       TimeRangeDTO
+	Variables       map[string][]string
 }
 
 type AnnotationsQueryDTO struct {
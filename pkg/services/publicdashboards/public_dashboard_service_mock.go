@@ -49,6 +49,32 @@ func (_m *FakePublicDashboardService) Create(ctx context.Context, u *user.Signed
 	return r0, r1
 }
 
+// CreateToken provides a mock function with given fields: ctx, u, dto
+func (_m *FakePublicDashboardService) CreateToken(ctx context.Context, u *user.SignedInUser, dto *models.SavePublicDashboardTokenDTO) (*models.PublicDashboardToken, error) {
+	ret := _m.Called(ctx, u, dto)
+
+	var r0 *models.PublicDashboardToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *user.SignedInUser, *models.SavePublicDashboardTokenDTO) (*models.PublicDashboardToken, error)); ok {
+		return rf(ctx, u, dto)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *user.SignedInUser, *models.SavePublicDashboardTokenDTO) *models.PublicDashboardToken); ok {
+		r0 = rf(ctx, u, dto)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PublicDashboardToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *user.SignedInUser, *models.SavePublicDashboardTokenDTO) error); ok {
+		r1 = rf(ctx, u, dto)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields: ctx, uid, dashboardUid
 func (_m *FakePublicDashboardService) Delete(ctx context.Context, uid string, dashboardUid string) error {
 	ret := _m.Called(ctx, uid, dashboardUid)
@@ -351,6 +377,32 @@ func (_m *FakePublicDashboardService) FindPublicDashboardAndDashboardByAccessTok
 	return r0, r1, r2
 }
 
+// FindTokens provides a mock function with given fields: ctx, orgId, dashboardUid, uid
+func (_m *FakePublicDashboardService) FindTokens(ctx context.Context, orgId int64, dashboardUid string, uid string) ([]*models.PublicDashboardToken, error) {
+	ret := _m.Called(ctx, orgId, dashboardUid, uid)
+
+	var r0 []*models.PublicDashboardToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) ([]*models.PublicDashboardToken, error)); ok {
+		return rf(ctx, orgId, dashboardUid, uid)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) []*models.PublicDashboardToken); ok {
+		r0 = rf(ctx, orgId, dashboardUid, uid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.PublicDashboardToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string) error); ok {
+		r1 = rf(ctx, orgId, dashboardUid, uid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetMetricRequest provides a mock function with given fields: ctx, dashboard, publicDashboard, panelId, reqDTO
 func (_m *FakePublicDashboardService) GetMetricRequest(ctx context.Context, dashboard *dashboards.Dashboard, publicDashboard *models.PublicDashboard, panelId int64, reqDTO models.PublicDashboardQueryDTO) (dtos.MetricRequest, error) {
 	ret := _m.Called(ctx, dashboard, publicDashboard, panelId, reqDTO)
@@ -499,6 +551,20 @@ func (_m *FakePublicDashboardService) NewPublicDashboardUid(ctx context.Context)
 	return r0, r1
 }
 
+// RevokeToken provides a mock function with given fields: ctx, orgId, dashboardUid, uid, tokenUid
+func (_m *FakePublicDashboardService) RevokeToken(ctx context.Context, orgId int64, dashboardUid string, uid string, tokenUid string) error {
+	ret := _m.Called(ctx, orgId, dashboardUid, uid, tokenUid)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string, string) error); ok {
+		r0 = rf(ctx, orgId, dashboardUid, uid, tokenUid)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, u, dto
 func (_m *FakePublicDashboardService) Update(ctx context.Context, u *user.SignedInUser, dto *models.SavePublicDashboardDTO) (*models.PublicDashboard, error) {
 	ret := _m.Called(ctx, u, dto)
@@ -525,6 +591,32 @@ func (_m *FakePublicDashboardService) Update(ctx context.Context, u *user.Signed
 	return r0, r1
 }
 
+// UseToken provides a mock function with given fields: ctx, accessToken
+func (_m *FakePublicDashboardService) UseToken(ctx context.Context, accessToken string) (*models.PublicDashboardToken, error) {
+	ret := _m.Called(ctx, accessToken)
+
+	var r0 *models.PublicDashboardToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.PublicDashboardToken, error)); ok {
+		return rf(ctx, accessToken)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.PublicDashboardToken); ok {
+		r0 = rf(ctx, accessToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PublicDashboardToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, accessToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewFakePublicDashboardService creates a new instance of FakePublicDashboardService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewFakePublicDashboardService(t interface {
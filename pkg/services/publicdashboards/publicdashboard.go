@@ -2,6 +2,7 @@ package publicdashboards
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana/pkg/api/dtos"
@@ -38,6 +39,11 @@ type Service interface {
 
 	ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error)
 	ExistsEnabledByDashboardUid(ctx context.Context, dashboardUid string) (bool, error)
+
+	FindTokens(ctx context.Context, orgId int64, dashboardUid string, uid string) ([]*PublicDashboardToken, error)
+	CreateToken(ctx context.Context, u *user.SignedInUser, dto *SavePublicDashboardTokenDTO) (*PublicDashboardToken, error)
+	RevokeToken(ctx context.Context, orgId int64, dashboardUid string, uid string, tokenUid string) error
+	UseToken(ctx context.Context, accessToken string) (*PublicDashboardToken, error)
 }
 
 // ServiceWrapper these methods have different behavior between OSS and Enterprise. The latter would call the OSS service first
@@ -63,6 +69,13 @@ type Store interface {
 	ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error)
 	ExistsEnabledByDashboardUid(ctx context.Context, dashboardUid string) (bool, error)
 	GetMetrics(ctx context.Context) (*Metrics, error)
+
+	FindTokens(ctx context.Context, publicDashboardUid string) ([]*PublicDashboardToken, error)
+	FindToken(ctx context.Context, uid string) (*PublicDashboardToken, error)
+	FindTokenByToken(ctx context.Context, token string) (*PublicDashboardToken, error)
+	CreateToken(ctx context.Context, token *PublicDashboardToken) (int64, error)
+	RevokeToken(ctx context.Context, uid string, revokedAt time.Time) (int64, error)
+	UpdateTokenLastUsed(ctx context.Context, uid string, usedAt time.Time) error
 }
 
 //go:generate mockery --name Middleware --structname FakePublicDashboardMiddleware --inpackage --filename public_dashboard_middleware_mock.go
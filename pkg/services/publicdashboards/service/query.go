@@ -12,6 +12,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/annotations"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboards/templatevars"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
@@ -164,9 +165,13 @@ func (pd *PublicDashboardServiceImpl) buildMetricRequest(dashboard *dashboards.D
 
 	ts := buildTimeSettings(dashboard, reqDTO, publicDashboard)
 
+	// template variables are resolved here, so viewers can only change them through the validated request
+	variables := templatevars.Resolve(dashboard.Data, reqDTO.Variables)
+
 	// determine safe resolution to query data at
 	safeInterval, safeResolution := pd.getSafeIntervalAndMaxDataPoints(reqDTO, ts)
 	for i := range queries {
+		templatevars.InterpolateQuery(queries[i], variables)
 		queries[i].Set("intervalMs", safeInterval)
 		queries[i].Set("maxDataPoints", safeResolution)
 		queries[i].Set("queryCachingTTL", reqDTO.QueryCachingTTL)
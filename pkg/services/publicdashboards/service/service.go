@@ -96,6 +96,7 @@ func (pd *PublicDashboardServiceImpl) GetPublicDashboardForView(ctx context.Cont
 	dash.Data.Get("timepicker").Set("hidden", !pubdash.TimeSelectionEnabled)
 
 	sanitizeData(dash.Data)
+	sanitizeVariables(dash.Data, &pubdash.VariableSettings)
 
 	return &dtos.DashboardFullWithMeta{Meta: meta, Dashboard: dash.Data}, nil
 }
@@ -485,6 +486,11 @@ func (pd *PublicDashboardServiceImpl) newCreatePublicDashboard(ctx context.Conte
 		share = PublicShareType
 	}
 
+	variableSettings := VariableSettings{}
+	if dto.PublicDashboard.VariableSettings != nil {
+		variableSettings = *dto.PublicDashboard.VariableSettings
+	}
+
 	now := time.Now()
 
 	return &PublicDashboard{
@@ -495,6 +501,7 @@ func (pd *PublicDashboardServiceImpl) newCreatePublicDashboard(ctx context.Conte
 		AnnotationsEnabled:   annotationsEnabled,
 		TimeSelectionEnabled: timeSelectionEnabled,
 		TimeSettings:         &TimeSettings{},
+		VariableSettings:     variableSettings,
 		Share:                share,
 		CreatedBy:            dto.UserId,
 		CreatedAt:            now,
@@ -515,12 +522,18 @@ func newUpdatePublicDashboard(dto *SavePublicDashboardDTO, pd *PublicDashboard)
 		share = pd.Share
 	}
 
+	variableSettings := pd.VariableSettings
+	if pubdashDTO.VariableSettings != nil {
+		variableSettings = *pubdashDTO.VariableSettings
+	}
+
 	return &PublicDashboard{
 		Uid:                  pd.Uid,
 		IsEnabled:            isEnabled,
 		AnnotationsEnabled:   annotationsEnabled,
 		TimeSelectionEnabled: timeSelectionEnabled,
 		TimeSettings:         pd.TimeSettings,
+		VariableSettings:     variableSettings,
 		Share:                share,
 		UpdatedBy:            dto.UserId,
 		UpdatedAt:            time.Now(),
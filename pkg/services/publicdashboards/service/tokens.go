@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// tokenLastUsedInterval is how often the last use of an access token is recorded, so queries don't all write to
+// the database
+const tokenLastUsedInterval = time.Minute
+
+// FindTokens returns the access tokens of a public dashboard
+func (pd *PublicDashboardServiceImpl) FindTokens(ctx context.Context, orgId int64, dashboardUid string, uid string) ([]*PublicDashboardToken, error) {
+	_, err := pd.findPublicDashboardOfDashboard(ctx, orgId, dashboardUid, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := pd.store.FindTokens(ctx, uid)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("FindTokens: failed to find access tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// CreateToken validates and creates an access token for a public dashboard
+func (pd *PublicDashboardServiceImpl) CreateToken(ctx context.Context, u *user.SignedInUser, dto *SavePublicDashboardTokenDTO) (*PublicDashboardToken, error) {
+	err := validation.ValidatePublicDashboardToken(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	pubdash, err := pd.findPublicDashboardOfDashboard(ctx, u.OrgID, dto.DashboardUid, dto.PublicDashboardUid)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := pd.NewPublicDashboardAccessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var expiresAt *time.Time
+	if dto.Token.ExpiresAt != nil {
+		expiry := dto.Token.ExpiresAt.UTC().Truncate(time.Second)
+		expiresAt = &expiry
+	}
+
+	token := &PublicDashboardToken{
+		Uid:                util.GenerateShortUID(),
+		PublicDashboardUid: pubdash.Uid,
+		OrgId:              pubdash.OrgId,
+		Name:               dto.Token.Name,
+		Token:              accessToken,
+		RateLimit:          dto.Token.RateLimit,
+		ExpiresAt:          expiresAt,
+		CreatedBy:          dto.UserId,
+		CreatedAt:          time.Now().UTC().Truncate(time.Second),
+	}
+
+	affectedRows, err := pd.store.CreateToken(ctx, token)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("CreateToken: failed to create access token for public dashboard with Uid %s: %w", pubdash.Uid, err)
+	} else if affectedRows == 0 {
+		return nil, ErrInternalServerError.Errorf("CreateToken: failed to create a database entry for access token of public dashboard with Uid %s. 0 rows changed, no error reported.", pubdash.Uid)
+	}
+
+	return token, nil
+}
+
+// RevokeToken revokes an access token of a public dashboard. Revoking a token twice is not an error.
+func (pd *PublicDashboardServiceImpl) RevokeToken(ctx context.Context, orgId int64, dashboardUid string, uid string, tokenUid string) error {
+	_, err := pd.findPublicDashboardOfDashboard(ctx, orgId, dashboardUid, uid)
+	if err != nil {
+		return err
+	}
+
+	token, err := pd.store.FindToken(ctx, tokenUid)
+	if err != nil {
+		return ErrInternalServerError.Errorf("RevokeToken: failed to find access token by uid: %s: %w", tokenUid, err)
+	}
+	if token == nil || token.PublicDashboardUid != uid {
+		return ErrTokenNotFound.Errorf("RevokeToken: access token not found by uid: %s", tokenUid)
+	}
+
+	if _, err := pd.store.RevokeToken(ctx, tokenUid, time.Now()); err != nil {
+		return ErrInternalServerError.Errorf("RevokeToken: failed to revoke access token: %w", err)
+	}
+
+	return nil
+}
+
+// UseToken records the use of an access token and returns it. The access token of the public dashboard itself is not
+// tracked, nil is returned for it.
+func (pd *PublicDashboardServiceImpl) UseToken(ctx context.Context, accessToken string) (*PublicDashboardToken, error) {
+	token, err := pd.store.FindTokenByToken(ctx, accessToken)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("UseToken: failed to find access token: %w", err)
+	}
+	if token == nil {
+		return nil, nil
+	}
+
+	now := time.Now()
+	if !token.IsActive(now) {
+		return nil, ErrPublicDashboardNotFound.Errorf("UseToken: access token is revoked or expired")
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= tokenLastUsedInterval {
+		if err := pd.store.UpdateTokenLastUsed(ctx, token.Uid, now); err != nil {
+			pd.log.Warn("Failed to record the use of a public dashboard access token", "uid", token.Uid, "error", err)
+		}
+	}
+
+	return token, nil
+}
+
+// findPublicDashboardOfDashboard returns the public dashboard when it exists and belongs to the dashboard
+func (pd *PublicDashboardServiceImpl) findPublicDashboardOfDashboard(ctx context.Context, orgId int64, dashboardUid string, uid string) (*PublicDashboard, error) {
+	pubdash, err := pd.store.Find(ctx, uid)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("findPublicDashboardOfDashboard: failed to find public dashboard by uid: %s: %w", uid, err)
+	}
+	if pubdash == nil || pubdash.OrgId != orgId {
+		return nil, ErrPublicDashboardNotFound.Errorf("findPublicDashboardOfDashboard: public dashboard not found by uid: %s", uid)
+	}
+	if pubdash.DashboardUid != dashboardUid {
+		return nil, ErrInvalidUid.Errorf("findPublicDashboardOfDashboard: the public dashboard does not belong to the dashboard")
+	}
+
+	return pubdash, nil
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestCreateToken(t *testing.T) {
+	pubdash := &PublicDashboard{Uid: "pubdash1", OrgId: 1, DashboardUid: "abc123"}
+	signedInUser := &user.SignedInUser{UserID: 2, OrgID: 1}
+
+	t.Run("creates the access token", func(t *testing.T) {
+		store := NewFakePublicDashboardStore(t)
+		store.On("Find", mock.Anything, "pubdash1").Return(pubdash, nil)
+		store.On("FindByAccessToken", mock.Anything, mock.Anything).Return(nil, nil)
+		store.On("CreateToken", mock.Anything, mock.Anything).Return(int64(1), nil)
+		service := &PublicDashboardServiceImpl{log: log.New("test.logger"), store: store}
+
+		expiresAt := time.Now().Add(time.Hour)
+		token, err := service.CreateToken(context.Background(), signedInUser, &SavePublicDashboardTokenDTO{
+			DashboardUid:       "abc123",
+			PublicDashboardUid: "pubdash1",
+			OrgID:              1,
+			UserId:             2,
+			Token:              &PublicDashboardTokenDTO{Name: "status page", ExpiresAt: &expiresAt, RateLimit: 60},
+		})
+		require.NoError(t, err)
+
+		assert.NotEmpty(t, token.Uid)
+		assert.Len(t, token.Token, 32)
+		assert.Equal(t, "pubdash1", token.PublicDashboardUid)
+		assert.Equal(t, int64(1), token.OrgId)
+		assert.Equal(t, int64(2), token.CreatedBy)
+		assert.Equal(t, int64(60), token.RateLimit)
+		assert.Equal(t, expiresAt.UTC().Truncate(time.Second), *token.ExpiresAt)
+	})
+
+	t.Run("returns an error when the access token is invalid", func(t *testing.T) {
+		store := NewFakePublicDashboardStore(t)
+		service := &PublicDashboardServiceImpl{log: log.New("test.logger"), store: store}
+
+		_, err := service.CreateToken(context.Background(), signedInUser, &SavePublicDashboardTokenDTO{
+			DashboardUid:       "abc123",
+			PublicDashboardUid: "pubdash1",
+			Token:              &PublicDashboardTokenDTO{Name: ""},
+		})
+		require.ErrorIs(t, err, ErrInvalidTokenName)
+	})
+
+	t.Run("returns an error when the public dashboard does not belong to the dashboard", func(t *testing.T) {
+		store := NewFakePublicDashboardStore(t)
+		store.On("Find", mock.Anything, "pubdash1").Return(pubdash, nil)
+		service := &PublicDashboardServiceImpl{log: log.New("test.logger"), store: store}
+
+		_, err := service.CreateToken(context.Background(), signedInUser, &SavePublicDashboardTokenDTO{
+			DashboardUid:       "other",
+			PublicDashboardUid: "pubdash1",
+			Token:              &PublicDashboardTokenDTO{Name: "status page"},
+		})
+		require.ErrorIs(t, err, ErrInvalidUid)
+	})
+}
+
+func TestRevokeToken(t *testing.T) {
+	pubdash := &PublicDashboard{Uid: "pubdash1", OrgId: 1, DashboardUid: "abc123"}
+
+	testCases := []struct {
+		Name          string
+		Token         *PublicDashboardToken
+		ExpectedError error
+	}{
+		{
+			Name:  "revokes the access token",
+			Token: &PublicDashboardToken{Uid: "token1", PublicDashboardUid: "pubdash1"},
+		},
+		{
+			Name:          "access token not found",
+			Token:         nil,
+			ExpectedError: ErrTokenNotFound,
+		},
+		{
+			Name:          "access token of another public dashboard",
+			Token:         &PublicDashboardToken{Uid: "token1", PublicDashboardUid: "pubdash2"},
+			ExpectedError: ErrTokenNotFound,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			store := NewFakePublicDashboardStore(t)
+			store.On("Find", mock.Anything, "pubdash1").Return(pubdash, nil)
+			store.On("FindToken", mock.Anything, "token1").Return(tt.Token, nil)
+			if tt.ExpectedError == nil {
+				store.On("RevokeToken", mock.Anything, "token1", mock.Anything).Return(int64(1), nil)
+			}
+			service := &PublicDashboardServiceImpl{log: log.New("test.logger"), store: store}
+
+			err := service.RevokeToken(context.Background(), 1, "abc123", "pubdash1", "token1")
+			if tt.ExpectedError != nil {
+				require.ErrorIs(t, err, tt.ExpectedError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("public dashboard of another org", func(t *testing.T) {
+		store := NewFakePublicDashboardStore(t)
+		store.On("Find", mock.Anything, "pubdash1").Return(pubdash, nil)
+		service := &PublicDashboardServiceImpl{log: log.New("test.logger"), store: store}
+
+		err := service.RevokeToken(context.Background(), 2, "abc123", "pubdash1", "token1")
+		require.ErrorIs(t, err, ErrPublicDashboardNotFound)
+	})
+}
+
+func TestUseToken(t *testing.T) {
+	t.Run("returns nil for the access token of the public dashboard", func(t *testing.T) {
+		store := NewFakePublicDashboardStore(t)
+		store.On("FindTokenByToken", mock.Anything, "accesstoken").Return(nil, nil)
+		service := &PublicDashboardServiceImpl{log: log.New("test.logger"), store: store}
+
+		token, err := service.UseToken(context.Background(), "accesstoken")
+		require.NoError(t, err)
+		assert.Nil(t, token)
+	})
+
+	t.Run("records the use of the access token", func(t *testing.T) {
+		store := NewFakePublicDashboardStore(t)
+		store.On("FindTokenByToken", mock.Anything, "accesstoken").Return(&PublicDashboardToken{Uid: "token1"}, nil)
+		store.On("UpdateTokenLastUsed", mock.Anything, "token1", mock.Anything).Return(nil)
+		service := &PublicDashboardServiceImpl{log: log.New("test.logger"), store: store}
+
+		token, err := service.UseToken(context.Background(), "accesstoken")
+		require.NoError(t, err)
+		assert.Equal(t, "token1", token.Uid)
+	})
+
+	t.Run("does not record the use of an access token used recently", func(t *testing.T) {
+		lastUsedAt := time.Now().Add(-10 * time.Second)
+		store := NewFakePublicDashboardStore(t)
+		store.On("FindTokenByToken", mock.Anything, "accesstoken").Return(&PublicDashboardToken{Uid: "token1", LastUsedAt: &lastUsedAt}, nil)
+		service := &PublicDashboardServiceImpl{log: log.New("test.logger"), store: store}
+
+		_, err := service.UseToken(context.Background(), "accesstoken")
+		require.NoError(t, err)
+		store.AssertNotCalled(t, "UpdateTokenLastUsed", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("does not fail when the use can't be recorded", func(t *testing.T) {
+		store := NewFakePublicDashboardStore(t)
+		store.On("FindTokenByToken", mock.Anything, "accesstoken").Return(&PublicDashboardToken{Uid: "token1"}, nil)
+		store.On("UpdateTokenLastUsed", mock.Anything, "token1", mock.Anything).Return(errors.New("db error"))
+		service := &PublicDashboardServiceImpl{log: log.New("test.logger"), store: store}
+
+		token, err := service.UseToken(context.Background(), "accesstoken")
+		require.NoError(t, err)
+		assert.NotNil(t, token)
+	})
+
+	t.Run("returns an error for a revoked or expired access token", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+		for _, token := range []*PublicDashboardToken{{Uid: "token1", RevokedAt: &past}, {Uid: "token1", ExpiresAt: &past}} {
+			store := NewFakePublicDashboardStore(t)
+			store.On("FindTokenByToken", mock.Anything, "accesstoken").Return(token, nil)
+			service := &PublicDashboardServiceImpl{log: log.New("test.logger"), store: store}
+
+			_, err := service.UseToken(context.Background(), "accesstoken")
+			require.ErrorIs(t, err, ErrPublicDashboardNotFound)
+		}
+	})
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards/templatevars"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+// variableHideVariable is the value of the hide field of a template variable that is not displayed
+const variableHideVariable = 2
+
+// sanitizeVariables hides the template variables viewers are not allowed to change, and restricts the options of
+// the others to the allowed values. Variable queries are removed like panel queries.
+func sanitizeVariables(data *simplejson.Json, settings *models.VariableSettings) {
+	for _, variableObj := range data.GetPath("templating", "list").MustArray() {
+		variable := simplejson.NewFromAny(variableObj)
+		name := variable.Get("name").MustString()
+		variableType := variable.Get("type").MustString()
+
+		if variableType == "query" {
+			variable.Del("query")
+			variable.Del("definition")
+			// the options can't be refreshed from the datasource, so the saved ones are used
+			variable.Set("refresh", 0)
+		}
+
+		var allowed []string
+		if settings != nil {
+			allowed = settings.AllowedValues[name]
+		}
+		if len(allowed) == 0 || !templatevars.IsSupportedType(variableType) {
+			variable.Set("hide", variableHideVariable)
+			continue
+		}
+
+		options := make([]any, 0, len(allowed))
+		for _, value := range allowed {
+			options = append(options, map[string]any{"text": value, "value": value, "selected": false})
+		}
+		variable.Set("options", options)
+		if variableType == "custom" {
+			// custom variables build their options from the query
+			variable.Set("query", strings.Join(allowed, ","))
+		}
+
+		// the value saved in the dashboard is replaced when it can't be selected
+		current := templatevars.CurrentValues(variable)
+		for _, value := range current {
+			if !settings.IsAllowed(name, value) {
+				current = nil
+				break
+			}
+		}
+		if len(current) == 0 {
+			variable.Set("current", map[string]any{"text": allowed[0], "value": allowed[0], "selected": false})
+		}
+	}
+}
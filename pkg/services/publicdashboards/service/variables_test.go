@@ -0,0 +1,83 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dashboardWithTemplateVariables = `
+{
+  "templating": {
+    "list": [
+      {
+        "name": "job",
+        "type": "query",
+        "query": "label_values(job)",
+        "definition": "label_values(job)",
+        "refresh": 1,
+        "current": {"text": "api", "value": "api"},
+        "options": [{"text": "api", "value": "api"}, {"text": "db", "value": "db"}, {"text": "web", "value": "web"}]
+      },
+      {
+        "name": "env",
+        "type": "custom",
+        "query": "dev,prod",
+        "multi": true,
+        "current": {"text": ["dev", "prod"], "value": ["dev", "prod"]},
+        "options": [{"text": "dev", "value": "dev"}, {"text": "prod", "value": "prod"}]
+      },
+      {
+        "name": "region",
+        "type": "custom",
+        "query": "eu,us",
+        "includeAll": true,
+        "current": {"text": "All", "value": "$__all"},
+        "options": [{"text": "All", "value": "$__all"}, {"text": "eu", "value": "eu"}, {"text": "us", "value": "us"}]
+      },
+      {
+        "name": "cluster",
+        "type": "constant",
+        "query": "c1"
+      },
+      {
+        "name": "filter",
+        "type": "textbox",
+        "query": "x"
+      }
+    ]
+  }
+}`
+
+func TestSanitizeVariables(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+	require.NoError(t, err)
+
+	sanitizeVariables(dashboard, &VariableSettings{AllowedValues: map[string][]string{
+		"job": {"db", "web"},
+		"env": {"dev", "prod"},
+	}})
+
+	list := dashboard.GetPath("templating", "list")
+
+	job := list.GetIndex(0)
+	assert.Nil(t, job.Get("query").Interface())
+	assert.Nil(t, job.Get("definition").Interface())
+	assert.Equal(t, 0, job.Get("refresh").MustInt())
+	assert.Nil(t, job.Get("hide").Interface())
+	assert.Len(t, job.Get("options").MustArray(), 2)
+	// the saved value is not allowed
+	assert.Equal(t, "db", job.GetPath("current", "value").MustString())
+
+	env := list.GetIndex(1)
+	assert.Nil(t, env.Get("hide").Interface())
+	assert.Equal(t, "dev,prod", env.Get("query").MustString())
+	assert.Equal(t, []string{"dev", "prod"}, env.GetPath("current", "value").MustStringArray())
+
+	for i := 2; i < 5; i++ {
+		assert.Equal(t, variableHideVariable, list.GetIndex(i).Get("hide").MustInt())
+	}
+}
@@ -1,18 +1,51 @@
 package validation
 
 import (
+	"regexp"
+	"time"
+
 	"github.com/google/uuid"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/tsdb/legacydata"
 	"github.com/grafana/grafana/pkg/util"
 )
 
+// variableNameRegex matches the names template variables can have
+var variableNameRegex = regexp.MustCompile(`^\w+$`)
+
 func ValidatePublicDashboard(dto *SavePublicDashboardDTO) error {
 	// if it is empty we override it in the service with public for retro compatibility
 	if dto.PublicDashboard.Share != "" && !IsValidShareType(dto.PublicDashboard.Share) {
 		return ErrInvalidShareType.Errorf("ValidateSavePublicDashboard: invalid share type")
 	}
 
+	if dto.PublicDashboard.VariableSettings != nil {
+		for name, values := range dto.PublicDashboard.VariableSettings.AllowedValues {
+			if !variableNameRegex.MatchString(name) {
+				return ErrInvalidVariable.Errorf("ValidateSavePublicDashboard: invalid variable name %q", name)
+			}
+			if len(values) == 0 {
+				return ErrInvalidVariable.Errorf("ValidateSavePublicDashboard: no values allowed for variable %s", name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func ValidatePublicDashboardToken(dto *SavePublicDashboardTokenDTO) error {
+	if dto.Token == nil || dto.Token.Name == "" || len(dto.Token.Name) > 190 {
+		return ErrInvalidTokenName.Errorf("ValidatePublicDashboardToken: name should be between 1 and 190 characters")
+	}
+
+	if dto.Token.ExpiresAt != nil && !dto.Token.ExpiresAt.After(time.Now()) {
+		return ErrInvalidTokenExpiry.Errorf("ValidatePublicDashboardToken: expiry should be in the future")
+	}
+
+	if dto.Token.RateLimit < 0 {
+		return ErrInvalidRateLimit.Errorf("ValidatePublicDashboardToken: rateLimit should be greater than or equal to 0")
+	}
+
 	return nil
 }
 
@@ -38,6 +71,18 @@ func ValidateQueryPublicDashboardRequest(req PublicDashboardQueryDTO, pd *Public
 		}
 	}
 
+	// viewers can only select the values allowed for each variable, the values end up in the queries
+	for name, values := range req.Variables {
+		if len(values) == 0 {
+			return ErrInvalidVariable.Errorf("ValidateQueryPublicDashboardRequest: no value for variable %s", name)
+		}
+		for _, value := range values {
+			if !pd.VariableSettings.IsAllowed(name, value) {
+				return ErrInvalidVariable.Errorf("ValidateQueryPublicDashboardRequest: value of variable %s is not allowed", name)
+			}
+		}
+	}
+
 	return nil
 }
 
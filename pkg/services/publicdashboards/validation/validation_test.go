@@ -1,7 +1,9 @@
 package validation
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/stretchr/testify/assert"
@@ -22,6 +24,68 @@ func TestValidatePublicDashboard(t *testing.T) {
 		err := ValidatePublicDashboard(dto)
 		require.Error(t, err)
 	})
+
+	t.Run("Returns no error when valid variable settings are received", func(t *testing.T) {
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", UserId: 1, PublicDashboard: &PublicDashboardDTO{
+			VariableSettings: &VariableSettings{AllowedValues: map[string][]string{"env": {"dev", "prod"}}},
+		}}
+
+		err := ValidatePublicDashboard(dto)
+		require.NoError(t, err)
+	})
+
+	t.Run("Returns error when variable name is invalid", func(t *testing.T) {
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", UserId: 1, PublicDashboard: &PublicDashboardDTO{
+			VariableSettings: &VariableSettings{AllowedValues: map[string][]string{"${env}": {"dev"}}},
+		}}
+
+		err := ValidatePublicDashboard(dto)
+		require.ErrorIs(t, err, ErrInvalidVariable)
+	})
+
+	t.Run("Returns error when variable has no allowed values", func(t *testing.T) {
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", UserId: 1, PublicDashboard: &PublicDashboardDTO{
+			VariableSettings: &VariableSettings{AllowedValues: map[string][]string{"env": {}}},
+		}}
+
+		err := ValidatePublicDashboard(dto)
+		require.ErrorIs(t, err, ErrInvalidVariable)
+	})
+}
+
+func TestValidatePublicDashboardToken(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	t.Run("Returns no error when valid token is received", func(t *testing.T) {
+		dto := &SavePublicDashboardTokenDTO{DashboardUid: "abc123", Token: &PublicDashboardTokenDTO{Name: "status page", ExpiresAt: &future, RateLimit: 60}}
+
+		err := ValidatePublicDashboardToken(dto)
+		require.NoError(t, err)
+	})
+
+	t.Run("Returns error when name is empty or too long", func(t *testing.T) {
+		for _, name := range []string{"", strings.Repeat("a", 191)} {
+			dto := &SavePublicDashboardTokenDTO{DashboardUid: "abc123", Token: &PublicDashboardTokenDTO{Name: name}}
+
+			err := ValidatePublicDashboardToken(dto)
+			require.ErrorIs(t, err, ErrInvalidTokenName)
+		}
+	})
+
+	t.Run("Returns error when expiry is in the past", func(t *testing.T) {
+		dto := &SavePublicDashboardTokenDTO{DashboardUid: "abc123", Token: &PublicDashboardTokenDTO{Name: "status page", ExpiresAt: &past}}
+
+		err := ValidatePublicDashboardToken(dto)
+		require.ErrorIs(t, err, ErrInvalidTokenExpiry)
+	})
+
+	t.Run("Returns error when rate limit is negative", func(t *testing.T) {
+		dto := &SavePublicDashboardTokenDTO{DashboardUid: "abc123", Token: &PublicDashboardTokenDTO{Name: "status page", RateLimit: -1}}
+
+		err := ValidatePublicDashboardToken(dto)
+		require.ErrorIs(t, err, ErrInvalidRateLimit)
+	})
 }
 
 func TestValidateQueryPublicDashboardRequest(t *testing.T) {
@@ -129,6 +193,52 @@ func TestValidateQueryPublicDashboardRequest(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Returns no error when variable values are allowed",
+			args: args{
+				req: PublicDashboardQueryDTO{
+					Variables: map[string][]string{"env": {"dev", "prod"}},
+				},
+				pd: &PublicDashboard{
+					VariableSettings: VariableSettings{AllowedValues: map[string][]string{"env": {"dev", "prod"}}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Returns validation error when variable value is not allowed",
+			args: args{
+				req: PublicDashboardQueryDTO{
+					Variables: map[string][]string{"env": {"dev", "\"} or vector(1)"}},
+				},
+				pd: &PublicDashboard{
+					VariableSettings: VariableSettings{AllowedValues: map[string][]string{"env": {"dev", "prod"}}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Returns validation error when variable is not allowed",
+			args: args{
+				req: PublicDashboardQueryDTO{
+					Variables: map[string][]string{"env": {"dev"}},
+				},
+				pd: &PublicDashboard{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Returns validation error when variable has no value",
+			args: args{
+				req: PublicDashboardQueryDTO{
+					Variables: map[string][]string{"env": {}},
+				},
+				pd: &PublicDashboard{
+					VariableSettings: VariableSettings{AllowedValues: map[string][]string{"env": {"dev"}}},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,146 @@
+package query
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/services/datasources"
+)
+
+var deduplicatedRequestsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: metrics.ExporterName,
+	Subsystem: "query",
+	Name:      "deduplicated_requests_total",
+	Help:      "Number of datasource requests that were served by an identical request already in flight",
+}, []string{"datasource_type"})
+
+// queryDataDeduplicated sends the request to the datasource, sharing the response with any identical
+// request that is already in flight instead of querying the datasource again.
+func (s *ServiceImpl) queryDataDeduplicated(ctx context.Context, ds *datasources.DataSource, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	if !s.deduplicateQueries || !canDeduplicate(s.cfg.SendUserHeader, ds) {
+		return s.queryDataLimited(ctx, ds, req)
+	}
+
+	key, err := deduplicationKey(req)
+	if err != nil {
+		s.log.FromContext(ctx).Warn("Failed to build query deduplication key", "error", err)
+		return s.queryDataLimited(ctx, ds, req)
+	}
+
+	executed := false
+	ch := s.inflight.DoChan(key, func() (any, error) {
+		executed = true
+		return s.queryDataLimited(ctx, ds, req)
+	})
+
+	var res singleflight.Result
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res = <-ch:
+	}
+
+	if !executed {
+		deduplicatedRequestsCounter.WithLabelValues(ds.Type).Inc()
+		// The request that queried the datasource may have been canceled independently of this one.
+		if errors.Is(res.Err, context.Canceled) && ctx.Err() == nil {
+			return s.queryDataLimited(ctx, ds, req)
+		}
+	}
+	if res.Err != nil {
+		return nil, res.Err
+	}
+
+	resp, _ := res.Val.(*backend.QueryDataResponse)
+	if resp == nil || !res.Shared {
+		return resp, nil
+	}
+	// Every caller gets its own copy, as responses may be modified further down the line.
+	return resp.DeepCopy(), nil
+}
+
+// inflightGroup tracks the requests currently sent to datasources. It is implemented by singleflight.Group.
+type inflightGroup interface {
+	DoChan(key string, fn func() (any, error)) <-chan singleflight.Result
+}
+
+// canDeduplicate returns false for datasources whose responses may depend on the user making the request.
+func canDeduplicate(sendUserHeader bool, ds *datasources.DataSource) bool {
+	if ds.JsonData != nil && ds.JsonData.Get("disableQueryDeduplication").MustBool(false) {
+		return false
+	}
+	return !datasources.ResponsesDependOnUser(sendUserHeader, ds.JsonData)
+}
+
+type deduplicationKeyQuery struct {
+	RefID         string          `json:"refId"`
+	QueryType     string          `json:"queryType"`
+	MaxDataPoints int64           `json:"maxDataPoints"`
+	Interval      int64           `json:"interval"`
+	From          int64           `json:"from"`
+	To            int64           `json:"to"`
+	Model         json.RawMessage `json:"model"`
+}
+
+// deduplicationKey identifies requests sending the same queries and headers over the same time range to the same datasource.
+func deduplicationKey(req *backend.QueryDataRequest) (string, error) {
+	k := struct {
+		OrgID             int64                   `json:"orgId"`
+		PluginID          string                  `json:"pluginId"`
+		DatasourceUID     string                  `json:"datasourceUid"`
+		DatasourceUpdated int64                   `json:"datasourceUpdated"`
+		Headers           map[string]string       `json:"headers,omitempty"`
+		Queries           []deduplicationKeyQuery `json:"queries"`
+	}{
+		OrgID:    req.PluginContext.OrgID,
+		PluginID: req.PluginContext.PluginID,
+		// Headers may carry credentials or cookies forwarded from the incoming request.
+		Headers: req.Headers,
+	}
+	if ds := req.PluginContext.DataSourceInstanceSettings; ds != nil {
+		k.DatasourceUID = ds.UID
+		k.DatasourceUpdated = ds.Updated.UnixNano()
+	}
+
+	for _, q := range req.Queries {
+		model := map[string]any{}
+		dec := json.NewDecoder(bytes.NewReader(q.JSON))
+		dec.UseNumber()
+		if err := dec.Decode(&model); err != nil {
+			return "", err
+		}
+		// The request ID is unique per panel request and does not change the result.
+		delete(model, "requestId")
+		b, err := json.Marshal(model)
+		if err != nil {
+			return "", err
+		}
+
+		k.Queries = append(k.Queries, deduplicationKeyQuery{
+			RefID:         q.RefID,
+			QueryType:     q.QueryType,
+			MaxDataPoints: q.MaxDataPoints,
+			Interval:      q.Interval.Milliseconds(),
+			From:          q.TimeRange.From.UnixNano(),
+			To:            q.TimeRange.To.UnixNano(),
+			Model:         b,
+		})
+	}
+
+	b, err := json.Marshal(k)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
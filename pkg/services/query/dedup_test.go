@@ -0,0 +1,155 @@
+package query
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestQueryDataDeduplicated(t *testing.T) {
+	newRequest := func(requestID string) *backend.QueryDataRequest {
+		return &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{
+				OrgID:                      1,
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{UID: "ds1"},
+			},
+			Queries: []backend.DataQuery{{
+				RefID: "A",
+				JSON:  []byte(`{"refId":"A","rawSql":"SELECT 1","requestId":"` + requestID + `"}`),
+			}},
+		}
+	}
+
+	t.Run("identical requests in flight share one datasource query", func(t *testing.T) {
+		const callers = 5
+		pc := &blockingPluginClient{release: make(chan struct{})}
+		inflight := &joinNotifyingGroup{joined: make(chan struct{}, callers)}
+		s := &ServiceImpl{cfg: setting.NewCfg(), pluginClient: pc, deduplicateQueries: true, inflight: inflight, log: log.New("test")}
+		ds := &datasources.DataSource{UID: "ds1", Type: "mysql"}
+
+		responses := make([]*backend.QueryDataResponse, callers)
+		var wg sync.WaitGroup
+		for i := 0; i < callers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				resp, err := s.queryDataDeduplicated(context.Background(), ds, newRequest(string(rune('a'+i))))
+				assert.NoError(t, err)
+				responses[i] = resp
+			}(i)
+		}
+
+		// The datasource query blocks until every caller has joined it.
+		for i := 0; i < callers; i++ {
+			<-inflight.joined
+		}
+		close(pc.release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), pc.calls.Load())
+		for i := 1; i < callers; i++ {
+			require.NotNil(t, responses[i])
+			assert.Equal(t, responses[0].Responses["A"].Frames[0].Name, responses[i].Responses["A"].Frames[0].Name)
+			assert.NotSame(t, responses[0].Responses["A"].Frames[0], responses[i].Responses["A"].Frames[0])
+		}
+	})
+
+	t.Run("user scoped datasources are not deduplicated", func(t *testing.T) {
+		pc := &blockingPluginClient{release: make(chan struct{})}
+		close(pc.release)
+		s := &ServiceImpl{cfg: setting.NewCfg(), pluginClient: pc, deduplicateQueries: true, inflight: &singleflight.Group{}, log: log.New("test")}
+		ds := &datasources.DataSource{UID: "ds1", Type: "mysql", JsonData: simplejson.NewFromAny(map[string]any{"oauthPassThru": true})}
+
+		_, err := s.queryDataDeduplicated(context.Background(), ds, newRequest("a"))
+		require.NoError(t, err)
+		_, err = s.queryDataDeduplicated(context.Background(), ds, newRequest("a"))
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), pc.calls.Load())
+	})
+}
+
+func TestCanDeduplicate(t *testing.T) {
+	assert.True(t, canDeduplicate(false, &datasources.DataSource{}))
+	assert.True(t, canDeduplicate(false, &datasources.DataSource{JsonData: simplejson.New()}))
+	assert.False(t, canDeduplicate(true, &datasources.DataSource{}))
+	assert.False(t, canDeduplicate(false, &datasources.DataSource{JsonData: simplejson.NewFromAny(map[string]any{"oauthPassThru": true})}))
+	assert.False(t, canDeduplicate(false, &datasources.DataSource{JsonData: simplejson.NewFromAny(map[string]any{"disableQueryDeduplication": true})}))
+	assert.False(t, canDeduplicate(false, &datasources.DataSource{JsonData: simplejson.NewFromAny(map[string]any{"teamHttpHeaders": map[string]any{}})}))
+	assert.False(t, canDeduplicate(false, &datasources.DataSource{JsonData: simplejson.NewFromAny(map[string]any{"keepCookies": []any{"session"}})}))
+}
+
+func TestDeduplicationKey(t *testing.T) {
+	newRequest := func(model string, from time.Time) *backend.QueryDataRequest {
+		return &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{OrgID: 1},
+			Queries: []backend.DataQuery{{
+				RefID:     "A",
+				JSON:      []byte(model),
+				TimeRange: backend.TimeRange{From: from, To: from.Add(time.Hour)},
+			}},
+		}
+	}
+	now := time.Now()
+
+	key := func(req *backend.QueryDataRequest) string {
+		k, err := deduplicationKey(req)
+		require.NoError(t, err)
+		return k
+	}
+
+	base := key(newRequest(`{"expr":"up","requestId":"Q1"}`, now))
+	assert.Equal(t, base, key(newRequest(`{"requestId":"Q2", "expr":"up"}`, now)))
+	assert.NotEqual(t, base, key(newRequest(`{"expr":"up"}`, now.Add(time.Second))))
+	assert.NotEqual(t, base, key(newRequest(`{"expr":"down"}`, now)))
+
+	withHeaders := func(req *backend.QueryDataRequest, headers map[string]string) *backend.QueryDataRequest {
+		req.Headers = headers
+		return req
+	}
+	assert.Equal(t, base, key(withHeaders(newRequest(`{"expr":"up"}`, now), map[string]string{})))
+	assert.NotEqual(t, base, key(withHeaders(newRequest(`{"expr":"up"}`, now), map[string]string{"Authorization": "Bearer a"})))
+	assert.NotEqual(t,
+		key(withHeaders(newRequest(`{"expr":"up"}`, now), map[string]string{"Cookie": "session=a"})),
+		key(withHeaders(newRequest(`{"expr":"up"}`, now), map[string]string{"Cookie": "session=b"})),
+	)
+}
+
+// joinNotifyingGroup signals every caller that has joined or started an in-flight request.
+type joinNotifyingGroup struct {
+	singleflight.Group
+	joined chan struct{}
+}
+
+func (g *joinNotifyingGroup) DoChan(key string, fn func() (any, error)) <-chan singleflight.Result {
+	ch := g.Group.DoChan(key, fn)
+	g.joined <- struct{}{}
+	return ch
+}
+
+type blockingPluginClient struct {
+	plugins.Client
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (c *blockingPluginClient) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	c.calls.Add(1)
+	<-c.release
+	return &backend.QueryDataResponse{Responses: backend.Responses{
+		"A": {Frames: data.Frames{data.NewFrame("result", data.NewField("value", nil, []int64{1}))}},
+	}}, nil
+}
@@ -0,0 +1,209 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+var (
+	errQueryQueueFull    = errors.New("too many queries are waiting for this data source, try again later")
+	errQueryQueueTimeout = errors.New("timed out waiting for other queries to this data source to complete")
+)
+
+var (
+	queryQueueDepthGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.ExporterName,
+		Subsystem: "query",
+		Name:      "queue_depth",
+		Help:      "Number of queries waiting for a concurrency slot",
+	}, []string{"scope"})
+
+	queryQueueWaitHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metrics.ExporterName,
+		Subsystem: "query",
+		Name:      "queue_wait_duration_seconds",
+		Help:      "Time queries spent waiting for a concurrency slot",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"scope", "datasource_type"})
+
+	queryQueueRejectedCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.ExporterName,
+		Subsystem: "query",
+		Name:      "queue_rejected_total",
+		Help:      "Number of queries rejected because the queue was full or the wait timed out",
+	}, []string{"scope", "datasource_type", "reason"})
+)
+
+// concurrencyLimiter bounds the number of queries running concurrently per data source and per
+// organization. Queries over a limit wait in a bounded queue until a slot frees up.
+type concurrencyLimiter struct {
+	dsLimit      int
+	orgLimit     int
+	queueSize    int
+	queueTimeout time.Duration
+
+	mu    sync.Mutex
+	slots map[string]*querySlots
+}
+
+type querySlots struct {
+	running chan struct{}
+	waiting atomic.Int64
+	// refs counts the queries holding or waiting for a slot, guarded by the limiter mutex.
+	refs int
+}
+
+func newConcurrencyLimiter(cfg *setting.Cfg) *concurrencyLimiter {
+	section := cfg.SectionWithEnvOverrides("query")
+	return &concurrencyLimiter{
+		dsLimit:      section.Key("datasource_concurrency_limit").MustInt(0),
+		orgLimit:     section.Key("org_concurrency_limit").MustInt(0),
+		queueSize:    section.Key("concurrency_queue_size").MustInt(100),
+		queueTimeout: section.Key("concurrency_queue_timeout").MustDuration(30 * time.Second),
+		slots:        map[string]*querySlots{},
+	}
+}
+
+// acquire waits for a slot for the data source and its organization. The returned function
+// must be called to release the slots once the query completed.
+func (l *concurrencyLimiter) acquire(ctx context.Context, orgID int64, ds *datasources.DataSource) (func(), error) {
+	var releases []func()
+	release := func() {
+		for i := len(releases) - 1; i >= 0; i-- {
+			releases[i]()
+		}
+	}
+
+	dsLimit := l.dsLimit
+	if ds.JsonData != nil {
+		if limit := ds.JsonData.Get("queryConcurrencyLimit").MustInt(0); limit > 0 {
+			dsLimit = limit
+		}
+	}
+	if dsLimit > 0 {
+		// The limit is part of the key so that changing it takes effect for new queries.
+		r, err := l.acquireSlot(ctx, "datasource", fmt.Sprintf("ds:%d:%s:%d", orgID, ds.UID, dsLimit), dsLimit, ds.Type)
+		if err != nil {
+			return nil, err
+		}
+		releases = append(releases, r)
+	}
+
+	if l.orgLimit > 0 {
+		r, err := l.acquireSlot(ctx, "org", fmt.Sprintf("org:%d", orgID), l.orgLimit, ds.Type)
+		if err != nil {
+			release()
+			return nil, err
+		}
+		releases = append(releases, r)
+	}
+
+	return release, nil
+}
+
+func (l *concurrencyLimiter) acquireSlot(ctx context.Context, scope, key string, limit int, dsType string) (func(), error) {
+	l.mu.Lock()
+	s, ok := l.slots[key]
+	if !ok {
+		s = &querySlots{running: make(chan struct{}, limit)}
+		l.slots[key] = s
+	}
+	s.refs++
+	l.mu.Unlock()
+
+	// Entries are dropped once no query holds or waits for their slots, so that the map does
+	// not keep one entry for every data source and organization that was ever queried.
+	unref := func() {
+		l.mu.Lock()
+		s.refs--
+		if s.refs == 0 {
+			delete(l.slots, key)
+		}
+		l.mu.Unlock()
+	}
+	release := func() {
+		<-s.running
+		unref()
+	}
+
+	select {
+	case s.running <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	if s.waiting.Add(1) > int64(l.queueSize) {
+		s.waiting.Add(-1)
+		unref()
+		queryQueueRejectedCounter.WithLabelValues(scope, dsType, "queue_full").Inc()
+		return nil, errQueryQueueFull
+	}
+	queryQueueDepthGauge.WithLabelValues(scope).Inc()
+	defer func() {
+		s.waiting.Add(-1)
+		queryQueueDepthGauge.WithLabelValues(scope).Dec()
+	}()
+
+	var timeout <-chan time.Time
+	if l.queueTimeout > 0 {
+		timer := time.NewTimer(l.queueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	start := time.Now()
+	select {
+	case s.running <- struct{}{}:
+		queryQueueWaitHistogram.WithLabelValues(scope, dsType).Observe(time.Since(start).Seconds())
+		return release, nil
+	case <-timeout:
+		unref()
+		queryQueueRejectedCounter.WithLabelValues(scope, dsType, "timeout").Inc()
+		return nil, errQueryQueueTimeout
+	case <-ctx.Done():
+		unref()
+		return nil, ctx.Err()
+	}
+}
+
+// queryDataLimited sends the request to the data source once the concurrency limits allow it. Requests
+// rejected by the limiter get an error response for each of their queries.
+func (s *ServiceImpl) queryDataLimited(ctx context.Context, ds *datasources.DataSource, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	if s.limiter == nil {
+		return s.pluginClient.QueryData(ctx, req)
+	}
+
+	release, err := s.limiter.acquire(ctx, req.PluginContext.OrgID, ds)
+	if err != nil {
+		var status backend.Status
+		switch {
+		case errors.Is(err, errQueryQueueFull):
+			status = backend.StatusTooManyRequests
+		case errors.Is(err, errQueryQueueTimeout):
+			status = backend.StatusTimeout
+		default:
+			return nil, err
+		}
+
+		resp := backend.NewQueryDataResponse()
+		for _, q := range req.Queries {
+			resp.Responses[q.RefID] = backend.DataResponse{Error: err, Status: status}
+		}
+		return resp, nil
+	}
+	defer release()
+
+	return s.pluginClient.QueryData(ctx, req)
+}
@@ -0,0 +1,154 @@
+package query
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/datasources"
+)
+
+func TestConcurrencyLimiter(t *testing.T) {
+	newLimiter := func(dsLimit, orgLimit, queueSize int, queueTimeout time.Duration) *concurrencyLimiter {
+		return &concurrencyLimiter{
+			dsLimit:      dsLimit,
+			orgLimit:     orgLimit,
+			queueSize:    queueSize,
+			queueTimeout: queueTimeout,
+			slots:        map[string]*querySlots{},
+		}
+	}
+	ds1 := &datasources.DataSource{UID: "ds1", Type: "mysql"}
+	ds2 := &datasources.DataSource{UID: "ds2", Type: "mysql"}
+
+	t.Run("queued queries run once a slot is released", func(t *testing.T) {
+		l := newLimiter(1, 0, 10, time.Second)
+		release, err := l.acquire(context.Background(), 1, ds1)
+		require.NoError(t, err)
+
+		acquired := make(chan struct{})
+		go func() {
+			r, err := l.acquire(context.Background(), 1, ds1)
+			assert.NoError(t, err)
+			r()
+			close(acquired)
+		}()
+
+		select {
+		case <-acquired:
+			t.Fatal("query should be queued")
+		case <-time.After(50 * time.Millisecond):
+		}
+		release()
+		<-acquired
+	})
+
+	t.Run("limits are per data source", func(t *testing.T) {
+		l := newLimiter(1, 0, 0, time.Second)
+		_, err := l.acquire(context.Background(), 1, ds1)
+		require.NoError(t, err)
+		_, err = l.acquire(context.Background(), 1, ds2)
+		require.NoError(t, err)
+		_, err = l.acquire(context.Background(), 2, ds1)
+		require.NoError(t, err)
+	})
+
+	t.Run("the data source limit can be overridden", func(t *testing.T) {
+		l := newLimiter(1, 0, 0, time.Second)
+		ds := &datasources.DataSource{UID: "ds1", JsonData: simplejson.NewFromAny(map[string]any{"queryConcurrencyLimit": 2})}
+		_, err := l.acquire(context.Background(), 1, ds)
+		require.NoError(t, err)
+		_, err = l.acquire(context.Background(), 1, ds)
+		require.NoError(t, err)
+		_, err = l.acquire(context.Background(), 1, ds)
+		require.ErrorIs(t, err, errQueryQueueFull)
+	})
+
+	t.Run("org limit applies across data sources", func(t *testing.T) {
+		l := newLimiter(0, 1, 0, time.Second)
+		_, err := l.acquire(context.Background(), 1, ds1)
+		require.NoError(t, err)
+		_, err = l.acquire(context.Background(), 1, ds2)
+		require.ErrorIs(t, err, errQueryQueueFull)
+	})
+
+	t.Run("queued queries time out", func(t *testing.T) {
+		l := newLimiter(1, 0, 1, 10*time.Millisecond)
+		_, err := l.acquire(context.Background(), 1, ds1)
+		require.NoError(t, err)
+		_, err = l.acquire(context.Background(), 1, ds1)
+		require.ErrorIs(t, err, errQueryQueueTimeout)
+	})
+
+	t.Run("queued queries are canceled with their context", func(t *testing.T) {
+		l := newLimiter(1, 0, 1, time.Second)
+		_, err := l.acquire(context.Background(), 1, ds1)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = l.acquire(ctx, 1, ds1)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("data source slot is released when the org slot is not available", func(t *testing.T) {
+		l := newLimiter(1, 1, 0, time.Second)
+		release, err := l.acquire(context.Background(), 1, ds1)
+		require.NoError(t, err)
+
+		// ds2 gets its data source slot, then fails on the org slot held by the ds1 query.
+		_, err = l.acquire(context.Background(), 1, ds2)
+		require.ErrorIs(t, err, errQueryQueueFull)
+
+		release()
+		_, err = l.acquire(context.Background(), 1, ds2)
+		require.NoError(t, err)
+	})
+
+	t.Run("slots are dropped once no query uses them", func(t *testing.T) {
+		l := newLimiter(1, 1, 1, 10*time.Millisecond)
+		release, err := l.acquire(context.Background(), 1, ds1)
+		require.NoError(t, err)
+		_, err = l.acquire(context.Background(), 1, ds1)
+		require.ErrorIs(t, err, errQueryQueueTimeout)
+		require.Len(t, l.slots, 2)
+
+		release()
+		assert.Empty(t, l.slots)
+	})
+}
+
+func TestQueryDataLimited(t *testing.T) {
+	pc := &blockingPluginClient{release: make(chan struct{})}
+	close(pc.release)
+	s := &ServiceImpl{
+		pluginClient: pc,
+		limiter:      &concurrencyLimiter{dsLimit: 1, queueSize: 0, slots: map[string]*querySlots{}},
+	}
+	ds := &datasources.DataSource{UID: "ds1", Type: "mysql"}
+	req := &backend.QueryDataRequest{
+		PluginContext: backend.PluginContext{OrgID: 1},
+		Queries:       []backend.DataQuery{{RefID: "A"}, {RefID: "B"}},
+	}
+
+	release, err := s.limiter.acquire(context.Background(), 1, ds)
+	require.NoError(t, err)
+
+	resp, err := s.queryDataLimited(context.Background(), ds, req)
+	require.NoError(t, err)
+	require.Len(t, resp.Responses, 2)
+	assert.Equal(t, backend.StatusTooManyRequests, resp.Responses["A"].Status)
+	assert.ErrorIs(t, resp.Responses["B"].Error, errQueryQueueFull)
+	assert.Equal(t, int32(0), pc.calls.Load())
+
+	release()
+	resp, err = s.queryDataLimited(context.Background(), ds, req)
+	require.NoError(t, err)
+	assert.NoError(t, resp.Responses["A"].Error)
+	assert.Equal(t, int32(1), pc.calls.Load())
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/singleflight"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/components/simplejson"
@@ -54,6 +55,9 @@ func ProvideService(
 		pCtxProvider:           pCtxProvider,
 		log:                    log.New("query_data"),
 		concurrentQueryLimit:   cfg.SectionWithEnvOverrides("query").Key("concurrent_query_limit").MustInt(runtime.NumCPU()),
+		deduplicateQueries:     cfg.SectionWithEnvOverrides("query").Key("deduplicate_queries").MustBool(false),
+		inflight:               &singleflight.Group{},
+		limiter:                newConcurrencyLimiter(cfg),
 	}
 	g.log.Info("Query Service initialization")
 	return g
@@ -77,6 +81,9 @@ type ServiceImpl struct {
 	pCtxProvider           *plugincontext.Provider
 	log                    log.Logger
 	concurrentQueryLimit   int
+	deduplicateQueries     bool
+	inflight               inflightGroup
+	limiter                *concurrencyLimiter
 }
 
 // Run ServiceImpl.
@@ -268,7 +275,7 @@ func (s *ServiceImpl) handleQuerySingleDatasource(ctx context.Context, user iden
 		req.Queries = append(req.Queries, q.query)
 	}
 
-	return s.pluginClient.QueryData(ctx, req)
+	return s.queryDataDeduplicated(ctx, ds, req)
 }
 
 // parseRequest parses a request into parsed queries grouped by datasource uid
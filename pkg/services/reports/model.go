@@ -0,0 +1,181 @@
+package reports
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrReportNotFound    = errutil.NotFound("reports.notFound", errutil.WithPublicMessage("Report not found"))
+	ErrDashboardNotFound = errutil.NotFound("reports.dashboardNotFound", errutil.WithPublicMessage("Dashboard not found"))
+	ErrCreatorNotFound   = errutil.NotFound("reports.creatorNotFound", errutil.WithPublicMessage("The creator of the report is not a member of the organization"))
+	ErrDashboardDenied   = errutil.Forbidden("reports.dashboardDenied", errutil.WithPublicMessage("The creator of the report can't view the dashboard"))
+
+	ErrBadRequest        = errutil.BadRequest("reports.badRequest")
+	ErrInvalidName       = errutil.BadRequest("reports.invalidName", errutil.WithPublicMessage("Invalid report name"))
+	ErrInvalidSchedule   = errutil.BadRequest("reports.invalidSchedule", errutil.WithPublicMessage("Invalid report schedule"))
+	ErrInvalidTimezone   = errutil.BadRequest("reports.invalidTimezone", errutil.WithPublicMessage("Invalid report timezone"))
+	ErrInvalidTimeRange  = errutil.BadRequest("reports.invalidTimeRange", errutil.WithPublicMessage("Both from and to should be set"))
+	ErrInvalidRecipients = errutil.BadRequest("reports.invalidRecipients", errutil.WithPublicMessage("Invalid report recipients"))
+	ErrInvalidFormat     = errutil.BadRequest("reports.invalidFormat", errutil.WithPublicMessage("Invalid report format"))
+	ErrInvalidVariable   = errutil.BadRequest("reports.invalidVariable", errutil.WithPublicMessage("Invalid template variable"))
+
+	ErrSendFailed = errutil.Internal("reports.sendFailed", errutil.WithPublicMessage("Failed to send the report"))
+)
+
+// Format is the format of the file attached to the report emails
+type Format string
+
+const (
+	FormatPDF Format = "pdf"
+	FormatPNG Format = "png"
+)
+
+// Status is the outcome of sending a report
+type Status string
+
+const (
+	StatusSent   Status = "sent"
+	StatusFailed Status = "failed"
+)
+
+// Report is the definition of a dashboard report sent by email on a schedule
+type Report struct {
+	ID           int64     `json:"-" xorm:"pk autoincr 'id'"`
+	UID          string    `json:"uid" xorm:"uid"`
+	OrgID        int64     `json:"-" xorm:"org_id"`
+	Name         string    `json:"name"`
+	DashboardUID string    `json:"dashboardUid" xorm:"dashboard_uid"`
+	Variables    Variables `json:"variables"`
+	// From and To are the time range of the dashboard, the time range saved in the dashboard is used when empty
+	From string `json:"from" xorm:"time_from"`
+	To   string `json:"to" xorm:"time_to"`
+	// Schedule is a cron expression evaluated in Timezone
+	Schedule   string     `json:"schedule"`
+	Timezone   string     `json:"timezone"`
+	Recipients Recipients `json:"recipients"`
+	Message    string     `json:"message"`
+	Format     Format     `json:"format"`
+	Enabled    bool       `json:"enabled"`
+	NextSendAt *time.Time `json:"nextSendAt,omitempty" xorm:"next_send_at"`
+	CreatedBy  int64      `json:"createdBy" xorm:"created_by"`
+	Created    time.Time  `json:"created"`
+	Updated    time.Time  `json:"updated"`
+}
+
+func (r Report) TableName() string {
+	return "scheduled_report"
+}
+
+// ReportHistory records a report being sent
+type ReportHistory struct {
+	ID         int64      `json:"id" xorm:"pk autoincr 'id'"`
+	ReportUID  string     `json:"reportUid" xorm:"report_uid"`
+	OrgID      int64      `json:"-" xorm:"org_id"`
+	Name       string     `json:"name"`
+	Recipients Recipients `json:"recipients"`
+	Format     Format     `json:"format"`
+	Status     Status     `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	// Manual is set when the report was sent through the API instead of by the scheduler
+	Manual bool      `json:"manual"`
+	SentAt time.Time `json:"sentAt" xorm:"sent_at"`
+}
+
+func (h ReportHistory) TableName() string {
+	return "scheduled_report_history"
+}
+
+// Variables are the values of the template variables of the dashboard by variable name, the values saved in the
+// dashboard are used for the other variables
+type Variables map[string][]string
+
+func (v *Variables) FromDB(data []byte) error {
+	*v = Variables{}
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
+
+func (v *Variables) ToDB() ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Recipients are the email addresses a report is sent to
+type Recipients []string
+
+func (r *Recipients) FromDB(data []byte) error {
+	*r = Recipients{}
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, r)
+}
+
+func (r *Recipients) ToDB() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// ReportDTO is the part of a report that users can change
+type ReportDTO struct {
+	Name         string     `json:"name"`
+	DashboardUID string     `json:"dashboardUid"`
+	Variables    Variables  `json:"variables"`
+	From         string     `json:"from"`
+	To           string     `json:"to"`
+	Schedule     string     `json:"schedule"`
+	Timezone     string     `json:"timezone"`
+	Recipients   Recipients `json:"recipients"`
+	Message      string     `json:"message"`
+	Format       Format     `json:"format"`
+	Enabled      bool       `json:"enabled"`
+}
+
+//
+// COMMANDS
+//
+
+type CreateReportCommand struct {
+	ReportDTO
+	OrgID  int64 `json:"-"`
+	UserID int64 `json:"-"`
+}
+
+type UpdateReportCommand struct {
+	ReportDTO
+	UID   string `json:"-"`
+	OrgID int64  `json:"-"`
+}
+
+type DeleteReportCommand struct {
+	UID   string
+	OrgID int64
+}
+
+type SendReportCommand struct {
+	UID   string
+	OrgID int64
+}
+
+//
+// QUERIES
+//
+
+type GetReportQuery struct {
+	UID   string
+	OrgID int64
+}
+
+type ListReportsQuery struct {
+	OrgID int64
+}
+
+type ListReportHistoryQuery struct {
+	OrgID int64
+	// ReportUID limits the history to a single report when set
+	ReportUID string
+	Limit     int
+}
@@ -0,0 +1,17 @@
+package reports
+
+import (
+	"context"
+)
+
+// Service manages the report definitions of the organizations and sends the reports by email
+type Service interface {
+	Create(ctx context.Context, cmd *CreateReportCommand) (*Report, error)
+	Update(ctx context.Context, cmd *UpdateReportCommand) (*Report, error)
+	Delete(ctx context.Context, cmd *DeleteReportCommand) error
+	Get(ctx context.Context, query *GetReportQuery) (*Report, error)
+	List(ctx context.Context, query *ListReportsQuery) ([]*Report, error)
+	// Send renders and sends a report right away, independently of its schedule
+	Send(ctx context.Context, cmd *SendReportCommand) (*ReportHistory, error)
+	ListHistory(ctx context.Context, query *ListReportHistoryQuery) ([]*ReportHistory, error)
+}
@@ -0,0 +1,246 @@
+package reportsimpl
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/reports"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints() {
+	s.routeRegister.Group("/api/scheduled-reports", func(reportRoute routing.RouteRegister) {
+		reportRoute.Get("/", middleware.ReqOrgAdmin, routing.Wrap(s.listHandler))
+		reportRoute.Post("/", middleware.ReqOrgAdmin, routing.Wrap(s.createHandler))
+		reportRoute.Get("/history", middleware.ReqOrgAdmin, routing.Wrap(s.listHistoryHandler))
+		reportRoute.Get("/:uid", middleware.ReqOrgAdmin, routing.Wrap(s.getHandler))
+		reportRoute.Put("/:uid", middleware.ReqOrgAdmin, routing.Wrap(s.updateHandler))
+		reportRoute.Delete("/:uid", middleware.ReqOrgAdmin, routing.Wrap(s.deleteHandler))
+		reportRoute.Post("/:uid/send", middleware.ReqOrgAdmin, routing.Wrap(s.sendHandler))
+		reportRoute.Get("/:uid/history", middleware.ReqOrgAdmin, routing.Wrap(s.listHistoryHandler))
+	})
+}
+
+// swagger:route GET /scheduled-reports scheduled_reports listScheduledReports
+//
+// Get the reports of the organization.
+//
+// Responses:
+// 200: listScheduledReportsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) listHandler(c *contextmodel.ReqContext) response.Response {
+	result, err := s.List(c.Req.Context(), &reports.ListReportsQuery{OrgID: c.SignedInUser.GetOrgID()})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get reports", err)
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route POST /scheduled-reports scheduled_reports createScheduledReport
+//
+// Create a report.
+//
+// The dashboard is rendered and sent by email to the recipients on the schedule of the report.
+//
+// Responses:
+// 200: scheduledReportResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) createHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := reports.CreateReportCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Err(reports.ErrBadRequest.Errorf("createHandler: bad request data: %w", err))
+	}
+	cmd.OrgID = c.SignedInUser.GetOrgID()
+	cmd.UserID = c.SignedInUser.UserID
+
+	report, err := s.Create(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to create report", err)
+	}
+
+	return response.JSON(http.StatusOK, report)
+}
+
+// swagger:route GET /scheduled-reports/{uid} scheduled_reports getScheduledReport
+//
+// Get a report.
+//
+// Responses:
+// 200: scheduledReportResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) getHandler(c *contextmodel.ReqContext) response.Response {
+	report, err := s.Get(c.Req.Context(), &reports.GetReportQuery{UID: web.Params(c.Req)[":uid"], OrgID: c.SignedInUser.GetOrgID()})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get report", err)
+	}
+
+	return response.JSON(http.StatusOK, report)
+}
+
+// swagger:route PUT /scheduled-reports/{uid} scheduled_reports updateScheduledReport
+//
+// Update a report.
+//
+// Responses:
+// 200: scheduledReportResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) updateHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := reports.UpdateReportCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Err(reports.ErrBadRequest.Errorf("updateHandler: bad request data: %w", err))
+	}
+	cmd.UID = web.Params(c.Req)[":uid"]
+	cmd.OrgID = c.SignedInUser.GetOrgID()
+
+	report, err := s.Update(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update report", err)
+	}
+
+	return response.JSON(http.StatusOK, report)
+}
+
+// swagger:route DELETE /scheduled-reports/{uid} scheduled_reports deleteScheduledReport
+//
+// Delete a report and its history.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) deleteHandler(c *contextmodel.ReqContext) response.Response {
+	err := s.Delete(c.Req.Context(), &reports.DeleteReportCommand{UID: web.Params(c.Req)[":uid"], OrgID: c.SignedInUser.GetOrgID()})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete report", err)
+	}
+
+	return response.Success("Report deleted")
+}
+
+// swagger:route POST /scheduled-reports/{uid}/send scheduled_reports sendScheduledReport
+//
+// Send a report now.
+//
+// The report is sent right away, its schedule is not changed.
+//
+// Responses:
+// 200: scheduledReportHistoryResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) sendHandler(c *contextmodel.ReqContext) response.Response {
+	history, err := s.Send(c.Req.Context(), &reports.SendReportCommand{UID: web.Params(c.Req)[":uid"], OrgID: c.SignedInUser.GetOrgID()})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to send report", err)
+	}
+
+	return response.JSON(http.StatusOK, history)
+}
+
+// swagger:route GET /scheduled-reports/history scheduled_reports listScheduledReportHistory
+//
+// Get the reports sent in the organization, most recent first.
+//
+// Responses:
+// 200: listScheduledReportHistoryResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+
+// swagger:route GET /scheduled-reports/{uid}/history scheduled_reports listScheduledReportHistoryByUID
+//
+// Get the history of a report, most recent first.
+//
+// Responses:
+// 200: listScheduledReportHistoryResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) listHistoryHandler(c *contextmodel.ReqContext) response.Response {
+	result, err := s.ListHistory(c.Req.Context(), &reports.ListReportHistoryQuery{
+		OrgID:     c.SignedInUser.GetOrgID(),
+		ReportUID: web.Params(c.Req)[":uid"],
+		Limit:     c.QueryInt("limit"),
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get report history", err)
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:parameters createScheduledReport
+type CreateScheduledReportParams struct {
+	// in:body
+	// required:true
+	Body reports.ReportDTO `json:"body"`
+}
+
+// swagger:parameters getScheduledReport deleteScheduledReport sendScheduledReport listScheduledReportHistoryByUID
+type ScheduledReportUIDParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
+// swagger:parameters updateScheduledReport
+type UpdateScheduledReportParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:body
+	// required:true
+	Body reports.ReportDTO `json:"body"`
+}
+
+// swagger:parameters listScheduledReportHistory listScheduledReportHistoryByUID
+type ListScheduledReportHistoryParams struct {
+	// Maximum number of entries to return, 100 by default
+	// in:query
+	// required:false
+	Limit int `json:"limit"`
+}
+
+// swagger:response listScheduledReportsResponse
+type ListScheduledReportsResponse struct {
+	// in: body
+	Body []*reports.Report `json:"body"`
+}
+
+// swagger:response scheduledReportResponse
+type ScheduledReportResponse struct {
+	// in: body
+	Body reports.Report `json:"body"`
+}
+
+// swagger:response scheduledReportHistoryResponse
+type ScheduledReportHistoryResponse struct {
+	// in: body
+	Body reports.ReportHistory `json:"body"`
+}
+
+// swagger:response listScheduledReportHistoryResponse
+type ListScheduledReportHistoryResponse struct {
+	// in: body
+	Body []*reports.ReportHistory `json:"body"`
+}
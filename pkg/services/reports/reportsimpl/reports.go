@@ -0,0 +1,299 @@
+package reportsimpl
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/reports"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// serverLock is the part of serverlock.ServerLockService used by the scheduler
+type serverLock interface {
+	LockExecuteAndRelease(ctx context.Context, actionName string, maxInterval time.Duration, fn func(ctx context.Context)) error
+}
+
+type Service struct {
+	cfg           *setting.Cfg
+	store         store
+	features      featuremgmt.FeatureToggles
+	lock          serverLock
+	renderer      rendering.Service
+	mailer        notifications.EmailSender
+	dashboards    dashboards.DashboardService
+	users         user.Service
+	permissions   accesscontrol.Service
+	routeRegister routing.RouteRegister
+	log           log.Logger
+	now           func() time.Time
+}
+
+var _ reports.Service = &Service{}
+
+func ProvideService(
+	cfg *setting.Cfg,
+	db db.DB,
+	features featuremgmt.FeatureToggles,
+	serverLockService *serverlock.ServerLockService,
+	renderService rendering.Service,
+	mailer notifications.EmailSender,
+	dashboardService dashboards.DashboardService,
+	userService user.Service,
+	accesscontrolService accesscontrol.Service,
+	routeRegister routing.RouteRegister,
+) *Service {
+	s := &Service{
+		cfg:           cfg,
+		store:         &sqlStore{db: db},
+		features:      features,
+		lock:          serverLockService,
+		renderer:      renderService,
+		mailer:        mailer,
+		dashboards:    dashboardService,
+		users:         userService,
+		permissions:   accesscontrolService,
+		routeRegister: routeRegister,
+		log:           log.New("reports"),
+		now:           time.Now,
+	}
+
+	// Register routes only when reports are enabled
+	if cfg.ScheduledReportsEnabled {
+		s.registerAPIEndpoints()
+	}
+
+	return s
+}
+
+func (s *Service) Create(ctx context.Context, cmd *reports.CreateReportCommand) (*reports.Report, error) {
+	if err := s.validate(ctx, cmd.OrgID, cmd.UserID, &cmd.ReportDTO); err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC().Truncate(time.Second)
+	report := &reports.Report{
+		UID:       util.GenerateShortUID(),
+		OrgID:     cmd.OrgID,
+		CreatedBy: cmd.UserID,
+		Created:   now,
+		Updated:   now,
+	}
+	applyDTO(report, &cmd.ReportDTO)
+
+	nextSendAt, err := nextSendAt(report, now)
+	if err != nil {
+		return nil, err
+	}
+	report.NextSendAt = nextSendAt
+
+	if err := s.store.Insert(ctx, report); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (s *Service) Update(ctx context.Context, cmd *reports.UpdateReportCommand) (*reports.Report, error) {
+	report, err := s.store.Get(ctx, cmd.OrgID, cmd.UID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validate(ctx, cmd.OrgID, report.CreatedBy, &cmd.ReportDTO); err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC().Truncate(time.Second)
+	applyDTO(report, &cmd.ReportDTO)
+	report.Updated = now
+
+	nextSendAt, err := nextSendAt(report, now)
+	if err != nil {
+		return nil, err
+	}
+	report.NextSendAt = nextSendAt
+
+	if err := s.store.Update(ctx, report); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (s *Service) Delete(ctx context.Context, cmd *reports.DeleteReportCommand) error {
+	affected, err := s.store.Delete(ctx, cmd.OrgID, cmd.UID)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return reports.ErrReportNotFound.Errorf("report not found by uid: %s", cmd.UID)
+	}
+
+	return nil
+}
+
+func (s *Service) Get(ctx context.Context, query *reports.GetReportQuery) (*reports.Report, error) {
+	return s.store.Get(ctx, query.OrgID, query.UID)
+}
+
+func (s *Service) List(ctx context.Context, query *reports.ListReportsQuery) ([]*reports.Report, error) {
+	return s.store.List(ctx, query.OrgID)
+}
+
+func (s *Service) Send(ctx context.Context, cmd *reports.SendReportCommand) (*reports.ReportHistory, error) {
+	report, err := s.store.Get(ctx, cmd.OrgID, cmd.UID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.send(ctx, report, true)
+}
+
+func (s *Service) ListHistory(ctx context.Context, query *reports.ListReportHistoryQuery) ([]*reports.ReportHistory, error) {
+	return s.store.ListHistory(ctx, query)
+}
+
+// validate checks the report settings and fills in the defaults. The dashboard is rendered as the creator of the
+// report, who must be able to view it.
+func (s *Service) validate(ctx context.Context, orgID int64, creatorID int64, dto *reports.ReportDTO) error {
+	dto.Name = strings.TrimSpace(dto.Name)
+	if dto.Name == "" {
+		return reports.ErrInvalidName.Errorf("validate: report name is empty")
+	}
+
+	if (dto.From == "") != (dto.To == "") {
+		return reports.ErrInvalidTimeRange.Errorf("validate: only one of from and to is set")
+	}
+
+	if dto.Timezone != "" {
+		if _, err := time.LoadLocation(dto.Timezone); err != nil {
+			return reports.ErrInvalidTimezone.Errorf("validate: %w", err)
+		}
+	}
+
+	if _, err := parseSchedule(dto.Schedule); err != nil {
+		return reports.ErrInvalidSchedule.Errorf("validate: %w", err)
+	}
+
+	if len(dto.Recipients) == 0 {
+		return reports.ErrInvalidRecipients.Errorf("validate: the report has no recipients")
+	}
+	for i, recipient := range dto.Recipients {
+		address, err := mail.ParseAddress(strings.TrimSpace(recipient))
+		if err != nil {
+			return reports.ErrInvalidRecipients.Errorf("validate: invalid recipient %q: %w", recipient, err)
+		}
+		dto.Recipients[i] = address.Address
+	}
+
+	switch dto.Format {
+	case "":
+		dto.Format = reports.FormatPNG
+	case reports.FormatPNG:
+	case reports.FormatPDF:
+		if !s.features.IsEnabled(ctx, featuremgmt.FlagNewPDFRendering) {
+			return reports.ErrInvalidFormat.Errorf("validate: PDF reports require the %s feature toggle", featuremgmt.FlagNewPDFRendering)
+		}
+	default:
+		return reports.ErrInvalidFormat.Errorf("validate: unknown format %q", dto.Format)
+	}
+
+	dashboard, err := s.dashboards.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: dto.DashboardUID, OrgID: orgID})
+	if err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return reports.ErrDashboardNotFound.Errorf("validate: dashboard not found by uid: %s", dto.DashboardUID)
+		}
+		return err
+	}
+
+	creator, err := s.reportUser(ctx, orgID, creatorID)
+	if err != nil {
+		return err
+	}
+	if err := canView(ctx, creator, dashboard); err != nil {
+		return err
+	}
+
+	variables := map[string]bool{}
+	for _, variable := range dashboard.Data.GetPath("templating", "list").MustArray() {
+		variables[simplejson.NewFromAny(variable).Get("name").MustString()] = true
+	}
+	for name := range dto.Variables {
+		if !variables[name] {
+			return reports.ErrInvalidVariable.Errorf("validate: the dashboard has no variable %q", name)
+		}
+	}
+
+	return nil
+}
+
+// reportUser returns the user with their current role and permissions in the organization of the report. Reports are
+// rendered as their creator, so the role they had when they created the report is not used.
+func (s *Service) reportUser(ctx context.Context, orgID int64, userID int64) (*user.SignedInUser, error) {
+	usr, err := s.users.GetSignedInUser(ctx, &user.GetSignedInUserQuery{UserID: userID, OrgID: orgID})
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, reports.ErrCreatorNotFound.Errorf("reportUser: user %d not found", userID)
+		}
+		return nil, err
+	}
+	if usr.IsDisabled || usr.OrgID != orgID || usr.OrgRole == "" {
+		return nil, reports.ErrCreatorNotFound.Errorf("reportUser: user %d is not an active member of organization %d", userID, orgID)
+	}
+
+	permissions, err := s.permissions.GetUserPermissions(ctx, usr, accesscontrol.Options{ReloadCache: true})
+	if err != nil {
+		return nil, err
+	}
+	usr.Permissions = map[int64]map[string][]string{orgID: accesscontrol.GroupScopesByAction(permissions)}
+
+	return usr, nil
+}
+
+func canView(ctx context.Context, usr *user.SignedInUser, dashboard *dashboards.Dashboard) error {
+	g, err := guardian.NewByDashboard(ctx, dashboard, dashboard.OrgID, usr)
+	if err != nil {
+		return err
+	}
+	ok, err := g.CanView()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return reports.ErrDashboardDenied.Errorf("canView: user %d can't view dashboard %s", usr.UserID, dashboard.UID)
+	}
+	return nil
+}
+
+func applyDTO(report *reports.Report, dto *reports.ReportDTO) {
+	report.Name = dto.Name
+	report.DashboardUID = dto.DashboardUID
+	report.Variables = dto.Variables
+	if report.Variables == nil {
+		report.Variables = reports.Variables{}
+	}
+	report.From = dto.From
+	report.To = dto.To
+	report.Schedule = dto.Schedule
+	report.Timezone = dto.Timezone
+	report.Recipients = dto.Recipients
+	report.Message = dto.Message
+	report.Format = dto.Format
+	report.Enabled = dto.Enabled
+}
@@ -0,0 +1,333 @@
+package reportsimpl
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/reports"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type fakeServerLock struct {
+	held bool
+}
+
+func (l *fakeServerLock) LockExecuteAndRelease(ctx context.Context, _ string, _ time.Duration, fn func(ctx context.Context)) error {
+	if l.held {
+		return errors.New("the lock is held by another instance")
+	}
+	fn(ctx)
+	return nil
+}
+
+type testEnv struct {
+	service  *Service
+	renderer *rendering.MockService
+	mailer   *notifications.NotificationServiceMock
+	lock     *fakeServerLock
+	guardian *guardian.FakeDashboardGuardian
+	// creator is returned for the creator of the reports, as a member of organization 1
+	creator *user.SignedInUser
+	now     time.Time
+}
+
+func setupTestEnv(t *testing.T, features featuremgmt.FeatureToggles) *testEnv {
+	t.Helper()
+
+	dashboardService := &dashboards.FakeDashboardService{}
+	dashboardService.On("GetDashboard", mock.Anything, &dashboards.GetDashboardQuery{UID: "dash1", OrgID: 1}).Return(&dashboards.Dashboard{
+		UID:   "dash1",
+		OrgID: 1,
+		Slug:  "service",
+		Title: "Service",
+		Data:  simplejson.NewFromAny(map[string]any{"templating": map[string]any{"list": []any{map[string]any{"name": "job"}}}}),
+	}, nil)
+	dashboardService.On("GetDashboard", mock.Anything, mock.Anything).Return(nil, dashboards.ErrDashboardNotFound)
+
+	cfg := setting.NewCfg()
+	cfg.AppURL = "http://localhost:3000/"
+	cfg.ScheduledReportsEnabled = true
+	cfg.ScheduledReportsRenderTimeout = time.Minute
+
+	env := &testEnv{
+		renderer: rendering.NewMockService(gomock.NewController(t)),
+		mailer:   notifications.MockNotificationService(),
+		lock:     &fakeServerLock{},
+		guardian: &guardian.FakeDashboardGuardian{CanViewValue: true},
+		creator:  &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleEditor},
+		now:      time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC),
+	}
+
+	origNewByDashboard := guardian.NewByDashboard
+	t.Cleanup(func() { guardian.NewByDashboard = origNewByDashboard })
+	guardian.MockDashboardGuardian(env.guardian)
+
+	users := &usertest.FakeUserService{
+		GetSignedInUserFn: func(ctx context.Context, query *user.GetSignedInUserQuery) (*user.SignedInUser, error) {
+			if env.creator == nil || query.UserID != env.creator.UserID {
+				return nil, user.ErrUserNotFound
+			}
+			usr := *env.creator
+			return &usr, nil
+		},
+	}
+
+	env.service = &Service{
+		cfg:        cfg,
+		store:      &sqlStore{db: db.InitTestDB(t)},
+		features:   features,
+		lock:       env.lock,
+		renderer:   env.renderer,
+		mailer:     env.mailer,
+		dashboards:  dashboardService,
+		users:       users,
+		permissions: actest.FakeService{},
+		log:         log.NewNopLogger(),
+		now:        func() time.Time { return env.now },
+	}
+
+	return env
+}
+
+// expectRender makes the renderer write a file with the given content
+func (env *testEnv) expectRender(t *testing.T, renderType rendering.RenderType, content string) {
+	env.renderer.EXPECT().HasCapability(gomock.Any(), rendering.FullHeightImages).Return(rendering.CapabilitySupportRequestResult{IsSupported: true}, nil)
+	env.renderer.EXPECT().Render(gomock.Any(), renderType, gomock.Any(), nil).DoAndReturn(
+		func(_ context.Context, _ rendering.RenderType, opts rendering.Opts, _ rendering.Session) (*rendering.RenderResult, error) {
+			assert.Equal(t, "d/dash1/service?kiosk=&orgId=1&var-job=db", opts.Path)
+			assert.Equal(t, -1, opts.Height)
+			assert.Equal(t, int64(1), opts.UserID)
+			// the dashboard is rendered with the current role of the creator
+			assert.Equal(t, org.RoleEditor, opts.OrgRole)
+
+			filePath := filepath.Join(t.TempDir(), "report."+string(renderType))
+			require.NoError(t, os.WriteFile(filePath, []byte(content), 0600))
+			return &rendering.RenderResult{FilePath: filePath}, nil
+		})
+}
+
+func validReport() reports.ReportDTO {
+	return reports.ReportDTO{
+		Name:         "Weekly",
+		DashboardUID: "dash1",
+		Variables:    reports.Variables{"job": {"db"}},
+		Schedule:     "0 8 * * 1",
+		Timezone:     "Europe/Paris",
+		Recipients:   reports.Recipients{"Ops <ops@example.com>"},
+		Enabled:      true,
+	}
+}
+
+func TestIntegrationCreateReport(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	t.Run("creates the report and schedules it", func(t *testing.T) {
+		env := setupTestEnv(t, featuremgmt.WithFeatures())
+
+		report, err := env.service.Create(context.Background(), &reports.CreateReportCommand{ReportDTO: validReport(), OrgID: 1, UserID: 1})
+		require.NoError(t, err)
+		assert.NotEmpty(t, report.UID)
+		assert.Equal(t, reports.FormatPNG, report.Format)
+		assert.Equal(t, reports.Recipients{"ops@example.com"}, report.Recipients)
+		// 2024-03-01 is a Friday, the report is sent the next Monday at 8 in Paris
+		require.NotNil(t, report.NextSendAt)
+		assert.Equal(t, time.Date(2024, 3, 4, 7, 0, 0, 0, time.UTC), *report.NextSendAt)
+
+		stored, err := env.service.Get(context.Background(), &reports.GetReportQuery{UID: report.UID, OrgID: 1})
+		require.NoError(t, err)
+		assert.Equal(t, report.Name, stored.Name)
+
+		_, err = env.service.Get(context.Background(), &reports.GetReportQuery{UID: report.UID, OrgID: 2})
+		require.ErrorIs(t, err, reports.ErrReportNotFound)
+	})
+
+	t.Run("disabled reports are not scheduled", func(t *testing.T) {
+		env := setupTestEnv(t, featuremgmt.WithFeatures())
+		dto := validReport()
+		dto.Enabled = false
+
+		report, err := env.service.Create(context.Background(), &reports.CreateReportCommand{ReportDTO: dto, OrgID: 1, UserID: 1})
+		require.NoError(t, err)
+		assert.Nil(t, report.NextSendAt)
+	})
+
+	testCases := []struct {
+		name     string
+		features featuremgmt.FeatureToggles
+		change   func(dto *reports.ReportDTO)
+		err      error
+	}{
+		{name: "empty name", change: func(dto *reports.ReportDTO) { dto.Name = " " }, err: reports.ErrInvalidName},
+		{name: "invalid schedule", change: func(dto *reports.ReportDTO) { dto.Schedule = "every monday" }, err: reports.ErrInvalidSchedule},
+		{name: "invalid timezone", change: func(dto *reports.ReportDTO) { dto.Timezone = "Mars/Olympus" }, err: reports.ErrInvalidTimezone},
+		{name: "half a time range", change: func(dto *reports.ReportDTO) { dto.From = "now-7d" }, err: reports.ErrInvalidTimeRange},
+		{name: "no recipients", change: func(dto *reports.ReportDTO) { dto.Recipients = nil }, err: reports.ErrInvalidRecipients},
+		{name: "invalid recipient", change: func(dto *reports.ReportDTO) { dto.Recipients = reports.Recipients{"ops"} }, err: reports.ErrInvalidRecipients},
+		{name: "unknown format", change: func(dto *reports.ReportDTO) { dto.Format = "csv" }, err: reports.ErrInvalidFormat},
+		{name: "PDF without the feature toggle", change: func(dto *reports.ReportDTO) { dto.Format = reports.FormatPDF }, err: reports.ErrInvalidFormat},
+		{name: "unknown dashboard", change: func(dto *reports.ReportDTO) { dto.DashboardUID = "unknown" }, err: reports.ErrDashboardNotFound},
+		{name: "unknown variable", change: func(dto *reports.ReportDTO) { dto.Variables = reports.Variables{"env": {"prod"}} }, err: reports.ErrInvalidVariable},
+	}
+
+	t.Run("returns an error when the creator can't view the dashboard", func(t *testing.T) {
+		env := setupTestEnv(t, featuremgmt.WithFeatures())
+		env.guardian.CanViewValue = false
+
+		_, err := env.service.Create(context.Background(), &reports.CreateReportCommand{ReportDTO: validReport(), OrgID: 1, UserID: 1})
+		require.ErrorIs(t, err, reports.ErrDashboardDenied)
+	})
+
+	for _, tc := range testCases {
+		t.Run("returns an error for "+tc.name, func(t *testing.T) {
+			env := setupTestEnv(t, featuremgmt.WithFeatures())
+			dto := validReport()
+			tc.change(&dto)
+
+			_, err := env.service.Create(context.Background(), &reports.CreateReportCommand{ReportDTO: dto, OrgID: 1, UserID: 1})
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+
+	t.Run("PDF reports can be created with the feature toggle", func(t *testing.T) {
+		env := setupTestEnv(t, featuremgmt.WithFeatures(featuremgmt.FlagNewPDFRendering))
+		dto := validReport()
+		dto.Format = reports.FormatPDF
+
+		_, err := env.service.Create(context.Background(), &reports.CreateReportCommand{ReportDTO: dto, OrgID: 1, UserID: 1})
+		require.NoError(t, err)
+	})
+}
+
+func TestIntegrationUpdateReport(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	env := setupTestEnv(t, featuremgmt.WithFeatures())
+	report, err := env.service.Create(context.Background(), &reports.CreateReportCommand{ReportDTO: validReport(), OrgID: 1, UserID: 1})
+	require.NoError(t, err)
+
+	dto := validReport()
+	dto.Schedule = "0 9 * * *"
+	dto.Timezone = ""
+	updated, err := env.service.Update(context.Background(), &reports.UpdateReportCommand{ReportDTO: dto, UID: report.UID, OrgID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, report.Created, updated.Created)
+	require.NotNil(t, updated.NextSendAt)
+	assert.Equal(t, time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC), *updated.NextSendAt)
+
+	_, err = env.service.Update(context.Background(), &reports.UpdateReportCommand{ReportDTO: dto, UID: report.UID, OrgID: 2})
+	require.ErrorIs(t, err, reports.ErrReportNotFound)
+}
+
+func TestIntegrationSendReport(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	t.Run("renders the dashboard and sends it by email", func(t *testing.T) {
+		env := setupTestEnv(t, featuremgmt.WithFeatures())
+		dto := validReport()
+		dto.Timezone = ""
+		dto.Message = "Have a look"
+		report, err := env.service.Create(context.Background(), &reports.CreateReportCommand{ReportDTO: dto, OrgID: 1, UserID: 1})
+		require.NoError(t, err)
+
+		env.expectRender(t, rendering.RenderPNG, "png content")
+		history, err := env.service.Send(context.Background(), &reports.SendReportCommand{UID: report.UID, OrgID: 1})
+		require.NoError(t, err)
+		assert.Equal(t, reports.StatusSent, history.Status)
+		assert.True(t, history.Manual)
+
+		email := env.mailer.EmailSync.SendEmailCommand
+		assert.Equal(t, []string{"ops@example.com"}, email.To)
+		assert.Equal(t, "scheduled_report", email.Template)
+		assert.Equal(t, "Weekly", email.Subject)
+		assert.Equal(t, "Have a look", email.Data["Message"])
+		assert.Equal(t, "http://localhost:3000/d/dash1/service?orgId=1&var-job=db", email.Data["DashboardURL"])
+		require.Len(t, email.AttachedFiles, 1)
+		assert.Equal(t, "service.png", email.AttachedFiles[0].Name)
+		assert.Equal(t, []byte("png content"), email.AttachedFiles[0].Content)
+
+		list, err := env.service.ListHistory(context.Background(), &reports.ListReportHistoryQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, report.UID, list[0].ReportUID)
+	})
+
+	t.Run("records the failures in the history", func(t *testing.T) {
+		env := setupTestEnv(t, featuremgmt.WithFeatures())
+		dto := validReport()
+		dto.Timezone = ""
+		report, err := env.service.Create(context.Background(), &reports.CreateReportCommand{ReportDTO: dto, OrgID: 1, UserID: 1})
+		require.NoError(t, err)
+
+		env.expectRender(t, rendering.RenderPNG, "png content")
+		env.mailer.ShouldError = errors.New("smtp is down")
+		_, err = env.service.Send(context.Background(), &reports.SendReportCommand{UID: report.UID, OrgID: 1})
+		require.ErrorIs(t, err, reports.ErrSendFailed)
+
+		list, err := env.service.ListHistory(context.Background(), &reports.ListReportHistoryQuery{OrgID: 1, ReportUID: report.UID})
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, reports.StatusFailed, list[0].Status)
+		assert.Contains(t, list[0].Error, "smtp is down")
+	})
+
+	t.Run("fails when the creator left the organization", func(t *testing.T) {
+		env := setupTestEnv(t, featuremgmt.WithFeatures())
+		report, err := env.service.Create(context.Background(), &reports.CreateReportCommand{ReportDTO: validReport(), OrgID: 1, UserID: 1})
+		require.NoError(t, err)
+
+		// the user is returned without a role in the organization of the report
+		env.creator = &user.SignedInUser{UserID: 1}
+		_, err = env.service.Send(context.Background(), &reports.SendReportCommand{UID: report.UID, OrgID: 1})
+		require.ErrorIs(t, err, reports.ErrSendFailed)
+
+		env.creator = nil
+		_, err = env.service.Send(context.Background(), &reports.SendReportCommand{UID: report.UID, OrgID: 1})
+		require.ErrorIs(t, err, reports.ErrSendFailed)
+
+		list, err := env.service.ListHistory(context.Background(), &reports.ListReportHistoryQuery{OrgID: 1, ReportUID: report.UID})
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		for _, history := range list {
+			assert.Equal(t, reports.StatusFailed, history.Status)
+			assert.Contains(t, history.Error, "reports.creatorNotFound")
+		}
+	})
+
+	t.Run("fails when the creator can no longer view the dashboard", func(t *testing.T) {
+		env := setupTestEnv(t, featuremgmt.WithFeatures())
+		report, err := env.service.Create(context.Background(), &reports.CreateReportCommand{ReportDTO: validReport(), OrgID: 1, UserID: 1})
+		require.NoError(t, err)
+
+		env.guardian.CanViewValue = false
+		_, err = env.service.Send(context.Background(), &reports.SendReportCommand{UID: report.UID, OrgID: 1})
+		require.ErrorIs(t, err, reports.ErrSendFailed)
+		require.ErrorIs(t, err, reports.ErrDashboardDenied)
+	})
+}
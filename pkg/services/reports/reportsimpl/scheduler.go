@@ -0,0 +1,112 @@
+package reportsimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+const (
+	// schedulerInterval is how often the scheduler looks for reports to send
+	schedulerInterval = time.Minute
+	// schedulerLockName is the server lock that makes sure a single instance sends the reports
+	schedulerLockName = "send scheduled reports"
+	// schedulerLockTimeout is how long the server lock is held before it is considered abandoned by a crashed instance
+	schedulerLockTimeout = 30 * time.Minute
+)
+
+// IsDisabled returns true when reports are disabled, the scheduler is not started then
+func (s *Service) IsDisabled() bool {
+	return !s.cfg.ScheduledReportsEnabled
+}
+
+// Run sends the reports as they are due. Every instance runs the scheduler, the server lock makes sure a report is
+// only sent by one of them.
+func (s *Service) Run(ctx context.Context) error {
+	ticker := time.NewTicker(schedulerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.sendDueReports(ctx)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *Service) sendDueReports(ctx context.Context) {
+	err := s.lock.LockExecuteAndRelease(ctx, schedulerLockName, schedulerLockTimeout, func(ctx context.Context) {
+		now := s.now()
+		due, err := s.store.FindDue(ctx, now)
+		if err != nil {
+			s.log.Error("Failed to find the reports to send", "error", err)
+			return
+		}
+
+		for _, report := range due {
+			if ctx.Err() != nil {
+				return
+			}
+			s.sendScheduled(ctx, report, now)
+		}
+	})
+	if err != nil {
+		s.log.Debug("Scheduled reports are sent by another instance", "error", err)
+	}
+}
+
+// sendScheduled moves the report to its next occurrence before sending it, so a report that fails to render is not
+// retried every minute
+func (s *Service) sendScheduled(ctx context.Context, report *reports.Report, now time.Time) {
+	logger := s.log.FromContext(ctx).New("reportUid", report.UID, "orgId", report.OrgID)
+
+	next, nextErr := nextSendAt(report, now)
+	if nextErr != nil {
+		logger.Error("Failed to compute the next time the report is sent, it won't be sent again", "error", nextErr)
+	}
+	if err := s.store.UpdateNextSendAt(ctx, report.ID, next); err != nil {
+		logger.Error("Failed to update the next time the report is sent", "error", err)
+		return
+	}
+	if nextErr != nil {
+		return
+	}
+
+	if _, err := s.send(ctx, report, false); err != nil {
+		logger.Error("Failed to send scheduled report", "error", err)
+		return
+	}
+	logger.Info("Sent scheduled report", "recipients", len(report.Recipients))
+}
+
+func parseSchedule(schedule string) (cron.Schedule, error) {
+	return cron.ParseStandard(schedule)
+}
+
+// nextSendAt returns the first time after the given time the report should be sent, or nil when it is disabled
+func nextSendAt(report *reports.Report, after time.Time) (*time.Time, error) {
+	if !report.Enabled {
+		return nil, nil
+	}
+
+	schedule, err := parseSchedule(report.Schedule)
+	if err != nil {
+		return nil, reports.ErrInvalidSchedule.Errorf("nextSendAt: %w", err)
+	}
+
+	location := time.UTC
+	if report.Timezone != "" {
+		location, err = time.LoadLocation(report.Timezone)
+		if err != nil {
+			return nil, reports.ErrInvalidTimezone.Errorf("nextSendAt: %w", err)
+		}
+	}
+
+	next := schedule.Next(after.In(location)).UTC()
+	return &next, nil
+}
@@ -0,0 +1,112 @@
+package reportsimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+func TestNextSendAt(t *testing.T) {
+	after := time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		report   *reports.Report
+		expected *time.Time
+		err      error
+	}{
+		{
+			name:   "disabled report",
+			report: &reports.Report{Schedule: "0 8 * * *"},
+		},
+		{
+			name:     "in UTC by default",
+			report:   &reports.Report{Schedule: "0 8 * * *", Enabled: true},
+			expected: timePtr(time.Date(2024, 3, 2, 8, 0, 0, 0, time.UTC)),
+		},
+		{
+			name:     "in the timezone of the report",
+			report:   &reports.Report{Schedule: "0 8 * * *", Timezone: "America/New_York", Enabled: true},
+			expected: timePtr(time.Date(2024, 3, 1, 13, 0, 0, 0, time.UTC)),
+		},
+		{
+			name:   "invalid schedule",
+			report: &reports.Report{Schedule: "0 8 * *", Enabled: true},
+			err:    reports.ErrInvalidSchedule,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			next, err := nextSendAt(tc.report, after)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, next)
+		})
+	}
+}
+
+func TestIntegrationSendDueReports(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	setup := func(t *testing.T) (*testEnv, *reports.Report) {
+		env := setupTestEnv(t, featuremgmt.WithFeatures())
+		dto := validReport()
+		dto.Schedule = "0 * * * *"
+		dto.Timezone = ""
+		report, err := env.service.Create(context.Background(), &reports.CreateReportCommand{ReportDTO: dto, OrgID: 1, UserID: 1})
+		require.NoError(t, err)
+		return env, report
+	}
+
+	t.Run("sends the reports that are due once", func(t *testing.T) {
+		env, report := setup(t)
+
+		// not due yet
+		env.service.sendDueReports(context.Background())
+		assert.Empty(t, env.mailer.EmailSync.To)
+
+		env.now = env.now.Add(time.Hour)
+		env.expectRender(t, rendering.RenderPNG, "png content")
+		env.service.sendDueReports(context.Background())
+		assert.Equal(t, []string{"ops@example.com"}, env.mailer.EmailSync.To)
+
+		stored, err := env.service.Get(context.Background(), &reports.GetReportQuery{UID: report.UID, OrgID: 1})
+		require.NoError(t, err)
+		require.NotNil(t, stored.NextSendAt)
+		assert.Equal(t, time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC), *stored.NextSendAt)
+
+		// the renderer mock fails the test if the report is rendered again
+		env.service.sendDueReports(context.Background())
+
+		history, err := env.service.ListHistory(context.Background(), &reports.ListReportHistoryQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		assert.False(t, history[0].Manual)
+	})
+
+	t.Run("does not send the reports when another instance holds the lock", func(t *testing.T) {
+		env, _ := setup(t)
+		env.lock.held = true
+
+		env.now = env.now.Add(time.Hour)
+		env.service.sendDueReports(context.Background())
+		assert.Empty(t, env.mailer.EmailSync.To)
+	})
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,178 @@
+package reportsimpl
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/reports"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+const (
+	reportWidth = 1500
+	// reportHeight is the height of the screenshots when the image renderer can't take full height screenshots
+	reportHeight   = 1080
+	reportTemplate = "scheduled_report"
+)
+
+// send renders the report and sends it to the recipients, the outcome is recorded in the history of the report
+func (s *Service) send(ctx context.Context, report *reports.Report, manual bool) (*reports.ReportHistory, error) {
+	history := &reports.ReportHistory{
+		ReportUID:  report.UID,
+		OrgID:      report.OrgID,
+		Name:       report.Name,
+		Recipients: report.Recipients,
+		Format:     report.Format,
+		Status:     reports.StatusSent,
+		Manual:     manual,
+		SentAt:     s.now().UTC().Truncate(time.Second),
+	}
+
+	err := s.renderAndMail(ctx, report)
+	if err != nil {
+		history.Status = reports.StatusFailed
+		history.Error = err.Error()
+	}
+
+	if insertErr := s.store.InsertHistory(ctx, history); insertErr != nil {
+		s.log.FromContext(ctx).Error("Failed to record the report in the history", "reportUid", report.UID, "error", insertErr)
+	}
+
+	if err != nil {
+		return history, reports.ErrSendFailed.Errorf("send: %w", err)
+	}
+
+	return history, nil
+}
+
+func (s *Service) renderAndMail(ctx context.Context, report *reports.Report) error {
+	dashboard, err := s.dashboards.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: report.DashboardUID, OrgID: report.OrgID})
+	if err != nil {
+		return fmt.Errorf("failed to get dashboard: %w", err)
+	}
+
+	creator, err := s.reportUser(ctx, report.OrgID, report.CreatedBy)
+	if err != nil {
+		return fmt.Errorf("failed to get the creator of the report: %w", err)
+	}
+	if err := canView(ctx, creator, dashboard); err != nil {
+		return err
+	}
+
+	content, err := s.render(ctx, report, dashboard, creator)
+	if err != nil {
+		return err
+	}
+
+	fileName := dashboard.Slug
+	if fileName == "" {
+		fileName = "dashboard"
+	}
+
+	link := dashboardURL(report, dashboard)
+	cmd := &notifications.SendEmailCommandSync{
+		SendEmailCommand: notifications.SendEmailCommand{
+			To:       report.Recipients,
+			Template: reportTemplate,
+			Subject:  report.Name,
+			Data: map[string]any{
+				"Name":           report.Name,
+				"Message":        report.Message,
+				"DashboardTitle": dashboard.Title,
+				"DashboardURL":   s.cfg.AppURL + link.String(),
+			},
+			AttachedFiles: []*notifications.SendEmailAttachFile{
+				{Name: fmt.Sprintf("%s.%s", fileName, report.Format), Content: content},
+			},
+		},
+	}
+
+	if err := s.mailer.SendEmailCommandHandlerSync(ctx, cmd); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+// render takes a screenshot of the whole dashboard as the creator of the report and returns its content
+func (s *Service) render(ctx context.Context, report *reports.Report, dashboard *dashboards.Dashboard, creator *user.SignedInUser) ([]byte, error) {
+	renderType := rendering.RenderPNG
+	if report.Format == reports.FormatPDF {
+		renderType = rendering.RenderPDF
+	}
+
+	height := reportHeight
+	if capability, err := s.renderer.HasCapability(ctx, rendering.FullHeightImages); err == nil && capability.IsSupported {
+		height = -1
+	}
+
+	renderURL := dashboardURL(report, dashboard)
+	query := renderURL.Query()
+	query.Set("kiosk", "")
+	renderURL.RawQuery = query.Encode()
+
+	result, err := s.renderer.Render(ctx, renderType, rendering.Opts{
+		AuthOpts: rendering.AuthOpts{
+			OrgID:   report.OrgID,
+			UserID:  creator.UserID,
+			OrgRole: creator.OrgRole,
+		},
+		ErrorOpts: rendering.ErrorOpts{
+			ErrorConcurrentLimitReached: true,
+			ErrorRenderUnavailable:      true,
+		},
+		TimeoutOpts: rendering.TimeoutOpts{
+			Timeout: s.cfg.ScheduledReportsRenderTimeout,
+		},
+		Width:           reportWidth,
+		Height:          height,
+		Path:            renderURL.String(),
+		Timezone:        report.Timezone,
+		Theme:           models.ThemeLight,
+		ConcurrentLimit: s.cfg.RendererConcurrentRequestLimit,
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render dashboard: %w", err)
+	}
+
+	content, err := os.ReadFile(result.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rendered dashboard: %w", err)
+	}
+	if err := os.Remove(result.FilePath); err != nil {
+		s.log.FromContext(ctx).Warn("Failed to remove rendered dashboard", "path", result.FilePath, "error", err)
+	}
+
+	return content, nil
+}
+
+// dashboardURL returns the relative URL of the dashboard with the time range and template variables of the report
+func dashboardURL(report *reports.Report, dashboard *dashboards.Dashboard) *url.URL {
+	u := &url.URL{Path: path.Join("d", dashboard.UID, dashboard.Slug)}
+	query := u.Query()
+	query.Set("orgId", strconv.FormatInt(report.OrgID, 10))
+	if report.From != "" && report.To != "" {
+		query.Set("from", report.From)
+		query.Set("to", report.To)
+	}
+	if report.Timezone != "" {
+		query.Set("timezone", report.Timezone)
+	}
+	for name, values := range report.Variables {
+		for _, value := range values {
+			query.Add("var-"+name, value)
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	return u
+}
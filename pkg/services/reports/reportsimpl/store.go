@@ -0,0 +1,22 @@
+package reportsimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+type store interface {
+	Insert(ctx context.Context, report *reports.Report) error
+	Update(ctx context.Context, report *reports.Report) error
+	Delete(ctx context.Context, orgID int64, uid string) (int64, error)
+	Get(ctx context.Context, orgID int64, uid string) (*reports.Report, error)
+	List(ctx context.Context, orgID int64) ([]*reports.Report, error)
+	// FindDue returns the enabled reports that should have been sent at the given time
+	FindDue(ctx context.Context, now time.Time) ([]*reports.Report, error)
+	UpdateNextSendAt(ctx context.Context, id int64, nextSendAt *time.Time) error
+
+	InsertHistory(ctx context.Context, history *reports.ReportHistory) error
+	ListHistory(ctx context.Context, query *reports.ListReportHistoryQuery) ([]*reports.ReportHistory, error)
+}
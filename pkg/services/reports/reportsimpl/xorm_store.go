@@ -0,0 +1,126 @@
+package reportsimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+// defaultHistoryLimit is the number of history entries returned when the query has no limit
+const defaultHistoryLimit = 100
+
+type sqlStore struct {
+	db db.DB
+}
+
+var _ store = &sqlStore{}
+
+func (s *sqlStore) Insert(ctx context.Context, report *reports.Report) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(report)
+		return err
+	})
+}
+
+func (s *sqlStore) Update(ctx context.Context, report *reports.Report) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.ID(report.ID).AllCols().Update(report)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return reports.ErrReportNotFound.Errorf("report not found by uid: %s", report.UID)
+		}
+		return nil
+	})
+}
+
+func (s *sqlStore) Delete(ctx context.Context, orgID int64, uid string) (int64, error) {
+	var affected int64
+	err := s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM scheduled_report WHERE org_id = ? AND uid = ?", orgID, uid)
+		if err != nil {
+			return err
+		}
+		affected, err = res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Exec("DELETE FROM scheduled_report_history WHERE org_id = ? AND report_uid = ?", orgID, uid)
+		return err
+	})
+
+	return affected, err
+}
+
+func (s *sqlStore) Get(ctx context.Context, orgID int64, uid string) (*reports.Report, error) {
+	report := &reports.Report{}
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(report)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return reports.ErrReportNotFound.Errorf("report not found by uid: %s", uid)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (s *sqlStore) List(ctx context.Context, orgID int64) ([]*reports.Report, error) {
+	result := make([]*reports.Report, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ?", orgID).Asc("name").Find(&result)
+	})
+
+	return result, err
+}
+
+func (s *sqlStore) FindDue(ctx context.Context, now time.Time) ([]*reports.Report, error) {
+	result := make([]*reports.Report, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("enabled = ? AND next_send_at <= ?", true, now.UTC()).Asc("next_send_at").Find(&result)
+	})
+
+	return result, err
+}
+
+func (s *sqlStore) UpdateNextSendAt(ctx context.Context, id int64, nextSendAt *time.Time) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Table("scheduled_report").ID(id).Cols("next_send_at").Update(&reports.Report{NextSendAt: nextSendAt})
+		return err
+	})
+}
+
+func (s *sqlStore) InsertHistory(ctx context.Context, history *reports.ReportHistory) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(history)
+		return err
+	})
+}
+
+func (s *sqlStore) ListHistory(ctx context.Context, query *reports.ListReportHistoryQuery) ([]*reports.ReportHistory, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+
+	result := make([]*reports.ReportHistory, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Where("org_id = ?", query.OrgID)
+		if query.ReportUID != "" {
+			sess.Where("report_uid = ?", query.ReportUID)
+		}
+		return sess.Desc("sent_at").Desc("id").Limit(limit).Find(&result)
+	})
+
+	return result, err
+}
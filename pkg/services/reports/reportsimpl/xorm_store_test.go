@@ -0,0 +1,153 @@
+package reportsimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/reports"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationReportsStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	now := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Hour)
+
+	newReport := func(uid string, orgID int64, nextSendAt *time.Time) *reports.Report {
+		return &reports.Report{
+			UID:          uid,
+			OrgID:        orgID,
+			Name:         "report " + uid,
+			DashboardUID: "dash1",
+			Variables:    reports.Variables{"job": {"api", "db"}},
+			Schedule:     "0 8 * * 1",
+			Recipients:   reports.Recipients{"ops@example.com"},
+			Format:       reports.FormatPNG,
+			Enabled:      nextSendAt != nil,
+			NextSendAt:   nextSendAt,
+			CreatedBy:    1,
+			Created:      now,
+			Updated:      now,
+		}
+	}
+
+	t.Run("reports are stored per organization", func(t *testing.T) {
+		store := &sqlStore{db: db.InitTestDB(t)}
+		require.NoError(t, store.Insert(ctx, newReport("report1", 1, nil)))
+		require.NoError(t, store.Insert(ctx, newReport("report2", 2, nil)))
+
+		report, err := store.Get(ctx, 1, "report1")
+		require.NoError(t, err)
+		assert.Equal(t, reports.Variables{"job": {"api", "db"}}, report.Variables)
+		assert.Equal(t, reports.Recipients{"ops@example.com"}, report.Recipients)
+		assert.Nil(t, report.NextSendAt)
+
+		_, err = store.Get(ctx, 1, "report2")
+		require.ErrorIs(t, err, reports.ErrReportNotFound)
+
+		list, err := store.List(ctx, 2)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, "report2", list[0].UID)
+	})
+
+	t.Run("finds the enabled reports that are due", func(t *testing.T) {
+		store := &sqlStore{db: db.InitTestDB(t)}
+		require.NoError(t, store.Insert(ctx, newReport("due", 1, &past)))
+		require.NoError(t, store.Insert(ctx, newReport("later", 1, &future)))
+		require.NoError(t, store.Insert(ctx, newReport("disabled", 1, nil)))
+
+		due, err := store.FindDue(ctx, now)
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		assert.Equal(t, "due", due[0].UID)
+
+		require.NoError(t, store.UpdateNextSendAt(ctx, due[0].ID, &future))
+		due, err = store.FindDue(ctx, now)
+		require.NoError(t, err)
+		assert.Empty(t, due)
+
+		report, err := store.Get(ctx, 1, "due")
+		require.NoError(t, err)
+		require.NotNil(t, report.NextSendAt)
+		assert.True(t, future.Equal(*report.NextSendAt))
+
+		require.NoError(t, store.UpdateNextSendAt(ctx, report.ID, nil))
+		report, err = store.Get(ctx, 1, "due")
+		require.NoError(t, err)
+		assert.Nil(t, report.NextSendAt)
+	})
+
+	t.Run("updates a report", func(t *testing.T) {
+		store := &sqlStore{db: db.InitTestDB(t)}
+		report := newReport("report1", 1, nil)
+		require.NoError(t, store.Insert(ctx, report))
+
+		report.Name = "weekly"
+		report.Variables = reports.Variables{}
+		require.NoError(t, store.Update(ctx, report))
+
+		updated, err := store.Get(ctx, 1, "report1")
+		require.NoError(t, err)
+		assert.Equal(t, "weekly", updated.Name)
+		assert.Empty(t, updated.Variables)
+	})
+
+	t.Run("lists the history most recent first and deletes it with the report", func(t *testing.T) {
+		store := &sqlStore{db: db.InitTestDB(t)}
+		require.NoError(t, store.Insert(ctx, newReport("report1", 1, nil)))
+		require.NoError(t, store.Insert(ctx, newReport("report2", 1, nil)))
+		for i, uid := range []string{"report1", "report2", "report1"} {
+			require.NoError(t, store.InsertHistory(ctx, &reports.ReportHistory{
+				ReportUID:  uid,
+				OrgID:      1,
+				Name:       "report " + uid,
+				Recipients: reports.Recipients{"ops@example.com"},
+				Format:     reports.FormatPNG,
+				Status:     reports.StatusSent,
+				SentAt:     now.Add(time.Duration(i) * time.Hour),
+			}))
+		}
+
+		history, err := store.ListHistory(ctx, &reports.ListReportHistoryQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, history, 3)
+		assert.True(t, now.Add(2*time.Hour).Equal(history[0].SentAt))
+
+		history, err = store.ListHistory(ctx, &reports.ListReportHistoryQuery{OrgID: 1, ReportUID: "report1", Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		assert.Equal(t, "report1", history[0].ReportUID)
+
+		history, err = store.ListHistory(ctx, &reports.ListReportHistoryQuery{OrgID: 2})
+		require.NoError(t, err)
+		assert.Empty(t, history)
+
+		affected, err := store.Delete(ctx, 1, "report1")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), affected)
+
+		history, err = store.ListHistory(ctx, &reports.ListReportHistoryQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		assert.Equal(t, "report2", history[0].ReportUID)
+
+		affected, err = store.Delete(ctx, 1, "report1")
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), affected)
+	})
+}
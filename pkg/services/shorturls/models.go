@@ -3,15 +3,23 @@ package shorturls
 import (
 	"time"
 
+	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
 var (
-	ErrShortURLBadRequest   = errutil.BadRequest("shorturl.bad-request")
-	ErrShortURLNotFound     = errutil.NotFound("shorturl.not-found")
-	ErrShortURLAbsolutePath = errutil.ValidationFailed("shorturl.absolute-path", errutil.WithPublicMessage("Path should be relative"))
-	ErrShortURLInvalidPath  = errutil.ValidationFailed("shorturl.invalid-path", errutil.WithPublicMessage("Invalid short URL path"))
-	ErrShortURLInternal     = errutil.Internal("shorturl.internal")
+	ErrShortURLBadRequest    = errutil.BadRequest("shorturl.bad-request")
+	ErrShortURLNotFound      = errutil.NotFound("shorturl.not-found")
+	ErrShortURLAbsolutePath  = errutil.ValidationFailed("shorturl.absolute-path", errutil.WithPublicMessage("Path should be relative"))
+	ErrShortURLInvalidPath   = errutil.ValidationFailed("shorturl.invalid-path", errutil.WithPublicMessage("Invalid short URL path"))
+	ErrShortURLInvalidExpiry = errutil.ValidationFailed("shorturl.invalid-expiry", errutil.WithPublicMessage("Expiry should be in the future"))
+	ErrShortURLForbidden     = errutil.Forbidden("shorturl.forbidden", errutil.WithPublicMessage("Only the owner of the short URL or an org admin can manage it"))
+	ErrShortURLInternal      = errutil.Internal("shorturl.internal")
+)
+
+const (
+	QuotaTargetSrv quota.TargetSrv = "short_url"
+	QuotaTarget    quota.Target    = "short_url"
 )
 
 type ShortUrl struct {
@@ -22,6 +30,42 @@ type ShortUrl struct {
 	CreatedBy  int64
 	CreatedAt  int64
 	LastSeenAt int64
+	// ExpiresAt is the Unix time after which the short URL no longer resolves, 0 if it never expires.
+	ExpiresAt int64
+	HitCount  int64
+}
+
+// IsExpired returns true if the short URL has an expiry that is not after the given time.
+func (s *ShortUrl) IsExpired(now time.Time) bool {
+	return s.ExpiresAt > 0 && s.ExpiresAt <= now.Unix()
+}
+
+type CreateShortURLCommand struct {
+	Path string
+	// ExpiresAt is the time after which the short URL no longer resolves, the zero value if it never expires.
+	ExpiresAt time.Time
+}
+
+type SearchShortURLsQuery struct {
+	OrgID int64
+	// CreatedBy restricts the search to the short URLs created by the user.
+	CreatedBy int64
+	// AllUsers searches the short URLs of every user of the org, CreatedBy is ignored.
+	AllUsers bool
+	Page     int
+	Limit    int
+}
+
+type SearchShortURLsResult struct {
+	TotalCount int64       `json:"totalCount"`
+	ShortURLs  []*ShortUrl `json:"shortUrls"`
+	Page       int         `json:"page"`
+	PerPage    int         `json:"perPage"`
+}
+
+type DeleteShortURLCommand struct {
+	OrgID int64
+	UID   string
 }
 
 type DeleteShortUrlCommand struct {
@@ -7,8 +7,10 @@ import (
 	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/shorturls"
 	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/teris-io/shortid"
 )
 
@@ -18,24 +20,53 @@ type ShortURLService struct {
 	SQLStore store
 }
 
-func ProvideService(db db.DB) *ShortURLService {
-	return &ShortURLService{
+func ProvideService(db db.DB, cfg *setting.Cfg, quotaService quota.Service) (*ShortURLService, error) {
+	s := &ShortURLService{
 		SQLStore: &sqlStore{
 			db: db,
 		},
 	}
+
+	defaultLimits, err := readQuotaConfig(cfg)
+	if err != nil {
+		return s, err
+	}
+
+	if err := quotaService.RegisterQuotaReporter(&quota.NewUsageReporter{
+		TargetSrv:     shorturls.QuotaTargetSrv,
+		DefaultLimits: defaultLimits,
+		Reporter:      s.Usage,
+	}); err != nil {
+		return s, err
+	}
+
+	return s, nil
+}
+
+func (s ShortURLService) Usage(ctx context.Context, scopeParams *quota.ScopeParameters) (*quota.Map, error) {
+	return s.SQLStore.Count(ctx, scopeParams)
 }
 
 func (s ShortURLService) GetShortURLByUID(ctx context.Context, user *user.SignedInUser, uid string) (*shorturls.ShortUrl, error) {
-	return s.SQLStore.Get(ctx, user, uid)
+	shortURL, err := s.SQLStore.Get(ctx, user, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Expired short URLs are kept until the cleanup job deletes them but no longer resolve
+	if shortURL.IsExpired(getTime()) {
+		return nil, shorturls.ErrShortURLNotFound.Errorf("short URL expired")
+	}
+
+	return shortURL, nil
 }
 
 func (s ShortURLService) UpdateLastSeenAt(ctx context.Context, shortURL *shorturls.ShortUrl) error {
 	return s.SQLStore.Update(ctx, shortURL)
 }
 
-func (s ShortURLService) CreateShortURL(ctx context.Context, user *user.SignedInUser, relPath string) (*shorturls.ShortUrl, error) {
-	relPath = strings.TrimSpace(relPath)
+func (s ShortURLService) CreateShortURL(ctx context.Context, user *user.SignedInUser, cmd *shorturls.CreateShortURLCommand) (*shorturls.ShortUrl, error) {
+	relPath := strings.TrimSpace(cmd.Path)
 
 	if path.IsAbs(relPath) {
 		return nil, shorturls.ErrShortURLAbsolutePath.Errorf("expected relative path: %s", relPath)
@@ -44,18 +75,27 @@ func (s ShortURLService) CreateShortURL(ctx context.Context, user *user.SignedIn
 		return nil, shorturls.ErrShortURLInvalidPath.Errorf("path cannot contain '../': %s", relPath)
 	}
 
+	now := getTime()
+	var expiresAt int64
+	if !cmd.ExpiresAt.IsZero() {
+		if !cmd.ExpiresAt.After(now) {
+			return nil, shorturls.ErrShortURLInvalidExpiry.Errorf("expiry is in the past: %s", cmd.ExpiresAt)
+		}
+		expiresAt = cmd.ExpiresAt.Unix()
+	}
+
 	uid, err := shortid.Generate()
 	if err != nil {
 		return nil, shorturls.ErrShortURLInternal.Errorf("failed to generate uid: %w", err)
 	}
 
-	now := time.Now().Unix()
 	shortURL := shorturls.ShortUrl{
 		OrgId:     user.OrgID,
 		Uid:       uid,
 		Path:      relPath,
 		CreatedBy: user.UserID,
-		CreatedAt: now,
+		CreatedAt: now.Unix(),
+		ExpiresAt: expiresAt,
 	}
 
 	if err := s.SQLStore.Insert(ctx, &shortURL); err != nil {
@@ -65,6 +105,41 @@ func (s ShortURLService) CreateShortURL(ctx context.Context, user *user.SignedIn
 	return &shortURL, nil
 }
 
+func (s ShortURLService) SearchShortURLs(ctx context.Context, query *shorturls.SearchShortURLsQuery) (*shorturls.SearchShortURLsResult, error) {
+	if query.Limit <= 0 {
+		query.Limit = 1000
+	}
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	return s.SQLStore.Search(ctx, query)
+}
+
+func (s ShortURLService) DeleteShortURL(ctx context.Context, cmd *shorturls.DeleteShortURLCommand) error {
+	return s.SQLStore.DeleteByUID(ctx, cmd)
+}
+
 func (s ShortURLService) DeleteStaleShortURLs(ctx context.Context, cmd *shorturls.DeleteShortUrlCommand) error {
 	return s.SQLStore.Delete(ctx, cmd)
 }
+
+func readQuotaConfig(cfg *setting.Cfg) (*quota.Map, error) {
+	limits := &quota.Map{}
+
+	if cfg == nil {
+		return limits, nil
+	}
+
+	globalQuotaTag, err := quota.NewTag(shorturls.QuotaTargetSrv, shorturls.QuotaTarget, quota.GlobalScope)
+	if err != nil {
+		return limits, err
+	}
+	orgQuotaTag, err := quota.NewTag(shorturls.QuotaTargetSrv, shorturls.QuotaTarget, quota.OrgScope)
+	if err != nil {
+		return limits, err
+	}
+
+	limits.Set(globalQuotaTag, cfg.Quota.Global.ShortURL)
+	limits.Set(orgQuotaTag, cfg.Quota.Org.ShortURL)
+	return limits, nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/shorturls"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/tests/testsuite"
@@ -26,7 +27,7 @@ func TestShortURLService(t *testing.T) {
 
 		service := ShortURLService{SQLStore: &sqlStore{db: store}}
 
-		newShortURL, err := service.CreateShortURL(context.Background(), user, refPath)
+		newShortURL, err := service.CreateShortURL(context.Background(), user, &shorturls.CreateShortURLCommand{Path: refPath})
 		require.NoError(t, err)
 		require.NotNil(t, newShortURL)
 		require.NotEmpty(t, newShortURL.Uid)
@@ -53,10 +54,18 @@ func TestShortURLService(t *testing.T) {
 			updatedShortURL, err := service.GetShortURLByUID(context.Background(), user, existingShortURL.Uid)
 			require.NoError(t, err)
 			require.Equal(t, expectedTime.Unix(), updatedShortURL.LastSeenAt)
+			require.Equal(t, int64(1), updatedShortURL.HitCount)
+
+			err = service.UpdateLastSeenAt(context.Background(), updatedShortURL)
+			require.NoError(t, err)
+
+			updatedShortURL, err = service.GetShortURLByUID(context.Background(), user, existingShortURL.Uid)
+			require.NoError(t, err)
+			require.Equal(t, int64(2), updatedShortURL.HitCount)
 		})
 
 		t.Run("and stale short urls can be deleted", func(t *testing.T) {
-			staleShortURL, err := service.CreateShortURL(context.Background(), user, refPath)
+			staleShortURL, err := service.CreateShortURL(context.Background(), user, &shorturls.CreateShortURLCommand{Path: refPath})
 			require.NoError(t, err)
 			require.NotNil(t, staleShortURL)
 			require.NotEmpty(t, staleShortURL.Uid)
@@ -81,6 +90,95 @@ func TestShortURLService(t *testing.T) {
 		})
 	})
 
+	t.Run("User can create short URLs that expire", func(t *testing.T) {
+		service := ShortURLService{SQLStore: &sqlStore{db: store}}
+
+		_, err := service.CreateShortURL(context.Background(), user, &shorturls.CreateShortURLCommand{
+			Path:      "mock/path",
+			ExpiresAt: time.Now().Add(-time.Minute),
+		})
+		require.True(t, shorturls.ErrShortURLInvalidExpiry.Is(err))
+
+		expiringShortURL, err := service.CreateShortURL(context.Background(), user, &shorturls.CreateShortURLCommand{
+			Path:      "mock/path",
+			ExpiresAt: time.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+
+		_, err = service.GetShortURLByUID(context.Background(), user, expiringShortURL.Uid)
+		require.NoError(t, err)
+
+		t.Run("and expired short URLs are not found", func(t *testing.T) {
+			origGetTime := getTime
+			t.Cleanup(func() {
+				getTime = origGetTime
+			})
+			getTime = func() time.Time {
+				return time.Now().Add(2 * time.Hour)
+			}
+
+			_, err := service.GetShortURLByUID(context.Background(), user, expiringShortURL.Uid)
+			require.True(t, shorturls.ErrShortURLNotFound.Is(err))
+
+			t.Run("and deleted by the cleanup", func(t *testing.T) {
+				cmd := shorturls.DeleteShortUrlCommand{OlderThan: time.Unix(0, 0)}
+				err := service.DeleteStaleShortURLs(context.Background(), &cmd)
+				require.NoError(t, err)
+				require.Equal(t, int64(1), cmd.NumDeleted)
+			})
+		})
+	})
+
+	t.Run("Short URLs can be searched and deleted", func(t *testing.T) {
+		store := db.InitTestDB(t)
+		service := ShortURLService{SQLStore: &sqlStore{db: store}}
+		otherUser := *user
+		otherUser.UserID = 2
+
+		for i := 0; i < 3; i++ {
+			_, err := service.CreateShortURL(context.Background(), user, &shorturls.CreateShortURLCommand{Path: "mock/path"})
+			require.NoError(t, err)
+		}
+		otherShortURL, err := service.CreateShortURL(context.Background(), &otherUser, &shorturls.CreateShortURLCommand{Path: "mock/path"})
+		require.NoError(t, err)
+
+		result, err := service.SearchShortURLs(context.Background(), &shorturls.SearchShortURLsQuery{CreatedBy: user.UserID, Limit: 2})
+		require.NoError(t, err)
+		require.Equal(t, int64(3), result.TotalCount)
+		require.Len(t, result.ShortURLs, 2)
+
+		result, err = service.SearchShortURLs(context.Background(), &shorturls.SearchShortURLsQuery{CreatedBy: user.UserID, Limit: 2, Page: 2})
+		require.NoError(t, err)
+		require.Len(t, result.ShortURLs, 1)
+
+		result, err = service.SearchShortURLs(context.Background(), &shorturls.SearchShortURLsQuery{AllUsers: true})
+		require.NoError(t, err)
+		require.Equal(t, int64(4), result.TotalCount)
+
+		// a caller without user id only finds the short URLs created without user
+		result, err = service.SearchShortURLs(context.Background(), &shorturls.SearchShortURLsQuery{})
+		require.NoError(t, err)
+		require.Zero(t, result.TotalCount)
+
+		usage, err := service.Usage(context.Background(), &quota.ScopeParameters{OrgID: 1})
+		require.NoError(t, err)
+		tag, err := quota.NewTag(shorturls.QuotaTargetSrv, shorturls.QuotaTarget, quota.GlobalScope)
+		require.NoError(t, err)
+		count, ok := usage.Get(tag)
+		require.True(t, ok)
+		require.Equal(t, int64(4), count)
+
+		err = service.DeleteShortURL(context.Background(), &shorturls.DeleteShortURLCommand{UID: otherShortURL.Uid})
+		require.NoError(t, err)
+
+		err = service.DeleteShortURL(context.Background(), &shorturls.DeleteShortURLCommand{UID: otherShortURL.Uid})
+		require.True(t, shorturls.ErrShortURLNotFound.Is(err))
+
+		result, err = service.SearchShortURLs(context.Background(), &shorturls.SearchShortURLsQuery{AllUsers: true})
+		require.NoError(t, err)
+		require.Equal(t, int64(3), result.TotalCount)
+	})
+
 	t.Run("User cannot look up nonexistent short URLs", func(t *testing.T) {
 		service := ShortURLService{SQLStore: &sqlStore{db: store}}
 
@@ -2,17 +2,22 @@ package shorturlimpl
 
 import (
 	"context"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/shorturls"
 	"github.com/grafana/grafana/pkg/services/user"
 )
 
 type store interface {
 	Get(ctx context.Context, user *user.SignedInUser, uid string) (*shorturls.ShortUrl, error)
+	Search(ctx context.Context, query *shorturls.SearchShortURLsQuery) (*shorturls.SearchShortURLsResult, error)
 	Update(ctx context.Context, shortURL *shorturls.ShortUrl) error
 	Insert(ctx context.Context, shortURL *shorturls.ShortUrl) error
+	DeleteByUID(ctx context.Context, cmd *shorturls.DeleteShortURLCommand) error
 	Delete(ctx context.Context, cmd *shorturls.DeleteShortUrlCommand) error
+	Count(ctx context.Context, scopeParams *quota.ScopeParameters) (*quota.Map, error)
 }
 
 type sqlStore struct {
@@ -39,13 +44,50 @@ func (s sqlStore) Get(ctx context.Context, user *user.SignedInUser, uid string)
 	return &shortURL, nil
 }
 
+func (s sqlStore) Search(ctx context.Context, query *shorturls.SearchShortURLsQuery) (*shorturls.SearchShortURLsResult, error) {
+	result := shorturls.SearchShortURLsResult{
+		ShortURLs: make([]*shorturls.ShortUrl, 0),
+		Page:      query.Page,
+		PerPage:   query.Limit,
+	}
+	err := s.db.WithDbSession(ctx, func(dbSession *db.Session) error {
+		whereConditions := []string{"org_id = ?"}
+		whereParams := []any{query.OrgID}
+		if !query.AllUsers {
+			whereConditions = append(whereConditions, "created_by = ?")
+			whereParams = append(whereParams, query.CreatedBy)
+		}
+		where := strings.Join(whereConditions, " AND ")
+
+		offset := query.Limit * (query.Page - 1)
+		if err := dbSession.Where(where, whereParams...).Desc("created_at").Limit(query.Limit, offset).Find(&result.ShortURLs); err != nil {
+			return err
+		}
+
+		count, err := dbSession.Where(where, whereParams...).Count(&shorturls.ShortUrl{})
+		if err != nil {
+			return err
+		}
+		result.TotalCount = count
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// Update records a visit of the short URL.
 func (s sqlStore) Update(ctx context.Context, shortURL *shorturls.ShortUrl) error {
-	shortURL.LastSeenAt = getTime().Unix()
+	lastSeenAt := getTime().Unix()
 	return s.db.WithTransactionalDbSession(ctx, func(dbSession *db.Session) error {
-		_, err := dbSession.ID(shortURL.Id).Update(shortURL)
-		if err != nil {
+		rawSQL := "UPDATE short_url SET last_seen_at = ?, hit_count = hit_count + 1 WHERE id = ?"
+		if _, err := dbSession.Exec(rawSQL, lastSeenAt, shortURL.Id); err != nil {
 			return err
 		}
+		shortURL.LastSeenAt = lastSeenAt
+		shortURL.HitCount++
 		return nil
 	})
 }
@@ -57,11 +99,30 @@ func (s sqlStore) Insert(ctx context.Context, shortURL *shorturls.ShortUrl) erro
 	})
 }
 
+func (s sqlStore) DeleteByUID(ctx context.Context, cmd *shorturls.DeleteShortURLCommand) error {
+	return s.db.WithTransactionalDbSession(ctx, func(session *db.Session) error {
+		result, err := session.Exec("DELETE FROM short_url WHERE org_id = ? AND uid = ?", cmd.OrgID, cmd.UID)
+		if err != nil {
+			return err
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return err
+		} else if n == 0 {
+			return shorturls.ErrShortURLNotFound.Errorf("short URL not found")
+		}
+		return nil
+	})
+}
+
+// Delete removes the short URLs that expired, and the ones without an expiry that were created before
+// cmd.OlderThan and never visited.
 func (s sqlStore) Delete(ctx context.Context, cmd *shorturls.DeleteShortUrlCommand) error {
 	return s.db.WithTransactionalDbSession(ctx, func(session *db.Session) error {
-		var rawSql = "DELETE FROM short_url WHERE created_at <= ? AND (last_seen_at IS NULL OR last_seen_at = 0)"
+		var rawSql = "DELETE FROM short_url WHERE (expires_at > 0 AND expires_at <= ?) OR " +
+			"(expires_at = 0 AND created_at <= ? AND (last_seen_at IS NULL OR last_seen_at = 0))"
 
-		if result, err := session.Exec(rawSql, cmd.OlderThan.Unix()); err != nil {
+		if result, err := session.Exec(rawSql, getTime().Unix(), cmd.OlderThan.Unix()); err != nil {
 			return err
 		} else if cmd.NumDeleted, err = result.RowsAffected(); err != nil {
 			return err
@@ -69,3 +130,47 @@ func (s sqlStore) Delete(ctx context.Context, cmd *shorturls.DeleteShortUrlComma
 		return nil
 	})
 }
+
+func (s sqlStore) Count(ctx context.Context, scopeParams *quota.ScopeParameters) (*quota.Map, error) {
+	u := &quota.Map{}
+	type result struct {
+		Count int64
+	}
+
+	r := result{}
+	if err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		rawSQL := "SELECT COUNT(*) AS count FROM short_url"
+		if _, err := sess.SQL(rawSQL).Get(&r); err != nil {
+			return err
+		}
+		return nil
+	}); err != nil {
+		return u, err
+	} else {
+		tag, err := quota.NewTag(shorturls.QuotaTargetSrv, shorturls.QuotaTarget, quota.GlobalScope)
+		if err != nil {
+			return nil, err
+		}
+		u.Set(tag, r.Count)
+	}
+
+	if scopeParams != nil && scopeParams.OrgID != 0 {
+		if err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+			rawSQL := "SELECT COUNT(*) AS count FROM short_url WHERE org_id = ?"
+			if _, err := sess.SQL(rawSQL, scopeParams.OrgID).Get(&r); err != nil {
+				return err
+			}
+			return nil
+		}); err != nil {
+			return u, err
+		} else {
+			tag, err := quota.NewTag(shorturls.QuotaTargetSrv, shorturls.QuotaTarget, quota.OrgScope)
+			if err != nil {
+				return nil, err
+			}
+			u.Set(tag, r.Count)
+		}
+	}
+
+	return u, nil
+}
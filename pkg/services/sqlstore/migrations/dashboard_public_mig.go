@@ -96,4 +96,36 @@ func addPublicDashboardMigration(mg *Migrator) {
 	mg.AddMigration("backfill empty share column fields with default of public", NewRawSQLMigration(
 		"UPDATE dashboard_public SET share='public' WHERE share=''",
 	))
+
+	mg.AddMigration("add variable_settings column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "variable_settings",
+		Type:     DB_Text,
+		Nullable: true,
+	}))
+
+	var dashboardPublicTokenV1 = Table{
+		Name: "dashboard_public_token",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "public_dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "name", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "token", Type: DB_NVarchar, Length: 32, Nullable: false},
+			{Name: "rate_limit", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "expires_at", Type: DB_DateTime, Nullable: true},
+			{Name: "revoked_at", Type: DB_DateTime, Nullable: true},
+			{Name: "last_used_at", Type: DB_DateTime, Nullable: true},
+			{Name: "created_by", Type: DB_Int, Nullable: false},
+			{Name: "created_at", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"uid"}, Type: UniqueIndex},
+			{Cols: []string{"token"}, Type: UniqueIndex},
+			{Cols: []string{"public_dashboard_uid"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard public token table v1", NewAddTableMigration(dashboardPublicTokenV1))
+	addTableIndicesMigrations(mg, "v1", dashboardPublicTokenV1)
 }
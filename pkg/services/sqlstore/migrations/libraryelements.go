@@ -84,3 +84,31 @@ func addLibraryElementsMigrations(mg *migrator.Migrator) {
 
 	mg.AddMigration("add index library_element org_id-folder_uid-name-kind", migrator.NewAddIndexMigration(libraryElementsV1, &migrator.Index{Cols: []string{"org_id", "folder_uid", "name", "kind"}, Type: migrator.UniqueIndex}))
 }
+
+// addLibraryElementVersionMigrations defines database migrations for the version history of library elements.
+func addLibraryElementVersionMigrations(mg *migrator.Migrator) {
+	libraryElementVersionV1 := migrator.Table{
+		Name: model.LibraryElementVersionTableName,
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "element_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "version", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "name", Type: migrator.DB_NVarchar, Length: 150, Nullable: false},
+			{Name: "model", Type: migrator.DB_MediumText, Nullable: false},
+			{Name: "created", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "created_by", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"element_id", "version"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create "+model.LibraryElementVersionTableName+" table v1", migrator.NewAddTableMigration(libraryElementVersionV1))
+	mg.AddMigration("add index "+model.LibraryElementVersionTableName+" element_id-version", migrator.NewAddIndexMigration(libraryElementVersionV1, libraryElementVersionV1.Indices[0]))
+
+	// the current model of the existing library elements is the first version of their history
+	mg.AddMigration("populate "+model.LibraryElementVersionTableName+" with the current versions", migrator.NewRawSQLMigration(`
+	INSERT INTO `+model.LibraryElementVersionTableName+` (org_id, element_id, version, name, model, created, created_by)
+	SELECT org_id, id, version, name, model, updated, updated_by FROM library_element`))
+}
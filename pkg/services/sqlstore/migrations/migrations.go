@@ -121,6 +121,14 @@ func (oss *OSSMigrations) AddMigration(mg *Migrator) {
 	ualert.AddRuleNotificationSettingsColumns(mg)
 
 	accesscontrol.AddAlertingScopeRemovalMigration(mg)
+
+	addScheduledReportMigrations(mg)
+
+	addPlaylistScheduleMigrations(mg)
+
+	addShortURLManagementMigrations(mg)
+
+	addLibraryElementVersionMigrations(mg)
 }
 
 func addStarMigrations(mg *Migrator) {
@@ -60,6 +60,35 @@ func addPlaylistUIDMigration(mg *Migrator) {
 	}))
 }
 
+func addPlaylistScheduleMigrations(mg *Migrator) {
+	mg.AddMigration("Add playlist column timezone", NewAddColumnMigration(playlistV2(), &Column{
+		Name: "timezone", Type: DB_NVarchar, Length: 255, Nullable: false, Default: "''",
+	}))
+
+	playlistItem := Table{Name: "playlist_item"}
+	mg.AddMigration("Add playlist_item column dwell", NewAddColumnMigration(playlistItem, &Column{
+		Name: "dwell", Type: DB_NVarchar, Length: 255, Nullable: true,
+	}))
+	mg.AddMigration("Add playlist_item column time_from", NewAddColumnMigration(playlistItem, &Column{
+		Name: "time_from", Type: DB_NVarchar, Length: 255, Nullable: true,
+	}))
+	mg.AddMigration("Add playlist_item column time_to", NewAddColumnMigration(playlistItem, &Column{
+		Name: "time_to", Type: DB_NVarchar, Length: 255, Nullable: true,
+	}))
+	mg.AddMigration("Add playlist_item column variables", NewAddColumnMigration(playlistItem, &Column{
+		Name: "variables", Type: DB_Text, Nullable: true,
+	}))
+	mg.AddMigration("Add playlist_item column active_from", NewAddColumnMigration(playlistItem, &Column{
+		Name: "active_from", Type: DB_NVarchar, Length: 5, Nullable: true,
+	}))
+	mg.AddMigration("Add playlist_item column active_to", NewAddColumnMigration(playlistItem, &Column{
+		Name: "active_to", Type: DB_NVarchar, Length: 5, Nullable: true,
+	}))
+	mg.AddMigration("Add playlist_item column active_days", NewAddColumnMigration(playlistItem, &Column{
+		Name: "active_days", Type: DB_NVarchar, Length: 255, Nullable: true,
+	}))
+}
+
 func playlistV2() Table {
 	return Table{
 		Name: "playlist",
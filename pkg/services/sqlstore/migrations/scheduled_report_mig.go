@@ -0,0 +1,61 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addScheduledReportMigrations(mg *Migrator) {
+	reportV1 := Table{
+		Name: "scheduled_report",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "name", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "variables", Type: DB_Text, Nullable: true},
+			{Name: "time_from", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "time_to", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "schedule", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "timezone", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "recipients", Type: DB_Text, Nullable: false},
+			{Name: "message", Type: DB_Text, Nullable: false},
+			{Name: "format", Type: DB_NVarchar, Length: 16, Nullable: false},
+			{Name: "enabled", Type: DB_Bool, Nullable: false},
+			{Name: "next_send_at", Type: DB_DateTime, Nullable: true},
+			{Name: "created_by", Type: DB_BigInt, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "uid"}, Type: UniqueIndex},
+			{Cols: []string{"next_send_at"}},
+		},
+	}
+
+	mg.AddMigration("create scheduled_report table v1", NewAddTableMigration(reportV1))
+	addTableIndicesMigrations(mg, "v1", reportV1)
+
+	reportHistoryV1 := Table{
+		Name: "scheduled_report_history",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "report_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "name", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "recipients", Type: DB_Text, Nullable: false},
+			{Name: "format", Type: DB_NVarchar, Length: 16, Nullable: false},
+			{Name: "status", Type: DB_NVarchar, Length: 16, Nullable: false},
+			{Name: "error", Type: DB_Text, Nullable: true},
+			{Name: "manual", Type: DB_Bool, Nullable: false},
+			{Name: "sent_at", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "report_uid"}},
+			{Cols: []string{"org_id", "sent_at"}},
+		},
+	}
+
+	mg.AddMigration("create scheduled_report_history table v1", NewAddTableMigration(reportHistoryV1))
+	addTableIndicesMigrations(mg, "v1", reportHistoryV1)
+}
@@ -29,3 +29,19 @@ func addShortURLMigrations(mg *Migrator) {
 		Mysql("ALTER TABLE short_url MODIFY created_by BIGINT;").
 		Postgres("ALTER TABLE short_url ALTER COLUMN created_by TYPE BIGINT;"))
 }
+
+func addShortURLManagementMigrations(mg *Migrator) {
+	shortURL := Table{Name: "short_url"}
+
+	mg.AddMigration("Add short_url column expires_at", NewAddColumnMigration(shortURL, &Column{
+		Name: "expires_at", Type: DB_BigInt, Nullable: false, Default: "0",
+	}))
+
+	mg.AddMigration("Add short_url column hit_count", NewAddColumnMigration(shortURL, &Column{
+		Name: "hit_count", Type: DB_BigInt, Nullable: false, Default: "0",
+	}))
+
+	mg.AddMigration("add index short_url.org_id-created_by", NewAddIndexMigration(shortURL, &Index{
+		Cols: []string{"org_id", "created_by"},
+	}))
+}
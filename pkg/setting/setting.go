@@ -452,10 +452,23 @@ type Cfg struct {
 	// Query history
 	QueryHistoryEnabled bool
 
+	// Scheduled reports
+	ScheduledReportsEnabled       bool
+	ScheduledReportsRenderTimeout time.Duration
+
+	// Dashboard lint
+	DashboardLintDisabledRules         []string
+	DashboardLintSeverities            map[string]string
+	DashboardLintMaxDataPoints         int64
+	DashboardLintEnforceOnSave         bool
+	DashboardLintEnforceOnProvisioning bool
+
 	Storage StorageSettings
 
 	Search SearchSettings
 
+	QueryCaching QueryCachingSettings
+
 	SecureSocksDSProxy SecureSocksDSProxySettings
 
 	// SAML Auth
@@ -1155,6 +1168,24 @@ func (cfg *Cfg) parseINIFile(iniFile *ini.File) error {
 	queryHistory := iniFile.Section("query_history")
 	cfg.QueryHistoryEnabled = queryHistory.Key("enabled").MustBool(true)
 
+	scheduledReports := iniFile.Section("scheduled_reports")
+	cfg.ScheduledReportsEnabled = scheduledReports.Key("enabled").MustBool(false)
+	cfg.ScheduledReportsRenderTimeout = scheduledReports.Key("render_timeout").MustDuration(time.Minute)
+
+	dashboardLint := iniFile.Section("dashboard_lint")
+	cfg.DashboardLintDisabledRules = util.SplitString(dashboardLint.Key("disabled_rules").MustString(""))
+	cfg.DashboardLintSeverities = map[string]string{}
+	for _, override := range util.SplitString(dashboardLint.Key("severities").MustString("")) {
+		rule, severity, found := strings.Cut(override, ":")
+		if !found {
+			return fmt.Errorf("invalid dashboard lint severity %q, expected <rule>:<severity>", override)
+		}
+		cfg.DashboardLintSeverities[rule] = severity
+	}
+	cfg.DashboardLintMaxDataPoints = dashboardLint.Key("max_data_points").MustInt64(5000)
+	cfg.DashboardLintEnforceOnSave = dashboardLint.Key("enforce_on_save").MustBool(false)
+	cfg.DashboardLintEnforceOnProvisioning = dashboardLint.Key("enforce_on_provisioning").MustBool(false)
+
 	panelsSection := iniFile.Section("panels")
 	cfg.DisableSanitizeHtml = panelsSection.Key("disable_sanitize_html").MustBool(false)
 
@@ -1202,6 +1233,7 @@ func (cfg *Cfg) parseINIFile(iniFile *ini.File) error {
 
 	cfg.Storage = readStorageSettings(iniFile)
 	cfg.Search = readSearchSettings(iniFile)
+	cfg.QueryCaching = readQueryCachingSettings(iniFile)
 
 	var err error
 	cfg.SecureSocksDSProxy, err = readSecureSocksDSProxySettings(iniFile)
@@ -0,0 +1,46 @@
+package setting
+
+import (
+	"time"
+
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type QueryCachingSettings struct {
+	// Enabled turns on server-side caching of datasource query and resource responses.
+	Enabled bool
+	// TTL is the default time-to-live for cached query responses.
+	TTL time.Duration
+	// ResourceTTL is the default time-to-live for cached resource responses.
+	ResourceTTL time.Duration
+	// TimeBucket is the resolution query time ranges are aligned to when building cache keys,
+	// so that requests made a few seconds apart share the same cached result.
+	TimeBucket time.Duration
+	// MaxValueSize is the maximum size in bytes of a response that will be cached. 0 means no limit.
+	MaxValueSize int
+	// Incremental enables incremental caching of time series queries, where only the part of the
+	// requested time range that is not already cached is fetched from the datasource.
+	Incremental bool
+	// IncrementalDatasources are the datasource types eligible for incremental caching.
+	IncrementalDatasources []string
+	// IncrementalOverlap is how much of the most recent cached data is fetched again on each
+	// incremental request, to pick up late samples.
+	IncrementalOverlap time.Duration
+}
+
+func readQueryCachingSettings(iniFile *ini.File) QueryCachingSettings {
+	s := QueryCachingSettings{}
+
+	section := iniFile.Section("query_caching")
+	s.Enabled = section.Key("enabled").MustBool(false)
+	s.TTL = section.Key("ttl").MustDuration(time.Minute)
+	s.ResourceTTL = section.Key("resource_ttl").MustDuration(5 * time.Minute)
+	s.TimeBucket = section.Key("time_bucket").MustDuration(10 * time.Second)
+	s.MaxValueSize = section.Key("max_value_size").MustInt(10 * 1024 * 1024)
+	s.Incremental = section.Key("incremental").MustBool(false)
+	s.IncrementalDatasources = util.SplitString(section.Key("incremental_datasources").MustString("prometheus,loki,postgres,grafana-postgresql-datasource,mysql,mssql"))
+	s.IncrementalOverlap = section.Key("incremental_overlap").MustDuration(time.Minute)
+	return s
+}
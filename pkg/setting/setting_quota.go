@@ -6,6 +6,7 @@ type OrgQuota struct {
 	Dashboard  int64 `target:"dashboard"`
 	ApiKey     int64 `target:"api_key"`
 	AlertRule  int64 `target:"alert_rule"`
+	ShortURL   int64 `target:"short_url"`
 }
 
 type UserQuota struct {
@@ -22,6 +23,7 @@ type GlobalQuota struct {
 	AlertRule    int64 `target:"alert_rule"`
 	File         int64 `target:"file"`
 	Correlations int64 `target:"correlations"`
+	ShortURL     int64 `target:"short_url"`
 }
 
 type QuotaSettings struct {
@@ -43,6 +45,7 @@ func (cfg *Cfg) readQuotaSettings() {
 		Dashboard:  quota.Key("org_dashboard").MustInt64(10),
 		ApiKey:     quota.Key("org_api_key").MustInt64(10),
 		AlertRule:  quota.Key("org_alert_rule").MustInt64(100),
+		ShortURL:   quota.Key("org_short_url").MustInt64(1000),
 	}
 
 	// per User limits
@@ -61,5 +64,6 @@ func (cfg *Cfg) readQuotaSettings() {
 		File:         quota.Key("global_file").MustInt64(-1),
 		AlertRule:    quota.Key("global_alert_rule").MustInt64(-1),
 		Correlations: quota.Key("global_correlations").MustInt64(-1),
+		ShortURL:     quota.Key("global_short_url").MustInt64(-1),
 	}
 }
@@ -40,6 +40,8 @@ type DatasourceInfo struct {
 	MaxConcurrentShardRequests int64
 	IncludeFrozen              bool
 	XPack                      bool
+	// RawQueriesEnabled allows ES|QL and SQL queries, which are not limited to the configured index
+	RawQueriesEnabled bool
 }
 
 type ConfiguredFields struct {
@@ -53,6 +55,9 @@ type Client interface {
 	GetConfiguredFields() ConfiguredFields
 	ExecuteMultisearch(r *MultiSearchRequest) (*MultiSearchResponse, error)
 	MultiSearch() *MultiSearchRequestBuilder
+	OpenPointInTime(keepAlive string) (string, error)
+	ExecuteESQL(query string) (*ColumnarResponse, error)
+	ExecuteSQL(query string, maxRows int) (*ColumnarResponse, error)
 }
 
 // NewClient creates a new elasticsearch client
@@ -232,6 +237,12 @@ func (c *baseClientImpl) ExecuteMultisearch(r *MultiSearchRequest) (*MultiSearch
 
 	msr.Status = res.StatusCode
 
+	err = c.paginateCompositeAggregations(r, &msr)
+	if err != nil {
+		c.logger.Error("Failed to paginate composite aggregations", "error", err, "stage", StageDatabaseRequest)
+		return nil, err
+	}
+
 	return &msr, nil
 }
 
@@ -248,6 +259,12 @@ func (c *baseClientImpl) createMultiSearchRequests(searchRequests []*SearchReque
 			body:     searchReq,
 			interval: searchReq.Interval,
 		}
+		// Searches against a point in time must not specify indices, they are part of the point in time
+		if searchReq.PointInTime != nil {
+			mr.header = map[string]any{
+				"search_type": "query_then_fetch",
+			}
+		}
 
 		multiRequests = append(multiRequests, &mr)
 	}
@@ -274,3 +291,35 @@ func (c *baseClientImpl) getMultiSearchQueryParameters() string {
 func (c *baseClientImpl) MultiSearch() *MultiSearchRequestBuilder {
 	return NewMultiSearchRequestBuilder()
 }
+
+// OpenPointInTime opens a point in time on the indices of the client and returns its id.
+// The point in time is kept alive for keepAlive after each search using it.
+func (c *baseClientImpl) OpenPointInTime(keepAlive string) (string, error) {
+	uriPath := path.Join(strings.Join(c.indices, ","), "_pit")
+	uriQuery := url.Values{"keep_alive": {keepAlive}, "ignore_unavailable": {"true"}}.Encode()
+
+	res, err := c.executeRequest(http.MethodPost, uriPath, uriQuery, nil)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			c.logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	if res.StatusCode >= http.StatusBadRequest {
+		return "", exp.DownstreamError(fmt.Errorf("failed to open point in time: unexpected status code %d", res.StatusCode), false)
+	}
+
+	var pit struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&pit); err != nil {
+		return "", err
+	}
+	if pit.ID == "" {
+		return "", errors.New("failed to open point in time: no id in response")
+	}
+	return pit.ID, nil
+}
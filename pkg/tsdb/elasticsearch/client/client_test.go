@@ -3,6 +3,7 @@ package es
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -217,6 +218,67 @@ func TestClient_Index(t *testing.T) {
 	}
 }
 
+func TestClient_PointInTime(t *testing.T) {
+	var requests []*http.Request
+	var bodies [][]byte
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		buf, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		bodies = append(bodies, buf)
+
+		if r.URL.Path == "/_msearch" {
+			_, err = rw.Write([]byte(`{"responses": [{"hits": {"hits": []}, "pit_id": "pit-2", "status": 200}]}`))
+		} else {
+			_, err = rw.Write([]byte(`{"id": "pit-1"}`))
+		}
+		require.NoError(t, err)
+	}))
+	t.Cleanup(ts.Close)
+
+	ds := DatasourceInfo{
+		URL:        ts.URL,
+		HTTPClient: ts.Client(),
+		Database:   "[logs-]YYYY.MM.DD",
+		Interval:   "Daily",
+	}
+	timeRange := backend.TimeRange{
+		From: time.Date(2018, 5, 10, 17, 50, 0, 0, time.UTC),
+		To:   time.Date(2018, 5, 11, 17, 55, 0, 0, time.UTC),
+	}
+	c, err := NewClient(context.Background(), &ds, timeRange, log.New("test", "test"), tracing.InitializeTracerForTest())
+	require.NoError(t, err)
+
+	id, err := c.OpenPointInTime("5m")
+	require.NoError(t, err)
+	assert.Equal(t, "pit-1", id)
+	require.Len(t, requests, 1)
+	assert.Equal(t, http.MethodPost, requests[0].Method)
+	assert.Equal(t, "/logs-2018.05.10,logs-2018.05.11/_pit", requests[0].URL.Path)
+	assert.Equal(t, "5m", requests[0].URL.Query().Get("keep_alive"))
+
+	msb := c.MultiSearch()
+	msb.Search(15*time.Second).PointInTime(id, "5m")
+	ms, err := msb.Build()
+	require.NoError(t, err)
+	res, err := c.ExecuteMultisearch(ms)
+	require.NoError(t, err)
+	assert.Equal(t, "pit-2", res.Responses[0].PitID)
+
+	requestBody := bytes.NewBuffer(bodies[1])
+	headerBytes, err := requestBody.ReadBytes('\n')
+	require.NoError(t, err)
+	jHeader, err := simplejson.NewJson(headerBytes)
+	require.NoError(t, err)
+	jBody, err := simplejson.NewJson(requestBody.Bytes())
+	require.NoError(t, err)
+
+	_, hasIndex := jHeader.CheckGet("index")
+	assert.False(t, hasIndex)
+	assert.Equal(t, "pit-1", jBody.GetPath("pit", "id").MustString())
+	assert.Equal(t, "5m", jBody.GetPath("pit", "keep_alive").MustString())
+}
+
 func createMultisearchForTest(t *testing.T, c Client) (*MultiSearchRequest, error) {
 	t.Helper()
 
@@ -231,3 +293,180 @@ func createMultisearchForTest(t *testing.T, c Client) (*MultiSearchRequest, erro
 	})
 	return msb.Build()
 }
+
+func TestClient_CompositeAggregationPagination(t *testing.T) {
+	newTestClient := func(t *testing.T, handler http.HandlerFunc) Client {
+		t.Helper()
+		ts := httptest.NewServer(handler)
+		t.Cleanup(ts.Close)
+		ds := DatasourceInfo{URL: ts.URL, HTTPClient: ts.Client(), Database: "metrics"}
+		c, err := NewClient(context.Background(), &ds, backend.TimeRange{From: time.Now().Add(-time.Hour), To: time.Now()}, log.New("test", "test"), tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		return c
+	}
+	// The server has 5 hosts, and returns pages of `size` buckets after the `after` key
+	hostsHandler := func(t *testing.T, bodies *[]string) http.HandlerFunc {
+		return func(rw http.ResponseWriter, r *http.Request) {
+			buf, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			lines := bytes.Split(bytes.TrimSpace(buf), []byte("\n"))
+			body, err := simplejson.NewJson(lines[1])
+			require.NoError(t, err)
+			*bodies = append(*bodies, string(lines[1]))
+
+			composite := body.GetPath("aggs", "2", "composite")
+			size := composite.Get("size").MustInt()
+			after := composite.GetPath("after", "host").MustInt(-1)
+			buckets := []any{}
+			for host := after + 1; host < 5 && len(buckets) < size; host++ {
+				buckets = append(buckets, map[string]any{"key": map[string]any{"host": host}, "doc_count": 1})
+			}
+			agg := map[string]any{"buckets": buckets}
+			if len(buckets) > 0 {
+				agg["after_key"] = buckets[len(buckets)-1].(map[string]any)["key"]
+			}
+			res, err := json.Marshal(map[string]any{"responses": []any{map[string]any{"aggregations": map[string]any{"2": agg}}}})
+			require.NoError(t, err)
+			_, err = rw.Write(res)
+			require.NoError(t, err)
+		}
+	}
+	search := func(t *testing.T, c Client, size, maxBuckets int) *MultiSearchResponse {
+		t.Helper()
+		msb := c.MultiSearch()
+		msb.Search(15*time.Second).Agg().Composite("2", func(a *CompositeAggregation, b AggBuilder) {
+			a.Sources = []*CompositeSource{{Name: "host", Field: "host"}}
+			a.Size = size
+			a.MaxBuckets = maxBuckets
+		})
+		ms, err := msb.Build()
+		require.NoError(t, err)
+		res, err := c.ExecuteMultisearch(ms)
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("requests pages until all buckets are returned", func(t *testing.T) {
+		var bodies []string
+		c := newTestClient(t, hostsHandler(t, &bodies))
+
+		res := search(t, c, 2, 100)
+		agg := res.Responses[0].Aggregations["2"].(map[string]any)
+		assert.Len(t, agg["buckets"], 5)
+		assert.NotContains(t, agg, "after_key")
+		require.Len(t, bodies, 3)
+		assert.Contains(t, bodies[1], `"after":{"host":1}`)
+		assert.Contains(t, bodies[2], `"after":{"host":3}`)
+	})
+
+	t.Run("stops at the maximum number of buckets", func(t *testing.T) {
+		var bodies []string
+		c := newTestClient(t, hostsHandler(t, &bodies))
+
+		res := search(t, c, 2, 3)
+		agg := res.Responses[0].Aggregations["2"].(map[string]any)
+		assert.Len(t, agg["buckets"], 3)
+		assert.Contains(t, agg, "after_key")
+		assert.Len(t, bodies, 2)
+	})
+}
+
+func TestClient_RawQueries(t *testing.T) {
+	newTestClient := func(t *testing.T, handler http.HandlerFunc) Client {
+		t.Helper()
+		ts := httptest.NewServer(handler)
+		t.Cleanup(ts.Close)
+
+		ds := DatasourceInfo{
+			URL:               ts.URL,
+			HTTPClient:        ts.Client(),
+			Database:          "logs",
+			RawQueriesEnabled: true,
+		}
+		timeRange := backend.TimeRange{
+			From: time.Date(2018, 5, 15, 17, 50, 0, 0, time.UTC),
+			To:   time.Date(2018, 5, 15, 17, 55, 0, 0, time.UTC),
+		}
+		c, err := NewClient(context.Background(), &ds, timeRange, log.New("test", "test"), tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		return c
+	}
+
+	t.Run("executes ES|QL queries", func(t *testing.T) {
+		var body map[string]interface{}
+		c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/_query", r.URL.Path)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			_, err := rw.Write([]byte(`{"columns": [{"name": "count", "type": "long"}], "values": [[9007199254740993]]}`))
+			require.NoError(t, err)
+		})
+
+		res, err := c.ExecuteESQL("FROM logs | STATS count = COUNT(*)")
+		require.NoError(t, err)
+		assert.Equal(t, "FROM logs | STATS count = COUNT(*)", body["query"])
+		assert.Equal(t, []ColumnarColumn{{Name: "count", Type: "long"}}, res.Columns)
+		assert.Equal(t, json.Number("9007199254740993"), res.Values[0][0])
+		assert.False(t, res.Truncated)
+	})
+
+	t.Run("returns the reason of failed queries", func(t *testing.T) {
+		c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(http.StatusBadRequest)
+			_, err := rw.Write([]byte(`{"error": {"reason": "Unknown index [missing]"}, "status": 400}`))
+			require.NoError(t, err)
+		})
+
+		_, err := c.ExecuteESQL("FROM missing")
+		require.EqualError(t, err, "Unknown index [missing]")
+	})
+
+	t.Run("follows SQL cursors up to the maximum number of rows", func(t *testing.T) {
+		var paths []string
+		var bodies []map[string]interface{}
+		c := newTestClient(t, func(rw http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			bodies = append(bodies, body)
+
+			var err error
+			switch {
+			case r.URL.Path == "/_sql/close":
+				_, err = rw.Write([]byte(`{"succeeded": true}`))
+			case body["cursor"] == nil:
+				assert.Equal(t, "json", r.URL.Query().Get("format"))
+				_, err = rw.Write([]byte(`{"columns": [{"name": "host", "type": "keyword"}], "rows": [["a"], ["b"]], "cursor": "c1"}`))
+			default:
+				_, err = rw.Write([]byte(`{"rows": [["c"], ["d"]], "cursor": "c2"}`))
+			}
+			require.NoError(t, err)
+		})
+
+		res, err := c.ExecuteSQL("SELECT host FROM logs", 3)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/_sql", "/_sql", "/_sql/close"}, paths)
+		assert.Equal(t, float64(3), bodies[0]["fetch_size"])
+		assert.Equal(t, "c1", bodies[1]["cursor"])
+		assert.Equal(t, "c2", bodies[2]["cursor"])
+		assert.Equal(t, [][]interface{}{{"a"}, {"b"}, {"c"}}, res.Values)
+		assert.True(t, res.Truncated)
+	})
+
+	t.Run("rejects queries when raw queries are not enabled", func(t *testing.T) {
+		requests := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			requests++
+		}))
+		t.Cleanup(ts.Close)
+		ds := DatasourceInfo{URL: ts.URL, HTTPClient: ts.Client(), Database: "logs"}
+		c, err := NewClient(context.Background(), &ds, backend.TimeRange{From: time.Now().Add(-time.Hour), To: time.Now()}, log.New("test", "test"), tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+
+		_, err = c.ExecuteESQL("FROM other")
+		require.ErrorIs(t, err, ErrRawQueriesDisabled)
+		_, err = c.ExecuteSQL("SELECT * FROM other", 10)
+		require.ErrorIs(t, err, ErrRawQueriesDisabled)
+		assert.Equal(t, 0, requests)
+	})
+}
@@ -0,0 +1,153 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// compositePage is a request for the next page of buckets of a composite aggregation
+type compositePage struct {
+	responseIdx int
+	key         string
+	agg         *CompositeAggregation
+	result      map[string]interface{}
+}
+
+// paginateCompositeAggregations requests the following pages of the top level composite aggregations
+// of the requests, and appends their buckets to the responses. Pages of all the aggregations are
+// requested together, until every aggregation is exhausted or has reached its maximum number of buckets.
+// The after_key of an aggregation is only kept in the response if more buckets were left out.
+func (c *baseClientImpl) paginateCompositeAggregations(r *MultiSearchRequest, msr *MultiSearchResponse) error {
+	var pages []*compositePage
+	for i, req := range r.Requests {
+		if i >= len(msr.Responses) || msr.Responses[i] == nil || msr.Responses[i].Error != nil {
+			continue
+		}
+		for _, agg := range req.Aggs {
+			composite, ok := agg.Aggregation.Aggregation.(*CompositeAggregation)
+			if !ok {
+				continue
+			}
+			result, ok := msr.Responses[i].Aggregations[agg.Key].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			page := &compositePage{responseIdx: i, key: agg.Key, agg: composite, result: result}
+			page.update(nil, len(compositeBuckets(result)))
+			pages = append(pages, page)
+		}
+	}
+
+	start := time.Now()
+	requests := 0
+	for {
+		var pending []*compositePage
+		var searchRequests []*SearchRequest
+		for _, page := range pages {
+			afterKey, ok := page.result["after_key"].(map[string]interface{})
+			if !ok || len(compositeBuckets(page.result)) >= page.agg.MaxBuckets {
+				continue
+			}
+			pending = append(pending, page)
+			searchRequests = append(searchRequests, page.searchRequest(r.Requests[page.responseIdx], afterKey))
+		}
+		if len(pending) == 0 {
+			break
+		}
+
+		res, err := c.executeMultisearchPage(searchRequests)
+		if err != nil {
+			return err
+		}
+		requests++
+
+		for i, page := range pending {
+			if i >= len(res.Responses) || res.Responses[i] == nil {
+				delete(page.result, "after_key")
+				continue
+			}
+			if res.Responses[i].Error != nil {
+				msr.Responses[page.responseIdx].Error = res.Responses[i].Error
+				delete(page.result, "after_key")
+				continue
+			}
+			next, _ := res.Responses[i].Aggregations[page.key].(map[string]interface{})
+			page.update(next, len(compositeBuckets(next)))
+		}
+	}
+
+	if requests > 0 {
+		c.logger.Debug("Completed pagination of composite aggregations", "requests", requests, "duration", time.Since(start))
+	}
+	return nil
+}
+
+// update appends the buckets of the next page to the result, and removes the after_key from the result
+// once there are no more buckets.
+func (p *compositePage) update(next map[string]interface{}, count int) {
+	if next != nil {
+		buckets := append(compositeBuckets(p.result), compositeBuckets(next)...)
+		p.result["buckets"] = buckets
+		if afterKey, ok := next["after_key"]; ok {
+			p.result["after_key"] = afterKey
+		}
+	}
+
+	buckets := compositeBuckets(p.result)
+	if len(buckets) > p.agg.MaxBuckets {
+		p.result["buckets"] = buckets[:p.agg.MaxBuckets]
+		return
+	}
+	// A page with fewer buckets than the page size is the last one
+	if count < p.agg.Size {
+		delete(p.result, "after_key")
+	}
+}
+
+// searchRequest returns the request for the page after afterKey. Only the composite aggregation is requested.
+func (p *compositePage) searchRequest(req *SearchRequest, afterKey map[string]interface{}) *SearchRequest {
+	agg := *p.agg
+	agg.After = afterKey
+
+	var container *aggContainer
+	for _, a := range req.Aggs {
+		if a.Key == p.key {
+			container = &aggContainer{Type: a.Aggregation.Type, Aggregation: &agg, Aggs: a.Aggregation.Aggs}
+		}
+	}
+
+	pageReq := *req
+	pageReq.Size = 0
+	pageReq.Aggs = AggArray{{Key: p.key, Aggregation: container}}
+	return &pageReq
+}
+
+func compositeBuckets(result map[string]interface{}) []interface{} {
+	buckets, _ := result["buckets"].([]interface{})
+	return buckets
+}
+
+func (c *baseClientImpl) executeMultisearchPage(requests []*SearchRequest) (*MultiSearchResponse, error) {
+	res, err := c.executeBatchRequest("_msearch", c.getMultiSearchQueryParameters(), c.createMultiSearchRequests(requests))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			c.logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	if res.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("failed to get the next page of a composite aggregation: unexpected status code %d", res.StatusCode)
+	}
+
+	var msr MultiSearchResponse
+	if err := json.NewDecoder(res.Body).Decode(&msr); err != nil {
+		return nil, err
+	}
+	msr.Status = res.StatusCode
+	return &msr, nil
+}
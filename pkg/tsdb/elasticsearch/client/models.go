@@ -14,6 +14,13 @@ type SearchRequest struct {
 	Query       *Query
 	Aggs        AggArray
 	CustomProps map[string]interface{}
+	PointInTime *PointInTime
+}
+
+// PointInTime represents the point in time a search request is executed against
+type PointInTime struct {
+	ID        string `json:"id"`
+	KeepAlive string `json:"keep_alive,omitempty"`
 }
 
 // MarshalJSON returns the JSON encoding of the request.
@@ -29,6 +36,10 @@ func (r *SearchRequest) MarshalJSON() ([]byte, error) {
 		root[key] = value
 	}
 
+	if r.PointInTime != nil {
+		root["pit"] = r.PointInTime
+	}
+
 	root["query"] = r.Query
 
 	if len(r.Aggs) > 0 {
@@ -48,6 +59,7 @@ type SearchResponse struct {
 	Error        map[string]interface{} `json:"error"`
 	Aggregations map[string]interface{} `json:"aggregations"`
 	Hits         *SearchResponseHits    `json:"hits"`
+	PitID        string                 `json:"pit_id"`
 }
 
 // MultiSearchRequest represents a multi search request
@@ -242,6 +254,48 @@ type TermsAggregation struct {
 	Missing     *string                `json:"missing,omitempty"`
 }
 
+// CompositeAggregation represents a composite aggregation. The client pages through its buckets
+// until all of them are returned or MaxBuckets is reached.
+type CompositeAggregation struct {
+	Sources    []*CompositeSource
+	Size       int
+	After      map[string]interface{}
+	MaxBuckets int
+}
+
+// CompositeSource represents a terms value source of a composite aggregation
+type CompositeSource struct {
+	Name          string
+	Field         string
+	MissingBucket bool
+}
+
+// MarshalJSON returns the JSON encoding of the composite aggregation
+func (a *CompositeAggregation) MarshalJSON() ([]byte, error) {
+	sources := make([]map[string]interface{}, 0, len(a.Sources))
+	for _, source := range a.Sources {
+		terms := map[string]interface{}{
+			"field": source.Field,
+		}
+		if source.MissingBucket {
+			terms["missing_bucket"] = true
+		}
+		sources = append(sources, map[string]interface{}{
+			source.Name: map[string]interface{}{"terms": terms},
+		})
+	}
+
+	root := map[string]interface{}{
+		"sources": sources,
+		"size":    a.Size,
+	}
+	if len(a.After) > 0 {
+		root["after"] = a.After
+	}
+
+	return json.Marshal(root)
+}
+
 // NestedAggregation represents a nested aggregation
 type NestedAggregation struct {
 	Path string `json:"path"`
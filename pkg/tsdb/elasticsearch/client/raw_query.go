@@ -0,0 +1,152 @@
+package es
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	exp "github.com/grafana/grafana-plugin-sdk-go/experimental/errorsource"
+)
+
+// ColumnarColumn represents a column of an ES|QL or SQL response
+type ColumnarColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// ColumnarResponse represents the response of an ES|QL or SQL query. Values holds the rows of both.
+type ColumnarResponse struct {
+	Columns []ColumnarColumn `json:"columns"`
+	Values  [][]interface{}  `json:"values"`
+	// Truncated is set when the rows were limited to the maximum number of rows
+	Truncated bool `json:"-"`
+}
+
+type sqlResponse struct {
+	Columns []ColumnarColumn `json:"columns"`
+	Rows    [][]interface{}  `json:"rows"`
+	Cursor  string           `json:"cursor"`
+}
+
+// ErrRawQueriesDisabled is returned for ES|QL and SQL queries when they are not enabled in the data source
+// settings. They can read any index the credentials of the data source can, not only the configured one.
+var ErrRawQueriesDisabled = errors.New("ES|QL and SQL queries are not enabled for this data source")
+
+// ExecuteESQL executes an ES|QL query
+func (c *baseClientImpl) ExecuteESQL(query string) (*ColumnarResponse, error) {
+	if !c.ds.RawQueriesEnabled {
+		return nil, ErrRawQueriesDisabled
+	}
+	var res ColumnarResponse
+	if err := c.executeJSONRequest("_query", map[string]interface{}{"query": query}, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ExecuteSQL executes an Elasticsearch SQL query. The pages of the result are fetched until maxRows
+// rows are returned, after which the cursor is closed.
+func (c *baseClientImpl) ExecuteSQL(query string, maxRows int) (*ColumnarResponse, error) {
+	if !c.ds.RawQueriesEnabled {
+		return nil, ErrRawQueriesDisabled
+	}
+	start := time.Now()
+	var page sqlResponse
+	if err := c.executeJSONRequest("_sql", map[string]interface{}{"query": query, "fetch_size": maxRows}, &page); err != nil {
+		return nil, err
+	}
+
+	res := &ColumnarResponse{Columns: page.Columns, Values: page.Rows}
+	for page.Cursor != "" && len(res.Values) < maxRows {
+		cursor := page.Cursor
+		page = sqlResponse{}
+		if err := c.executeJSONRequest("_sql", map[string]interface{}{"cursor": cursor}, &page); err != nil {
+			return nil, err
+		}
+		res.Values = append(res.Values, page.Rows...)
+	}
+
+	if len(res.Values) > maxRows {
+		res.Values = res.Values[:maxRows]
+		res.Truncated = true
+	}
+	if page.Cursor != "" {
+		res.Truncated = true
+		if err := c.executeJSONRequest("_sql/close", map[string]interface{}{"cursor": page.Cursor}, nil); err != nil {
+			c.logger.Warn("Failed to close SQL cursor", "error", err)
+		}
+	}
+
+	c.logger.Debug("Completed SQL query", "rows", len(res.Values), "duration", time.Since(start))
+	return res, nil
+}
+
+// executeJSONRequest posts the body as JSON and decodes the response into out. Error responses of
+// Elasticsearch are returned as downstream errors with their reason.
+func (c *baseClientImpl) executeJSONRequest(uriPath string, body interface{}, out interface{}) error {
+	u, err := url.Parse(c.ds.URL)
+	if err != nil {
+		return err
+	}
+	u.Path = path.Join(u.Path, uriPath)
+	if uriPath == "_sql" {
+		u.RawQuery = "format=json"
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodPost, u.String(), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	c.logger.Debug("Sending request to Elasticsearch", "url", c.ds.URL, "path", uriPath)
+	res, err := c.ds.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			c.logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	if res.StatusCode >= http.StatusBadRequest {
+		return exp.DownstreamError(errorFromResponse(res), false)
+	}
+	if out == nil {
+		return nil
+	}
+
+	dec := json.NewDecoder(res.Body)
+	// Long values would lose precision as float64
+	dec.UseNumber()
+	return dec.Decode(out)
+}
+
+// errorFromResponse returns the reason of an error response of Elasticsearch
+func errorFromResponse(res *http.Response) error {
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+
+	var errRes struct {
+		Error struct {
+			Reason string `json:"reason"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &errRes); err == nil && errRes.Error.Reason != "" {
+		return errors.New(errRes.Error.Reason)
+	}
+	return fmt.Errorf("unexpected status code %d", res.StatusCode)
+}
@@ -1,8 +1,11 @@
 package elasticsearch
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"regexp"
 	"slices"
@@ -20,6 +23,12 @@ import (
 
 const (
 	defaultSize = 500
+	// defaultCompositeSize is the number of buckets per page of composite aggregations
+	defaultCompositeSize = 1000
+	// defaultCompositeMaxBuckets is the maximum number of buckets of composite aggregations
+	defaultCompositeMaxBuckets = 10000
+	// pitKeepAlive is how long a point in time used to paginate logs is kept after each page
+	pitKeepAlive = "5m"
 )
 
 type elasticsearchDataQuery struct {
@@ -58,6 +67,20 @@ func (e *elasticsearchDataQuery) execute() (*backend.QueryDataResponse, error) {
 		return errorsource.AddPluginErrorToResponse(e.dataQueries[0].RefID, response, err), nil
 	}
 
+	// ES|QL and SQL queries are executed on their own, the other queries are sent in a single multisearch request
+	dslQueries := make([]*Query, 0, len(queries))
+	for _, q := range queries {
+		if isRawQuery(q) {
+			response.Responses[q.RefID] = e.executeRawQuery(q)
+			continue
+		}
+		dslQueries = append(dslQueries, q)
+	}
+	if len(dslQueries) == 0 {
+		return response, nil
+	}
+	queries = dslQueries
+
 	ms := e.client.MultiSearch()
 
 	from := e.dataQueries[0].TimeRange.From.UnixNano() / int64(time.Millisecond)
@@ -74,17 +97,24 @@ func (e *elasticsearchDataQuery) execute() (*backend.QueryDataResponse, error) {
 	if err != nil {
 		mqs, _ := json.Marshal(e.dataQueries)
 		e.logger.Error("Failed to build multisearch request", "error", err, "queriesLength", len(queries), "queries", string(mqs), "duration", time.Since(start), "stage", es.StagePrepareRequest)
-		return errorsource.AddPluginErrorToResponse(e.dataQueries[0].RefID, response, err), nil
+		return errorsource.AddPluginErrorToResponse(queries[0].RefID, response, err), nil
 	}
 
 	e.logger.Info("Prepared request", "queriesLength", len(queries), "duration", time.Since(start), "stage", es.StagePrepareRequest)
 	res, err := e.client.ExecuteMultisearch(req)
 	if err != nil {
 		// We are returning error containing the source that was added trough errorsource.Middleware
-		return errorsource.AddErrorToResponse(e.dataQueries[0].RefID, response, err), nil
+		return errorsource.AddErrorToResponse(queries[0].RefID, response, err), nil
 	}
 
-	return parseResponse(e.ctx, res.Responses, queries, e.client.GetConfiguredFields(), e.keepLabelsInResponse, e.logger, e.tracer)
+	result, err := parseResponse(e.ctx, res.Responses, queries, e.client.GetConfiguredFields(), e.keepLabelsInResponse, e.logger, e.tracer)
+	if err != nil {
+		return nil, err
+	}
+	for refID, dataResponse := range response.Responses {
+		result.Responses[refID] = dataResponse
+	}
+	return result, nil
 }
 
 func (e *elasticsearchDataQuery) processQuery(q *Query, ms *es.MultiSearchRequestBuilder, from, to int64) error {
@@ -102,7 +132,11 @@ func (e *elasticsearchDataQuery) processQuery(q *Query, ms *es.MultiSearchReques
 	filters.AddQueryStringFilter(q.RawQuery, true)
 
 	if isLogsQuery(q) {
-		processLogsQuery(q, b, from, to, defaultTimeField)
+		cursor, err := e.logsCursor(q)
+		if err != nil {
+			return err
+		}
+		processLogsQuery(q, b, from, to, defaultTimeField, cursor)
 	} else if isDocumentQuery(q) {
 		processDocumentQuery(q, b, from, to, defaultTimeField)
 	} else {
@@ -273,6 +307,48 @@ func addTermsAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg, metrics []*Metr
 	return aggBuilder
 }
 
+// addCompositeAgg adds a composite aggregation of the terms of the bucket aggregation field and of its
+// additional fields. Unlike terms aggregations, all the buckets are returned, paginated by the client.
+func addCompositeAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg) es.AggBuilder {
+	aggBuilder.Composite(bucketAgg.ID, func(a *es.CompositeAggregation, b es.AggBuilder) {
+		missingBucket := bucketAgg.Settings.Get("missingBucket").MustBool()
+		for _, field := range compositeFields(bucketAgg) {
+			a.Sources = append(a.Sources, &es.CompositeSource{Name: field, Field: field, MissingBucket: missingBucket})
+		}
+
+		a.Size = positiveIntSetting(bucketAgg.Settings, "size", defaultCompositeSize)
+		a.MaxBuckets = positiveIntSetting(bucketAgg.Settings, "maxBuckets", defaultCompositeMaxBuckets)
+
+		aggBuilder = b
+	})
+
+	return aggBuilder
+}
+
+// positiveIntSetting returns the value of a setting given as a number or a string, or defaultValue
+// if the setting is missing, invalid, or not positive.
+func positiveIntSetting(settings *simplejson.Json, name string, defaultValue int) int {
+	value, err := settings.Get(name).Int()
+	if err != nil {
+		value = stringToIntWithDefaultValue(settings.Get(name).MustString(), defaultValue)
+	}
+	if value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// compositeFields returns the fields of the sources of a composite aggregation
+func compositeFields(bucketAgg *BucketAgg) []string {
+	fields := []string{bucketAgg.Field}
+	for _, field := range bucketAgg.Settings.Get("additionalFields").MustArray() {
+		if f, ok := field.(string); ok && f != "" && !slices.Contains(fields, f) {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
 func addNestedAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg) es.AggBuilder {
 	aggBuilder.Nested(bucketAgg.ID, bucketAgg.Field, func(a *es.NestedAggregation, b es.AggBuilder) {
 		aggBuilder = b
@@ -332,6 +408,12 @@ func isQueryWithError(query *Query) error {
 			return fmt.Errorf("invalid query, missing metrics and aggregations")
 		}
 	}
+	for i, bucketAgg := range query.BucketAggs {
+		// Elasticsearch doesn't allow composite aggregations as sub-aggregations
+		if bucketAgg.Type == compositeType && i > 0 {
+			return fmt.Errorf("invalid query, composite aggregation must be the first bucket aggregation")
+		}
+	}
 	return nil
 }
 
@@ -351,7 +433,59 @@ func isRawDocumentQuery(query *Query) bool {
 	return query.Metrics[0].Type == rawDocumentType
 }
 
-func processLogsQuery(q *Query, b *es.SearchRequestBuilder, from, to int64, defaultTimeField string) {
+// logsCursor is the position after the last log line of a page. It is returned to the client
+// as an opaque string in the frame metadata, and sent back in the query to get the next page.
+type logsCursor struct {
+	PitID       string `json:"pit"`
+	SearchAfter []any  `json:"searchAfter,omitempty"`
+}
+
+func (c logsCursor) encode() (string, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeLogsCursor(s string) (*logsCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid logs cursor: %w", err)
+	}
+	var c logsCursor
+	dec := json.NewDecoder(bytes.NewReader(b))
+	// Sort values can be longs which don't fit in a float64
+	dec.UseNumber()
+	if err := dec.Decode(&c); err != nil {
+		return nil, fmt.Errorf("invalid logs cursor: %w", err)
+	}
+	if c.PitID == "" {
+		return nil, errors.New("invalid logs cursor: missing point in time")
+	}
+	return &c, nil
+}
+
+// logsCursor returns the position of the requested logs page. The first page of a paginated
+// query opens a point in time, so that the following pages are consistent even while new
+// documents are indexed. It returns nil for queries that are not paginated.
+func (e *elasticsearchDataQuery) logsCursor(q *Query) (*logsCursor, error) {
+	settings := q.Metrics[0].Settings
+	if cursor := settings.Get("cursor").MustString(); cursor != "" {
+		return decodeLogsCursor(cursor)
+	}
+	if !settings.Get("pagination").MustBool() {
+		return nil, nil
+	}
+
+	id, err := e.client.OpenPointInTime(pitKeepAlive)
+	if err != nil {
+		return nil, err
+	}
+	return &logsCursor{PitID: id}, nil
+}
+
+func processLogsQuery(q *Query, b *es.SearchRequestBuilder, from, to int64, defaultTimeField string, cursor *logsCursor) {
 	metric := q.Metrics[0]
 	sort := es.SortOrderDesc
 	if metric.Settings.Get("sortDirection").MustString() == "asc" {
@@ -359,7 +493,12 @@ func processLogsQuery(q *Query, b *es.SearchRequestBuilder, from, to int64, defa
 		sort = es.SortOrderAsc
 	}
 	b.Sort(sort, defaultTimeField, "boolean")
-	b.Sort(sort, "_doc", "")
+	if cursor != nil {
+		// _doc is only unique within a shard, searches against a point in time have _shard_doc as unique tiebreaker
+		b.Sort(sort, "_shard_doc", "")
+	} else {
+		b.Sort(sort, "_doc", "")
+	}
 	b.AddDocValueField(defaultTimeField)
 	// We need to add timeField as field with standardized time format to not receive
 	// invalid formats that elasticsearch can parse, but our frontend can't (e.g. yyyy_MM_dd_HH_mm_ss)
@@ -367,11 +506,18 @@ func processLogsQuery(q *Query, b *es.SearchRequestBuilder, from, to int64, defa
 	b.Size(stringToIntWithDefaultValue(metric.Settings.Get("limit").MustString(), defaultSize))
 	b.AddHighlight()
 
-	// This is currently used only for log context query to get
-	// log lines before and after the selected log line
-	searchAfter := metric.Settings.Get("searchAfter").MustArray()
-	for _, value := range searchAfter {
-		b.AddSearchAfter(value)
+	if cursor != nil {
+		b.PointInTime(cursor.PitID, pitKeepAlive)
+		for _, value := range cursor.SearchAfter {
+			b.AddSearchAfter(value)
+		}
+	} else {
+		// This is currently used only for log context query to get
+		// log lines before and after the selected log line
+		searchAfter := metric.Settings.Get("searchAfter").MustArray()
+		for _, value := range searchAfter {
+			b.AddSearchAfter(value)
+		}
 	}
 
 	// For log query, we add a date histogram aggregation
@@ -421,6 +567,8 @@ func processTimeSeriesQuery(q *Query, b *es.SearchRequestBuilder, from, to int64
 			aggBuilder = addFiltersAgg(aggBuilder, bucketAgg)
 		case termsType:
 			aggBuilder = addTermsAgg(aggBuilder, bucketAgg, q.Metrics)
+		case compositeType:
+			aggBuilder = addCompositeAgg(aggBuilder, bucketAgg)
 		case geohashGridType:
 			aggBuilder = addGeoHashGridAgg(aggBuilder, bucketAgg)
 		case nestedType:
@@ -3,6 +3,7 @@ package elasticsearch
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 
@@ -118,6 +119,72 @@ func TestExecuteElasticsearchDataQuery(t *testing.T) {
 			require.Equal(t, firstLevel.Aggregation.Aggregation.(*es.TermsAggregation).Order["_key"], "asc")
 		})
 
+		t.Run("With composite agg", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"bucketAggs": [
+					{
+						"type": "composite",
+						"field": "@host",
+						"id": "2",
+						"settings": { "size": "50", "additionalFields": ["@port"], "missingBucket": true }
+					}
+				],
+				"metrics": [{"type": "avg", "field": "@value", "id": "1" }]
+			}`, from, to)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+			firstLevel := sr.Aggs[0]
+			require.Equal(t, "composite", firstLevel.Aggregation.Type)
+			composite := firstLevel.Aggregation.Aggregation.(*es.CompositeAggregation)
+			require.Equal(t, []*es.CompositeSource{
+				{Name: "@host", Field: "@host", MissingBucket: true},
+				{Name: "@port", Field: "@port", MissingBucket: true},
+			}, composite.Sources)
+			require.Equal(t, 50, composite.Size)
+			require.Equal(t, defaultCompositeMaxBuckets, composite.MaxBuckets)
+			require.Equal(t, "1", firstLevel.Aggregation.Aggs[0].Key)
+		})
+
+		t.Run("With composite agg and invalid size settings should use the defaults", func(t *testing.T) {
+			testCases := []struct {
+				desc     string
+				settings string
+			}{
+				{desc: "zero", settings: `{ "size": 0, "maxBuckets": 0 }`},
+				{desc: "zero strings", settings: `{ "size": "0", "maxBuckets": "0" }`},
+				{desc: "negative", settings: `{ "size": -10, "maxBuckets": -1 }`},
+				{desc: "negative strings", settings: `{ "size": "-10", "maxBuckets": "-1" }`},
+				{desc: "not numbers", settings: `{ "size": "many", "maxBuckets": "all" }`},
+			}
+			for _, tc := range testCases {
+				t.Run(tc.desc, func(t *testing.T) {
+					c := newFakeClient()
+					_, err := executeElasticsearchDataQuery(c, `{
+						"bucketAggs": [{ "type": "composite", "field": "@host", "id": "2", "settings": `+tc.settings+` }],
+						"metrics": [{"type": "count", "id": "1" }]
+					}`, from, to)
+					require.NoError(t, err)
+					composite := c.multisearchRequests[0].Requests[0].Aggs[0].Aggregation.Aggregation.(*es.CompositeAggregation)
+					require.Equal(t, defaultCompositeSize, composite.Size)
+					require.Equal(t, defaultCompositeMaxBuckets, composite.MaxBuckets)
+				})
+			}
+		})
+
+		t.Run("With composite agg as sub-aggregation should return error", func(t *testing.T) {
+			c := newFakeClient()
+			res, err := executeElasticsearchDataQuery(c, `{
+				"bucketAggs": [
+					{ "type": "terms", "field": "@region", "id": "2" },
+					{ "type": "composite", "field": "@host", "id": "3" }
+				],
+				"metrics": [{"type": "count", "id": "1" }]
+			}`, from, to)
+			require.NoError(t, err)
+			require.ErrorContains(t, res.Responses["A"].Error, "composite aggregation must be the first bucket aggregation")
+		})
+
 		t.Run("With term agg and order by metric agg", func(t *testing.T) {
 			c := newFakeClient()
 			_, err := executeElasticsearchDataQuery(c, `{
@@ -1426,6 +1493,45 @@ func TestExecuteElasticsearchDataQuery(t *testing.T) {
 			require.Equal(t, secondSearchAfter, "2")
 		})
 
+		t.Run("With paginated log query should open a point in time", func(t *testing.T) {
+			c := newFakeClient()
+			c.pitID = "pit-1"
+			_, err := executeElasticsearchDataQuery(c, `{
+				"metrics": [{ "type": "logs", "id": "1", "settings": { "pagination": true }}]
+			}`, from, to)
+			require.NoError(t, err)
+			require.Equal(t, 1, c.openedPits)
+			sr := c.multisearchRequests[0].Requests[0]
+			require.Equal(t, &es.PointInTime{ID: "pit-1", KeepAlive: pitKeepAlive}, sr.PointInTime)
+			require.Equal(t, sr.Sort["_shard_doc"], map[string]string{"order": "desc"})
+			require.Nil(t, sr.Sort["_doc"])
+			require.Nil(t, sr.CustomProps["search_after"])
+		})
+
+		t.Run("With log query with cursor should continue after the cursor", func(t *testing.T) {
+			c := newFakeClient()
+			cursor, err := logsCursor{PitID: "pit-2", SearchAfter: []any{json.Number("1684398201000"), json.Number("9007199254740993")}}.encode()
+			require.NoError(t, err)
+			_, err = executeElasticsearchDataQuery(c, fmt.Sprintf(`{
+				"metrics": [{ "type": "logs", "id": "1", "settings": { "pagination": true, "cursor": %q }}]
+			}`, cursor), from, to)
+			require.NoError(t, err)
+			require.Equal(t, 0, c.openedPits)
+			sr := c.multisearchRequests[0].Requests[0]
+			require.Equal(t, "pit-2", sr.PointInTime.ID)
+			require.Equal(t, []any{json.Number("1684398201000"), json.Number("9007199254740993")}, sr.CustomProps["search_after"])
+		})
+
+		t.Run("With log query with invalid cursor should return error", func(t *testing.T) {
+			c := newFakeClient()
+			res, err := executeElasticsearchDataQuery(c, `{
+				"metrics": [{ "type": "logs", "id": "1", "settings": { "cursor": "not a cursor" }}]
+			}`, from, to)
+			require.NoError(t, err)
+			require.ErrorContains(t, res.Responses["A"].Error, "invalid logs cursor")
+			require.Empty(t, c.multisearchRequests)
+		})
+
 		t.Run("With invalid query should return error", (func(t *testing.T) {
 			c := newFakeClient()
 			res, err := executeElasticsearchDataQuery(c, `{
@@ -1806,6 +1912,10 @@ type fakeClient struct {
 	multiSearchError    error
 	builder             *es.MultiSearchRequestBuilder
 	multisearchRequests []*es.MultiSearchRequest
+	pitID               string
+	openedPits          int
+	columnarResponse    *es.ColumnarResponse
+	rawQueries          []string
 }
 
 func newFakeClient() *fakeClient {
@@ -1831,6 +1941,21 @@ func (c *fakeClient) ExecuteMultisearch(r *es.MultiSearchRequest) (*es.MultiSear
 	return c.multiSearchResponse, c.multiSearchError
 }
 
+func (c *fakeClient) OpenPointInTime(keepAlive string) (string, error) {
+	c.openedPits++
+	return c.pitID, nil
+}
+
+func (c *fakeClient) ExecuteESQL(query string) (*es.ColumnarResponse, error) {
+	c.rawQueries = append(c.rawQueries, query)
+	return c.columnarResponse, nil
+}
+
+func (c *fakeClient) ExecuteSQL(query string, maxRows int) (*es.ColumnarResponse, error) {
+	c.rawQueries = append(c.rawQueries, query)
+	return c.columnarResponse, nil
+}
+
 func (c *fakeClient) MultiSearch() *es.MultiSearchRequestBuilder {
 	c.builder = es.NewMultiSearchRequestBuilder()
 	return c.builder
@@ -162,6 +162,11 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 			xpack = false
 		}
 
+		rawQueriesEnabled, ok := jsonData["rawQueriesEnabled"].(bool)
+		if !ok {
+			rawQueriesEnabled = false
+		}
+
 		configuredFields := es.ConfiguredFields{
 			TimeField:       timeField,
 			LogLevelField:   logLevelField,
@@ -178,6 +183,7 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 			Interval:                   interval,
 			IncludeFrozen:              includeFrozen,
 			XPack:                      xpack,
+			RawQueriesEnabled:          rawQueriesEnabled,
 		}
 		return model, nil
 	}
@@ -1,6 +1,7 @@
 package elasticsearch
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"io"
@@ -10,6 +11,9 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
 )
 
 func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
@@ -100,8 +104,50 @@ func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthReque
 		message = "Elasticsearch data source is not healthy"
 	}
 
-	return &backend.CheckHealthResult{
+	result := &backend.CheckHealthResult{
 		Status:  status,
 		Message: message,
-	}, nil
+	}
+	if status == backend.HealthStatusOk && ds.RawQueriesEnabled {
+		// ES|QL and SQL are not available in every version and license of Elasticsearch
+		result.JSONDetails, err = json.Marshal(map[string]bool{
+			"esql": queryLanguageAvailable(ctx, ds, "_query", "ROW 1", logger),
+			"sql":  queryLanguageAvailable(ctx, ds, "_sql", "SELECT 1", logger),
+		})
+		if err != nil {
+			logger.Warn("Failed to marshal health check details", "error", err)
+		}
+	}
+	return result, nil
+}
+
+// queryLanguageAvailable returns whether a query sent to the endpoint of a query language succeeds
+func queryLanguageAvailable(ctx context.Context, ds *es.DatasourceInfo, uriPath string, query string, logger log.Logger) bool {
+	u, err := url.Parse(ds.URL)
+	if err != nil {
+		return false
+	}
+	u.Path = path.Join(u.Path, uriPath)
+
+	body, err := json.Marshal(map[string]string{"query": query})
+	if err != nil {
+		return false
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := ds.HTTPClient.Do(request)
+	if err != nil {
+		logger.Debug("Failed to check query language", "path", uriPath, "error", err)
+		return false
+	}
+	defer func() {
+		if err := response.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+	return response.StatusCode < http.StatusBadRequest
 }
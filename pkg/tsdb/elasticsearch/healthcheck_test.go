@@ -22,6 +22,19 @@ func Test_Healthcheck_OK(t *testing.T) {
 	})
 	assert.Equal(t, backend.HealthStatusOk, res.Status)
 	assert.Equal(t, "Elasticsearch data source is healthy", res.Message)
+	assert.Nil(t, res.JSONDetails)
+}
+
+func Test_Healthcheck_RawQueriesEnabled(t *testing.T) {
+	service := &Service{
+		im: &FakeInstanceManager{isDsHealthy: true, rawQueriesEnabled: true},
+	}
+	res, _ := service.CheckHealth(context.Background(), &backend.CheckHealthRequest{
+		PluginContext: backend.PluginContext{},
+		Headers:       nil,
+	})
+	assert.Equal(t, backend.HealthStatusOk, res.Status)
+	assert.JSONEq(t, `{"esql": true, "sql": true}`, string(res.JSONDetails))
 }
 
 func Test_Healthcheck_Timeout(t *testing.T) {
@@ -57,7 +70,8 @@ func (fakeRoundTripper *FakeRoundTripper) RoundTrip(req *http.Request) (*http.Re
 }
 
 type FakeInstanceManager struct {
-	isDsHealthy bool
+	isDsHealthy       bool
+	rawQueriesEnabled bool
 }
 
 func (fakeInstanceManager *FakeInstanceManager) Get(tx context.Context, pluginContext backend.PluginContext) (instancemgmt.Instance, error) {
@@ -65,7 +79,8 @@ func (fakeInstanceManager *FakeInstanceManager) Get(tx context.Context, pluginCo
 	httpClient.Transport = &FakeRoundTripper{isDsHealthy: fakeInstanceManager.isDsHealthy}
 
 	return es.DatasourceInfo{
-		HTTPClient: httpClient,
+		HTTPClient:        httpClient,
+		RawQueriesEnabled: fakeInstanceManager.rawQueriesEnabled,
 	}, nil
 }
 
@@ -3,6 +3,7 @@ package elasticsearch
 import (
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 )
 
@@ -16,6 +17,11 @@ type Query struct {
 	IntervalMs    int64
 	RefID         string
 	MaxDataPoints int64
+	// QueryType is set to esql or sql for queries in these languages, which are sent as they are
+	QueryType string
+	// Format is the format of the result of ES|QL and SQL queries, table or time_series
+	Format    string
+	TimeRange backend.TimeRange
 }
 
 // BucketAgg represents a bucket aggregation of the time series query model of the datasource
@@ -42,6 +42,9 @@ func parseQuery(tsdbQuery []backend.DataQuery, logger log.Logger) ([]*Query, err
 			IntervalMs:    intervalMs,
 			RefID:         q.RefID,
 			MaxDataPoints: q.MaxDataPoints,
+			QueryType:     q.QueryType,
+			Format:        model.Get("format").MustString(""),
+			TimeRange:     q.TimeRange,
 		})
 	}
 
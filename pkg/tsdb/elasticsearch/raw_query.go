@@ -0,0 +1,239 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana-plugin-sdk-go/experimental/errorsource"
+
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+)
+
+const (
+	// Query types of raw queries, which are sent as they are instead of being built with the query DSL
+	esqlQueryType = "esql"
+	sqlQueryType  = "sql"
+
+	timeSeriesFormat = "time_series"
+	// maxSQLRows is the maximum number of rows fetched by SQL queries
+	maxSQLRows = 10000
+)
+
+func isRawQuery(query *Query) bool {
+	return query.QueryType == esqlQueryType || query.QueryType == sqlQueryType
+}
+
+// executeRawQuery executes an ES|QL or SQL query and converts its columns to a data frame
+func (e *elasticsearchDataQuery) executeRawQuery(q *Query) backend.DataResponse {
+	query := interpolateRawQuery(q, e.client.GetConfiguredFields().TimeField)
+
+	var res *es.ColumnarResponse
+	var err error
+	if q.QueryType == esqlQueryType {
+		res, err = e.client.ExecuteESQL(query)
+	} else {
+		res, err = e.client.ExecuteSQL(query, maxSQLRows)
+	}
+	if errors.Is(err, es.ErrRawQueriesDisabled) {
+		return errorsource.Response(errorsource.DownstreamError(err, false))
+	}
+	if err != nil {
+		e.logger.Error("Failed to execute raw query", "error", err, "queryType", q.QueryType, "stage", es.StageDatabaseRequest)
+		return errorsource.Response(errorsource.DownstreamError(err, false))
+	}
+
+	frame, err := columnarResponseToFrame(res)
+	if err != nil {
+		return errorsource.Response(errorsource.PluginError(err, false))
+	}
+	frame.Meta = &data.FrameMeta{ExecutedQueryString: query}
+	if res.Truncated {
+		frame.AppendNotices(data.Notice{
+			Severity: data.NoticeSeverityWarning,
+			Text:     fmt.Sprintf("Results were limited to %d rows.", len(res.Values)),
+		})
+	}
+
+	if q.Format == timeSeriesFormat && frame.TimeSeriesSchema().Type == data.TimeSeriesTypeLong {
+		wide, err := data.LongToWide(frame, nil)
+		if err != nil {
+			return errorsource.Response(errorsource.DownstreamError(fmt.Errorf("failed to convert the result to time series: %w", err), false))
+		}
+		frame = wide
+	}
+
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
+
+var rawQueryMacroRegexp = regexp.MustCompile(`\$__(timeFilter|timeFrom|timeTo)\(([^)]*)\)`)
+
+// interpolateRawQuery replaces the time range and interval macros of the query:
+//   - $__timeFilter(field) filters the field, or the time field by default, on the time range
+//   - $__timeFrom() and $__timeTo() are the start and the end of the time range
+//   - $__interval is the interval as a time span literal, and $__interval_ms the interval in milliseconds
+func interpolateRawQuery(q *Query, timeField string) string {
+	from := q.TimeRange.From.UTC().Format(time.RFC3339Nano)
+	to := q.TimeRange.To.UTC().Format(time.RFC3339Nano)
+	datetime := func(value string) string {
+		if q.QueryType == esqlQueryType {
+			return fmt.Sprintf(`TO_DATETIME("%s")`, value)
+		}
+		return fmt.Sprintf(`CAST('%s' AS DATETIME)`, value)
+	}
+
+	query := rawQueryMacroRegexp.ReplaceAllStringFunc(q.RawQuery, func(match string) string {
+		groups := rawQueryMacroRegexp.FindStringSubmatch(match)
+		switch groups[1] {
+		case "timeFilter":
+			field := strings.TrimSpace(groups[2])
+			if field == "" {
+				field = timeField
+				// Elasticsearch SQL needs quotes for field names such as @timestamp
+				if q.QueryType == sqlQueryType {
+					field = strconv.Quote(field)
+				}
+			}
+			return fmt.Sprintf("%s >= %s AND %s <= %s", field, datetime(from), field, datetime(to))
+		case "timeFrom":
+			return datetime(from)
+		default:
+			return datetime(to)
+		}
+	})
+
+	query = strings.ReplaceAll(query, "$__interval_ms", strconv.FormatInt(q.Interval.Milliseconds(), 10))
+	query = strings.ReplaceAll(query, "$__interval", rawQueryInterval(q.QueryType, q.Interval))
+	return query
+}
+
+// rawQueryInterval returns the interval in the largest unit it is a multiple of. Elasticsearch SQL
+// intervals can't be shorter than a second.
+func rawQueryInterval(queryType string, interval time.Duration) string {
+	units := []struct {
+		duration time.Duration
+		name     string
+	}{
+		{24 * time.Hour, "DAYS"},
+		{time.Hour, "HOURS"},
+		{time.Minute, "MINUTES"},
+		{time.Second, "SECONDS"},
+		{time.Millisecond, "MILLISECONDS"},
+	}
+
+	if queryType == sqlQueryType {
+		units = units[:len(units)-1]
+		if interval < time.Second {
+			interval = time.Second
+		}
+	}
+
+	for _, unit := range units {
+		if interval%unit.duration == 0 || unit == units[len(units)-1] {
+			count := int64(interval / unit.duration)
+			if count < 1 {
+				count = 1
+			}
+			if queryType == sqlQueryType {
+				return fmt.Sprintf("INTERVAL %d %s", count, unit.name)
+			}
+			return fmt.Sprintf("%d %s", count, strings.ToLower(unit.name))
+		}
+	}
+	return ""
+}
+
+// columnarResponseToFrame converts the columns of an ES|QL or SQL response to fields of the matching type
+func columnarResponseToFrame(res *es.ColumnarResponse) (*data.Frame, error) {
+	frame := data.NewFrame("")
+	for i, column := range res.Columns {
+		field, err := columnToField(column, res.Values, i)
+		if err != nil {
+			return nil, err
+		}
+		frame.Fields = append(frame.Fields, field)
+	}
+	return frame, nil
+}
+
+func columnToField(column es.ColumnarColumn, rows [][]interface{}, idx int) (*data.Field, error) {
+	value := func(row []interface{}) interface{} {
+		if idx < len(row) {
+			return row[idx]
+		}
+		return nil
+	}
+
+	switch column.Type {
+	case "date", "datetime", "date_nanos":
+		values := make([]*time.Time, len(rows))
+		for i, row := range rows {
+			v, ok := value(row).(string)
+			if !ok {
+				continue
+			}
+			t, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse value %q of column %s: %w", v, column.Name, err)
+			}
+			values[i] = &t
+		}
+		return data.NewField(column.Name, nil, values), nil
+	case "byte", "short", "integer", "long", "unsigned_long", "counter_integer", "counter_long":
+		values := make([]*int64, len(rows))
+		for i, row := range rows {
+			if n, ok := value(row).(json.Number); ok {
+				if v, err := n.Int64(); err == nil {
+					values[i] = &v
+				} else if f, err := n.Float64(); err == nil {
+					// unsigned longs can overflow int64
+					v := int64(f)
+					values[i] = &v
+				}
+			}
+		}
+		return data.NewField(column.Name, nil, values), nil
+	case "double", "float", "half_float", "scaled_float", "counter_double":
+		values := make([]*float64, len(rows))
+		for i, row := range rows {
+			if n, ok := value(row).(json.Number); ok {
+				if v, err := n.Float64(); err == nil {
+					values[i] = &v
+				}
+			}
+		}
+		return data.NewField(column.Name, nil, values), nil
+	case "boolean":
+		values := make([]*bool, len(rows))
+		for i, row := range rows {
+			if v, ok := value(row).(bool); ok {
+				values[i] = &v
+			}
+		}
+		return data.NewField(column.Name, nil, values), nil
+	default:
+		values := make([]*string, len(rows))
+		for i, row := range rows {
+			switch v := value(row).(type) {
+			case nil:
+			case string:
+				values[i] = &v
+			default:
+				// Objects, arrays and values of other types are returned as JSON
+				b, err := json.Marshal(v)
+				if err != nil {
+					return nil, err
+				}
+				s := string(b)
+				values[i] = &s
+			}
+		}
+		return data.NewField(column.Name, nil, values), nil
+	}
+}
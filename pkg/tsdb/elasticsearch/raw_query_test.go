@@ -0,0 +1,163 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+)
+
+func TestInterpolateRawQuery(t *testing.T) {
+	timeRange := backend.TimeRange{
+		From: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name      string
+		queryType string
+		query     string
+		interval  time.Duration
+		expected  string
+	}{
+		{
+			name:      "ES|QL time filter on the time field",
+			queryType: esqlQueryType,
+			query:     "FROM logs | WHERE $__timeFilter()",
+			expected:  `FROM logs | WHERE @timestamp >= TO_DATETIME("2024-03-01T10:00:00Z") AND @timestamp <= TO_DATETIME("2024-03-01T11:00:00Z")`,
+		},
+		{
+			name:      "ES|QL time filter on another field",
+			queryType: esqlQueryType,
+			query:     "FROM logs | WHERE $__timeFilter(event.created)",
+			expected:  `FROM logs | WHERE event.created >= TO_DATETIME("2024-03-01T10:00:00Z") AND event.created <= TO_DATETIME("2024-03-01T11:00:00Z")`,
+		},
+		{
+			name:      "ES|QL interval",
+			queryType: esqlQueryType,
+			query:     "STATS c = COUNT(*) BY b = BUCKET(@timestamp, $__interval) | EVAL ms = $__interval_ms",
+			interval:  90 * time.Second,
+			expected:  "STATS c = COUNT(*) BY b = BUCKET(@timestamp, 90 seconds) | EVAL ms = 90000",
+		},
+		{
+			name:      "SQL time filter on the time field",
+			queryType: sqlQueryType,
+			query:     "SELECT * FROM logs WHERE $__timeFilter() AND ts < $__timeTo()",
+			expected:  `SELECT * FROM logs WHERE "@timestamp" >= CAST('2024-03-01T10:00:00Z' AS DATETIME) AND "@timestamp" <= CAST('2024-03-01T11:00:00Z' AS DATETIME) AND ts < CAST('2024-03-01T11:00:00Z' AS DATETIME)`,
+		},
+		{
+			name:      "SQL interval",
+			queryType: sqlQueryType,
+			query:     "SELECT HISTOGRAM(ts, $__interval) FROM logs",
+			interval:  2 * time.Hour,
+			expected:  "SELECT HISTOGRAM(ts, INTERVAL 2 HOURS) FROM logs",
+		},
+		{
+			name:      "SQL intervals are at least one second",
+			queryType: sqlQueryType,
+			query:     "SELECT HISTOGRAM(ts, $__interval) FROM logs",
+			interval:  200 * time.Millisecond,
+			expected:  "SELECT HISTOGRAM(ts, INTERVAL 1 SECONDS) FROM logs",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q := &Query{QueryType: test.queryType, RawQuery: test.query, Interval: test.interval, TimeRange: timeRange}
+			assert.Equal(t, test.expected, interpolateRawQuery(q, "@timestamp"))
+		})
+	}
+}
+
+func TestExecuteRawQuery(t *testing.T) {
+	execute := func(t *testing.T, c *fakeClient, queryType string, body string) *backend.QueryDataResponse {
+		t.Helper()
+		req := backend.QueryDataRequest{
+			Queries: []backend.DataQuery{{
+				RefID:     "A",
+				QueryType: queryType,
+				JSON:      json.RawMessage(body),
+				Interval:  time.Minute,
+				TimeRange: backend.TimeRange{
+					From: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+					To:   time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC),
+				},
+			}},
+		}
+		res, err := newElasticsearchDataQuery(context.Background(), c, &req, log.New("test.logger"), tracing.InitializeTracerForTest()).execute()
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("converts columns to typed fields", func(t *testing.T) {
+		c := newFakeClient()
+		c.columnarResponse = &es.ColumnarResponse{
+			Columns: []es.ColumnarColumn{
+				{Name: "time", Type: "date"},
+				{Name: "count", Type: "long"},
+				{Name: "avg", Type: "double"},
+				{Name: "ok", Type: "boolean"},
+				{Name: "host", Type: "keyword"},
+				{Name: "tags", Type: "unsupported"},
+			},
+			Values: [][]interface{}{
+				{"2024-03-01T10:00:00.000Z", json.Number("3"), json.Number("1.5"), true, "a", []interface{}{"x", "y"}},
+				{nil, nil, nil, nil, nil, nil},
+			},
+		}
+
+		res := execute(t, c, esqlQueryType, `{"query": "FROM logs | WHERE $__timeFilter()"}`)
+		require.Len(t, c.multisearchRequests, 0)
+		require.Len(t, c.rawQueries, 1)
+		require.NoError(t, res.Responses["A"].Error)
+
+		frame := res.Responses["A"].Frames[0]
+		assert.Equal(t, c.rawQueries[0], frame.Meta.ExecutedQueryString)
+		require.Len(t, frame.Fields, 6)
+		assert.Equal(t, data.FieldTypeNullableTime, frame.Fields[0].Type())
+		assert.Equal(t, time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC), *frame.Fields[0].At(0).(*time.Time))
+		assert.Equal(t, int64(3), *frame.Fields[1].At(0).(*int64))
+		assert.Equal(t, 1.5, *frame.Fields[2].At(0).(*float64))
+		assert.True(t, *frame.Fields[3].At(0).(*bool))
+		assert.Equal(t, "a", *frame.Fields[4].At(0).(*string))
+		assert.Equal(t, `["x","y"]`, *frame.Fields[5].At(0).(*string))
+		for _, field := range frame.Fields {
+			_, ok := field.ConcreteAt(1)
+			assert.False(t, ok)
+		}
+	})
+
+	t.Run("converts long results to time series", func(t *testing.T) {
+		c := newFakeClient()
+		c.columnarResponse = &es.ColumnarResponse{
+			Columns: []es.ColumnarColumn{
+				{Name: "time", Type: "datetime"},
+				{Name: "host", Type: "keyword"},
+				{Name: "count", Type: "long"},
+			},
+			Values: [][]interface{}{
+				{"2024-03-01T10:00:00Z", "a", json.Number("1")},
+				{"2024-03-01T10:00:00Z", "b", json.Number("2")},
+			},
+			Truncated: true,
+		}
+
+		res := execute(t, c, sqlQueryType, `{"query": "SELECT time, host, count FROM logs", "format": "time_series"}`)
+		require.NoError(t, res.Responses["A"].Error)
+
+		frame := res.Responses["A"].Frames[0]
+		assert.Equal(t, data.TimeSeriesTypeWide, frame.TimeSeriesSchema().Type)
+		assert.Len(t, frame.Fields, 3)
+		require.Len(t, frame.Meta.Notices, 1)
+		assert.Equal(t, "Results were limited to 2 rows.", frame.Meta.Notices[0].Text)
+	})
+}
@@ -37,6 +37,7 @@ const (
 	histogramType   = "histogram"
 	filtersType     = "filters"
 	termsType       = "terms"
+	compositeType   = "composite"
 	geohashGridType = "geohash_grid"
 	//  Document types
 	rawDocumentType = "raw_document"
@@ -208,7 +209,11 @@ func processLogsResponse(res *es.SearchResponse, target *Query, configuredFields
 	frames := data.Frames{}
 	frame := data.NewFrame("", fields...)
 	setPreferredVisType(frame, data.VisTypeLogs)
-	setLogsCustomMeta(frame, searchWords, stringToIntWithDefaultValue(target.Metrics[0].Settings.Get("limit").MustString(), defaultSize))
+	limit := stringToIntWithDefaultValue(target.Metrics[0].Settings.Get("limit").MustString(), defaultSize)
+	setLogsCustomMeta(frame, searchWords, limit)
+	if err := setLogsCursor(frame, res, limit); err != nil {
+		return err
+	}
 	frames = append(frames, frame)
 	queryRes.Frames = frames
 
@@ -424,6 +429,8 @@ func processBuckets(aggs map[string]interface{}, target *Query,
 		if depth == maxDepth {
 			if aggDef.Type == dateHistType {
 				err = processMetrics(esAgg, target, queryResult, props)
+			} else if aggDef.Type == compositeType {
+				err = processCompositeAggregationDocs(esAgg, aggDef, target, queryResult)
 			} else {
 				err = processAggregationDocs(esAgg, aggDef, target, queryResult, props)
 			}
@@ -439,7 +446,13 @@ func processBuckets(aggs map[string]interface{}, target *Query,
 					newProps[k] = v
 				}
 
-				if key, err := bucket.Get("key").String(); err == nil {
+				if aggDef.Type == compositeType {
+					for name, value := range bucket.Get("key").MustMap() {
+						if value != nil {
+							newProps[name] = compositeKeyString(value)
+						}
+					}
+				} else if key, err := bucket.Get("key").String(); err == nil {
 					newProps[aggDef.Field] = key
 				} else if key, err := bucket.Get("key").Int64(); err == nil {
 					newProps[aggDef.Field] = strconv.FormatInt(key, 10)
@@ -818,6 +831,97 @@ func processAggregationDocs(esAgg *simplejson.Json, aggDef *BucketAgg, target *Q
 	return nil
 }
 
+// processCompositeAggregationDocs builds a table with a column per source of the composite aggregation,
+// followed by the metrics.
+func processCompositeAggregationDocs(esAgg *simplejson.Json, aggDef *BucketAgg, target *Query, queryResult *backend.DataResponse) error {
+	buckets := esAgg.Get("buckets").MustArray()
+
+	var fields []*data.Field
+	for _, name := range compositeFields(aggDef) {
+		field := compositeKeyField(name, buckets)
+		isFilterable := true
+		field.Config = &data.FieldConfig{Filterable: &isFilterable}
+		fields = append(fields, field)
+	}
+
+	for _, v := range buckets {
+		bucket := simplejson.NewFromAny(v)
+		var values []interface{}
+		for _, metric := range target.Metrics {
+			switch metric.Type {
+			case countType:
+				addMetricValueToFields(&fields, values, getMetricName(metric.Type), castToFloat(bucket.Get("doc_count")))
+			case extendedStatsType:
+				addExtendedStatsToFields(&fields, bucket, metric, values)
+			case percentilesType:
+				addPercentilesToFields(&fields, bucket, metric, values)
+			case topMetricsType:
+				addTopMetricsToFields(&fields, bucket, metric, values)
+			default:
+				addOtherMetricsToFields(&fields, bucket, metric, values, target)
+			}
+		}
+	}
+
+	frame := data.NewFrame("", fields...)
+	// The client keeps the after key when it stopped before the last page
+	if _, ok := esAgg.CheckGet("after_key"); ok {
+		frame.AppendNotices(data.Notice{
+			Severity: data.NoticeSeverityWarning,
+			Text:     fmt.Sprintf("Results were limited to %d buckets of the composite aggregation. Narrow the query or increase the maximum number of buckets.", len(buckets)),
+		})
+	}
+	queryResult.Frames = data.Frames{frame}
+	return nil
+}
+
+// compositeKeyField returns the field of the values of a source of the composite aggregation. Sources
+// of numeric fields have number keys, and any other has string keys.
+func compositeKeyField(name string, buckets []interface{}) *data.Field {
+	numeric := true
+	for _, b := range buckets {
+		switch simplejson.NewFromAny(b).GetPath("key", name).Interface().(type) {
+		case nil, float64, json.Number:
+		default:
+			numeric = false
+		}
+	}
+
+	if numeric {
+		values := make([]*float64, 0, len(buckets))
+		for _, b := range buckets {
+			var value *float64
+			if f, err := simplejson.NewFromAny(b).GetPath("key", name).Float64(); err == nil {
+				value = &f
+			}
+			values = append(values, value)
+		}
+		return data.NewField(name, nil, values)
+	}
+
+	values := make([]*string, 0, len(buckets))
+	for _, b := range buckets {
+		var value *string
+		if key := simplejson.NewFromAny(b).GetPath("key", name).Interface(); key != nil {
+			s := compositeKeyString(key)
+			value = &s
+		}
+		values = append(values, value)
+	}
+	return data.NewField(name, nil, values)
+}
+
+func compositeKeyString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
 func extractDataField(name string, v interface{}) *data.Field {
 	var field *data.Field
 	switch v.(type) {
@@ -1217,6 +1321,26 @@ func setLogsCustomMeta(frame *data.Frame, searchWords map[string]bool, limit int
 	}
 }
 
+// setLogsCursor adds the cursor of the next page to the metadata of paginated logs queries,
+// unless the page is the last one.
+func setLogsCursor(frame *data.Frame, res *es.SearchResponse, limit int) error {
+	hits := res.Hits.Hits
+	if res.PitID == "" || len(hits) == 0 || len(hits) < limit {
+		return nil
+	}
+	searchAfter, ok := hits[len(hits)-1]["sort"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	cursor, err := logsCursor{PitID: res.PitID, SearchAfter: searchAfter}.encode()
+	if err != nil {
+		return err
+	}
+	frame.Meta.Custom.(map[string]interface{})["cursor"] = cursor
+	return nil
+}
+
 func createFields(frames data.Frames, propKeys []string) []*data.Field {
 	var fields []*data.Field
 	// Otherwise use the fields from frames
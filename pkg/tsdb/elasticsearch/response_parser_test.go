@@ -434,6 +434,67 @@ func TestProcessLogsResponse(t *testing.T) {
 			"limit":       500,
 		}, customMeta)
 	})
+
+	t.Run("Paginated log query returns the cursor of the next page", func(t *testing.T) {
+		targets := map[string]string{
+			"A": `{
+					"metrics": [{ "type": "logs", "settings": { "limit": "2", "pagination": true } }]
+				}`,
+		}
+
+		response := `{
+			"responses": [
+				{
+					"pit_id": "pit-2",
+					"hits": {
+						"hits": [
+							{ "_id": "1", "_source": { "@timestamp": "2023-02-08T15:10:56.000Z", "line": "b" }, "sort": [1675869056000, 8] },
+							{ "_id": "2", "_source": { "@timestamp": "2023-02-08T15:10:55.000Z", "line": "a" }, "sort": [1675869055000, 4] }
+						]
+					},
+					"status": 200
+				}
+			]
+		}`
+
+		result, err := parseTestResponse(targets, response, false)
+		require.NoError(t, err)
+		frame := result.Responses["A"].Frames[0]
+		encoded, ok := frame.Meta.Custom.(map[string]any)["cursor"].(string)
+		require.True(t, ok)
+
+		cursor, err := decodeLogsCursor(encoded)
+		require.NoError(t, err)
+		require.Equal(t, "pit-2", cursor.PitID)
+		require.Equal(t, []any{json.Number("1675869055000"), json.Number("4")}, cursor.SearchAfter)
+	})
+
+	t.Run("Paginated log query doesn't return a cursor for the last page", func(t *testing.T) {
+		targets := map[string]string{
+			"A": `{
+					"metrics": [{ "type": "logs", "settings": { "limit": "2", "pagination": true } }]
+				}`,
+		}
+
+		response := `{
+			"responses": [
+				{
+					"pit_id": "pit-2",
+					"hits": {
+						"hits": [
+							{ "_id": "1", "_source": { "@timestamp": "2023-02-08T15:10:56.000Z", "line": "b" }, "sort": [1675869056000, 8] }
+						]
+					},
+					"status": 200
+				}
+			]
+		}`
+
+		result, err := parseTestResponse(targets, response, false)
+		require.NoError(t, err)
+		frame := result.Responses["A"].Frames[0]
+		require.NotContains(t, frame.Meta.Custom, "cursor")
+	})
 }
 
 func TestProcessRawDataResponse(t *testing.T) {
@@ -1326,6 +1387,135 @@ func TestProcessBuckets(t *testing.T) {
 			requireFloatAt(t, 369.0, f3, 0)
 			requireFloatAt(t, 200.0, f3, 1)
 		})
+
+		t.Run("Composite agg without date histogram", func(t *testing.T) {
+			query := []byte(`
+	[
+		{
+		  "refId": "A",
+		  "metrics": [
+			{ "type": "avg", "id": "1", "field": "@value" },
+			{ "type": "count", "id": "3" }
+		  ],
+		  "bucketAggs": [{ "id": "2", "type": "composite", "field": "host", "settings": { "additionalFields": ["port"], "missingBucket": true } }]
+		}
+	]
+	`)
+
+			response := []byte(`
+	{
+		"responses": [
+		  {
+			"aggregations": {
+			  "2": {
+				"buckets": [
+				  { "1": { "value": 1000 }, "key": { "host": "server-1", "port": 80 }, "doc_count": 369 },
+				  { "1": { "value": 2000 }, "key": { "host": null, "port": 443 }, "doc_count": 200 }
+				]
+			  }
+			}
+		  }
+		]
+	}
+	`)
+
+			result, err := queryDataTest(query, response)
+			require.NoError(t, err)
+
+			frames := result.response.Responses["A"].Frames
+			require.Len(t, frames, 1)
+			frame := frames[0]
+			requireFrameLength(t, frame, 2)
+			require.Len(t, frame.Fields, 4)
+			require.Empty(t, frame.Meta)
+
+			require.Equal(t, "host", frame.Fields[0].Name)
+			requireStringAt(t, "server-1", frame.Fields[0], 0)
+			require.Nil(t, frame.Fields[0].At(1))
+			require.Equal(t, "port", frame.Fields[1].Name)
+			requireFloatAt(t, 80, frame.Fields[1], 0)
+			requireFloatAt(t, 443, frame.Fields[1], 1)
+			requireFloatAt(t, 1000.0, frame.Fields[2], 0)
+			requireFloatAt(t, 2000.0, frame.Fields[2], 1)
+			requireFloatAt(t, 369.0, frame.Fields[3], 0)
+			requireFloatAt(t, 200.0, frame.Fields[3], 1)
+		})
+
+		t.Run("Composite agg with more buckets than the maximum", func(t *testing.T) {
+			query := []byte(`
+	[
+		{
+		  "refId": "A",
+		  "metrics": [{ "type": "count", "id": "1" }],
+		  "bucketAggs": [{ "id": "2", "type": "composite", "field": "host", "settings": { "size": 1, "maxBuckets": 1 } }]
+		}
+	]
+	`)
+
+			response := []byte(`
+	{
+		"responses": [
+		  {
+			"aggregations": {
+			  "2": {
+				"after_key": { "host": "server-1" },
+				"buckets": [{ "key": { "host": "server-1" }, "doc_count": 369 }]
+			  }
+			}
+		  }
+		]
+	}
+	`)
+
+			result, err := queryDataTest(query, response)
+			require.NoError(t, err)
+
+			frame := result.response.Responses["A"].Frames[0]
+			require.Len(t, frame.Meta.Notices, 1)
+			require.Equal(t, data.NoticeSeverityWarning, frame.Meta.Notices[0].Severity)
+		})
+
+		t.Run("Composite agg with date histogram", func(t *testing.T) {
+			query := []byte(`
+	[
+		{
+		  "refId": "A",
+		  "metrics": [{ "type": "count", "id": "1" }],
+		  "bucketAggs": [
+			{ "id": "2", "type": "composite", "field": "host", "settings": { "additionalFields": ["port"] } },
+			{ "id": "3", "type": "date_histogram", "field": "@timestamp" }
+		  ]
+		}
+	]
+	`)
+
+			response := []byte(`
+	{
+		"responses": [
+		  {
+			"aggregations": {
+			  "2": {
+				"buckets": [
+				  {
+					"key": { "host": "server-1", "port": 80 },
+					"3": { "buckets": [{ "doc_count": 1, "key": 1000 }, { "doc_count": 3, "key": 2000 }] }
+				  }
+				]
+			  }
+			}
+		  }
+		]
+	}
+	`)
+
+			result, err := queryDataTest(query, response)
+			require.NoError(t, err)
+
+			frames := result.response.Responses["A"].Frames
+			require.Len(t, frames, 1)
+			require.Equal(t, "server-1 80", frames[0].Name)
+			requireFloatAt(t, 3, frames[0].Fields[1], 1)
+		})
 	})
 
 	t.Run("Top metrics", func(t *testing.T) {
@@ -83,6 +83,7 @@ func newPostgres(ctx context.Context, cfg *setting.Cfg, dsInfo sqleng.DataSource
 		DSInfo:            dsInfo,
 		MetricColumnTypes: []string{"UNKNOWN", "TEXT", "VARCHAR", "CHAR"},
 		RowLimit:          cfg.DataProxyRowLimit,
+		Guardrails:        postgresQueryGuardrails{},
 	}
 
 	queryResultTransformer := postgresQueryResultTransformer{}
@@ -238,6 +239,26 @@ func (t *postgresQueryResultTransformer) TransformQueryError(_ log.Logger, err e
 	return err
 }
 
+// postgresQueryGuardrails scopes the guardrails to the query transaction with SET LOCAL.
+// Postgres has no per-query memory cap, so the memory limit is applied to work_mem,
+// the memory each sort and hash operation may use before spilling to disk.
+type postgresQueryGuardrails struct{}
+
+func (postgresQueryGuardrails) ReadOnlyTransactions() bool {
+	return true
+}
+
+func (postgresQueryGuardrails) SessionStatements(timeout time.Duration, memoryLimitMB int) []string {
+	var stmts []string
+	if timeout > 0 {
+		stmts = append(stmts, fmt.Sprintf("SET LOCAL statement_timeout = %d", timeout.Milliseconds()))
+	}
+	if memoryLimitMB > 0 {
+		stmts = append(stmts, fmt.Sprintf("SET LOCAL work_mem = '%dMB'", memoryLimitMB))
+	}
+	return stmts
+}
+
 // CheckHealth pings the connected SQL database
 func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
 	dsHandler, err := s.getDSInfo(ctx, req.PluginContext)
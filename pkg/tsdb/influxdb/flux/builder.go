@@ -59,7 +59,9 @@ type frameBuilder struct {
 	labels              []string
 	maxPoints           int // max points in a series
 	maxSeries           int // max number of series
+	maxTotalPoints      int // max points in all the series of the response
 	totalSeries         int
+	totalPoints         int
 	hasUsualStartStop   bool // has _start and _stop timestamp-labels
 }
 
@@ -254,6 +256,22 @@ func (e maxPointsExceededError) Error() string {
 	return fmt.Sprintf("max data points limit exceeded (count is %d)", e.Count)
 }
 
+type maxSeriesExceededError struct {
+	Limit int
+}
+
+func (e maxSeriesExceededError) Error() string {
+	return fmt.Sprintf("results are truncated, max series reached (%d)", e.Limit)
+}
+
+type maxTotalPointsExceededError struct {
+	Limit int
+}
+
+func (e maxTotalPointsExceededError) Error() string {
+	return fmt.Sprintf("results are truncated, max points reached (%d)", e.Limit)
+}
+
 func getColumnInfo(col *query.FluxColumn) (info *columnInfo, isTimestamp bool, err error) {
 	dataType := col.DataType()
 	isTimestamp = isTimestampType(dataType)
@@ -316,7 +334,7 @@ func (fb *frameBuilder) Append(record *query.FluxRecord) error {
 	if (fb.currentGroupKey == nil) || !isTableIDEqual(table, fb.currentGroupKey) {
 		fb.totalSeries++
 		if fb.totalSeries > fb.maxSeries {
-			return fmt.Errorf("results are truncated, max series reached (%d)", fb.maxSeries)
+			return maxSeriesExceededError{Limit: fb.maxSeries}
 		}
 
 		// labels have the same value for every row in the same "table",
@@ -405,5 +423,12 @@ func (fb *frameBuilder) Append(record *query.FluxRecord) error {
 		return maxPointsExceededError{Count: pointsCount}
 	}
 
+	// the budget of the whole response is enforced while the rows are read,
+	// so that a response with many tables can not exhaust the memory
+	fb.totalPoints++
+	if fb.maxTotalPoints > 0 && fb.totalPoints > fb.maxTotalPoints {
+		return maxTotalPointsExceededError{Limit: fb.maxTotalPoints}
+	}
+
 	return nil
 }
@@ -16,9 +16,12 @@ import (
 const maxPointsEnforceFactor float64 = 10
 
 // executeQuery runs a flux query using the queryModel to interpolate the query and the runner to execute it.
-// maxSeries somehow limits the response.
-func executeQuery(ctx context.Context, logger log.Logger, query queryModel, runner queryRunner, maxSeries int) (dr backend.DataResponse) {
+// maxSeries and maxTotalPoints limit the number of series and points of the response. The response is
+// converted to frames while it is read, and reading stops as soon as a limit is exceeded.
+func executeQuery(ctx context.Context, logger log.Logger, query queryModel, runner queryRunner, maxSeries int, maxTotalPoints int) (dr backend.DataResponse) {
 	dr = backend.DataResponse{}
+	// truncated is set when the frames read until a series or total points limit was exceeded are returned
+	truncated := false
 
 	flux := interpolate(query)
 
@@ -32,7 +35,7 @@ func executeQuery(ctx context.Context, logger log.Logger, query queryModel, runn
 		// we only enforce a larger number than maxDataPoints
 		maxPointsEnforced := int(float64(query.MaxDataPoints) * maxPointsEnforceFactor)
 
-		dr = readDataFrames(logger, tables, maxPointsEnforced, maxSeries)
+		dr = readDataFrames(ctx, logger, tables, maxPointsEnforced, maxSeries, maxTotalPoints)
 
 		if dr.Error != nil {
 			// we check if a too-many-data-points error happened, and if it is so,
@@ -40,7 +43,10 @@ func executeQuery(ctx context.Context, logger log.Logger, query queryModel, runn
 			// (we have to do it in such a complicated way, because at the point where
 			// the error happens, there is not enough info to create a nice error message)
 			var maxPointError maxPointsExceededError
-			if errors.As(dr.Error, &maxPointError) {
+			var maxSeriesError maxSeriesExceededError
+			var maxTotalPointsError maxTotalPointsExceededError
+			switch {
+			case errors.As(dr.Error, &maxPointError):
 				text := fmt.Sprintf("A query returned too many datapoints and the results have been truncated at %d points to prevent memory issues. At the current graph size, Grafana can only draw %d.", maxPointError.Count, query.MaxDataPoints)
 				// we recommend to the user to use AggregateWindow(), but only if it is not already used
 				if !strings.Contains(query.RawQuery, "aggregateWindow(") {
@@ -48,6 +54,8 @@ func executeQuery(ctx context.Context, logger log.Logger, query queryModel, runn
 				}
 
 				dr.Error = fmt.Errorf(text)
+			case errors.As(dr.Error, &maxSeriesError), errors.As(dr.Error, &maxTotalPointsError):
+				truncated = true
 			}
 		}
 	}
@@ -61,19 +69,39 @@ func executeQuery(ctx context.Context, logger log.Logger, query queryModel, runn
 		firstFrame.SetMeta(&data.FrameMeta{})
 	}
 	firstFrame.Meta.ExecutedQueryString = flux
+	if truncated {
+		// the notice explains why the returned frames are incomplete
+		firstFrame.AppendNotices(data.Notice{
+			Severity: data.NoticeSeverityWarning,
+			Text:     dr.Error.Error(),
+		})
+	}
 	return dr
 }
 
-func readDataFrames(logger log.Logger, result *api.QueryTableResult, maxPoints int, maxSeries int) (dr backend.DataResponse) {
-	logger.Debug("Reading data frames from query result", "maxPoints", maxPoints, "maxSeries", maxSeries)
+// readDataFrames converts the tables of the result to frames while they are read. The result is closed
+// when it returns, which cancels the upstream request if the result was not read to the end.
+func readDataFrames(ctx context.Context, logger log.Logger, result *api.QueryTableResult, maxPoints int, maxSeries int, maxTotalPoints int) (dr backend.DataResponse) {
+	logger.Debug("Reading data frames from query result", "maxPoints", maxPoints, "maxSeries", maxSeries, "maxTotalPoints", maxTotalPoints)
 	dr = backend.DataResponse{}
+	defer func() {
+		if err := result.Close(); err != nil {
+			logger.Warn("Failed to close query result", "err", err)
+		}
+	}()
 
 	builder := &frameBuilder{
-		maxPoints: maxPoints,
-		maxSeries: maxSeries,
+		maxPoints:      maxPoints,
+		maxSeries:      maxSeries,
+		maxTotalPoints: maxTotalPoints,
 	}
 
 	for result.Next() {
+		if err := ctx.Err(); err != nil {
+			dr.Error = err
+			return dr
+		}
+
 		// Observe when there is new grouping key producing new table
 		if result.TableChanged() {
 			if builder.frames != nil {
@@ -62,7 +62,7 @@ func executeMockedQuery(t *testing.T, name string, query queryModel) *backend.Da
 		testDataPath: name + ".csv",
 	}
 
-	dr := executeQuery(context.Background(), glog, query, runner, 50)
+	dr := executeQuery(context.Background(), glog, query, runner, 50, 0)
 	return &dr
 }
 
@@ -230,7 +230,7 @@ func TestRealQuery(t *testing.T) {
 		dr := executeQuery(context.Background(), glog, queryModel{
 			MaxDataPoints: 100,
 			RawQuery:      "buckets()",
-		}, runner, 50)
+		}, runner, 50, 0)
 		experimental.CheckGoldenJSONResponse(t, "testdata", "buckets-real.golden", &dr, true)
 	})
 }
@@ -251,6 +251,8 @@ func TestMaxDataPointsExceededNoAggregate(t *testing.T) {
 
 	// it should contain the error-message
 	require.EqualError(t, dr.Error, "A query returned too many datapoints and the results have been truncated at 21 points to prevent memory issues. At the current graph size, Grafana can only draw 2. Try using the aggregateWindow() function in your query to reduce the number of points returned.")
+	require.Equal(t, backend.ErrorSource(""), dr.ErrorSource)
+	require.Empty(t, dr.Frames[0].Meta.Notices)
 	assertDataResponseDimensions(t, dr, 2, 21)
 }
 
@@ -316,3 +318,39 @@ func TestWithoutTimeColumn(t *testing.T) {
 	require.Equal(t, "cpu", dr.Frames[0].Fields[0].Name)
 	require.Equal(t, "host", dr.Frames[0].Fields[1].Name)
 }
+
+func TestMaxTotalPointsExceeded(t *testing.T) {
+	runner := &MockRunner{testDataPath: "multiple.csv"}
+	dr := executeQuery(context.Background(), glog, queryModel{MaxDataPoints: 100}, runner, 50, 3)
+
+	require.EqualError(t, dr.Error, "results are truncated, max points reached (3)")
+	// the limit is enforced by Grafana, so it is not a downstream error
+	require.Equal(t, backend.ErrorSource(""), dr.ErrorSource)
+	// the frames read until the limit was exceeded are returned
+	require.Len(t, dr.Frames, 2)
+	require.Equal(t, 2, dr.Frames[0].Fields[0].Len())
+	require.Equal(t, 2, dr.Frames[1].Fields[0].Len())
+	require.Len(t, dr.Frames[0].Meta.Notices, 1)
+	require.Equal(t, "results are truncated, max points reached (3)", dr.Frames[0].Meta.Notices[0].Text)
+}
+
+func TestMaxSeriesExceeded(t *testing.T) {
+	runner := &MockRunner{testDataPath: "multiple.csv"}
+	dr := executeQuery(context.Background(), glog, queryModel{MaxDataPoints: 100}, runner, 2, 0)
+
+	require.EqualError(t, dr.Error, "results are truncated, max series reached (2)")
+	require.Equal(t, backend.ErrorSource(""), dr.ErrorSource)
+	require.Len(t, dr.Frames, 2)
+}
+
+func TestReadDataFramesCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	runner := &MockRunner{testDataPath: "multiple.csv"}
+	result, err := runner.runQuery(ctx, "")
+	require.NoError(t, err)
+
+	cancel()
+	dr := readDataFrames(ctx, glog, result, 100, 50, 0)
+	require.ErrorContains(t, dr.Error, context.Canceled.Error())
+	require.Equal(t, backend.ErrorSource(""), dr.ErrorSource)
+}
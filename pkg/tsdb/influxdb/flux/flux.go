@@ -29,6 +29,12 @@ func Query(ctx context.Context, dsInfo *models.DatasourceInfo, tsdbQuery backend
 
 	timeRange := tsdbQuery.Queries[0].TimeRange
 	for _, query := range tsdbQuery.Queries {
+		// the remaining queries are not executed once the request is canceled
+		if err := ctx.Err(); err != nil {
+			tRes.Responses[query.RefID] = backend.DataResponse{Error: err}
+			continue
+		}
+
 		qm, err := getQueryModel(query, timeRange, dsInfo)
 		if err != nil {
 			tRes.Responses[query.RefID] = backend.DataResponse{Error: err}
@@ -37,7 +43,7 @@ func Query(ctx context.Context, dsInfo *models.DatasourceInfo, tsdbQuery backend
 
 		// If the default changes also update labels/placeholder in config page.
 		maxSeries := dsInfo.MaxSeries
-		res := executeQuery(ctx, logger, *qm, r, maxSeries)
+		res := executeQuery(ctx, logger, *qm, r, maxSeries, dsInfo.MaxPoints)
 
 		tRes.Responses[query.RefID] = res
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
@@ -15,6 +16,7 @@ import (
 	"github.com/grafana/grafana/pkg/tsdb/influxdb/fsql"
 
 	"github.com/grafana/grafana/pkg/infra/httpclient"
+	"github.com/grafana/grafana/pkg/infra/localcache"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/tsdb/influxdb/influxql"
 	"github.com/grafana/grafana/pkg/tsdb/influxdb/models"
@@ -22,6 +24,9 @@ import (
 
 var logger log.Logger = log.New("tsdb.influxdb")
 
+// metadataCacheTTL is how long the results of metadata requests are cached
+const metadataCacheTTL = time.Minute
+
 type Service struct {
 	im       instancemgmt.InstanceManager
 	features featuremgmt.FeatureToggles
@@ -62,6 +67,11 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 			maxSeries = 1000
 		}
 
+		maxPoints := jsonData.MaxPoints
+		if maxPoints == 0 {
+			maxPoints = 1000000
+		}
+
 		version := jsonData.Version
 		if version == "" {
 			version = influxVersionInfluxQL
@@ -82,9 +92,11 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 			DefaultBucket: jsonData.DefaultBucket,
 			Organization:  jsonData.Organization,
 			MaxSeries:     maxSeries,
+			MaxPoints:     maxPoints,
 			SecureGrpc:    true,
 			Token:         settings.DecryptedSecureJSONData["token"],
 			Timeout:       opts.Timeouts.Timeout,
+			MetadataCache: localcache.New(metadataCacheTTL, 2*metadataCacheTTL),
 		}
 		return model, nil
 	}
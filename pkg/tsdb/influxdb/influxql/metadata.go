@@ -0,0 +1,178 @@
+package influxql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/tsdb/influxdb/models"
+)
+
+// Kinds of metadata that can be requested with Metadata
+const (
+	MetadataMeasurements      = "measurements"
+	MetadataFieldKeys         = "field-keys"
+	MetadataTagKeys           = "tag-keys"
+	MetadataTagValues         = "tag-values"
+	MetadataRetentionPolicies = "retention-policies"
+)
+
+var ErrInvalidMetadataRequest = errors.New("invalid metadata request")
+
+// Metadata runs the SHOW statement of the kind of metadata and returns the values found. The statement is
+// built from these parameters:
+//   - policy: the retention policy of the measurement
+//   - measurement: the measurement of the field keys, tag keys or tag values
+//   - key: the tag key of the tag values, required for tag values
+//   - filter: a regular expression the measurements or tag values must match
+//   - limit: the maximum number of measurements or tag values
+//
+// Results are cached in the metadata cache of the datasource instance, if it has one.
+func Metadata(ctx context.Context, dsInfo *models.DatasourceInfo, kind string, params url.Values) ([]string, error) {
+	logger := glog.FromContext(ctx)
+
+	statement, column, err := buildMetadataStatement(dsInfo, kind, params)
+	if err != nil {
+		return nil, err
+	}
+	policy := params.Get("policy")
+
+	cacheKey := fmt.Sprintf("%s/%s/%s", dsInfo.DbName, policy, statement)
+	if dsInfo.MetadataCache != nil {
+		if values, ok := dsInfo.MetadataCache.Get(cacheKey); ok {
+			return values.([]string), nil
+		}
+	}
+
+	request, err := createRequest(ctx, logger, dsInfo, statement, policy)
+	if err != nil {
+		return nil, err
+	}
+	res, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	var response models.Response
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		if res.StatusCode/100 != 2 {
+			return nil, fmt.Errorf("InfluxDB returned error: %s", res.Status)
+		}
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	if res.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("InfluxDB returned error: %s", res.Status)
+	}
+
+	values, err := metadataValues(response, column)
+	if err != nil {
+		return nil, err
+	}
+
+	if dsInfo.MetadataCache != nil {
+		dsInfo.MetadataCache.SetDefault(cacheKey, values)
+	}
+	return values, nil
+}
+
+// buildMetadataStatement returns the statement of the kind of metadata and the column of its results
+func buildMetadataStatement(dsInfo *models.DatasourceInfo, kind string, params url.Values) (string, string, error) {
+	from := ""
+	if measurement := params.Get("measurement"); measurement != "" {
+		from = " FROM " + quoteIdentifier(measurement)
+		if policy := params.Get("policy"); policy != "" && policy != defaultRetentionPolicy {
+			from = " FROM " + quoteIdentifier(policy) + "." + quoteIdentifier(measurement)
+		}
+	}
+
+	limit := ""
+	if l := params.Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n <= 0 {
+			return "", "", fmt.Errorf("%w: limit must be a positive number", ErrInvalidMetadataRequest)
+		}
+		limit = fmt.Sprintf(" LIMIT %d", n)
+	}
+
+	filter := params.Get("filter")
+
+	switch kind {
+	case MetadataMeasurements:
+		statement := "SHOW MEASUREMENTS"
+		if filter != "" {
+			statement += " WITH MEASUREMENT =~ " + quoteRegex(filter)
+		}
+		return statement + limit, "name", nil
+	case MetadataFieldKeys:
+		return "SHOW FIELD KEYS" + from, "fieldKey", nil
+	case MetadataTagKeys:
+		return "SHOW TAG KEYS" + from, "tagKey", nil
+	case MetadataTagValues:
+		key := params.Get("key")
+		if key == "" {
+			return "", "", fmt.Errorf("%w: key is required for tag values", ErrInvalidMetadataRequest)
+		}
+		statement := "SHOW TAG VALUES" + from + " WITH KEY = " + quoteIdentifier(key)
+		if filter != "" {
+			statement += " WHERE " + quoteIdentifier(key) + " =~ " + quoteRegex(filter)
+		}
+		return statement + limit, "value", nil
+	case MetadataRetentionPolicies:
+		return "SHOW RETENTION POLICIES ON " + quoteIdentifier(dsInfo.DbName), "name", nil
+	default:
+		return "", "", fmt.Errorf("%w: unknown metadata %q", ErrInvalidMetadataRequest, kind)
+	}
+}
+
+// metadataValues returns the distinct values of the column in all the series of the response
+func metadataValues(response models.Response, column string) ([]string, error) {
+	values := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, result := range response.Results {
+		if result.Error != "" {
+			return nil, errors.New(result.Error)
+		}
+		for _, row := range result.Series {
+			idx := -1
+			for i, c := range row.Columns {
+				if c == column {
+					idx = i
+				}
+			}
+			if idx == -1 {
+				continue
+			}
+			for _, v := range row.Values {
+				if idx >= len(v) || v[idx] == nil {
+					continue
+				}
+				value := fmt.Sprintf("%v", v[idx])
+				if !seen[value] {
+					seen[value] = true
+					values = append(values, value)
+				}
+			}
+		}
+	}
+	return values, nil
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(name) + `"`
+}
+
+func quoteRegex(regex string) string {
+	return "/" + strings.ReplaceAll(regex, "/", `\/`) + "/"
+}
@@ -0,0 +1,128 @@
+package influxql
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/tsdb/influxdb/models"
+)
+
+func TestBuildMetadataStatement(t *testing.T) {
+	dsInfo := &models.DatasourceInfo{DbName: "telegraf"}
+
+	tests := []struct {
+		name      string
+		kind      string
+		params    url.Values
+		statement string
+		column    string
+	}{
+		{
+			name:      "measurements",
+			kind:      MetadataMeasurements,
+			params:    url.Values{"filter": {"cpu/.*"}, "limit": {"100"}},
+			statement: `SHOW MEASUREMENTS WITH MEASUREMENT =~ /cpu\/.*/ LIMIT 100`,
+			column:    "name",
+		},
+		{
+			name:      "field keys of a measurement of a retention policy",
+			kind:      MetadataFieldKeys,
+			params:    url.Values{"measurement": {"cpu"}, "policy": {"one_week"}},
+			statement: `SHOW FIELD KEYS FROM "one_week"."cpu"`,
+			column:    "fieldKey",
+		},
+		{
+			name:      "tag keys of the default retention policy",
+			kind:      MetadataTagKeys,
+			params:    url.Values{"measurement": {`my "cpu"`}, "policy": {"default"}},
+			statement: `SHOW TAG KEYS FROM "my \"cpu\""`,
+			column:    "tagKey",
+		},
+		{
+			name:      "tag values",
+			kind:      MetadataTagValues,
+			params:    url.Values{"measurement": {"cpu"}, "key": {"host"}, "filter": {"^web"}},
+			statement: `SHOW TAG VALUES FROM "cpu" WITH KEY = "host" WHERE "host" =~ /^web/`,
+			column:    "value",
+		},
+		{
+			name:      "retention policies",
+			kind:      MetadataRetentionPolicies,
+			statement: `SHOW RETENTION POLICIES ON "telegraf"`,
+			column:    "name",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			statement, column, err := buildMetadataStatement(dsInfo, test.kind, test.params)
+			require.NoError(t, err)
+			assert.Equal(t, test.statement, statement)
+			assert.Equal(t, test.column, column)
+		})
+	}
+
+	t.Run("rejects invalid requests", func(t *testing.T) {
+		_, _, err := buildMetadataStatement(dsInfo, MetadataTagValues, url.Values{})
+		require.ErrorIs(t, err, ErrInvalidMetadataRequest)
+		_, _, err = buildMetadataStatement(dsInfo, MetadataMeasurements, url.Values{"limit": {"-1"}})
+		require.ErrorIs(t, err, ErrInvalidMetadataRequest)
+		_, _, err = buildMetadataStatement(dsInfo, "users", url.Values{})
+		require.ErrorIs(t, err, ErrInvalidMetadataRequest)
+	})
+}
+
+func TestMetadata(t *testing.T) {
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		_, err := w.Write([]byte(`{"results": [{"statement_id": 0, "series": [
+			{"name": "cpu", "columns": ["key", "value"], "values": [["host", "a"], ["host", "b"]]},
+			{"name": "mem", "columns": ["key", "value"], "values": [["host", "b"], ["host", "c"]]}
+		]}]}`))
+		require.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	dsInfo := &models.DatasourceInfo{
+		HTTPClient:    server.Client(),
+		URL:           server.URL,
+		DbName:        "telegraf",
+		HTTPMode:      "GET",
+		MetadataCache: localcache.New(time.Minute, time.Minute),
+	}
+	params := url.Values{"key": {"host"}, "policy": {"one_week"}}
+
+	values, err := Metadata(context.Background(), dsInfo, MetadataTagValues, params)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, values)
+	require.Len(t, requests, 1)
+	assert.Equal(t, `SHOW TAG VALUES WITH KEY = "host"`, requests[0].URL.Query().Get("q"))
+	assert.Equal(t, "one_week", requests[0].URL.Query().Get("rp"))
+
+	// the second request is served from the cache
+	values, err = Metadata(context.Background(), dsInfo, MetadataTagValues, params)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, values)
+	require.Len(t, requests, 1)
+}
+
+func TestMetadataError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"results": [{"statement_id": 0, "error": "database not found: telegraf"}]}`))
+		require.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	dsInfo := &models.DatasourceInfo{HTTPClient: server.Client(), URL: server.URL, DbName: "telegraf", HTTPMode: "GET"}
+	_, err := Metadata(context.Background(), dsInfo, MetadataMeasurements, url.Values{})
+	require.EqualError(t, err, "database not found: telegraf")
+}
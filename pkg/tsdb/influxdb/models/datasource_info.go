@@ -3,6 +3,8 @@ package models
 import (
 	"net/http"
 	"time"
+
+	"github.com/grafana/grafana/pkg/infra/localcache"
 )
 
 type DatasourceInfo struct {
@@ -18,8 +20,12 @@ type DatasourceInfo struct {
 	DefaultBucket string `json:"defaultBucket"`
 	Organization  string `json:"organization"`
 	MaxSeries     int    `json:"maxSeries"`
+	MaxPoints     int    `json:"maxPoints"`
 	Timeout       time.Duration
 
 	// FlightSQL grpc connection
 	SecureGrpc bool `json:"secureGrpc"`
+
+	// MetadataCache caches the results of InfluxQL metadata requests of the instance
+	MetadataCache *localcache.CacheService `json:"-"`
 }
@@ -0,0 +1,69 @@
+package influxdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/tsdb/influxdb/influxql"
+)
+
+// CallResource serves the metadata of InfluxQL datasources, which is used by template variables and the query
+// editor. The path is the kind of metadata, and the parameters of the statement are passed in the query string.
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	logger := logger.FromContext(ctx)
+
+	if req.Method != http.MethodGet {
+		return sendResourceError(sender, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+	}
+
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		logger.Error("Failed to get data source info", "error", err)
+		return err
+	}
+	if dsInfo.Version != influxVersionInfluxQL {
+		return sendResourceError(sender, http.StatusNotFound, errors.New("metadata is only available for InfluxQL"))
+	}
+
+	params := url.Values{}
+	if u, err := url.Parse(req.URL); err == nil {
+		params = u.Query()
+	}
+
+	values, err := influxql.Metadata(ctx, dsInfo, strings.Trim(req.Path, "/"), params)
+	if err != nil {
+		if errors.Is(err, influxql.ErrInvalidMetadataRequest) {
+			return sendResourceError(sender, http.StatusBadRequest, err)
+		}
+		logger.Warn("Metadata request failed", "path", req.Path, "error", err)
+		return sendResourceError(sender, http.StatusBadGateway, err)
+	}
+
+	body, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	return sender.Send(&backend.CallResourceResponse{
+		Status:  http.StatusOK,
+		Headers: map[string][]string{"Content-Type": {"application/json"}},
+		Body:    body,
+	})
+}
+
+func sendResourceError(sender backend.CallResourceResponseSender, status int, err error) error {
+	body, marshalErr := json.Marshal(map[string]string{"message": err.Error()})
+	if marshalErr != nil {
+		return marshalErr
+	}
+	return sender.Send(&backend.CallResourceResponse{
+		Status:  status,
+		Headers: map[string][]string{"Content-Type": {"application/json"}},
+		Body:    body,
+	})
+}
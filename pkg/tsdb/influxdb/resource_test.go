@@ -0,0 +1,57 @@
+package influxdb
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeResourceSender struct {
+	res *backend.CallResourceResponse
+}
+
+func (s *fakeResourceSender) Send(res *backend.CallResourceResponse) error {
+	s.res = res
+	return nil
+}
+
+func TestCallResource(t *testing.T) {
+	callResource := func(t *testing.T, s *Service, path string, url string) *backend.CallResourceResponse {
+		t.Helper()
+		sender := &fakeResourceSender{}
+		err := s.CallResource(context.Background(), &backend.CallResourceRequest{
+			Method: http.MethodGet,
+			Path:   path,
+			URL:    url,
+		}, sender)
+		require.NoError(t, err)
+		require.NotNil(t, sender.res)
+		return sender.res
+	}
+
+	t.Run("returns the measurements", func(t *testing.T) {
+		s := GetMockService(influxVersionInfluxQL, RoundTripper{
+			Body: `{"results": [{"series": [{"columns": ["name"],"name": "measurements","values": [["cpu"],["disk"]]}],"statement_id": 0}]}`,
+		})
+		res := callResource(t, s, "measurements", "measurements?limit=10")
+		assert.Equal(t, http.StatusOK, res.Status)
+		assert.JSONEq(t, `["cpu", "disk"]`, string(res.Body))
+	})
+
+	t.Run("rejects invalid requests", func(t *testing.T) {
+		s := GetMockService(influxVersionInfluxQL, RoundTripper{})
+		res := callResource(t, s, "tag-values", "tag-values?measurement=cpu")
+		assert.Equal(t, http.StatusBadRequest, res.Status)
+		assert.JSONEq(t, `{"message": "invalid metadata request: key is required for tag values"}`, string(res.Body))
+	})
+
+	t.Run("is not available for Flux", func(t *testing.T) {
+		s := GetMockService(influxVersionFlux, RoundTripper{})
+		res := callResource(t, s, "measurements", "measurements")
+		assert.Equal(t, http.StatusNotFound, res.Status)
+	})
+}
@@ -0,0 +1,42 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMySQLQueryGuardrails(t *testing.T) {
+	g := mysqlQueryGuardrails{}
+
+	t.Run("does not change the session of the pooled connection", func(t *testing.T) {
+		assert.Empty(t, g.SessionStatements(5*time.Second, 0))
+	})
+
+	t.Run("adds the execution time hint after the leading SELECT", func(t *testing.T) {
+		testCases := map[string]string{
+			"SELECT 1":                       "SELECT /*+ MAX_EXECUTION_TIME(5000) */ 1",
+			"  select a FROM t":              "  select /*+ MAX_EXECUTION_TIME(5000) */ a FROM t",
+			"-- comment\nSELECT 1":           "-- comment\nSELECT /*+ MAX_EXECUTION_TIME(5000) */ 1",
+			"# comment\nSELECT 1":            "# comment\nSELECT /*+ MAX_EXECUTION_TIME(5000) */ 1",
+			"/* comment */ SELECT 1":         "/* comment */ SELECT /*+ MAX_EXECUTION_TIME(5000) */ 1",
+			"SHOW TABLES":                    "SHOW TABLES",
+			"SELECTED":                       "SELECTED",
+			"/*!50000 SELECT */ 1":           "/*!50000 SELECT */ 1",
+			"WITH t AS (SELECT 1) SELECT 1":  "WITH t AS (SELECT 1) SELECT 1",
+			"(SELECT 1) UNION (SELECT 2)":    "(SELECT 1) UNION (SELECT 2)",
+			"SELECT 'SELECT' AS keyword":     "SELECT /*+ MAX_EXECUTION_TIME(5000) */ 'SELECT' AS keyword",
+			"SELECT /*+BKA(t1)*/ a FROM t1":  "SELECT /*+ MAX_EXECUTION_TIME(5000) BKA(t1)*/ a FROM t1",
+			"/**/SELECT 1":                   "/**/SELECT /*+ MAX_EXECUTION_TIME(5000) */ 1",
+			"SELECT/*+ BKA(t1) */ a FROM t1": "SELECT/*+ MAX_EXECUTION_TIME(5000) BKA(t1) */ a FROM t1",
+		}
+		for query, expected := range testCases {
+			assert.Equal(t, expected, g.AddQueryHints(query, 5*time.Second), query)
+		}
+	})
+
+	t.Run("leaves the query unchanged without a timeout", func(t *testing.T) {
+		assert.Equal(t, "SELECT 1", g.AddQueryHints("SELECT 1", 0))
+	})
+}
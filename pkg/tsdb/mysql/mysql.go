@@ -8,9 +8,11 @@ import (
 	"fmt"
 	"net/url"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/VividCortex/mysqlerr"
 	"github.com/go-sql-driver/mysql"
@@ -145,6 +147,7 @@ func newInstanceSettings(cfg *setting.Cfg, logger log.Logger) datasource.Instanc
 			TimeColumnNames:   []string{"time", "time_sec"},
 			MetricColumnTypes: []string{"CHAR", "VARCHAR", "TINYTEXT", "TEXT", "MEDIUMTEXT", "LONGTEXT"},
 			RowLimit:          cfg.DataProxyRowLimit,
+			Guardrails:        mysqlQueryGuardrails{},
 		}
 
 		rowTransformer := mysqlQueryResultTransformer{
@@ -217,6 +220,46 @@ func (t *mysqlQueryResultTransformer) TransformQueryError(logger log.Logger, err
 	return err
 }
 
+// mysqlQueryGuardrails passes the execution time limit as an optimizer hint of the query, as a session
+// variable would stay set on the pooled connection after the query. MySQL only enforces the limit on
+// SELECT statements and has no per-query memory limit, so the memory limit is not supported.
+type mysqlQueryGuardrails struct{}
+
+func (mysqlQueryGuardrails) ReadOnlyTransactions() bool {
+	return true
+}
+
+func (mysqlQueryGuardrails) SessionStatements(time.Duration, int) []string {
+	return nil
+}
+
+// leadingSelect matches the SELECT keyword starting a query, after any whitespace and comments.
+// Executable /*! ... */ comments are not skipped.
+var leadingSelect = regexp.MustCompile(`(?i)^(\s*(?:(?:--[^\n]*(?:\n|$)|#[^\n]*(?:\n|$)|/\*(?:[^!][\s\S]*?)?\*/)\s*)*SELECT\b)`)
+
+// existingHint matches an optimizer hint comment following the SELECT keyword.
+var existingHint = regexp.MustCompile(`^\s*/\*\+`)
+
+func (mysqlQueryGuardrails) AddQueryHints(query string, timeout time.Duration) string {
+	if timeout <= 0 {
+		return query
+	}
+	loc := leadingSelect.FindStringIndex(query)
+	if loc == nil {
+		return query
+	}
+	hint := fmt.Sprintf("MAX_EXECUTION_TIME(%d)", timeout.Milliseconds())
+	// Only the first hint comment of a query block is used, so the hint joins an existing one.
+	if existing := existingHint.FindStringIndex(query[loc[1]:]); existing != nil {
+		at := loc[1] + existing[1]
+		if rest := query[at:]; rest == "" || !unicode.IsSpace(rune(rest[0])) {
+			hint += " "
+		}
+		return query[:at] + " " + hint + query[at:]
+	}
+	return query[:loc[1]] + " /*+ " + hint + " */" + query[loc[1]:]
+}
+
 func (t *mysqlQueryResultTransformer) GetConverterList() []sqlutil.StringConverter {
 	// For the MySQL driver , we have these possible data types:
 	// https://www.w3schools.com/sql/sql_datatypes.asp#:~:text=In%20MySQL%20there%20are%20three,numeric%2C%20and%20date%20and%20time.
@@ -82,6 +82,39 @@ func (c *Client) QueryExemplars(ctx context.Context, q *models.Query) (*http.Res
 	return c.doer.Do(req)
 }
 
+// QueryMetadata fetches the metadata of a metric. The metadata endpoint only supports GET requests.
+func (c *Client) QueryMetadata(ctx context.Context, metric string) (*http.Response, error) {
+	u, err := c.createUrl("api/v1/metadata", map[string]string{"metric": metric})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := createRequest(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.doer.Do(req)
+}
+
+// QuerySeries fetches the series matching the selector in the time range. Prometheus versions supporting it
+// return at most limit series.
+func (c *Client) QuerySeries(ctx context.Context, match string, start, end time.Time, limit int) (*http.Response, error) {
+	qv := map[string]string{
+		"match[]": match,
+		"start":   formatTime(start),
+		"end":     formatTime(end),
+		"limit":   strconv.Itoa(limit),
+	}
+
+	req, err := c.createQueryRequest(ctx, "api/v1/series", qv)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.doer.Do(req)
+}
+
 func (c *Client) QueryResource(ctx context.Context, req *backend.CallResourceRequest) (*http.Response, error) {
 	// The way URL is represented in CallResourceRequest and what we need for the fetch function is different
 	// so here we have to do a bit of parsing, so we can then compose it with the base url in correct way.
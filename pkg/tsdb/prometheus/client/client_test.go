@@ -138,4 +138,44 @@ func TestClient(t *testing.T) {
 			require.Equal(t, "http://localhost:9090/api/v1/query_range?end=1234&query=rate%28ALERTS%7Bjob%3D%22test%22+%5B%24__rate_interval%5D%7D%29&start=0&step=1", doer.Req.URL.String())
 		})
 	})
+
+	t.Run("QueryMetadata", func(t *testing.T) {
+		doer := &MockDoer{}
+
+		t.Run("sends GET request with POST method", func(t *testing.T) {
+			client := NewClient(doer, http.MethodPost, "http://localhost:9090")
+			res, err := client.QueryMetadata(context.Background(), "up")
+			defer func() {
+				if res != nil && res.Body != nil {
+					if err := res.Body.Close(); err != nil {
+						fmt.Println("Error", "err", err)
+					}
+				}
+			}()
+			require.NoError(t, err)
+			require.NotNil(t, doer.Req)
+			require.Equal(t, http.MethodGet, doer.Req.Method)
+			require.Equal(t, "http://localhost:9090/api/v1/metadata?metric=up", doer.Req.URL.String())
+		})
+	})
+
+	t.Run("QuerySeries", func(t *testing.T) {
+		doer := &MockDoer{}
+
+		t.Run("sends correct GET query", func(t *testing.T) {
+			client := NewClient(doer, http.MethodGet, "http://localhost:9090")
+			res, err := client.QuerySeries(context.Background(), "up", time.Unix(0, 0), time.Unix(1234, 0), 100)
+			defer func() {
+				if res != nil && res.Body != nil {
+					if err := res.Body.Close(); err != nil {
+						fmt.Println("Error", "err", err)
+					}
+				}
+			}()
+			require.NoError(t, err)
+			require.NotNil(t, doer.Req)
+			require.Equal(t, http.MethodGet, doer.Req.Method)
+			require.Equal(t, "http://localhost:9090/api/v1/series?end=1234&limit=100&match%5B%5D=up&start=0", doer.Req.URL.String())
+		})
+	})
 }
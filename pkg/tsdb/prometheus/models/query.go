@@ -60,8 +60,22 @@ type QueryModel struct {
 	Interval       string `json:"interval,omitempty"`
 	IntervalMs     int64  `json:"intervalMs,omitempty"`
 	IntervalFactor int64  `json:"intervalFactor,omitempty"`
+	// Format of native histograms: heatmap (the default) returns heatmap-cells frames, and quantiles returns
+	// a series for each of the HistogramQuantiles
+	HistogramFormat    HistogramFormat `json:"histogramFormat,omitempty"`
+	HistogramQuantiles []float64       `json:"histogramQuantiles,omitempty"`
 }
 
+type HistogramFormat string
+
+const (
+	HistogramFormatHeatmap   HistogramFormat = "heatmap"
+	HistogramFormatQuantiles HistogramFormat = "quantiles"
+)
+
+// defaultHistogramQuantiles are the quantiles of native histograms returned when none are requested
+var defaultHistogramQuantiles = []float64{0.5, 0.9, 0.99}
+
 type TimeRange struct {
 	Start time.Time
 	End   time.Time
@@ -80,6 +94,9 @@ type Query struct {
 	ExemplarQuery bool
 	UtcOffsetSec  int64
 	Scope         Scope
+	// HistogramQuantiles are the quantiles returned for native histograms, which are returned as heatmap
+	// cells when there are none
+	HistogramQuantiles []float64
 }
 
 type Scope struct {
@@ -144,6 +161,11 @@ func Parse(query backend.DataQuery, dsScrapeInterval string, intervalCalculator
 		exemplarQuery = false
 	}
 
+	histogramQuantiles, err := parseHistogramQuantiles(model.HistogramFormat, model.HistogramQuantiles)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Query{
 		Expr:          expr,
 		Step:          calculatedStep,
@@ -155,9 +177,30 @@ func Parse(query backend.DataQuery, dsScrapeInterval string, intervalCalculator
 		RangeQuery:    rangeQuery,
 		ExemplarQuery: exemplarQuery,
 		UtcOffsetSec:  model.UtcOffsetSec,
+
+		HistogramQuantiles: histogramQuantiles,
 	}, nil
 }
 
+func parseHistogramQuantiles(format HistogramFormat, quantiles []float64) ([]float64, error) {
+	switch format {
+	case "", HistogramFormatHeatmap:
+		return nil, nil
+	case HistogramFormatQuantiles:
+		if len(quantiles) == 0 {
+			return defaultHistogramQuantiles, nil
+		}
+		for _, q := range quantiles {
+			if q < 0 || q > 1 || math.IsNaN(q) {
+				return nil, fmt.Errorf("invalid histogram quantile %v, quantiles must be between 0 and 1", q)
+			}
+		}
+		return quantiles, nil
+	default:
+		return nil, fmt.Errorf("unknown histogram format %q", format)
+	}
+}
+
 func (query *Query) Type() TimeSeriesQueryType {
 	if query.InstantQuery {
 		return InstantQueryType
@@ -504,6 +504,41 @@ func TestParse(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, true, res.RangeQuery)
 	})
+
+	t.Run("parsing query model with native histogram quantiles", func(t *testing.T) {
+		timeRange := backend.TimeRange{
+			From: now,
+			To:   now.Add(48 * time.Hour),
+		}
+
+		q := queryContext(`{
+			"expr": "http_request_duration_seconds",
+			"histogramFormat": "quantiles",
+			"refId": "A"
+		}`, timeRange, time.Duration(1)*time.Minute)
+		res, err := models.Parse(q, "15s", intervalCalculator, false, false)
+		require.NoError(t, err)
+		require.Equal(t, []float64{0.5, 0.9, 0.99}, res.HistogramQuantiles)
+
+		q = queryContext(`{
+			"expr": "http_request_duration_seconds",
+			"histogramFormat": "quantiles",
+			"histogramQuantiles": [0.75],
+			"refId": "A"
+		}`, timeRange, time.Duration(1)*time.Minute)
+		res, err = models.Parse(q, "15s", intervalCalculator, false, false)
+		require.NoError(t, err)
+		require.Equal(t, []float64{0.75}, res.HistogramQuantiles)
+
+		q = queryContext(`{
+			"expr": "http_request_duration_seconds",
+			"histogramFormat": "quantiles",
+			"histogramQuantiles": [1.5],
+			"refId": "A"
+		}`, timeRange, time.Duration(1)*time.Minute)
+		_, err = models.Parse(q, "15s", intervalCalculator, false, false)
+		require.EqualError(t, err, "invalid histogram quantile 1.5, quantiles must be between 0 and 1")
+	})
 }
 
 func TestRateInterval(t *testing.T) {
@@ -113,6 +113,14 @@ func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceReq
 		return sender.Send(vResp)
 	}
 
+	if strings.EqualFold(req.Path, "lint") {
+		lintResp, err := i.resource.Lint(ctx, req)
+		if err != nil {
+			return err
+		}
+		return sender.Send(lintResp)
+	}
+
 	resp, err := i.resource.Execute(ctx, req)
 	if err != nil {
 		return err
@@ -0,0 +1,137 @@
+package querydata
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// histogramBucket is a bucket of a native histogram sample
+type histogramBucket struct {
+	lower float64
+	upper float64
+	count float64
+}
+
+// histogramQuantileFrames replaces the heatmap-cells frames of native histograms with a time series for
+// each of the quantiles. The other frames are returned unchanged.
+func histogramQuantileFrames(frames data.Frames, quantiles []float64) data.Frames {
+	result := make(data.Frames, 0, len(frames))
+	for _, frame := range frames {
+		if frame.Meta == nil || frame.Meta.Type != "heatmap-cells" || len(frame.Fields) < 4 {
+			result = append(result, frame)
+			continue
+		}
+		result = append(result, histogramQuantileFramesOf(frame, quantiles)...)
+	}
+	return result
+}
+
+// histogramQuantileFramesOf computes the quantiles of the samples of a heatmap-cells frame, whose rows are the
+// buckets of the samples ordered by time. Rows whose bounds or count are not numbers are skipped.
+func histogramQuantileFramesOf(frame *data.Frame, quantiles []float64) data.Frames {
+	timeField, yMin, yMax, count := frame.Fields[0], frame.Fields[1], frame.Fields[2], frame.Fields[3]
+
+	var times []time.Time
+	var samples [][]histogramBucket
+	for i := 0; i < timeField.Len(); i++ {
+		t, ok := timeField.At(i).(time.Time)
+		if !ok {
+			continue
+		}
+		bucket, ok := histogramBucketAt(yMin, yMax, count, i)
+		if !ok {
+			continue
+		}
+		if len(times) == 0 || !times[len(times)-1].Equal(t) {
+			times = append(times, t)
+			samples = append(samples, nil)
+		}
+		samples[len(samples)-1] = append(samples[len(samples)-1], bucket)
+	}
+
+	frames := make(data.Frames, 0, len(quantiles))
+	for _, q := range quantiles {
+		labels := data.Labels{}
+		for k, v := range yMin.Labels {
+			labels[k] = v
+		}
+		labels["quantile"] = strconv.FormatFloat(q, 'f', -1, 64)
+
+		values := make([]float64, len(samples))
+		for i, buckets := range samples {
+			values[i] = bucketQuantile(q, buckets)
+		}
+
+		valueField := data.NewField(data.TimeSeriesValueFieldName, labels, values)
+		t := data.NewField(data.TimeSeriesTimeFieldName, nil, append([]time.Time(nil), times...))
+		quantileFrame := data.NewFrame(frame.Name, t, valueField)
+		quantileFrame.Meta = &data.FrameMeta{
+			Type:   data.FrameTypeTimeSeriesMulti,
+			Custom: map[string]string{"resultType": "matrix"},
+		}
+		frames = append(frames, quantileFrame)
+	}
+	return frames
+}
+
+// histogramBucketAt returns the bucket of the row, or false if a value of the row is null or not a number
+func histogramBucketAt(yMin, yMax, count *data.Field, idx int) (histogramBucket, bool) {
+	var values [3]float64
+	for i, field := range []*data.Field{yMin, yMax, count} {
+		v, err := field.FloatAt(idx)
+		if err != nil || math.IsNaN(v) {
+			return histogramBucket{}, false
+		}
+		values[i] = v
+	}
+	return histogramBucket{lower: values[0], upper: values[1], count: values[2]}, true
+}
+
+// bucketQuantile estimates the quantile of the buckets of a native histogram sample like histogram_quantile()
+// in PromQL. Within the bucket of the quantile, the value is interpolated exponentially, as the buckets of
+// native histograms grow exponentially, except in the zero bucket and buckets spanning zero, which are
+// interpolated linearly. Prometheus interpolates histograms with custom buckets linearly, but they can't be told
+// apart from the frame, so they are interpolated exponentially too.
+func bucketQuantile(q float64, buckets []histogramBucket) float64 {
+	if q < 0 {
+		return math.Inf(-1)
+	}
+	if q > 1 {
+		return math.Inf(1)
+	}
+
+	total := 0.0
+	for _, b := range buckets {
+		total += b.count
+	}
+	if total == 0 {
+		return math.NaN()
+	}
+
+	rank := q * total
+	cumulative := 0.0
+	for _, b := range buckets {
+		if b.count > 0 && cumulative+b.count >= rank {
+			return interpolate(b, (rank-cumulative)/b.count)
+		}
+		cumulative += b.count
+	}
+	return buckets[len(buckets)-1].upper
+}
+
+// interpolate returns the value at the fraction of the bucket
+func interpolate(b histogramBucket, fraction float64) float64 {
+	if b.lower <= 0 && b.upper >= 0 {
+		return b.lower + (b.upper-b.lower)*fraction
+	}
+	logLower := math.Log2(math.Abs(b.lower))
+	logUpper := math.Log2(math.Abs(b.upper))
+	if b.lower > 0 {
+		return math.Exp2(logLower + (logUpper-logLower)*fraction)
+	}
+	// negative buckets are mirrored
+	return -math.Exp2(logUpper + (logLower-logUpper)*(1-fraction))
+}
@@ -0,0 +1,90 @@
+package querydata
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/models"
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/querydata/exemplar"
+)
+
+func TestBucketQuantile(t *testing.T) {
+	buckets := []histogramBucket{
+		{lower: 0, upper: 1, count: 2},
+		{lower: 1, upper: 2, count: 0},
+		{lower: 2, upper: 4, count: 6},
+	}
+
+	assert.Equal(t, 0.5, bucketQuantile(0.125, buckets))
+	assert.Equal(t, 1.0, bucketQuantile(0.25, buckets))
+	assert.InDelta(t, 2*math.Sqrt2, bucketQuantile(0.625, buckets), 1e-9)
+	assert.Equal(t, 4.0, bucketQuantile(1, buckets))
+	assert.InDelta(t, -2*math.Sqrt2, bucketQuantile(0.5, []histogramBucket{{lower: -4, upper: -2, count: 2}}), 1e-9)
+	assert.Equal(t, 0.0, bucketQuantile(0.5, []histogramBucket{{lower: -1, upper: 1, count: 2}}))
+	assert.True(t, math.IsInf(bucketQuantile(-1, buckets), -1))
+	assert.True(t, math.IsInf(bucketQuantile(2, buckets), 1))
+	assert.True(t, math.IsNaN(bucketQuantile(0.5, []histogramBucket{{lower: 0, upper: 1, count: 0}})))
+}
+
+func TestQueryData_parseResponseHistogramQuantiles(t *testing.T) {
+	qd := QueryData{exemplarSampler: exemplar.NewStandardDeviationSampler}
+	resBody := `{"status": "success", "data": {"resultType": "matrix", "result": [{
+		"metric": {"__name__": "http_request_duration_seconds", "job": "api"},
+		"histograms": [
+			[1700000000, {"count": "4", "sum": "5", "buckets": [[0, "0", "1", "2"], [0, "1", "2", "2"]]}],
+			[1700000060, {"count": "4", "sum": "9", "buckets": [[0, "1", "2", "2"], [0, "2", "4", "2"]]}]
+		]
+	}]}}`
+
+	res := &http.Response{Body: io.NopCloser(bytes.NewBufferString(resBody))}
+	q := &models.Query{RangeQuery: true, Step: time.Minute, HistogramQuantiles: []float64{0.5, 0.75}}
+	result := qd.parseResponse(context.Background(), q, res, false)
+	require.NoError(t, result.Error)
+	require.Len(t, result.Frames, 2)
+
+	for i, expected := range []struct {
+		quantile string
+		values   []float64
+	}{
+		{quantile: "0.5", values: []float64{1, 2}},
+		{quantile: "0.75", values: []float64{math.Sqrt2, 2 * math.Sqrt2}},
+	} {
+		frame := result.Frames[i]
+		require.Equal(t, data.FrameTypeTimeSeriesMulti, frame.Meta.Type)
+		require.Len(t, frame.Fields, 2)
+		assert.Equal(t, []time.Time{time.Unix(1700000000, 0).UTC(), time.Unix(1700000060, 0).UTC()}, []time.Time{
+			frame.Fields[0].At(0).(time.Time), frame.Fields[0].At(1).(time.Time),
+		})
+		assert.Equal(t, data.Labels{"__name__": "http_request_duration_seconds", "job": "api", "quantile": expected.quantile}, frame.Fields[1].Labels)
+		assert.InDeltaSlice(t, expected.values, []float64{frame.Fields[1].At(0).(float64), frame.Fields[1].At(1).(float64)}, 1e-9)
+	}
+}
+
+func TestHistogramQuantileFramesSkipsInvalidRows(t *testing.T) {
+	ts := time.Unix(1700000000, 0).UTC()
+	one, two := 1.0, 2.0
+	frame := data.NewFrame("",
+		data.NewField("xMax", nil, []time.Time{ts, ts, ts}),
+		data.NewField("yMin", nil, []*float64{&one, nil, &one}),
+		data.NewField("yMax", nil, []*float64{&two, &two, &two}),
+		data.NewField("count", nil, []string{"2", "2", "not a number"}),
+	)
+	frame.Meta = &data.FrameMeta{Type: "heatmap-cells"}
+
+	var frames data.Frames
+	require.NotPanics(t, func() {
+		frames = histogramQuantileFrames(data.Frames{frame}, []float64{0.5})
+	})
+	require.Len(t, frames, 1)
+	require.Equal(t, 1, frames[0].Fields[1].Len())
+	assert.InDelta(t, math.Sqrt2, frames[0].Fields[1].At(0).(float64), 1e-9)
+}
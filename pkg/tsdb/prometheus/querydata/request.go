@@ -44,6 +44,7 @@ type QueryData struct {
 	URL                string
 	TimeInterval       string
 	exemplarSampler    func() exemplar.Sampler
+	splitting          querySplitting
 }
 
 func New(
@@ -66,6 +67,11 @@ func New(
 		httpMethod = http.MethodPost
 	}
 
+	splitting, err := newQuerySplitting(jsonData)
+	if err != nil {
+		return nil, err
+	}
+
 	promClient := client.NewClient(httpClient, httpMethod, settings.URL)
 
 	// standard deviation sampler is the default for backwards compatibility
@@ -80,6 +86,7 @@ func New(
 		ID:                 settings.ID,
 		URL:                settings.URL,
 		exemplarSampler:    exemplarSampler,
+		splitting:          splitting,
 	}, nil
 }
 
@@ -130,7 +137,12 @@ func (s *QueryData) fetch(ctx context.Context, client *client.Client, q *models.
 	}
 
 	if q.RangeQuery {
-		res := s.rangeQuery(traceCtx, client, q, enablePrometheusDataplane)
+		var res backend.DataResponse
+		if queries := s.splitting.splitRange(q); queries != nil {
+			res = s.splitRangeQuery(traceCtx, client, q, queries, enablePrometheusDataplane)
+		} else {
+			res = s.rangeQuery(traceCtx, client, q, enablePrometheusDataplane)
+		}
 		if res.Error != nil {
 			if dr.Error == nil {
 				dr.Error = res.Error
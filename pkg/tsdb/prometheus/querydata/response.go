@@ -34,6 +34,10 @@ func (s *QueryData) parseResponse(ctx context.Context, q *models.Query, res *htt
 	})
 	r.Status = backend.Status(res.StatusCode)
 
+	if len(q.HistogramQuantiles) > 0 {
+		r.Frames = histogramQuantileFrames(r.Frames, q.HistogramQuantiles)
+	}
+
 	// Add frame to attach metadata
 	if len(r.Frames) == 0 && !q.ExemplarQuery {
 		r.Frames = append(r.Frames, data.NewFrame(""))
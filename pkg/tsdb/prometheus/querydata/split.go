@@ -0,0 +1,153 @@
+package querydata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/client"
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/models"
+)
+
+// defaultSplitConcurrency is the number of sub-interval queries executed at the same time
+const defaultSplitConcurrency = 4
+
+// querySplitting configures the splitting of long range queries into sub-intervals
+type querySplitting struct {
+	// interval is the maximum length of a sub-interval. Range queries are not split when it is 0.
+	interval    time.Duration
+	concurrency int
+}
+
+func newQuerySplitting(jsonData map[string]any) (querySplitting, error) {
+	splitting := querySplitting{concurrency: defaultSplitConcurrency}
+
+	if interval, ok := jsonData["queryTimeSplitInterval"].(string); ok && interval != "" {
+		d, err := gtime.ParseDuration(interval)
+		if err != nil {
+			return splitting, fmt.Errorf("invalid query split interval %q: %w", interval, err)
+		}
+		splitting.interval = d
+	}
+	if concurrency, ok := jsonData["queryTimeSplitConcurrency"].(float64); ok && concurrency >= 1 {
+		splitting.concurrency = int(concurrency)
+	}
+	return splitting, nil
+}
+
+// splitRange returns the sub-queries of the step aligned sub-intervals of the range of the query, or nil if the
+// range is not longer than the split interval. The sub-intervals do not overlap, so that every step of the
+// range is evaluated by a single sub-query.
+func (s querySplitting) splitRange(q *models.Query) []*models.Query {
+	tr := q.TimeRange()
+	if s.interval <= 0 || tr.Step <= 0 || tr.End.Sub(tr.Start) <= s.interval {
+		return nil
+	}
+
+	length := s.interval / tr.Step * tr.Step
+	if length < tr.Step {
+		length = tr.Step
+	}
+
+	var queries []*models.Query
+	for start := tr.Start; !start.After(tr.End); start = start.Add(length) {
+		end := start.Add(length - tr.Step)
+		if end.After(tr.End) {
+			end = tr.End
+		}
+		sub := *q
+		sub.Start = start
+		sub.End = end
+		queries = append(queries, &sub)
+	}
+	return queries
+}
+
+// splitRangeQuery executes the sub-queries of a range query with bounded concurrency, and merges their frames.
+// The first failed sub-query cancels the others, and its response is returned.
+func (s *QueryData) splitRangeQuery(ctx context.Context, c *client.Client, q *models.Query, queries []*models.Query, enablePrometheusDataplaneFlag bool) backend.DataResponse {
+	s.log.FromContext(ctx).Debug("Splitting range query", "query", q.Expr, "subQueries", len(queries), "interval", s.splitting.interval)
+
+	responses := make([]backend.DataResponse, len(queries))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(s.splitting.concurrency)
+	for i, sub := range queries {
+		i, sub := i, sub
+		g.Go(func() error {
+			responses[i] = s.rangeQuery(gctx, c, sub, enablePrometheusDataplaneFlag)
+			return responses[i].Error
+		})
+	}
+	if err := g.Wait(); err != nil {
+		for _, res := range responses {
+			if errors.Is(res.Error, err) {
+				return res
+			}
+		}
+	}
+
+	dr := backend.DataResponse{Frames: mergeSplitFrames(responses), Status: responses[0].Status}
+	for i, frame := range dr.Frames {
+		if frame.Meta == nil {
+			continue
+		}
+		// like in other responses, only the first frame has the executed query
+		frame.Meta.ExecutedQueryString = ""
+		if i == 0 {
+			frame.Meta.ExecutedQueryString = executedQueryString(q)
+		}
+	}
+	return dr
+}
+
+// mergeSplitFrames appends the rows of the frames of the same series of the responses of consecutive
+// sub-intervals. The series are identified by the name of their frame and the labels of their value.
+func mergeSplitFrames(responses []backend.DataResponse) data.Frames {
+	var frames data.Frames
+	series := make(map[string]*data.Frame)
+	for _, res := range responses {
+		for _, frame := range res.Frames {
+			if len(frame.Fields) < 2 || frame.Meta == nil {
+				continue
+			}
+			key := fmt.Sprintf("%s/%s/%s", frame.Meta.Type, frame.Name, frame.Fields[1].Labels.String())
+			merged, ok := series[key]
+			if !ok || len(merged.Fields) != len(frame.Fields) {
+				series[key] = frame
+				frames = append(frames, frame)
+				continue
+			}
+			for i, field := range frame.Fields {
+				for row := 0; row < field.Len(); row++ {
+					merged.Fields[i].Append(field.At(row))
+				}
+			}
+			for _, notice := range frame.Meta.Notices {
+				if !hasNotice(merged.Meta.Notices, notice) {
+					merged.AppendNotices(notice)
+				}
+			}
+		}
+	}
+
+	if len(frames) == 0 && len(responses) > 0 {
+		// keep the empty frame the metadata is attached to
+		return responses[0].Frames
+	}
+	return frames
+}
+
+func hasNotice(notices []data.Notice, notice data.Notice) bool {
+	for _, n := range notices {
+		if n.Severity == notice.Severity && n.Text == notice.Text {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package querydata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/models"
+)
+
+func TestQuerySplitting_splitRange(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	q := &models.Query{Expr: "up", Start: start, End: start.Add(10 * time.Hour), Step: time.Hour, RangeQuery: true}
+
+	t.Run("does not split ranges shorter than the interval", func(t *testing.T) {
+		assert.Nil(t, querySplitting{}.splitRange(q))
+		assert.Nil(t, querySplitting{interval: 24 * time.Hour}.splitRange(q))
+	})
+
+	t.Run("splits the range in step aligned sub-intervals", func(t *testing.T) {
+		queries := querySplitting{interval: 4*time.Hour + 30*time.Minute}.splitRange(q)
+		require.Len(t, queries, 3)
+		assert.Equal(t, start, queries[0].Start)
+		assert.Equal(t, start.Add(3*time.Hour), queries[0].End)
+		assert.Equal(t, start.Add(4*time.Hour), queries[1].Start)
+		assert.Equal(t, start.Add(7*time.Hour), queries[1].End)
+		assert.Equal(t, start.Add(8*time.Hour), queries[2].Start)
+		assert.Equal(t, start.Add(10*time.Hour), queries[2].End)
+	})
+}
+
+func TestQueryData_splitRangeQuery(t *testing.T) {
+	var mtx sync.Mutex
+	var ranges [][2]int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		start, err := strconv.ParseInt(r.Form.Get("start"), 10, 64)
+		require.NoError(t, err)
+		end, err := strconv.ParseInt(r.Form.Get("end"), 10, 64)
+		require.NoError(t, err)
+		mtx.Lock()
+		ranges = append(ranges, [2]int64{start, end})
+		mtx.Unlock()
+
+		var values []string
+		for ts := start; ts <= end; ts += 3600 {
+			values = append(values, fmt.Sprintf(`[%d, "%d"]`, ts, ts))
+		}
+		_, err = fmt.Fprintf(w, `{"status": "success", "data": {"resultType": "matrix", "result": [
+			{"metric": {"job": "api"}, "values": [%s]}
+		]}}`, strings.Join(values, ","))
+		require.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	settings := backend.DataSourceInstanceSettings{
+		URL:      server.URL,
+		JSONData: json.RawMessage(`{"queryTimeSplitInterval": "4h", "queryTimeSplitConcurrency": 2}`),
+	}
+	qd, err := New(server.Client(), settings, log.New())
+	require.NoError(t, err)
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	res, err := qd.Execute(context.Background(), &backend.QueryDataRequest{
+		Queries: []backend.DataQuery{{
+			RefID:     "A",
+			JSON:      []byte(`{"expr": "up", "range": true, "interval": "1h"}`),
+			TimeRange: backend.TimeRange{From: from, To: from.Add(10 * time.Hour)},
+		}},
+	})
+	require.NoError(t, err)
+	dr := res.Responses["A"]
+	require.NoError(t, dr.Error)
+
+	assert.Len(t, ranges, 3)
+	require.Len(t, dr.Frames, 1)
+	frame := dr.Frames[0]
+	require.Equal(t, 11, frame.Fields[0].Len())
+	for i := 0; i < frame.Fields[0].Len(); i++ {
+		assert.Equal(t, from.Add(time.Duration(i)*time.Hour), frame.Fields[0].At(i))
+	}
+	assert.Equal(t, "Expr: up\nStep: 1h0m0s", frame.Meta.ExecutedQueryString)
+}
+
+func TestQueryData_splitRangeQueryFailure(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		if r.Form.Get("start") == strconv.FormatInt(from.Unix(), 10) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, err := w.Write([]byte(`{"status": "error", "errorType": "bad_data", "error": "invalid expression"}`))
+			require.NoError(t, err)
+			return
+		}
+		// the other sub-queries only complete when they are canceled
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+
+	settings := backend.DataSourceInstanceSettings{
+		URL:      server.URL,
+		JSONData: json.RawMessage(`{"queryTimeSplitInterval": "4h", "queryTimeSplitConcurrency": 3}`),
+	}
+	qd, err := New(server.Client(), settings, log.New())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := qd.Execute(ctx, &backend.QueryDataRequest{
+		Queries: []backend.DataQuery{{
+			RefID:     "A",
+			JSON:      []byte(`{"expr": "up", "range": true, "interval": "1h"}`),
+			TimeRange: backend.TimeRange{From: from, To: from.Add(10 * time.Hour)},
+		}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, ctx.Err(), "the other sub-queries were not canceled")
+	dr := res.Responses["A"]
+	require.Error(t, dr.Error)
+	assert.NotErrorIs(t, dr.Error, context.Canceled)
+}
@@ -0,0 +1,462 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// Severities of the issues found by Lint
+const (
+	LintSeverityError   = "error"
+	LintSeverityWarning = "warning"
+	LintSeverityInfo    = "info"
+)
+
+// seriesEstimateLimit is the maximum number of series counted for a selector
+const seriesEstimateLimit = 10000
+
+// defaultLintRange is the time range of the series estimation when the request does not have one
+const defaultLintRange = time.Hour
+
+// lintVariables replaces the Grafana interval and range variables, so that expressions of the query editor can
+// be parsed before they are interpolated.
+var lintVariables = strings.NewReplacer(
+	"${__rate_interval_ms}", "60000",
+	"$__rate_interval_ms", "60000",
+	"${__rate_interval}", "1m",
+	"$__rate_interval", "1m",
+	"${__interval_ms}", "60000",
+	"$__interval_ms", "60000",
+	"${__interval}", "1m",
+	"$__interval", "1m",
+	"${__range_ms}", "3600000",
+	"$__range_ms", "3600000",
+	"${__range_s}", "3600",
+	"$__range_s", "3600",
+	"${__range}", "1h",
+	"$__range", "1h",
+)
+
+// rateFunctions are the functions computing the rate of counters
+var rateFunctions = map[string]bool{"rate": true, "irate": true, "increase": true}
+
+// counterFunctions are the functions that can be used on counters without a rate
+var counterFunctions = map[string]bool{
+	"rate":              true,
+	"irate":             true,
+	"increase":          true,
+	"resets":            true,
+	"changes":           true,
+	"absent":            true,
+	"absent_over_time":  true,
+	"present_over_time": true,
+	"timestamp":         true,
+}
+
+type LintIssue struct {
+	Severity string `json:"severity"`
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+	Metric   string `json:"metric,omitempty"`
+}
+
+type SeriesEstimate struct {
+	Selector string `json:"selector"`
+	Count    int    `json:"count"`
+	// Limited is true when the count reached the limit of the estimation
+	Limited bool `json:"limited"`
+}
+
+type LintResult struct {
+	Issues          []LintIssue      `json:"issues"`
+	Series          []SeriesEstimate `json:"series"`
+	EstimatedSeries int              `json:"estimatedSeries"`
+}
+
+type lintRequest struct {
+	Expr  string  `json:"expr"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+type apiResponse struct {
+	Status string          `json:"status"`
+	Data   json.RawMessage `json:"data"`
+	Error  string          `json:"error"`
+}
+
+type metricMetadata struct {
+	Type string `json:"type"`
+}
+
+// Lint reports the issues of a PromQL expression and estimates the number of series it selects. The expression
+// and the time range of the estimation, in seconds since epoch, are read from the expr, start and end query
+// parameters or from the JSON body of the request.
+func (r *Resource) Lint(ctx context.Context, req *backend.CallResourceRequest) (*backend.CallResourceResponse, error) {
+	lintReq, err := parseLintRequest(req)
+	if err != nil {
+		return lintResponse(http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+
+	end := time.Now()
+	if lintReq.End > 0 {
+		end = secondsToTime(lintReq.End)
+	}
+	start := end.Add(-defaultLintRange)
+	if lintReq.Start > 0 {
+		start = secondsToTime(lintReq.Start)
+	}
+
+	return lintResponse(http.StatusOK, r.LintExpr(ctx, lintReq.Expr, start, end))
+}
+
+// LintExpr reports the issues of a PromQL expression and estimates the number of series it selects in the
+// time range. Issues found with the metadata of the metrics are only reported when Prometheus has it.
+func (r *Resource) LintExpr(ctx context.Context, expr string, start, end time.Time) *LintResult {
+	logger := r.log.FromContext(ctx)
+	result := &LintResult{Issues: []LintIssue{}, Series: []SeriesEstimate{}}
+
+	node, err := parser.ParseExpr(lintVariables.Replace(expr))
+	if err != nil {
+		result.Issues = append(result.Issues, LintIssue{Severity: LintSeverityError, Code: "parse-error", Message: err.Error()})
+		return result
+	}
+
+	types := make(map[string]string)
+	selectors := make(map[string]bool)
+	parser.Inspect(node, func(node parser.Node, path []parser.Node) error {
+		vs, ok := node.(*parser.VectorSelector)
+		if !ok {
+			return nil
+		}
+
+		for _, m := range vs.LabelMatchers {
+			if isUnboundedMatcher(m) {
+				result.Issues = append(result.Issues, LintIssue{
+					Severity: LintSeverityWarning,
+					Code:     "unbounded-regex",
+					Message:  fmt.Sprintf("label %q is matched by the unbounded regex %q, which must be evaluated against every value of the label", m.Name, m.Value),
+					Metric:   vs.Name,
+				})
+			}
+		}
+
+		selector := (&parser.VectorSelector{Name: vs.Name, LabelMatchers: vs.LabelMatchers}).String()
+		if !selectors[selector] {
+			selectors[selector] = true
+			result.Series = append(result.Series, r.estimateSeries(ctx, selector, start, end))
+		}
+
+		if vs.Name == "" {
+			return nil
+		}
+		metricType, ok := types[vs.Name]
+		if !ok {
+			metricType, err = r.metricType(ctx, vs.Name)
+			if err != nil {
+				logger.Warn("Failed to fetch metric metadata", "metric", vs.Name, "error", err)
+			}
+			types[vs.Name] = metricType
+		}
+		if issue, ok := typeIssue(vs.Name, metricType, path); ok {
+			result.Issues = append(result.Issues, issue)
+		}
+		return nil
+	})
+
+	for _, series := range result.Series {
+		result.EstimatedSeries += series.Count
+		if series.Limited {
+			result.Issues = append(result.Issues, LintIssue{
+				Severity: LintSeverityWarning,
+				Code:     "high-cardinality",
+				Message:  fmt.Sprintf("%s selects more than %d series", series.Selector, seriesEstimateLimit),
+			})
+		}
+	}
+	return result
+}
+
+// typeIssue returns the issue of the use of a metric of the type, given the path of its selector
+func typeIssue(metric, metricType string, path []parser.Node) (LintIssue, bool) {
+	call := enclosingCall(path)
+	switch metricType {
+	case "gauge":
+		if call != nil && rateFunctions[call.Func.Name] {
+			return LintIssue{
+				Severity: LintSeverityWarning,
+				Code:     "rate-over-gauge",
+				Message:  fmt.Sprintf("%s() should only be used with counters, but %s is a gauge. Use deriv() or delta() instead.", call.Func.Name, metric),
+				Metric:   metric,
+			}, true
+		}
+	case "counter":
+		if call != nil && counterFunctions[call.Func.Name] {
+			return LintIssue{}, false
+		}
+		for _, n := range path {
+			if agg, ok := n.(*parser.AggregateExpr); ok && (agg.Op == parser.COUNT || agg.Op == parser.GROUP) {
+				return LintIssue{}, false
+			}
+		}
+		return LintIssue{
+			Severity: LintSeverityWarning,
+			Code:     "counter-without-rate",
+			Message:  fmt.Sprintf("%s is a counter, its value is usually only meaningful with rate() or increase()", metric),
+			Metric:   metric,
+		}, true
+	}
+	return LintIssue{}, false
+}
+
+// enclosingCall returns the function call closest to a selector in its path
+func enclosingCall(path []parser.Node) *parser.Call {
+	for i := len(path) - 1; i >= 0; i-- {
+		if call, ok := path[i].(*parser.Call); ok {
+			return call
+		}
+	}
+	return nil
+}
+
+// metricType returns the type of the metric in its metadata. The series of classic histograms and summaries
+// and the counters of OpenMetrics targets are named after their metric family, so the family is looked up
+// when the metric has no metadata.
+func (r *Resource) metricType(ctx context.Context, metric string) (string, error) {
+	metricType, err := r.fetchMetricType(ctx, metric)
+	if err != nil || metricType != "" {
+		return metricType, err
+	}
+
+	for _, suffix := range []string{"_total", "_bucket", "_count", "_sum"} {
+		family, ok := strings.CutSuffix(metric, suffix)
+		if !ok {
+			continue
+		}
+		familyType, err := r.fetchMetricType(ctx, family)
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case suffix == "_total" && familyType == "counter":
+			return "counter", nil
+		case suffix != "_total" && (familyType == "histogram" || familyType == "summary"):
+			return "counter", nil
+		}
+	}
+	return "", nil
+}
+
+func (r *Resource) fetchMetricType(ctx context.Context, metric string) (string, error) {
+	var metadata map[string][]metricMetadata
+	if err := r.queryAPI(ctx, &metadata, func() (*http.Response, error) {
+		return r.promClient.QueryMetadata(ctx, metric)
+	}); err != nil {
+		return "", err
+	}
+
+	for _, m := range metadata[metric] {
+		if m.Type != "" && m.Type != "unknown" {
+			return m.Type, nil
+		}
+	}
+	return "", nil
+}
+
+// isUnboundedMatcher returns whether the regex of a matcher must be evaluated against every value of the label:
+// regexes with a branch starting with a wildcard, like ".*foo.*", and negative regexes matching any value, like "".
+func isUnboundedMatcher(m *labels.Matcher) bool {
+	switch m.Type {
+	case labels.MatchRegexp:
+		return isUnboundedRegex(m.Value)
+	case labels.MatchNotRegexp:
+		return m.Value == "" || isUnboundedRegex(m.Value)
+	default:
+		return false
+	}
+}
+
+func isUnboundedRegex(value string) bool {
+	re, err := syntax.Parse(value, syntax.Perl)
+	if err != nil {
+		return false
+	}
+	return startsWithWildcard(re.Simplify())
+}
+
+func startsWithWildcard(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpStar, syntax.OpPlus:
+		return re.Sub[0].Op == syntax.OpAnyChar || re.Sub[0].Op == syntax.OpAnyCharNotNL
+	case syntax.OpAlternate:
+		for _, sub := range re.Sub {
+			if startsWithWildcard(sub) {
+				return true
+			}
+		}
+		return false
+	case syntax.OpConcat, syntax.OpCapture:
+		return len(re.Sub) > 0 && startsWithWildcard(re.Sub[0])
+	default:
+		return false
+	}
+}
+
+func (r *Resource) estimateSeries(ctx context.Context, selector string, start, end time.Time) SeriesEstimate {
+	estimate := SeriesEstimate{Selector: selector}
+
+	resp, err := r.promClient.QuerySeries(ctx, selector, start, end, seriesEstimateLimit)
+	if err == nil {
+		defer func() {
+			if err := resp.Body.Close(); err != nil {
+				r.log.FromContext(ctx).Warn("Failed to close response body", "error", err)
+			}
+		}()
+		estimate.Count, err = countSeries(resp.Body, seriesEstimateLimit)
+	}
+	if err != nil {
+		r.log.FromContext(ctx).Warn("Failed to estimate series", "selector", selector, "error", err)
+		return SeriesEstimate{Selector: selector}
+	}
+
+	estimate.Limited = estimate.Count >= seriesEstimateLimit
+	return estimate
+}
+
+// countSeries counts the series of a series API response without decoding them, and stops reading at limit
+// series, as Prometheus versions without the limit parameter return every series.
+func countSeries(body io.Reader, limit int) (int, error) {
+	dec := json.NewDecoder(body)
+	if err := expectDelim(dec, '{'); err != nil {
+		return 0, err
+	}
+
+	var res apiResponse
+	count := 0
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return 0, err
+		}
+		switch key {
+		case "data":
+			if err := expectDelim(dec, '['); err != nil {
+				return 0, err
+			}
+			for dec.More() {
+				if count >= limit {
+					return count, nil
+				}
+				var series json.RawMessage
+				if err := dec.Decode(&series); err != nil {
+					return 0, err
+				}
+				count++
+			}
+			if err := expectDelim(dec, ']'); err != nil {
+				return 0, err
+			}
+		case "status":
+			err = dec.Decode(&res.Status)
+		case "error":
+			err = dec.Decode(&res.Error)
+		default:
+			var value json.RawMessage
+			err = dec.Decode(&value)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if res.Status != "success" {
+		return 0, fmt.Errorf("prometheus returned error: %s", res.Error)
+	}
+	return count, nil
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("unexpected response: expected %s, got %v", delim, token)
+	}
+	return nil
+}
+
+// queryAPI decodes the data of the response of a Prometheus API request into v
+func (r *Resource) queryAPI(ctx context.Context, v any, query func() (*http.Response, error)) error {
+	resp, err := query()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			r.log.FromContext(ctx).Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	var res apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return fmt.Errorf("unexpected response with status %s: %w", resp.Status, err)
+	}
+	if res.Status != "success" {
+		return fmt.Errorf("prometheus returned error: %s", res.Error)
+	}
+	return json.Unmarshal(res.Data, v)
+}
+
+func parseLintRequest(req *backend.CallResourceRequest) (lintRequest, error) {
+	var lintReq lintRequest
+	if len(req.Body) > 0 {
+		if err := json.Unmarshal(req.Body, &lintReq); err != nil {
+			return lintReq, fmt.Errorf("invalid request body: %w", err)
+		}
+	} else if u, err := url.Parse(req.URL); err == nil {
+		params := u.Query()
+		lintReq.Expr = params.Get("expr")
+		for name, value := range map[string]*float64{"start": &lintReq.Start, "end": &lintReq.End} {
+			if params.Get(name) == "" {
+				continue
+			}
+			if *value, err = strconv.ParseFloat(params.Get(name), 64); err != nil {
+				return lintReq, fmt.Errorf("invalid %s: %w", name, err)
+			}
+		}
+	}
+
+	if lintReq.Expr == "" {
+		return lintReq, fmt.Errorf("expr is required")
+	}
+	return lintReq, nil
+}
+
+func lintResponse(status int, v any) (*backend.CallResourceResponse, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return &backend.CallResourceResponse{
+		Status:  status,
+		Headers: map[string][]string{"Content-Type": {"application/json"}},
+		Body:    body,
+	}, nil
+}
+
+func secondsToTime(s float64) time.Time {
+	return time.Unix(0, int64(s*float64(time.Second)))
+}
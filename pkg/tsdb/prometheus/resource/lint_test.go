@@ -0,0 +1,176 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	"github.com/stretchr/testify/require"
+)
+
+func setupLintResource(t *testing.T, metadata map[string]string, series map[string]int) *Resource {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.NoError(t, req.ParseForm())
+		var data any
+		switch req.URL.Path {
+		case "/api/v1/metadata":
+			metric := req.Form.Get("metric")
+			m := map[string][]map[string]string{}
+			if metricType, ok := metadata[metric]; ok {
+				m[metric] = []map[string]string{{"type": metricType}}
+			}
+			data = m
+		case "/api/v1/series":
+			s := make([]map[string]string, series[req.Form.Get("match[]")])
+			for i := range s {
+				s[i] = map[string]string{"instance": fmt.Sprint(i)}
+			}
+			data = s
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]any{"status": "success", "data": data}))
+	}))
+	t.Cleanup(srv.Close)
+
+	r, err := New(srv.Client(), backend.DataSourceInstanceSettings{URL: srv.URL, JSONData: []byte(`{"httpMethod":"GET"}`)}, log.New())
+	require.NoError(t, err)
+	return r
+}
+
+func issueCodes(result *LintResult) []string {
+	codes := make([]string, 0, len(result.Issues))
+	for _, issue := range result.Issues {
+		codes = append(codes, issue.Code)
+	}
+	return codes
+}
+
+func TestLintExpr(t *testing.T) {
+	now := time.Now()
+	metadata := map[string]string{
+		"http_requests_total":      "counter",
+		"memory_bytes":             "gauge",
+		"request_duration_seconds": "histogram",
+	}
+
+	t.Run("reports rate over gauges", func(t *testing.T) {
+		r := setupLintResource(t, metadata, nil)
+		result := r.LintExpr(context.Background(), "rate(memory_bytes[5m])", now.Add(-time.Hour), now)
+		require.Equal(t, []string{"rate-over-gauge"}, issueCodes(result))
+		require.Equal(t, "memory_bytes", result.Issues[0].Metric)
+	})
+
+	t.Run("reports counters without rate", func(t *testing.T) {
+		r := setupLintResource(t, metadata, nil)
+		result := r.LintExpr(context.Background(), "sum(http_requests_total) / sum(rate(http_requests_total[$__rate_interval]))", now.Add(-time.Hour), now)
+		require.Equal(t, []string{"counter-without-rate"}, issueCodes(result))
+	})
+
+	t.Run("uses the type of the metric family of histogram series", func(t *testing.T) {
+		r := setupLintResource(t, metadata, nil)
+		result := r.LintExpr(context.Background(), "histogram_quantile(0.9, request_duration_seconds_bucket)", now.Add(-time.Hour), now)
+		require.Equal(t, []string{"counter-without-rate"}, issueCodes(result))
+
+		result = r.LintExpr(context.Background(), "histogram_quantile(0.9, sum by (le) (rate(request_duration_seconds_bucket[5m])))", now.Add(-time.Hour), now)
+		require.Empty(t, result.Issues)
+	})
+
+	t.Run("does not report metrics without metadata", func(t *testing.T) {
+		r := setupLintResource(t, metadata, nil)
+		result := r.LintExpr(context.Background(), "rate(unknown_metric[5m]) + unknown_metric", now.Add(-time.Hour), now)
+		require.Empty(t, result.Issues)
+	})
+
+	t.Run("reports unbounded regex matchers", func(t *testing.T) {
+		r := setupLintResource(t, metadata, nil)
+		result := r.LintExpr(context.Background(), `memory_bytes{job=~".*", instance=~"host-.*"}`, now.Add(-time.Hour), now)
+		require.Equal(t, []string{"unbounded-regex"}, issueCodes(result))
+		require.Contains(t, result.Issues[0].Message, `"job"`)
+	})
+
+	t.Run("reports regex matchers matching any value", func(t *testing.T) {
+		for _, matcher := range []string{`job=~".*api.*"`, `job=~"(?i).+api"`, `job=~"api|.*web"`, `job!~""`, `job!~".*test"`} {
+			t.Run(matcher, func(t *testing.T) {
+				r := setupLintResource(t, metadata, nil)
+				result := r.LintExpr(context.Background(), fmt.Sprintf("memory_bytes{%s}", matcher), now.Add(-time.Hour), now)
+				require.Equal(t, []string{"unbounded-regex"}, issueCodes(result))
+			})
+		}
+		for _, matcher := range []string{`job=~"api|web"`, `job=~"api-.*"`, `job!~"test"`, `job=""`, `job!=""`} {
+			t.Run(matcher, func(t *testing.T) {
+				r := setupLintResource(t, metadata, nil)
+				result := r.LintExpr(context.Background(), fmt.Sprintf("memory_bytes{%s}", matcher), now.Add(-time.Hour), now)
+				require.Empty(t, result.Issues)
+			})
+		}
+	})
+
+	t.Run("reports parse errors", func(t *testing.T) {
+		r := setupLintResource(t, metadata, nil)
+		result := r.LintExpr(context.Background(), "rate(memory_bytes[5m]", now.Add(-time.Hour), now)
+		require.Equal(t, []string{"parse-error"}, issueCodes(result))
+		require.Equal(t, LintSeverityError, result.Issues[0].Severity)
+	})
+
+	t.Run("estimates the series of each selector", func(t *testing.T) {
+		r := setupLintResource(t, metadata, map[string]int{
+			`memory_bytes{job="api"}`: 3,
+			// Prometheus versions without the limit parameter return every series
+			"http_requests_total": seriesEstimateLimit + 10,
+		})
+		result := r.LintExpr(context.Background(), `memory_bytes{job="api"} / on() group_left sum(rate(http_requests_total[5m])) + memory_bytes{job="api"} offset 1h`, now.Add(-time.Hour), now)
+		require.Equal(t, []SeriesEstimate{
+			{Selector: `memory_bytes{job="api"}`, Count: 3},
+			{Selector: "http_requests_total", Count: seriesEstimateLimit, Limited: true},
+		}, result.Series)
+		require.Equal(t, 3+seriesEstimateLimit, result.EstimatedSeries)
+		require.Equal(t, []string{"high-cardinality"}, issueCodes(result))
+	})
+}
+
+func TestLint(t *testing.T) {
+	r := setupLintResource(t, map[string]string{"memory_bytes": "gauge"}, map[string]int{"memory_bytes": 2})
+
+	t.Run("reads the expression from the query string", func(t *testing.T) {
+		resp, err := r.Lint(context.Background(), &backend.CallResourceRequest{
+			Path:   "lint",
+			Method: http.MethodGet,
+			URL:    "lint?expr=" + strings.ReplaceAll("irate(memory_bytes[1m])", "[", "%5B") + "&start=1700000000&end=1700003600",
+		})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.Status)
+
+		var result LintResult
+		require.NoError(t, json.Unmarshal(resp.Body, &result))
+		require.Equal(t, []string{"rate-over-gauge"}, issueCodes(&result))
+		require.Equal(t, 2, result.EstimatedSeries)
+	})
+
+	t.Run("reads the expression from the body", func(t *testing.T) {
+		resp, err := r.Lint(context.Background(), &backend.CallResourceRequest{
+			Path:   "lint",
+			Method: http.MethodPost,
+			Body:   []byte(`{"expr":"memory_bytes","start":1700000000,"end":1700003600}`),
+		})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `{"issues":[],"series":[{"selector":"memory_bytes","count":2,"limited":false}],"estimatedSeries":2}`, string(resp.Body))
+	})
+
+	t.Run("requires an expression", func(t *testing.T) {
+		resp, err := r.Lint(context.Background(), &backend.CallResourceRequest{Path: "lint", Method: http.MethodGet, URL: "lint"})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+	})
+}
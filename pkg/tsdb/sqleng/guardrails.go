@@ -0,0 +1,226 @@
+package sqleng
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrStatementNotAllowed is returned for queries that are rejected because the data source only allows read-only statements.
+var ErrStatementNotAllowed = errors.New("only read-only statements are allowed by this data source")
+
+// QueryGuardrails is implemented by dialects that can push query guardrails down to the database,
+// so that the database itself refuses writes and cancels runaway queries.
+type QueryGuardrails interface {
+	// ReadOnlyTransactions reports whether queries can be executed in read-only transactions.
+	ReadOnlyTransactions() bool
+	// SessionStatements returns the statements limiting the execution time and the memory used by the
+	// queries of the current transaction. Zero values mean no limit. The statements must not outlive
+	// the transaction, as the connection goes back to the pool afterwards.
+	SessionStatements(timeout time.Duration, memoryLimitMB int) []string
+}
+
+// QueryHinter is implemented by guardrails of databases that cannot scope a setting to a transaction,
+// and instead pass the execution time limit as an optimizer hint in the query itself.
+type QueryHinter interface {
+	// AddQueryHints returns the query with the hints limiting its execution time. Zero means no limit.
+	AddQueryHints(query string, timeout time.Duration) string
+}
+
+// readOnlyStatements are the keywords a read-only statement can start with.
+var readOnlyStatements = map[string]bool{
+	"SELECT":   true,
+	"WITH":     true,
+	"VALUES":   true,
+	"TABLE":    true,
+	"SHOW":     true,
+	"EXPLAIN":  true,
+	"DESCRIBE": true,
+	"DESC":     true,
+}
+
+// writeKeywords are keywords that make a statement modify data or schema wherever they appear,
+// e.g. in data-modifying common table expressions or SELECT ... INTO.
+var writeKeywords = map[string]bool{
+	"INSERT":   true,
+	"UPDATE":   true,
+	"DELETE":   true,
+	"MERGE":    true,
+	"UPSERT":   true,
+	"DROP":     true,
+	"CREATE":   true,
+	"ALTER":    true,
+	"TRUNCATE": true,
+	"GRANT":    true,
+	"REVOKE":   true,
+	"INTO":     true,
+	"COPY":     true,
+	"CALL":     true,
+	"EXEC":     true,
+	"EXECUTE":  true,
+}
+
+// sqlLexer describes how a SQL dialect quotes strings and comments.
+type sqlLexer struct {
+	backslashEscapes bool
+	hashComments     bool
+}
+
+// CheckReadOnlyStatement returns ErrStatementNotAllowed if the query contains anything other than
+// read-only statements. Dialects differ in how strings and comments are delimited, so the query is
+// checked with each known lexer and must be accepted by all of them.
+func CheckReadOnlyStatement(query string) error {
+	for _, lexer := range []sqlLexer{{}, {backslashEscapes: true, hashComments: true}} {
+		if err := lexer.checkReadOnly(query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l sqlLexer) checkReadOnly(query string) error {
+	atStatementStart := true
+	for _, token := range l.tokens(query) {
+		if token == ";" {
+			atStatementStart = true
+			continue
+		}
+
+		keyword := strings.ToUpper(token)
+		if atStatementStart {
+			if !readOnlyStatements[keyword] {
+				return fmt.Errorf("%w: %s statement", ErrStatementNotAllowed, keyword)
+			}
+			atStatementStart = false
+		}
+		if writeKeywords[keyword] {
+			return fmt.Errorf("%w: %s is not allowed", ErrStatementNotAllowed, keyword)
+		}
+	}
+	return nil
+}
+
+// tokens splits the query into words and statement separators, skipping strings, quoted
+// identifiers and comments.
+func (l sqlLexer) tokens(query string) []string {
+	var tokens []string
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case c == ';':
+			tokens = append(tokens, ";")
+			i++
+		case c == '\'' || c == '"' || c == '`':
+			i = l.skipQuoted(runes, i+1, c)
+		case c == '[':
+			i = skipUntil(runes, i+1, "]")
+		case c == '-' && i+1 < len(runes) && runes[i+1] == '-', c == '#' && l.hashComments:
+			i = skipUntil(runes, i+1, "\n")
+		case c == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			// MySQL executes the content of /*! ... */ comments, so it is checked like the rest of the query.
+			if i+2 < len(runes) && runes[i+2] == '!' {
+				i += 3
+				continue
+			}
+			i = skipUntil(runes, i+2, "*/")
+		case isWordRune(c):
+			start := i
+			for i < len(runes) && isWordRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		default:
+			i++
+		}
+	}
+	return tokens
+}
+
+func (l sqlLexer) skipQuoted(runes []rune, i int, quote rune) int {
+	for i < len(runes) {
+		switch {
+		case l.backslashEscapes && runes[i] == '\\':
+			i += 2
+		case runes[i] == quote:
+			// A doubled quote is an escaped quote.
+			if i+1 < len(runes) && runes[i+1] == quote {
+				i += 2
+				continue
+			}
+			return i + 1
+		default:
+			i++
+		}
+	}
+	return i
+}
+
+func skipUntil(runes []rune, i int, end string) int {
+	idx := strings.Index(string(runes[i:]), end)
+	if idx < 0 {
+		return len(runes)
+	}
+	return i + len([]rune(string(runes[i:])[:idx])) + len([]rune(end))
+}
+
+func isWordRune(c rune) bool {
+	return c == '_' || c == '$' || unicode.IsLetter(c) || unicode.IsDigit(c)
+}
+
+// guarded reports whether queries of this data source need to run in a transaction set up by the guardrails.
+func (e *DataSourceHandler) guarded() bool {
+	if e.guardrails == nil {
+		return false
+	}
+	jsonData := e.dsInfo.JsonData
+	if jsonData.EnforceReadOnly && e.guardrails.ReadOnlyTransactions() {
+		return true
+	}
+	return len(e.guardrails.SessionStatements(time.Duration(jsonData.QueryTimeout)*time.Second, jsonData.QueryMemoryLimit)) > 0
+}
+
+// queryRows executes the query, inside a transaction applying the configured guardrails if there are any.
+// The returned function must be called once the rows have been closed.
+func (e *DataSourceHandler) queryRows(ctx context.Context, query string) (*sql.Rows, func(), error) {
+	if hinter, ok := e.guardrails.(QueryHinter); ok {
+		query = hinter.AddQueryHints(query, time.Duration(e.dsInfo.JsonData.QueryTimeout)*time.Second)
+	}
+
+	if !e.guarded() {
+		rows, err := e.db.QueryContext(ctx, query)
+		return rows, func() {}, err
+	}
+
+	jsonData := e.dsInfo.JsonData
+	tx, err := e.db.BeginTx(ctx, &sql.TxOptions{
+		ReadOnly: jsonData.EnforceReadOnly && e.guardrails.ReadOnlyTransactions(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	// Nothing is ever committed: the transaction only scopes the guardrails to this query.
+	rollback := func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			e.log.FromContext(ctx).Warn("Failed to roll back query transaction", "err", err)
+		}
+	}
+
+	for _, stmt := range e.guardrails.SessionStatements(time.Duration(jsonData.QueryTimeout)*time.Second, jsonData.QueryMemoryLimit) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			rollback()
+			return nil, nil, fmt.Errorf("failed to apply query limits: %w", err)
+		}
+	}
+
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		rollback()
+		return nil, nil, err
+	}
+	return rows, rollback, nil
+}
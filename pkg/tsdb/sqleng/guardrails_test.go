@@ -0,0 +1,248 @@
+package sqleng
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckReadOnlyStatement(t *testing.T) {
+	allowed := []string{
+		"SELECT 1",
+		"select time, value from metrics where time > now() - interval '1 hour'",
+		"WITH t AS (SELECT 1 AS a) SELECT a FROM t",
+		"(SELECT 1) UNION (SELECT 2)",
+		"SELECT 1; SELECT 2;",
+		"SHOW TABLES",
+		"EXPLAIN SELECT 1",
+		"SELECT 'DROP TABLE users' AS text",
+		"SELECT \"delete\" FROM t",
+		"SELECT `update` FROM t",
+		"SELECT [insert] FROM t",
+		"SELECT 1 -- DELETE FROM t\n",
+		"SELECT /* INSERT INTO t */ 1",
+		"SELECT replace(name, 'a', 'b') FROM t",
+		"SELECT 'it''s' FROM t",
+		"SELECT name FROM t FOR SHARE",
+	}
+	for _, query := range allowed {
+		assert.NoError(t, CheckReadOnlyStatement(query), query)
+	}
+
+	rejected := []string{
+		"DELETE FROM t",
+		"insert into t values (1)",
+		"UPDATE t SET a = 1",
+		"DROP TABLE t",
+		"TRUNCATE t",
+		"SET ROLE admin",
+		"SELECT 1; DROP TABLE t",
+		"WITH d AS (DELETE FROM t RETURNING *) SELECT * FROM d",
+		"SELECT * INTO backup FROM t",
+		"SELECT * FROM t FOR UPDATE",
+		"SELECT 1 /*! ; DROP TABLE t */",
+		// Backslashes escape quotes in MySQL strings but not in Postgres strings.
+		"SELECT 'a\\'; DELETE FROM t; -- '",
+		"SELECT '\\'' ; DELETE FROM t ; -- '",
+		// # starts a comment in MySQL but is an operator in Postgres.
+		"SELECT 1 # 2; DELETE FROM t",
+		"CALL cleanup()",
+		"EXEC sp_cleanup",
+	}
+	for _, query := range rejected {
+		assert.ErrorIs(t, CheckReadOnlyStatement(query), ErrStatementNotAllowed, query)
+	}
+}
+
+func TestQueryRows(t *testing.T) {
+	newHandler := func(t *testing.T, drv *recordingDriver, guardrails QueryGuardrails, jsonData JsonData) *DataSourceHandler {
+		db := openRecordingDB(t, drv)
+		return &DataSourceHandler{
+			db:         db,
+			log:        backend.NewLoggerWith("logger", "test"),
+			dsInfo:     DataSourceInfo{JsonData: jsonData},
+			guardrails: guardrails,
+		}
+	}
+
+	t.Run("runs read-only data sources in a read-only transaction that is rolled back", func(t *testing.T) {
+		drv := &recordingDriver{}
+		e := newHandler(t, drv, testGuardrails{readOnly: true}, JsonData{EnforceReadOnly: true, QueryTimeout: 5})
+
+		rows, release, err := e.queryRows(context.Background(), "SELECT 1")
+		require.NoError(t, err)
+		require.NoError(t, rows.Close())
+		release()
+
+		assert.Equal(t, []string{
+			"BEGIN READ ONLY",
+			"EXEC SET LOCAL statement_timeout = 5000",
+			"QUERY SELECT 1",
+			"ROLLBACK",
+		}, drv.log())
+	})
+
+	t.Run("does not use a transaction without guardrails to apply", func(t *testing.T) {
+		drv := &recordingDriver{}
+		e := newHandler(t, drv, testGuardrails{readOnly: true}, JsonData{})
+
+		rows, release, err := e.queryRows(context.Background(), "SELECT 1")
+		require.NoError(t, err)
+		require.NoError(t, rows.Close())
+		release()
+
+		assert.Equal(t, []string{"QUERY SELECT 1"}, drv.log())
+	})
+
+	t.Run("rolls back when the session statements fail", func(t *testing.T) {
+		drv := &recordingDriver{execErr: errors.New("unknown variable")}
+		e := newHandler(t, drv, testGuardrails{readOnly: true}, JsonData{EnforceReadOnly: true, QueryTimeout: 5})
+
+		_, _, err := e.queryRows(context.Background(), "SELECT 1")
+		require.ErrorContains(t, err, "failed to apply query limits")
+
+		assert.Equal(t, []string{
+			"BEGIN READ ONLY",
+			"EXEC SET LOCAL statement_timeout = 5000",
+			"ROLLBACK",
+		}, drv.log())
+	})
+
+	t.Run("adds query hints", func(t *testing.T) {
+		drv := &recordingDriver{}
+		e := newHandler(t, drv, hintingGuardrails{}, JsonData{QueryTimeout: 5})
+
+		rows, release, err := e.queryRows(context.Background(), "SELECT 1")
+		require.NoError(t, err)
+		require.NoError(t, rows.Close())
+		release()
+
+		assert.Equal(t, []string{"QUERY SELECT 1 /* timeout 5s */"}, drv.log())
+	})
+}
+
+type testGuardrails struct {
+	readOnly bool
+}
+
+func (g testGuardrails) ReadOnlyTransactions() bool {
+	return g.readOnly
+}
+
+func (testGuardrails) SessionStatements(timeout time.Duration, _ int) []string {
+	if timeout <= 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("SET LOCAL statement_timeout = %d", timeout.Milliseconds())}
+}
+
+type hintingGuardrails struct{}
+
+func (hintingGuardrails) ReadOnlyTransactions() bool { return false }
+
+func (hintingGuardrails) SessionStatements(time.Duration, int) []string { return nil }
+
+func (hintingGuardrails) AddQueryHints(query string, timeout time.Duration) string {
+	return fmt.Sprintf("%s /* timeout %s */", query, timeout)
+}
+
+var recordingDriverCount atomic.Int64
+
+// openRecordingDB opens a database whose driver records the statements and transactions it receives.
+func openRecordingDB(t *testing.T, drv *recordingDriver) *sql.DB {
+	t.Helper()
+	name := fmt.Sprintf("sqleng-recording-%d", recordingDriverCount.Add(1))
+	sql.Register(name, drv)
+	db, err := sql.Open(name, "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+type recordingDriver struct {
+	execErr error
+
+	mu      sync.Mutex
+	entries []string
+}
+
+func (d *recordingDriver) record(entry string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.entries = append(d.entries, entry)
+}
+
+func (d *recordingDriver) log() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.entries...)
+}
+
+func (d *recordingDriver) Open(string) (driver.Conn, error) {
+	return &recordingConn{driver: d}, nil
+}
+
+type recordingConn struct {
+	driver *recordingDriver
+}
+
+func (c *recordingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *recordingConn) Close() error { return nil }
+
+func (c *recordingConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *recordingConn) BeginTx(_ context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if opts.ReadOnly {
+		c.driver.record("BEGIN READ ONLY")
+	} else {
+		c.driver.record("BEGIN")
+	}
+	return c, nil
+}
+
+func (c *recordingConn) Commit() error {
+	c.driver.record("COMMIT")
+	return nil
+}
+
+func (c *recordingConn) Rollback() error {
+	c.driver.record("ROLLBACK")
+	return nil
+}
+
+func (c *recordingConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.driver.record("EXEC " + query)
+	if c.driver.execErr != nil {
+		return nil, c.driver.execErr
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (c *recordingConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.driver.record("QUERY " + query)
+	return &emptyRows{}, nil
+}
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string { return []string{"value"} }
+
+func (emptyRows) Close() error { return nil }
+
+func (emptyRows) Next([]driver.Value) error { return io.EOF }
@@ -59,6 +59,9 @@ type JsonData struct {
 	SecureDSProxyUsername   string `json:"secureSocksProxyUsername"`
 	AllowCleartextPasswords bool   `json:"allowCleartextPasswords"`
 	AuthenticationType      string `json:"authenticationType"`
+	EnforceReadOnly         bool   `json:"enforceReadOnly"`
+	QueryTimeout            int    `json:"queryTimeout"`
+	QueryMemoryLimit        int    `json:"queryMemoryLimit"`
 }
 
 type DataSourceInfo struct {
@@ -77,6 +80,7 @@ type DataPluginConfiguration struct {
 	TimeColumnNames   []string
 	MetricColumnTypes []string
 	RowLimit          int64
+	Guardrails        QueryGuardrails
 }
 
 type DataSourceHandler struct {
@@ -89,6 +93,7 @@ type DataSourceHandler struct {
 	dsInfo                 DataSourceInfo
 	rowLimit               int64
 	userError              string
+	guardrails             QueryGuardrails
 }
 
 type QueryJson struct {
@@ -124,6 +129,7 @@ func NewQueryDataHandler(userFacingDefaultError string, db *sql.DB, config DataP
 		dsInfo:                 config.DSInfo,
 		rowLimit:               config.RowLimit,
 		userError:              userFacingDefaultError,
+		guardrails:             config.Guardrails,
 	}
 
 	if len(config.TimeColumnNames) > 0 {
@@ -248,11 +254,19 @@ func (e *DataSourceHandler) executeQuery(query backend.DataQuery, wg *sync.WaitG
 		return
 	}
 
-	rows, err := e.db.QueryContext(queryContext, interpolatedQuery)
+	if e.dsInfo.JsonData.EnforceReadOnly {
+		if err := CheckReadOnlyStatement(interpolatedQuery); err != nil {
+			errAppendDebug("query rejected", err, interpolatedQuery)
+			return
+		}
+	}
+
+	rows, release, err := e.queryRows(queryContext, interpolatedQuery)
 	if err != nil {
 		errAppendDebug("db query error", e.TransformQueryError(logger, err), interpolatedQuery)
 		return
 	}
+	defer release()
 	defer func() {
 		if err := rows.Close(); err != nil {
 			logger.Warn("Failed to close rows", "err", err)
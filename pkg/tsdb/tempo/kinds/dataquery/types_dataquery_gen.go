@@ -25,14 +25,16 @@ const (
 
 // Defines values for TempoQueryType.
 const (
-	TempoQueryTypeClear         TempoQueryType = "clear"
-	TempoQueryTypeNativeSearch  TempoQueryType = "nativeSearch"
-	TempoQueryTypeSearch        TempoQueryType = "search"
-	TempoQueryTypeServiceMap    TempoQueryType = "serviceMap"
-	TempoQueryTypeTraceId       TempoQueryType = "traceId"
-	TempoQueryTypeTraceql       TempoQueryType = "traceql"
-	TempoQueryTypeTraceqlSearch TempoQueryType = "traceqlSearch"
-	TempoQueryTypeUpload        TempoQueryType = "upload"
+	TempoQueryTypeClear             TempoQueryType = "clear"
+	TempoQueryTypeNativeSearch      TempoQueryType = "nativeSearch"
+	TempoQueryTypeSearch            TempoQueryType = "search"
+	TempoQueryTypeServiceMap        TempoQueryType = "serviceMap"
+	TempoQueryTypeTraceId           TempoQueryType = "traceId"
+	TempoQueryTypeTraceql           TempoQueryType = "traceql"
+	TempoQueryTypeTraceqlMetrics    TempoQueryType = "traceqlMetrics"
+	TempoQueryTypeTraceqlSearch     TempoQueryType = "traceqlSearch"
+	TempoQueryTypeTraceqlServiceMap TempoQueryType = "traceqlServiceMap"
+	TempoQueryTypeUpload            TempoQueryType = "upload"
 )
 
 // Defines values for TraceqlSearchScope.
@@ -138,6 +140,9 @@ type TempoQuery struct {
 	// Defines the maximum number of spans per spanset that are returned from Tempo
 	Spss *int64 `json:"spss,omitempty"`
 
+	// Step of TraceQL metrics queries. Use duration format, for example: 30s, 1m
+	Step *string `json:"step,omitempty"`
+
 	// The type of the table that is used to display the search results
 	TableType *SearchTableType `json:"tableType,omitempty"`
 }
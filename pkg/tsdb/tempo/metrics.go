@@ -0,0 +1,255 @@
+package tempo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/tracing"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/tsdb/tempo/kinds/dataquery"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// metricsFnRegex matches the metrics functions of TraceQL, like the query editor does to detect metrics queries
+var metricsFnRegex = regexp.MustCompile(`\|\s*(rate|count_over_time|avg_over_time|max_over_time|min_over_time|quantile_over_time)\s*\(`)
+
+// MetricsQueryRangeResponse is the response of the TraceQL metrics query range API
+type MetricsQueryRangeResponse struct {
+	Series []MetricsSeries `json:"series"`
+}
+
+type MetricsSeries struct {
+	Labels     []MetricsSeriesLabel  `json:"labels"`
+	Samples    []MetricsSeriesSample `json:"samples"`
+	PromLabels string                `json:"promLabels"`
+}
+
+type MetricsSeriesLabel struct {
+	Key   string       `json:"key"`
+	Value MetricsValue `json:"value"`
+}
+
+// MetricsValue is a label value. Integers are encoded as strings.
+type MetricsValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    any     `json:"intValue,omitempty"`
+	DoubleValue any     `json:"doubleValue,omitempty"`
+	BoolValue   *bool   `json:"boolValue,omitempty"`
+}
+
+// MetricsSeriesSample is a sample of a series. Timestamps are encoded as strings, and values that are not
+// finite too.
+type MetricsSeriesSample struct {
+	TimestampMs json.Number `json:"timestampMs"`
+	Value       any         `json:"value"`
+}
+
+func isTraceQLMetricsQuery(query string) bool {
+	return metricsFnRegex.MatchString(strings.TrimSpace(query))
+}
+
+func (s *Service) runTraceQLMetricsQuery(ctx context.Context, pCtx backend.PluginContext, query backend.DataQuery) (*backend.DataResponse, error) {
+	ctxLogger := s.logger.FromContext(ctx)
+	ctxLogger.Debug("Running TraceQL metrics query", "function", logEntrypoint())
+
+	result := &backend.DataResponse{}
+
+	ctx, span := tracing.DefaultTracer().Start(ctx, "datasource.tempo.runTraceQLMetricsQuery", trace.WithAttributes(
+		attribute.String("queryType", query.QueryType),
+	))
+	defer span.End()
+
+	model := &dataquery.TempoQuery{}
+	if err := json.Unmarshal(query.JSON, model); err != nil {
+		ctxLogger.Error("Failed to unmarshall Tempo query model", "error", err, "function", logEntrypoint())
+		return result, err
+	}
+
+	if model.Query == nil || *model.Query == "" {
+		err := fmt.Errorf("query is required")
+		ctxLogger.Error("Failed to validate model query", "error", err, "function", logEntrypoint())
+		return result, err
+	}
+
+	dsInfo, err := s.getDSInfo(ctx, pCtx)
+	if err != nil {
+		ctxLogger.Error("Failed to get datasource information", "error", err, "function", logEntrypoint())
+		return nil, err
+	}
+
+	step := metricsStep(model, query.Interval)
+	metrics, err := s.queryMetricsRange(ctx, dsInfo, *model.Query, query.TimeRange.From, query.TimeRange.To, step)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		result.Error = err
+		return result, nil
+	}
+
+	result.Frames = metricsResponseToFrames(*model.Query, query.RefID, metrics)
+	return result, nil
+}
+
+// queryMetricsRange runs a TraceQL metrics query on the time range. The step is chosen by Tempo when it is empty.
+func (s *Service) queryMetricsRange(ctx context.Context, dsInfo *Datasource, query string, start, end time.Time, step string) (*MetricsQueryRangeResponse, error) {
+	ctxLogger := s.logger.FromContext(ctx)
+
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("start", strconv.FormatInt(start.Unix(), 10))
+	params.Set("end", strconv.FormatInt(end.Unix(), 10))
+	if step != "" {
+		params.Set("step", step)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/metrics/query_range?%s", dsInfo.URL, params.Encode()), nil)
+	if err != nil {
+		ctxLogger.Error("Failed to create request", "error", err, "function", logEntrypoint())
+		return nil, err
+	}
+	request.Header.Set("Accept", "application/json")
+
+	resp, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		ctxLogger.Error("Failed to send request to Tempo", "error", err, "function", logEntrypoint())
+		return nil, fmt.Errorf("failed get to tempo: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			ctxLogger.Error("Failed to close response body", "error", err, "function", logEntrypoint())
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		ctxLogger.Error("Failed to read response body", "error", err, "function", logEntrypoint())
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to run TraceQL metrics query: %s Status: %s Body: %s", query, resp.Status, string(body))
+	}
+
+	metrics := &MetricsQueryRangeResponse{}
+	if err := json.Unmarshal(body, metrics); err != nil {
+		ctxLogger.Error("Failed to unmarshal TraceQL metrics response", "error", err, "function", logEntrypoint())
+		return nil, fmt.Errorf("failed to unmarshal TraceQL metrics response: %w", err)
+	}
+	return metrics, nil
+}
+
+// metricsStep returns the step of the query, which defaults to the interval of the query
+func metricsStep(model *dataquery.TempoQuery, interval time.Duration) string {
+	if model.Step != nil && *model.Step != "" {
+		return *model.Step
+	}
+	if interval > 0 {
+		return interval.String()
+	}
+	return ""
+}
+
+// metricsResponseToFrames converts the series of a TraceQL metrics response into time series frames, with the
+// labels of the series as labels of the value field. Series are named like in the query editor.
+func metricsResponseToFrames(query string, refID string, metrics *MetricsQueryRangeResponse) data.Frames {
+	frames := make(data.Frames, 0, len(metrics.Series))
+	for _, series := range metrics.Series {
+		labels := data.Labels{}
+		names := make([]string, 0, len(series.Labels))
+		for _, label := range series.Labels {
+			labels[label.Key] = label.Value.String()
+			names = append(names, fmt.Sprintf("%s=%s", label.Key, label.Value.displayString()))
+		}
+
+		name := ""
+		switch {
+		case len(series.Labels) == 1:
+			name = series.Labels[0].Value.displayString()
+		case len(series.Labels) > 1:
+			name = "{" + strings.Join(names, ", ") + "}"
+		case len(metrics.Series) == 1:
+			name = query
+		}
+
+		samples := append([]MetricsSeriesSample(nil), series.Samples...)
+		sort.SliceStable(samples, func(i, j int) bool {
+			return samples[i].timestamp().Before(samples[j].timestamp())
+		})
+		times := make([]time.Time, 0, len(samples))
+		values := make([]float64, 0, len(samples))
+		for _, sample := range samples {
+			times = append(times, sample.timestamp())
+			values = append(values, sample.value())
+		}
+
+		valueField := data.NewField(data.TimeSeriesValueFieldName, labels, values)
+		if name != "" {
+			valueField.Config = &data.FieldConfig{DisplayNameFromDS: name}
+		}
+		frame := data.NewFrame(name, data.NewField(data.TimeSeriesTimeFieldName, nil, times), valueField)
+		frame.RefID = refID
+		frame.Meta = &data.FrameMeta{
+			Type:                   data.FrameTypeTimeSeriesMulti,
+			PreferredVisualization: data.VisTypeGraph,
+		}
+		frames = append(frames, frame)
+	}
+	return frames
+}
+
+// String returns the value of the label
+func (v MetricsValue) String() string {
+	switch {
+	case v.StringValue != nil:
+		return *v.StringValue
+	case v.IntValue != nil:
+		return fmt.Sprint(v.IntValue)
+	case v.DoubleValue != nil:
+		return fmt.Sprint(v.DoubleValue)
+	case v.BoolValue != nil:
+		return strconv.FormatBool(*v.BoolValue)
+	}
+	return ""
+}
+
+// displayString returns the value of the label as displayed in the name of the series, with strings quoted
+func (v MetricsValue) displayString() string {
+	if v.StringValue != nil || v.String() == "" {
+		return strconv.Quote(v.String())
+	}
+	return v.String()
+}
+
+func (s MetricsSeriesSample) timestamp() time.Time {
+	ms, err := s.TimestampMs.Int64()
+	if err != nil {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms).UTC()
+}
+
+func (s MetricsSeriesSample) value() float64 {
+	switch v := s.Value.(type) {
+	case float64:
+		return v
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		if err == nil {
+			return f
+		}
+	}
+	return math.NaN()
+}
@@ -0,0 +1,193 @@
+package tempo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/tracing"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/tsdb/tempo/kinds/dataquery"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const MetricsPathPrefix = "metrics/"
+
+// metricsStreamChunks is the number of sub-ranges a streamed TraceQL metrics query is split into. The partial
+// result is sent after each of them.
+const metricsStreamChunks = 4
+
+// metricsRangeFetcher runs a TraceQL metrics query on a sub-range of the query
+type metricsRangeFetcher func(ctx context.Context, start, end time.Time) (*MetricsQueryRangeResponse, error)
+
+func (s *Service) runMetricsStream(ctx context.Context, req *backend.RunStreamRequest, sender *backend.StreamSender, datasource *Datasource) error {
+	ctx, span := tracing.DefaultTracer().Start(ctx, "datasource.tempo.runMetricsStream")
+	defer span.End()
+
+	var backendQuery *backend.DataQuery
+	if err := json.Unmarshal(req.Data, &backendQuery); err != nil {
+		err = fmt.Errorf("error unmarshaling backend query model: %v", err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	model := &dataquery.TempoQuery{}
+	if err := json.Unmarshal(req.Data, model); err != nil {
+		err = fmt.Errorf("error unmarshaling Tempo query model: %v", err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	if model.Query == nil || *model.Query == "" {
+		return fmt.Errorf("query is empty")
+	}
+	query := *model.Query
+	step := metricsStep(model, backendQuery.Interval)
+
+	fetch := func(ctx context.Context, start, end time.Time) (*MetricsQueryRangeResponse, error) {
+		return s.queryMetricsRange(ctx, datasource, query, start, end, step)
+	}
+	return s.processMetricsStream(ctx, query, backendQuery.RefID, backendQuery.TimeRange, step, fetch, sender)
+}
+
+// processMetricsStream runs the query on consecutive sub-ranges of the time range, and sends the frames of the
+// sub-ranges completed so far after each of them
+func (s *Service) processMetricsStream(ctx context.Context, query, refID string, tr backend.TimeRange, step string, fetch metricsRangeFetcher, sender StreamSender) error {
+	ctx, span := tracing.DefaultTracer().Start(ctx, "datasource.tempo.processMetricsStream")
+	defer span.End()
+
+	var stepDuration time.Duration
+	if step != "" {
+		d, err := gtime.ParseDuration(step)
+		if err != nil {
+			return fmt.Errorf("invalid step %q: %w", step, err)
+		}
+		stepDuration = d
+	}
+
+	ranges := splitMetricsRange(tr.From, tr.To, stepDuration, metricsStreamChunks)
+	span.SetAttributes(attribute.Int("chunks", len(ranges)))
+
+	merged := &MetricsQueryRangeResponse{}
+	for i, r := range ranges {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		res, err := fetch(ctx, r.From, r.To)
+		if err != nil {
+			s.logger.Error("Error running TraceQL metrics query", "err", err)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
+		mergeMetricsResponse(merged, res)
+
+		state := dataquery.SearchStreamingStateStreaming
+		if i == len(ranges)-1 {
+			state = dataquery.SearchStreamingStateDone
+		}
+		if err := sendMetricsResponse(metricsResponseToFrames(query, refID, merged), state, sender); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
+	}
+	return nil
+}
+
+// splitMetricsRange splits the time range into consecutive sub-ranges, whose length is a multiple of the step
+func splitMetricsRange(from, to time.Time, step time.Duration, chunks int) []backend.TimeRange {
+	length := to.Sub(from) / time.Duration(chunks)
+	if step > 0 {
+		length = (length + step - 1) / step * step
+	}
+	if length < time.Second {
+		return []backend.TimeRange{{From: from, To: to}}
+	}
+
+	var ranges []backend.TimeRange
+	for start := from; start.Before(to); start = start.Add(length) {
+		end := start.Add(length)
+		if end.After(to) {
+			end = to
+		}
+		ranges = append(ranges, backend.TimeRange{From: start, To: end})
+	}
+	return ranges
+}
+
+// mergeMetricsResponse appends the samples of the series of a sub-range to the series of the previous ones.
+// Samples at the bounds of the sub-ranges can be returned for both of them, so those are only appended once.
+func mergeMetricsResponse(merged *MetricsQueryRangeResponse, res *MetricsQueryRangeResponse) {
+	for _, series := range res.Series {
+		idx := -1
+		for i, m := range merged.Series {
+			if metricsSeriesKey(m) == metricsSeriesKey(series) {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			merged.Series = append(merged.Series, series)
+			continue
+		}
+
+		seen := make(map[string]bool, len(merged.Series[idx].Samples))
+		for _, sample := range merged.Series[idx].Samples {
+			seen[sample.TimestampMs.String()] = true
+		}
+		for _, sample := range series.Samples {
+			if !seen[sample.TimestampMs.String()] {
+				merged.Series[idx].Samples = append(merged.Series[idx].Samples, sample)
+			}
+		}
+	}
+}
+
+func metricsSeriesKey(series MetricsSeries) string {
+	if series.PromLabels != "" {
+		return series.PromLabels
+	}
+	key := ""
+	for _, label := range series.Labels {
+		key += label.Key + "=" + label.Value.displayString() + ","
+	}
+	return key
+}
+
+func sendMetricsResponse(frames data.Frames, state dataquery.SearchStreamingState, sender StreamSender) error {
+	framesAsJson, err := json.Marshal(&frames)
+	if err != nil {
+		return err
+	}
+
+	frame := createMetricsResponseDataFrame()
+	frame.Fields[0].Append(json.RawMessage(framesAsJson))
+	frame.Fields[1].Append(string(state))
+	frame.Fields[2].Append("")
+	return sender.SendFrame(frame, data.IncludeAll)
+}
+
+func sendMetricsError(metricsErr error, sender StreamSender) error {
+	frame := createMetricsResponseDataFrame()
+	frame.Fields[0].Append(json.RawMessage{})
+	frame.Fields[1].Append(string(dataquery.SearchStreamingStateError))
+	frame.Fields[2].Append(metricsErr.Error())
+	return sender.SendFrame(frame, data.IncludeAll)
+}
+
+func createMetricsResponseDataFrame() *data.Frame {
+	frame := data.NewFrame("response")
+	frame.Fields = append(frame.Fields, data.NewField("frames", nil, []json.RawMessage{}))
+	frame.Fields = append(frame.Fields, data.NewField("state", nil, []string{}))
+	frame.Fields = append(frame.Fields, data.NewField("error", nil, []string{}))
+
+	return frame
+}
@@ -0,0 +1,162 @@
+package tempo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/tsdb/tempo/kinds/dataquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const metricsResponseJSON = `{
+  "series": [
+    {
+      "labels": [{"key": "resource.service.name", "value": {"stringValue": "api"}}],
+      "samples": [{"timestampMs": "1700000060000", "value": 2}, {"timestampMs": "1700000000000", "value": 1.5}],
+      "promLabels": "{resource.service.name=\"api\"}"
+    },
+    {
+      "labels": [
+        {"key": "resource.service.name", "value": {"stringValue": "db"}},
+        {"key": "span.http.status_code", "value": {"intValue": "500"}}
+      ],
+      "samples": [{"timestampMs": "1700000000000", "value": "NaN"}],
+      "promLabels": "{resource.service.name=\"db\", span.http.status_code=500}"
+    }
+  ],
+  "metrics": {"inspectedTraces": 10}
+}`
+
+func TestTraceQLMetrics(t *testing.T) {
+	t.Run("isTraceQLMetricsQuery", func(t *testing.T) {
+		assert.True(t, isTraceQLMetricsQuery("{ } | rate() by (resource.service.name)"))
+		assert.True(t, isTraceQLMetricsQuery("{ status = error } | quantile_over_time(duration, .9)"))
+		assert.False(t, isTraceQLMetricsQuery("{ span.rate = 5 }"))
+	})
+
+	t.Run("metricsResponseToFrames", func(t *testing.T) {
+		metrics := &MetricsQueryRangeResponse{}
+		require.NoError(t, json.Unmarshal([]byte(metricsResponseJSON), metrics))
+
+		frames := metricsResponseToFrames("{ } | rate()", "A", metrics)
+		require.Len(t, frames, 2)
+
+		frame := frames[0]
+		assert.Equal(t, "A", frame.RefID)
+		assert.Equal(t, data.FrameTypeTimeSeriesMulti, frame.Meta.Type)
+		assert.Equal(t, []time.Time{time.UnixMilli(1700000000000).UTC(), time.UnixMilli(1700000060000).UTC()}, []time.Time{frame.Fields[0].At(0).(time.Time), frame.Fields[0].At(1).(time.Time)})
+		assert.Equal(t, 1.5, frame.Fields[1].At(0))
+		assert.Equal(t, 2.0, frame.Fields[1].At(1))
+		assert.Equal(t, data.Labels{"resource.service.name": "api"}, frame.Fields[1].Labels)
+		assert.Equal(t, `"api"`, frame.Fields[1].Config.DisplayNameFromDS)
+
+		frame = frames[1]
+		assert.Equal(t, data.Labels{"resource.service.name": "db", "span.http.status_code": "500"}, frame.Fields[1].Labels)
+		assert.Equal(t, `{resource.service.name="db", span.http.status_code=500}`, frame.Fields[1].Config.DisplayNameFromDS)
+		assert.True(t, math.IsNaN(frame.Fields[1].At(0).(float64)))
+	})
+
+	t.Run("queryMetricsRange", func(t *testing.T) {
+		var query string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.RawQuery
+			if r.URL.Path != "/api/metrics/query_range" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(metricsResponseJSON))
+		}))
+		defer srv.Close()
+
+		service := &Service{logger: backend.NewLoggerWith("logger", "tempo-test")}
+		res, err := service.queryMetricsRange(context.Background(), &Datasource{HTTPClient: srv.Client(), URL: srv.URL}, "{ } | rate()", time.Unix(1700000000, 0), time.Unix(1700003600, 0), "1m0s")
+		require.NoError(t, err)
+		assert.Len(t, res.Series, 2)
+		assert.Equal(t, "end=1700003600&query=%7B+%7D+%7C+rate%28%29&start=1700000000&step=1m0s", query)
+
+		_, err = service.queryMetricsRange(context.Background(), &Datasource{HTTPClient: srv.Client(), URL: srv.URL + "/unknown"}, "{ } | rate()", time.Unix(1700000000, 0), time.Unix(1700003600, 0), "")
+		require.ErrorContains(t, err, "404")
+	})
+
+	t.Run("metricsStep", func(t *testing.T) {
+		step := "30s"
+		assert.Equal(t, "30s", metricsStep(&dataquery.TempoQuery{Step: &step}, time.Minute))
+		assert.Equal(t, "1m0s", metricsStep(&dataquery.TempoQuery{}, time.Minute))
+		assert.Equal(t, "", metricsStep(&dataquery.TempoQuery{}, 0))
+	})
+}
+
+func TestSplitMetricsRange(t *testing.T) {
+	from := time.Unix(0, 0)
+
+	ranges := splitMetricsRange(from, from.Add(time.Hour), time.Minute, 4)
+	require.Equal(t, []backend.TimeRange{
+		{From: from, To: from.Add(15 * time.Minute)},
+		{From: from.Add(15 * time.Minute), To: from.Add(30 * time.Minute)},
+		{From: from.Add(30 * time.Minute), To: from.Add(45 * time.Minute)},
+		{From: from.Add(45 * time.Minute), To: from.Add(time.Hour)},
+	}, ranges)
+
+	// sub-ranges are a multiple of the step
+	ranges = splitMetricsRange(from, from.Add(time.Hour), 20*time.Minute, 4)
+	require.Equal(t, []backend.TimeRange{
+		{From: from, To: from.Add(20 * time.Minute)},
+		{From: from.Add(20 * time.Minute), To: from.Add(40 * time.Minute)},
+		{From: from.Add(40 * time.Minute), To: from.Add(time.Hour)},
+	}, ranges)
+}
+
+func TestProcessMetricsStream(t *testing.T) {
+	service := &Service{logger: backend.NewLoggerWith("logger", "tsdb.tempo.test")}
+	from := time.Unix(1700000000, 0)
+	tr := backend.TimeRange{From: from, To: from.Add(4 * time.Minute)}
+
+	t.Run("sends the merged series after each sub-range", func(t *testing.T) {
+		fetch := func(ctx context.Context, start, end time.Time) (*MetricsQueryRangeResponse, error) {
+			// the sample at the end of a sub-range is also returned for the next one
+			return &MetricsQueryRangeResponse{Series: []MetricsSeries{{
+				PromLabels: "{}",
+				Samples: []MetricsSeriesSample{
+					{TimestampMs: json.Number(strconv.FormatInt(start.UnixMilli(), 10)), Value: 1.0},
+					{TimestampMs: json.Number(strconv.FormatInt(end.UnixMilli(), 10)), Value: 1.0},
+				},
+			}}}, nil
+		}
+		sender := &mockSender{}
+		require.NoError(t, service.processMetricsStream(context.Background(), "{ } | rate()", "A", tr, "1m", fetch, sender))
+		require.Len(t, sender.responses, 4)
+
+		for i, response := range sender.responses {
+			state := response.Fields[1].At(0).(string)
+			if i == 3 {
+				assert.Equal(t, string(dataquery.SearchStreamingStateDone), state)
+			} else {
+				assert.Equal(t, string(dataquery.SearchStreamingStateStreaming), state)
+			}
+
+			var frames data.Frames
+			require.NoError(t, json.Unmarshal(response.Fields[0].At(0).(json.RawMessage), &frames))
+			require.Len(t, frames, 1)
+			assert.Equal(t, i+2, frames[0].Rows())
+		}
+	})
+
+	t.Run("returns the error of a sub-range", func(t *testing.T) {
+		fetch := func(ctx context.Context, start, end time.Time) (*MetricsQueryRangeResponse, error) {
+			return nil, errors.New("query failed")
+		}
+		sender := &mockSender{}
+		require.ErrorContains(t, service.processMetricsStream(context.Background(), "{ } | rate()", "A", tr, "", fetch, sender), "query failed")
+		require.Empty(t, sender.responses)
+	})
+}
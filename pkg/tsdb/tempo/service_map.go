@@ -0,0 +1,370 @@
+package tempo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/tracing"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/tsdb/tempo/kinds/dataquery"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// defaultServiceMapTraceLimit is the number of traces the service map is computed from by default
+	defaultServiceMapTraceLimit = 20
+	// maxServiceMapTraceLimit is the maximum number of traces the service map is computed from
+	maxServiceMapTraceLimit = 200
+	// serviceMapConcurrency is the number of traces fetched at the same time
+	serviceMapConcurrency = 4
+)
+
+type searchResponse struct {
+	Traces []struct {
+		TraceID string `json:"traceID"`
+	} `json:"traces"`
+}
+
+// serviceMapStats are the statistics of the requests of a node or an edge of the service map
+type serviceMapStats struct {
+	durations []float64
+	errors    int
+}
+
+func (s *serviceMapStats) add(duration float64, isError bool) {
+	s.durations = append(s.durations, duration)
+	if isError {
+		s.errors++
+	}
+}
+
+// p95 returns the 95th percentile of the durations, with the nearest-rank method
+func (s *serviceMapStats) p95() float64 {
+	if len(s.durations) == 0 {
+		return 0
+	}
+	durations := append([]float64(nil), s.durations...)
+	sort.Float64s(durations)
+	return durations[int(math.Ceil(0.95*float64(len(durations))))-1]
+}
+
+func (s *serviceMapStats) errorRate() float64 {
+	if len(s.durations) == 0 {
+		return 0
+	}
+	return float64(s.errors) / float64(len(s.durations))
+}
+
+type serviceMapEdge struct {
+	source string
+	target string
+	serviceMapStats
+}
+
+// serviceMap is a service graph computed from the spans of traces. The requests of a service are its spans
+// whose parent is in another service or not in the trace.
+type serviceMap struct {
+	nodes map[string]*serviceMapStats
+	edges map[string]*serviceMapEdge
+}
+
+func newServiceMap() *serviceMap {
+	return &serviceMap{nodes: map[string]*serviceMapStats{}, edges: map[string]*serviceMapEdge{}}
+}
+
+func (s *Service) runTraceQLServiceMapQuery(ctx context.Context, pCtx backend.PluginContext, query backend.DataQuery) (*backend.DataResponse, error) {
+	ctxLogger := s.logger.FromContext(ctx)
+	ctxLogger.Debug("Computing service map from TraceQL search", "function", logEntrypoint())
+
+	result := &backend.DataResponse{}
+
+	ctx, span := tracing.DefaultTracer().Start(ctx, "datasource.tempo.runTraceQLServiceMapQuery", trace.WithAttributes(
+		attribute.String("queryType", query.QueryType),
+	))
+	defer span.End()
+
+	model := &dataquery.TempoQuery{}
+	if err := json.Unmarshal(query.JSON, model); err != nil {
+		ctxLogger.Error("Failed to unmarshall Tempo query model", "error", err, "function", logEntrypoint())
+		return result, err
+	}
+
+	if model.Query == nil || *model.Query == "" {
+		err := fmt.Errorf("query is required")
+		ctxLogger.Error("Failed to validate model query", "error", err, "function", logEntrypoint())
+		return result, err
+	}
+
+	dsInfo, err := s.getDSInfo(ctx, pCtx)
+	if err != nil {
+		ctxLogger.Error("Failed to get datasource information", "error", err, "function", logEntrypoint())
+		return nil, err
+	}
+
+	limit := int64(defaultServiceMapTraceLimit)
+	if model.Limit != nil && *model.Limit > 0 {
+		limit = min(*model.Limit, maxServiceMapTraceLimit)
+	}
+
+	start, end := query.TimeRange.From.Unix(), query.TimeRange.To.Unix()
+	traceIDs, err := s.searchTraceIDs(ctx, dsInfo, *model.Query, limit, start, end)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		result.Error = err
+		return result, nil
+	}
+	span.SetAttributes(attribute.Int("traces_count", len(traceIDs)))
+
+	frames, err := s.fetchTraceFrames(ctx, dsInfo, traceIDs, start, end)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		result.Error = err
+		return result, nil
+	}
+
+	sm := newServiceMap()
+	for _, frame := range frames {
+		sm.addTrace(frame)
+	}
+
+	nodes, edges := sm.toFrames(query.TimeRange.Duration().Seconds())
+	nodes.RefID = query.RefID
+	edges.RefID = query.RefID
+	result.Frames = data.Frames{nodes, edges}
+	return result, nil
+}
+
+// searchTraceIDs returns the IDs of the traces matching the TraceQL query
+func (s *Service) searchTraceIDs(ctx context.Context, dsInfo *Datasource, query string, limit int64, start, end int64) ([]string, error) {
+	ctxLogger := s.logger.FromContext(ctx)
+
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", strconv.FormatInt(limit, 10))
+	params.Set("start", strconv.FormatInt(start, 10))
+	params.Set("end", strconv.FormatInt(end, 10))
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/search?%s", dsInfo.URL, params.Encode()), nil)
+	if err != nil {
+		ctxLogger.Error("Failed to create request", "error", err, "function", logEntrypoint())
+		return nil, err
+	}
+	request.Header.Set("Accept", "application/json")
+
+	resp, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		ctxLogger.Error("Failed to send request to Tempo", "error", err, "function", logEntrypoint())
+		return nil, fmt.Errorf("failed get to tempo: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			ctxLogger.Error("Failed to close response body", "error", err, "function", logEntrypoint())
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		ctxLogger.Error("Failed to read response body", "error", err, "function", logEntrypoint())
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to search traces: %s Status: %s Body: %s", query, resp.Status, string(body))
+	}
+
+	var search searchResponse
+	if err := json.Unmarshal(body, &search); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal search response: %w", err)
+	}
+
+	traceIDs := make([]string, 0, len(search.Traces))
+	for _, t := range search.Traces {
+		traceIDs = append(traceIDs, t.TraceID)
+	}
+	return traceIDs, nil
+}
+
+// fetchTraceFrames fetches the traces with bounded concurrency and converts them to trace frames
+func (s *Service) fetchTraceFrames(ctx context.Context, dsInfo *Datasource, traceIDs []string, start, end int64) ([]*data.Frame, error) {
+	frames := make([]*data.Frame, len(traceIDs))
+	errs := make([]error, len(traceIDs))
+
+	sem := make(chan struct{}, serviceMapConcurrency)
+	var wg sync.WaitGroup
+	for i, traceID := range traceIDs {
+		wg.Add(1)
+		go func(i int, traceID string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			frames[i], errs[i] = s.fetchTraceFrame(ctx, dsInfo, traceID, start, end)
+		}(i, traceID)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return frames, nil
+}
+
+func (s *Service) fetchTraceFrame(ctx context.Context, dsInfo *Datasource, traceID string, start, end int64) (*data.Frame, error) {
+	ctxLogger := s.logger.FromContext(ctx)
+
+	request, err := s.createRequest(ctx, dsInfo, traceID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed get to tempo: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			ctxLogger.Error("Failed to close response body", "error", err, "function", logEntrypoint())
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get trace with id: %s Status: %s Body: %s", traceID, resp.Status, string(body))
+	}
+
+	pbUnmarshaler := ptrace.ProtoUnmarshaler{}
+	otTrace, err := pbUnmarshaler.UnmarshalTraces(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert tempo response to Otlp: %w", err)
+	}
+
+	return TraceToFrame(otTrace)
+}
+
+// addTrace adds the requests of the spans of a trace frame to the service map
+func (sm *serviceMap) addTrace(frame *data.Frame) {
+	if frame == nil {
+		return
+	}
+	spanIDs, _ := frame.FieldByName("spanID")
+	parentIDs, _ := frame.FieldByName("parentSpanID")
+	services, _ := frame.FieldByName("serviceName")
+	statusCodes, _ := frame.FieldByName("statusCode")
+	durations, _ := frame.FieldByName("duration")
+	if spanIDs == nil || parentIDs == nil || services == nil || statusCodes == nil || durations == nil {
+		return
+	}
+
+	serviceOf := make(map[string]string, frame.Rows())
+	for i := 0; i < frame.Rows(); i++ {
+		serviceOf[spanIDs.At(i).(string)] = services.At(i).(string)
+	}
+
+	for i := 0; i < frame.Rows(); i++ {
+		service := services.At(i).(string)
+		parentService, hasParent := serviceOf[parentIDs.At(i).(string)]
+		if hasParent && parentService == service {
+			continue
+		}
+
+		duration := durations.At(i).(float64)
+		isError := statusCodes.At(i).(int64) == int64(ptrace.StatusCodeError)
+
+		node, ok := sm.nodes[service]
+		if !ok {
+			node = &serviceMapStats{}
+			sm.nodes[service] = node
+		}
+		node.add(duration, isError)
+
+		if !hasParent {
+			continue
+		}
+		if _, ok := sm.nodes[parentService]; !ok {
+			sm.nodes[parentService] = &serviceMapStats{}
+		}
+		edgeID := parentService + "_" + service
+		edge, ok := sm.edges[edgeID]
+		if !ok {
+			edge = &serviceMapEdge{source: parentService, target: service}
+			sm.edges[edgeID] = edge
+		}
+		edge.add(duration, isError)
+	}
+}
+
+// toFrames returns the nodes and edges frames of the service map, in the node graph format. Rates are computed
+// over the length of the time range in seconds.
+func (sm *serviceMap) toFrames(seconds float64) (*data.Frame, *data.Frame) {
+	if seconds <= 0 {
+		seconds = 1
+	}
+
+	nodes := data.NewFrame("Nodes",
+		data.NewField("id", nil, []string{}),
+		data.NewField("title", nil, []string{}).SetConfig(&data.FieldConfig{DisplayName: "Service name"}),
+		data.NewField("mainstat", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "p95 response time", Unit: "ms"}),
+		data.NewField("secondarystat", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "Requests per second", Unit: "r/sec"}),
+		data.NewField("arc__success", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "Success", Color: map[string]any{"mode": "fixed", "fixedColor": "green"}}),
+		data.NewField("arc__failed", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "Failed", Color: map[string]any{"mode": "fixed", "fixedColor": "red"}}),
+		data.NewField("detail__errorRate", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "Error rate", Unit: "percentunit"}),
+	)
+	nodes.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeNodeGraph}
+
+	names := make([]string, 0, len(sm.nodes))
+	for name := range sm.nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		stats := sm.nodes[name]
+		errorRate := stats.errorRate()
+		if len(stats.durations) == 0 {
+			// a node that only sends requests to other services
+			nodes.AppendRow(name, name, 0.0, 0.0, 1.0, 0.0, 0.0)
+			continue
+		}
+		nodes.AppendRow(name, name, stats.p95(), float64(len(stats.durations))/seconds, 1-errorRate, errorRate, errorRate)
+	}
+
+	edges := data.NewFrame("Edges",
+		data.NewField("id", nil, []string{}),
+		data.NewField("source", nil, []string{}),
+		data.NewField("target", nil, []string{}),
+		data.NewField("mainstat", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "p95 response time", Unit: "ms"}),
+		data.NewField("secondarystat", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "Requests per second", Unit: "r/sec"}),
+		data.NewField("detail__errorRate", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "Error rate", Unit: "percentunit"}),
+	)
+	edges.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeNodeGraph}
+
+	ids := make([]string, 0, len(sm.edges))
+	for id := range sm.edges {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		edge := sm.edges[id]
+		edges.AppendRow(id, edge.source, edge.target, edge.p95(), float64(len(edge.durations))/seconds, edge.errorRate())
+	}
+
+	return nodes, edges
+}
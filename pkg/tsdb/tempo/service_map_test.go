@@ -0,0 +1,130 @@
+package tempo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+type testSpan struct {
+	id       byte
+	parent   byte
+	service  string
+	duration time.Duration
+	isError  bool
+}
+
+func makeTestTrace(traceID byte, spans ...testSpan) ptrace.Traces {
+	td := ptrace.NewTraces()
+	for _, s := range spans {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("service.name", s.service)
+		span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+		span.SetTraceID(pcommon.TraceID{traceID})
+		span.SetSpanID(pcommon.SpanID{s.id})
+		if s.parent != 0 {
+			span.SetParentSpanID(pcommon.SpanID{s.parent})
+		}
+		start := time.Unix(1700000000, 0)
+		span.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+		span.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(s.duration)))
+		if s.isError {
+			span.Status().SetCode(ptrace.StatusCodeError)
+		}
+	}
+	return td
+}
+
+func TestServiceMap(t *testing.T) {
+	traces := []ptrace.Traces{
+		makeTestTrace(1,
+			testSpan{id: 1, service: "frontend", duration: 100 * time.Millisecond},
+			testSpan{id: 2, parent: 1, service: "frontend", duration: 90 * time.Millisecond},
+			testSpan{id: 3, parent: 2, service: "api", duration: 80 * time.Millisecond},
+			testSpan{id: 4, parent: 3, service: "db", duration: 20 * time.Millisecond, isError: true},
+		),
+		makeTestTrace(2,
+			testSpan{id: 1, service: "frontend", duration: 50 * time.Millisecond},
+			testSpan{id: 2, parent: 1, service: "api", duration: 40 * time.Millisecond},
+		),
+	}
+
+	sm := newServiceMap()
+	for _, td := range traces {
+		frame, err := TraceToFrame(td)
+		require.NoError(t, err)
+		sm.addTrace(frame)
+	}
+
+	nodes, edges := sm.toFrames(10)
+
+	assert.Equal(t, data.VisTypeNodeGraph, string(nodes.Meta.PreferredVisualization))
+	require.Equal(t, 3, nodes.Rows())
+	// api
+	assert.Equal(t, []any{"api", "api", 80.0, 0.2, 1.0, 0.0, 0.0}, nodes.RowCopy(0))
+	// db
+	assert.Equal(t, []any{"db", "db", 20.0, 0.1, 0.0, 1.0, 1.0}, nodes.RowCopy(1))
+	// frontend, spans of the same service are not requests
+	assert.Equal(t, []any{"frontend", "frontend", 100.0, 0.2, 1.0, 0.0, 0.0}, nodes.RowCopy(2))
+
+	require.Equal(t, 2, edges.Rows())
+	assert.Equal(t, []any{"api_db", "api", "db", 20.0, 0.1, 1.0}, edges.RowCopy(0))
+	assert.Equal(t, []any{"frontend_api", "frontend", "api", 80.0, 0.2, 0.0}, edges.RowCopy(1))
+}
+
+func TestServiceMapStats(t *testing.T) {
+	stats := &serviceMapStats{}
+	for i := 1; i <= 100; i++ {
+		stats.add(float64(i), i%10 == 0)
+	}
+	assert.Equal(t, 95.0, stats.p95())
+	assert.Equal(t, 0.1, stats.errorRate())
+}
+
+func TestServiceMapFetch(t *testing.T) {
+	traceBody, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(makeTestTrace(1,
+		testSpan{id: 1, service: "frontend", duration: 100 * time.Millisecond},
+		testSpan{id: 2, parent: 1, service: "api", duration: 80 * time.Millisecond},
+	))
+	require.NoError(t, err)
+
+	var searchQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/search":
+			searchQuery = r.URL.RawQuery
+			_, _ = w.Write([]byte(`{"traces": [{"traceID": "1"}, {"traceID": "2"}]}`))
+		case strings.HasPrefix(r.URL.Path, "/api/traces/"):
+			_, _ = w.Write(traceBody)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	service := &Service{logger: backend.NewLoggerWith("logger", "tempo-test")}
+	dsInfo := &Datasource{HTTPClient: srv.Client(), URL: srv.URL}
+
+	traceIDs, err := service.searchTraceIDs(context.Background(), dsInfo, `{ status = error }`, 20, 1700000000, 1700003600)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, traceIDs)
+	assert.Equal(t, "end=1700003600&limit=20&q=%7B+status+%3D+error+%7D&start=1700000000", searchQuery)
+
+	frames, err := service.fetchTraceFrames(context.Background(), dsInfo, traceIDs, 1700000000, 1700003600)
+	require.NoError(t, err)
+	require.Len(t, frames, 2)
+	assert.Equal(t, 2, frames[0].Rows())
+
+	_, err = service.fetchTraceFrames(context.Background(), &Datasource{HTTPClient: srv.Client(), URL: srv.URL + "/unknown"}, traceIDs, 0, 0)
+	require.ErrorContains(t, err, "404")
+}
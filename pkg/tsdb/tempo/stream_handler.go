@@ -11,7 +11,7 @@ import (
 func (s *Service) SubscribeStream(ctx context.Context, req *backend.SubscribeStreamRequest) (*backend.SubscribeStreamResponse, error) {
 	s.logger.Debug("Allowing access to stream", "path", req.Path, "user", req.PluginContext.User)
 	status := backend.SubscribeStreamStatusPermissionDenied
-	if strings.HasPrefix(req.Path, SearchPathPrefix) {
+	if strings.HasPrefix(req.Path, SearchPathPrefix) || strings.HasPrefix(req.Path, MetricsPathPrefix) {
 		status = backend.SubscribeStreamStatusOK
 	}
 
@@ -44,5 +44,16 @@ func (s *Service) RunStream(ctx context.Context, request *backend.RunStreamReque
 		}
 	}
 
+	if strings.HasPrefix(request.Path, MetricsPathPrefix) {
+		tempoDatasource, err := s.getDSInfo(ctx, request.PluginContext)
+		if err != nil {
+			return err
+		}
+		if err = s.runMetricsStream(ctx, request, sender, tempoDatasource); err != nil {
+			return sendMetricsError(err, sender)
+		}
+		return nil
+	}
+
 	return fmt.Errorf("unknown path %s", request.Path)
 }
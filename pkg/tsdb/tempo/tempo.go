@@ -2,6 +2,7 @@ package tempo
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"runtime"
@@ -112,6 +113,19 @@ func (s *Service) query(ctx context.Context, pCtx backend.PluginContext, query b
 	if query.QueryType == string(dataquery.TempoQueryTypeTraceId) {
 		return s.getTrace(ctx, pCtx, query)
 	}
+	if query.QueryType == string(dataquery.TempoQueryTypeTraceqlMetrics) {
+		return s.runTraceQLMetricsQuery(ctx, pCtx, query)
+	}
+	if query.QueryType == string(dataquery.TempoQueryTypeTraceqlServiceMap) {
+		return s.runTraceQLServiceMapQuery(ctx, pCtx, query)
+	}
+	// TraceQL queries of the query editor are metrics queries when they use a metrics function
+	if query.QueryType == string(dataquery.TempoQueryTypeTraceql) {
+		model := &dataquery.TempoQuery{}
+		if err := json.Unmarshal(query.JSON, model); err == nil && model.Query != nil && isTraceQLMetricsQuery(*model.Query) {
+			return s.runTraceQLMetricsQuery(ctx, pCtx, query)
+		}
+	}
 	return nil, fmt.Errorf("unsupported query type: '%s' for query with refID '%s'", query.QueryType, query.RefID)
 }
 
@@ -11,13 +11,14 @@ export const UnsupportedTemplateVariablesAlert = () => (
     severity="warning"
     title={t(
       'public-dashboard.modal-alerts.unsupported-template-variable-alert-title',
-      'Template variables are not supported'
+      'Some template variables are not supported'
     )}
     data-testid={selectors.TemplateVariablesWarningAlert}
     bottomSpacing={0}
   >
     <Trans i18nKey="public-dashboard.modal-alerts.unsupported-template-variable-alert-desc">
-      This public dashboard may not work since it uses template variables
+      This public dashboard may not work since it uses template variables other than query, custom and constant
+      variables
     </Trans>
   </Alert>
 );
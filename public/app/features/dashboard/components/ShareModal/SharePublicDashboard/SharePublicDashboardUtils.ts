@@ -19,8 +19,13 @@ export interface PublicDashboardSettings {
   timeSelectionEnabled: boolean;
 }
 
+export interface PublicDashboardVariableSettings {
+  allowedValues?: Record<string, string[]>;
+}
+
 export interface PublicDashboard extends PublicDashboardSettings {
   accessToken?: string;
+  variableSettings?: PublicDashboardVariableSettings;
   uid: string;
   dashboardUid: string;
   timeSettings?: object;
@@ -42,9 +47,12 @@ export interface SessionUser {
   totalDashboards: number;
 }
 
+// Template variables of these types are resolved by the server with the values saved in the dashboard
+const supportedVariableTypes = new Set(['query', 'custom', 'constant']);
+
 // Instance methods
 export const dashboardHasTemplateVariables = (variables: TypedVariableModel[]): boolean => {
-  return variables.length > 0;
+  return variables.some((variable) => !supportedVariableTypes.has(variable?.type));
 };
 
 export const publicDashboardPersisted = (publicDashboard?: PublicDashboard): boolean => {
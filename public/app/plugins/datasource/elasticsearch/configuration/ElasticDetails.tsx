@@ -138,6 +138,19 @@ export const ElasticDetails = ({ value, onChange }: Props) => {
           />
         </InlineField>
       )}
+
+      <InlineField
+        label="ES|QL and SQL queries"
+        htmlFor="es_config_rawQueries"
+        labelWidth={29}
+        tooltip="Allow ES|QL and SQL queries. They can read any index the data source credentials give access to, not only the configured index."
+      >
+        <InlineSwitch
+          id="es_config_rawQueries"
+          value={value.jsonData.rawQueriesEnabled ?? false}
+          onChange={jsonDataSwitchChangeHandler('rawQueriesEnabled', value, onChange)}
+        />
+      </InlineField>
     </ConfigSubSection>
   );
 };
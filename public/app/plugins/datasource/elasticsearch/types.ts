@@ -27,6 +27,10 @@ export { Elasticsearch as ElasticsearchQuery } from './dataquery.gen';
 type ExtendedLogsSettings = SchemaLogs['settings'] & {
   searchAfter?: unknown[];
   sortDirection?: 'asc' | 'desc';
+  // Backend pagination: the first page opens a point in time, and each page returns
+  // the cursor of the next one in the frame metadata (meta.custom.cursor).
+  pagination?: boolean;
+  cursor?: string;
 };
 
 export interface Logs extends SchemaLogs {
@@ -64,6 +68,7 @@ export interface ElasticsearchOptions extends DataSourceJsonData {
   logLevelField?: string;
   dataLinks?: DataLinkConfig[];
   includeFrozen?: boolean;
+  rawQueriesEnabled?: boolean;
   index?: string;
   sigV4Auth?: boolean;
   oauthPassThru?: boolean;
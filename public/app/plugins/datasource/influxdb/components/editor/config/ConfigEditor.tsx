@@ -49,11 +49,13 @@ const versionsWithSQL: Array<SelectableValue<InfluxVersion>> = [
 export type Props = DataSourcePluginOptionsEditorProps<InfluxOptions>;
 type State = {
   maxSeries: string | undefined;
+  maxPoints: string | undefined;
 };
 
 export class ConfigEditor extends PureComponent<Props, State> {
   state = {
     maxSeries: '',
+    maxPoints: '',
   };
 
   htmlPrefix: string;
@@ -61,6 +63,7 @@ export class ConfigEditor extends PureComponent<Props, State> {
   constructor(props: Props) {
     super(props);
     this.state.maxSeries = props.options.jsonData.maxSeries?.toString() || '';
+    this.state.maxPoints = props.options.jsonData.maxPoints?.toString() || '';
     this.htmlPrefix = uniqueId('influxdb-config');
   }
 
@@ -172,6 +175,25 @@ export class ConfigEditor extends PureComponent<Props, State> {
               }}
             />
           </InlineField>
+          {options.jsonData.version === InfluxVersion.Flux && (
+            <InlineField
+              labelWidth={20}
+              label="Max points"
+              tooltip="Limit the number of points of all the series of a Flux query that Grafana will process. The results are truncated when the limit is reached. Defaults to 1000000."
+            >
+              <Input
+                placeholder="1000000"
+                type="number"
+                className="width-20"
+                value={this.state.maxPoints}
+                onChange={(event: { currentTarget: { value: string } }) => {
+                  this.setState({ maxPoints: event.currentTarget.value });
+                  const val = parseInt(event.currentTarget.value, 10);
+                  updateDatasourcePluginJsonDataOption(this.props, 'maxPoints', Number.isFinite(val) ? val : undefined);
+                }}
+              />
+            </InlineField>
+          )}
         </FieldSet>
       </>
     );
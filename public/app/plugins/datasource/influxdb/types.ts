@@ -20,6 +20,7 @@ export interface InfluxOptions extends DataSourceJsonData {
   organization?: string;
   defaultBucket?: string;
   maxSeries?: number;
+  maxPoints?: number;
 
   // With SQL
   metadata?: Array<Record<string, string>>;
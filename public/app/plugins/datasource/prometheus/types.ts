@@ -19,6 +19,9 @@ export interface PromQuery extends GenPromQuery, DataQuery {
   disableTextWrap?: boolean;
   fullMetaSearch?: boolean;
   includeNullMetadata?: boolean;
+  // native histograms are returned as heatmap cells, or as a series for each of the histogram quantiles
+  histogramFormat?: 'heatmap' | 'quantiles';
+  histogramQuantiles?: number[];
 }
 
 export enum PrometheusCacheLevel {
@@ -48,6 +51,8 @@ export interface PromOptions extends DataSourceJsonData {
   defaultEditor?: QueryEditorMode;
   incrementalQuerying?: boolean;
   incrementalQueryOverlapWindow?: string;
+  queryTimeSplitInterval?: string;
+  queryTimeSplitConcurrency?: number;
   disableRecordingRules?: boolean;
   sigV4Auth?: boolean;
   oauthPassThru?: boolean;
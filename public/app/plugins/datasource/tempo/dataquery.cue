@@ -46,6 +46,8 @@ composableKinds: DataQuery: {
 					limit?: int64
 					// Defines the maximum number of spans per spanset that are returned from Tempo
 					spss?: int64
+					// Step of TraceQL metrics queries. Use duration format, for example: 30s, 1m
+					step?: string
 					filters: [...#TraceqlFilter]
 					// Filters that are used to query the metrics summary
 					groupBy?: [...#TraceqlFilter]
@@ -54,7 +56,7 @@ composableKinds: DataQuery: {
 				} @cuetsy(kind="interface") @grafana(TSVeneer="type")
 
 				// search = Loki search, nativeSearch = Tempo search for backwards compatibility
-				#TempoQueryType: "traceql" | "traceqlSearch" | "search" | "serviceMap" | "upload" | "nativeSearch" | "traceId" | "clear" @cuetsy(kind="type")
+				#TempoQueryType: "traceql" | "traceqlSearch" | "traceqlMetrics" | "traceqlServiceMap" | "search" | "serviceMap" | "upload" | "nativeSearch" | "traceId" | "clear" @cuetsy(kind="type")
 
 				// The state of the TraceQL streaming search query
 				#SearchStreamingState: "pending" | "streaming" | "done" | "error" @cuetsy(kind="enum")
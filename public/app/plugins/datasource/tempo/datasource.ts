@@ -61,7 +61,7 @@ import {
   transformTrace,
   transformTraceList,
 } from './resultTransformer';
-import { doTempoChannelStream } from './streaming';
+import { doTempoChannelStream, doTempoMetricsStream } from './streaming';
 import { SearchQueryParams, TempoJsonData, TempoQuery } from './types';
 import { getErrorMessage } from './utils';
 import { TempoVariableSupport } from './variables';
@@ -357,7 +357,7 @@ export class TempoDatasource extends DataSourceWithBackend<TempoQuery, TempoJson
               grafana_version: config.buildInfo.version,
               query: queryValue ?? '',
             });
-            subQueries.push(this.handleTraceQlMetricsQuery(options, queryValue));
+            subQueries.push(this.handleTraceQlMetricsQuery(options, targets.traceql[0], queryValue));
           } else {
             reportInteraction('grafana_traces_traceql_queried', {
               datasourceType: 'tempo',
@@ -433,6 +433,16 @@ export class TempoDatasource extends DataSourceWithBackend<TempoQuery, TempoJson
       }
     }
 
+    if (targets.traceqlServiceMap?.length) {
+      reportInteraction('grafana_traces_traceql_service_map_queried', {
+        datasourceType: 'tempo',
+        app: options.app ?? '',
+        grafana_version: config.buildInfo.version,
+      });
+
+      subQueries.push(super.query({ ...options, targets: targets.traceqlServiceMap }));
+    }
+
     if (targets.upload?.length) {
       if (this.uploadedJson) {
         reportInteraction('grafana_traces_json_file_uploaded', {
@@ -687,8 +697,13 @@ export class TempoDatasource extends DataSourceWithBackend<TempoQuery, TempoJson
 
   handleTraceQlMetricsQuery = (
     options: DataQueryRequest<TempoQuery>,
+    target: TempoQuery,
     queryValue: string
   ): Observable<DataQueryResponse> => {
+    if (config.featureToggles.traceQLStreaming && this.isFeatureAvailable(FeatureName.streaming)) {
+      return doTempoMetricsStream({ ...target, query: queryValue }, this, options);
+    }
+
     return this._request('/api/metrics/query_range', {
       query: queryValue,
       start: options.range.from.unix(),
@@ -4,6 +4,8 @@ import { v4 as uuidv4 } from 'uuid';
 
 import {
   DataFrame,
+  dataFrameFromJSON,
+  DataFrameJSON,
   DataQueryRequest,
   DataQueryResponse,
   DataSourceInstanceSettings,
@@ -96,6 +98,70 @@ export function doTempoChannelStream(
     );
 }
 
+export function doTempoMetricsStream(
+  query: TempoQuery,
+  ds: TempoDatasource,
+  options: DataQueryRequest<TempoQuery>
+): Observable<DataQueryResponse> {
+  const range = options.range;
+
+  let frames: DataFrame[] | undefined = undefined;
+  let state: LoadingState = LoadingState.NotStarted;
+
+  return getGrafanaLiveSrv()
+    .getStream<MutableDataFrame>({
+      scope: LiveChannelScope.DataSource,
+      namespace: ds.uid,
+      path: `metrics/${getLiveStreamKey()}`,
+      data: {
+        ...query,
+        interval: options.intervalMs * 1e6,
+        timeRange: {
+          from: range.from.toISOString(),
+          to: range.to.toISOString(),
+        },
+      },
+    })
+    .pipe(
+      takeWhile((evt) => {
+        if ('message' in evt && evt?.message) {
+          const frameState: SearchStreamingState = evt.message.data.values[1][0];
+          if (frameState === SearchStreamingState.Done || frameState === SearchStreamingState.Error) {
+            return false;
+          }
+        }
+        return true;
+      }, true)
+    )
+    .pipe(
+      map((evt) => {
+        if ('message' in evt && evt?.message) {
+          // Schema should be [frames, state, error]
+          const series: DataFrameJSON[] = evt.message.data.values[0][0];
+          const frameState: SearchStreamingState = evt.message.data.values[1][0];
+          const error = evt.message.data.values[2][0];
+
+          switch (frameState) {
+            case SearchStreamingState.Done:
+              state = LoadingState.Done;
+              break;
+            case SearchStreamingState.Streaming:
+              state = LoadingState.Streaming;
+              break;
+            case SearchStreamingState.Error:
+              throw new Error(error);
+          }
+
+          frames = (series || []).map((frame) => dataFrameFromJSON(frame));
+        }
+        return {
+          data: frames || [],
+          state,
+        };
+      })
+    );
+}
+
 function metricsDataFrame(metrics: SearchMetrics, state: SearchStreamingState, elapsedTime: number) {
   const progressThresholds: ThresholdsConfig = {
     steps: [